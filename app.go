@@ -7,6 +7,8 @@ import (
 
 	"github.com/melodiez14/meiko/src/cron"
 	"github.com/melodiez14/meiko/src/email"
+	"github.com/melodiez14/meiko/src/module/calendar"
+	"github.com/melodiez14/meiko/src/module/grade"
 	"github.com/melodiez14/meiko/src/util/alias"
 	"github.com/melodiez14/meiko/src/util/auth"
 	"github.com/melodiez14/meiko/src/util/conn"
@@ -14,6 +16,7 @@ import (
 	"github.com/melodiez14/meiko/src/util/jsonconfig"
 	"github.com/melodiez14/meiko/src/webserver"
 	"github.com/melodiez14/meiko/src/webserver/handler/bot"
+	"github.com/melodiez14/meiko/src/webserver/handler/token"
 )
 
 type configuration struct {
@@ -23,6 +26,9 @@ type configuration struct {
 	Webserver webserver.Config      `json:"webserver"`
 	Email     email.Config          `json:"email"`
 	Auth      auth.Config           `json:"auth"`
+	Grade     grade.Config          `json:"grade"`
+	Bot       bot.Config            `json:"bot"`
+	Calendar  calendar.Config       `json:"calendar"`
 }
 
 func init() {
@@ -44,9 +50,12 @@ func main() {
 	alias.InitDirectory(config.Directory)
 	conn.InitDB(config.Database)
 	conn.InitRedis(config.Redis)
-	bot.Init()
+	bot.Init(config.Bot)
 	cron.Init()
 	auth.Init(config.Auth)
+	auth.RegisterTokenValidator(token.ValidateToken)
 	email.Init(config.Email)
+	grade.Init(config.Grade)
+	calendar.Init(config.Calendar)
 	webserver.Start(config.Webserver)
 }
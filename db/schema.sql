@@ -5,6 +5,27 @@
 SET NAMES utf8mb4;
 SET FOREIGN_KEY_CHECKS = 0;
 
+-- ----------------------------
+-- Table structure for api_tokens
+-- ----------------------------
+DROP TABLE IF EXISTS `api_tokens`;
+CREATE TABLE `api_tokens` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `users_id` int(10) unsigned NOT NULL,
+  `name` varchar(45) NOT NULL,
+  `prefix` varchar(11) NOT NULL,
+  `token_hash` char(64) NOT NULL,
+  `scopes` varchar(1024) NOT NULL DEFAULT '',
+  `expires_at` datetime DEFAULT NULL,
+  `last_used_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_api_tokens_token_hash` (`token_hash`),
+  KEY `fk_api_tokens_users1_idx` (`users_id`),
+  CONSTRAINT `fk_api_tokens_users1` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 ROW_FORMAT=COMPACT;
+
 -- ----------------------------
 -- Table structure for assigments
 -- ----------------------------
@@ -27,17 +48,49 @@ CREATE TABLE `assigments` (
 -- ----------------------------
 DROP TABLE IF EXISTS `attendances`;
 CREATE TABLE `attendances` (
-  `meetings_number` tinyint(3) unsigned NOT NULL,
-  `p_users_courses_users_id` int(10) unsigned NOT NULL,
-  `p_users_courses_courses_id` int(10) unsigned NOT NULL,
-  `status` tinyint(3) unsigned DEFAULT NULL,
-  `meeting_date` date NOT NULL,
+  `meetings_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `status` tinyint(3) unsigned NOT NULL DEFAULT '0',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`meetings_id`,`users_id`),
+  KEY `fk_attendances_users` (`users_id`),
+  CONSTRAINT `fk_attendances_meetings` FOREIGN KEY (`meetings_id`) REFERENCES `meetings` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION,
+  CONSTRAINT `fk_attendances_users` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 ROW_FORMAT=COMPACT;
+
+-- ----------------------------
+-- Table structure for audit_logs
+-- ----------------------------
+DROP TABLE IF EXISTS `audit_logs`;
+CREATE TABLE `audit_logs` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `users_id` int(10) unsigned DEFAULT NULL,
+  `action` varchar(15) NOT NULL,
+  `target_table` varchar(45) NOT NULL,
+  `target_id` varchar(45) NOT NULL,
+  `diff` text NOT NULL,
+  `ip` varchar(45) NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `fk_audit_logs_users1_idx` (`users_id`),
+  KEY `idx_audit_logs_target` (`target_table`,`target_id`),
+  KEY `idx_audit_logs_created_at` (`created_at`),
+  CONSTRAINT `fk_audit_logs_users1` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE SET NULL ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 ROW_FORMAT=COMPACT;
+
+-- ----------------------------
+-- Table structure for calendar_tokens
+-- ----------------------------
+DROP TABLE IF EXISTS `calendar_tokens`;
+CREATE TABLE `calendar_tokens` (
+  `users_id` int(10) unsigned NOT NULL,
+  `token_hash` char(64) NOT NULL,
   `created_at` datetime NOT NULL,
   `updated_at` datetime NOT NULL,
-  PRIMARY KEY (`meetings_number`,`p_users_courses_users_id`,`p_users_courses_courses_id`),
-  KEY `fk_attendances_p_users_courses1` (`p_users_courses_users_id`,`p_users_courses_courses_id`),
-  CONSTRAINT `fk_attendances_meetings` FOREIGN KEY (`meetings_number`) REFERENCES `meetings` (`number`) ON DELETE NO ACTION ON UPDATE NO ACTION,
-  CONSTRAINT `fk_attendances_p_users_courses1` FOREIGN KEY (`p_users_courses_users_id`, `p_users_courses_courses_id`) REFERENCES `p_users_courses` (`users_id`, `courses_id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+  PRIMARY KEY (`users_id`),
+  UNIQUE KEY `unique_calendar_tokens_token_hash` (`token_hash`),
+  CONSTRAINT `fk_calendar_tokens_users1` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 ROW_FORMAT=COMPACT;
 
 -- ----------------------------
@@ -120,6 +173,21 @@ CREATE TABLE `grade_parameters` (
   CONSTRAINT `fk_grade_parameters_courses1` FOREIGN KEY (`courses_id`) REFERENCES `courses` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 ROW_FORMAT=COMPACT;
 
+-- ----------------------------
+-- Table structure for grades
+-- ----------------------------
+DROP TABLE IF EXISTS `grades`;
+CREATE TABLE `grades` (
+  `schedules_id` int(10) unsigned NOT NULL,
+  `users_id` int(10) unsigned NOT NULL,
+  `score` float(5,2) unsigned NOT NULL DEFAULT '0.00',
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`schedules_id`,`users_id`),
+  KEY `fk_grades_users` (`users_id`),
+  CONSTRAINT `fk_grades_users` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 -- ----------------------------
 -- Table structure for informations
 -- ----------------------------
@@ -188,14 +256,17 @@ CREATE TABLE `log_books` (
 -- ----------------------------
 DROP TABLE IF EXISTS `meetings`;
 CREATE TABLE `meetings` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
   `number` tinyint(3) unsigned NOT NULL,
-  `courses_id` int(10) unsigned NOT NULL,
+  `subject` varchar(100) NOT NULL,
   `description` text,
+  `date` date NOT NULL,
+  `schedules_id` int(10) unsigned NOT NULL,
   `created_at` datetime NOT NULL,
   `updated_at` datetime NOT NULL,
-  PRIMARY KEY (`number`,`courses_id`),
-  KEY `fk_meetings_courses` (`courses_id`),
-  CONSTRAINT `fk_meetings_courses` FOREIGN KEY (`courses_id`) REFERENCES `courses` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_meetings_schedules_number` (`schedules_id`,`number`),
+  KEY `fk_meetings_schedules` (`schedules_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
 -- ----------------------------
@@ -228,6 +299,7 @@ CREATE TABLE `p_users_assignments` (
   `courses_id` int(10) unsigned NOT NULL,
   `score` float(5,2) unsigned DEFAULT NULL,
   `description` text,
+  `comment` text,
   `created_at` datetime NOT NULL,
   `updated_at` datetime NOT NULL,
   PRIMARY KEY (`assigments_id`,`users_id`,`courses_id`),
@@ -334,19 +406,21 @@ CREATE TABLE `researches` (
 -- ----------------------------
 DROP TABLE IF EXISTS `rolegroups`;
 CREATE TABLE `rolegroups` (
-  `id` int(10) unsigned NOT NULL,
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
   `name` varchar(15) NOT NULL,
   `created_at` datetime NOT NULL,
   `updated_at` datetime NOT NULL,
-  PRIMARY KEY (`id`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8 ROW_FORMAT=COMPACT;
+  `is_2fa_required` tinyint(1) unsigned NOT NULL DEFAULT '0',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`)
+) ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8 ROW_FORMAT=COMPACT;
 
 -- ----------------------------
 -- Records of rolegroups
 -- ----------------------------
 BEGIN;
-INSERT INTO `rolegroups` VALUES (1, 'Assistant', '2017-09-28 18:48:29', '2017-09-28 18:48:31');
-INSERT INTO `rolegroups` VALUES (2, 'Lecturer', '2017-09-28 18:48:50', '2017-09-28 18:48:52');
+INSERT INTO `rolegroups` VALUES (1, 'Assistant', '2017-09-28 18:48:29', '2017-09-28 18:48:31', 0);
+INSERT INTO `rolegroups` VALUES (2, 'Lecturer', '2017-09-28 18:48:50', '2017-09-28 18:48:52', 0);
 COMMIT;
 
 -- ----------------------------
@@ -355,7 +429,7 @@ COMMIT;
 DROP TABLE IF EXISTS `rolegroups_modules`;
 CREATE TABLE `rolegroups_modules` (
   `rolegroups_id` int(10) unsigned NOT NULL AUTO_INCREMENT,
-  `modules` enum('users','courses','attendances','roles') NOT NULL,
+  `modules` enum('users','courses','attendances','roles','schedules','assignments','informations','audits') NOT NULL,
   `ability` enum('CREATE','READ','UPDATE','DELETE','XCREATE','XREAD','XUPDATE','XDELETE') NOT NULL,
   `created_at` datetime NOT NULL,
   `updated_at` datetime NOT NULL,
@@ -375,8 +449,33 @@ INSERT INTO `rolegroups_modules` VALUES (2, 'users', 'XDELETE', '2017-09-28 18:5
 INSERT INTO `rolegroups_modules` VALUES (2, 'courses', 'UPDATE', '2017-10-01 13:52:34', '2017-10-01 13:52:35');
 INSERT INTO `rolegroups_modules` VALUES (2, 'courses', 'XCREATE', '2017-10-01 07:20:20', '2017-10-01 07:20:22');
 INSERT INTO `rolegroups_modules` VALUES (2, 'courses', 'XREAD', '2017-09-30 17:26:27', '2017-09-30 17:26:29');
+INSERT INTO `rolegroups_modules` VALUES (2, 'audits', 'XREAD', '2017-09-30 17:26:27', '2017-09-30 17:26:29');
 COMMIT;
 
+-- ----------------------------
+-- Table structure for schedules_calendars
+-- ----------------------------
+DROP TABLE IF EXISTS `schedules_calendars`;
+CREATE TABLE `schedules_calendars` (
+  `schedules_id` int(10) unsigned NOT NULL,
+  `token` varchar(64) NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`schedules_id`),
+  UNIQUE KEY `unique_schedules_calendars_token` (`token`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 ROW_FORMAT=COMPACT;
+
+-- ----------------------------
+-- Table structure for schedules_capacities
+-- ----------------------------
+DROP TABLE IF EXISTS `schedules_capacities`;
+CREATE TABLE `schedules_capacities` (
+  `schedules_id` int(10) unsigned NOT NULL,
+  `capacity` smallint(5) unsigned NOT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`schedules_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 ROW_FORMAT=COMPACT;
+
 -- ----------------------------
 -- Table structure for users
 -- ----------------------------
@@ -386,7 +485,7 @@ CREATE TABLE `users` (
   `name` varchar(50) NOT NULL,
   `gender` tinyint(3) unsigned NOT NULL,
   `email` varchar(45) NOT NULL,
-  `password` varchar(45) NOT NULL,
+  `password` varchar(255) NOT NULL,
   `note` varchar(100) NOT NULL DEFAULT '',
   `rolegroups_id` int(11) unsigned DEFAULT NULL,
   `status` tinyint(3) unsigned NOT NULL DEFAULT '0',
@@ -413,4 +512,34 @@ INSERT INTO `users` VALUES (2, 'Risal Bro', 0, 'risal.falah@gmail.com', '2af9b1b
 INSERT INTO `users` VALUES (4, 'Bro Risal', 1, 'risal@live.com', '2af9b1ba42dc5eb01743e6b3759b6e4b', 'Hello im risal falah', NULL, 2, '085860141146', 'risalf', '140810140016', 3513, '2017-09-28 20:10:04', 0, '2017-09-28 19:38:23', '2017-09-28 19:54:08');
 COMMIT;
 
+-- ----------------------------
+-- Table structure for users_recovery_codes
+-- ----------------------------
+DROP TABLE IF EXISTS `users_recovery_codes`;
+CREATE TABLE `users_recovery_codes` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `users_id` int(10) unsigned NOT NULL,
+  `code_hash` char(64) NOT NULL,
+  `used_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `fk_users_recovery_codes_users1_idx` (`users_id`),
+  CONSTRAINT `fk_users_recovery_codes_users1` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 ROW_FORMAT=COMPACT;
+
+-- ----------------------------
+-- Table structure for users_totp
+-- ----------------------------
+DROP TABLE IF EXISTS `users_totp`;
+CREATE TABLE `users_totp` (
+  `users_id` int(10) unsigned NOT NULL,
+  `secret` varchar(64) NOT NULL,
+  `last_step` bigint(20) unsigned NOT NULL DEFAULT '0',
+  `enabled_at` datetime DEFAULT NULL,
+  `created_at` datetime NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`users_id`),
+  CONSTRAINT `fk_users_totp_users1` FOREIGN KEY (`users_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 ROW_FORMAT=COMPACT;
+
 SET FOREIGN_KEY_CHECKS = 1;
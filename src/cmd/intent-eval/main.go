@@ -0,0 +1,42 @@
+// Command intent-eval trains the bot intent classifier using the training utterances of the corpus
+// and reports the accuracy against the held-out utterances
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/melodiez14/meiko/src/util/classifier"
+)
+
+func main() {
+
+	path := flag.String("corpus", "files/etc/meiko/bot/intent.json", "path of the intent corpus")
+	flag.Parse()
+
+	corpus, err := classifier.LoadCorpus(*path)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	nb := classifier.NewNaiveBayes()
+	nb.Train(corpus.TrainSamples())
+	report := classifier.Evaluate(nb, corpus.TestSamples())
+
+	fmt.Printf("corpus version : %s\n", corpus.Version)
+	fmt.Printf("accuracy       : %.2f%% (%d/%d)\n\n", report.Accuracy*100, report.Correct, report.Total)
+
+	intents := []string{}
+	for name := range report.Intents {
+		intents = append(intents, name)
+	}
+	sort.Strings(intents)
+
+	fmt.Printf("%-12s %9s %9s %7s\n", "intent", "precision", "recall", "total")
+	for _, name := range intents {
+		val := report.Intents[name]
+		fmt.Printf("%-12s %8.2f%% %8.2f%% %7d\n", name, val.Precision()*100, val.Recall()*100, val.Total)
+	}
+}
@@ -33,3 +33,15 @@ func SendAccountCreated(name, email string, code uint16) {
 		SetTemplate("files/var/www/meiko/email/account_created.html", data).
 		Deliver()
 }
+
+// SendEnrollmentApproved is used for notifying the student that the enrollment has been approved
+func SendEnrollmentApproved(name, email, course string) {
+	data := map[string]interface{}{
+		"name":   name,
+		"course": course,
+	}
+
+	NewRequest(email, "Your enrollment has been approved").
+		SetTemplate("files/var/www/meiko/email/enrollment_approved.html", data).
+		Deliver()
+}
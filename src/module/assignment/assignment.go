@@ -13,19 +13,17 @@ import (
 // GetByCourseID func ...
 func GetByCourseID(courseID int64) ([]Assignment, error) {
 	var assignments []Assignment
-	query := fmt.Sprintf(queryGetByCourseID, courseID)
-	err := conn.DB.Select(&assignments, query)
+	err := conn.NewQuery(queryGetByCourseID, courseID).Select(&assignments)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
 	return assignments, nil
 }
 
-// GetIncompleteByUserID func ...
+// GetIncompleteByUserID returns the active assignments of the enrolled schedules which haven't been uploaded by the user
 func GetIncompleteByUserID(userID int64) ([]Assignment, error) {
 	var assignments []Assignment
-	query := fmt.Sprintf(queryGetIncompleteByUserID, userID, userID)
-	err := conn.DB.Select(&assignments, query)
+	err := conn.NewQuery(queryGetIncompleteByUserID, userID, pStatusStudent, StatusAssignmentActive, userID).Select(&assignments)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
@@ -35,8 +33,7 @@ func GetIncompleteByUserID(userID int64) ([]Assignment, error) {
 // GetCompleteByUserID func ...
 func GetCompleteByUserID(userID int64) ([]int64, error) {
 	var assignmentsID []int64
-	query := fmt.Sprintf(queryGetCompleteByUserID, userID)
-	err := conn.DB.Select(&assignmentsID, query)
+	err := conn.NewQuery(queryGetCompleteByUserID, userID).Select(&assignmentsID)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
@@ -46,16 +43,16 @@ func GetCompleteByUserID(userID int64) ([]int64, error) {
 // IsExistByGradeParameterID func ...
 func IsExistByGradeParameterID(gpID int64) bool {
 	var x string
-	query := fmt.Sprintf(`
+	query := `
 		SELECT
 			id
 		FROM
 			grade_parameters
 		WHERE
-			id = (%d)
+			id = (?)
 		LIMIT 1;
-	`, gpID)
-	err := conn.DB.Get(&x, query)
+	`
+	err := conn.NewQuery(query, gpID).Get(&x)
 	if err == sql.ErrNoRows {
 		return false
 	}
@@ -64,11 +61,7 @@ func IsExistByGradeParameterID(gpID int64) bool {
 
 // Insert function is ...
 func Insert(GradeParameters int64, Name, Status, DueDate string, Description sql.NullString, tx ...*sqlx.Tx) (string, error) {
-	queryDescription := fmt.Sprintf("NULL")
-	if Description.Valid {
-		queryDescription = fmt.Sprintf("('%s')", Description.String)
-	}
-	query := fmt.Sprintf(`
+	query := `
 		INSERT INTO
 			assignments(
 				name,
@@ -80,30 +73,20 @@ func Insert(GradeParameters int64, Name, Status, DueDate string, Description sql
 				updated_at
 			)
 		VALUES(
-			('%s'),
-			('%s'),
-			('%s'),
-			('%d'),
-			%s,
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
 			NOW(),
 			NOW()
 		);
-		`, Name, Status, DueDate, GradeParameters, queryDescription)
-
-	var result sql.Result
-	var err error
-	if len(tx) == 1 {
-		result, err = tx[0].Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
+		`
+
+	result, err := conn.NewQuery(query, Name, Status, DueDate, GradeParameters, Description).WithTx(tx...).ExecAffected()
 	if err != nil {
 		return "", err
 	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return "", fmt.Errorf("No rows affected")
-	}
 	id, err := result.LastInsertId()
 	if err != nil {
 		return "", fmt.Errorf("Error get LastIsertedId")
@@ -115,62 +98,59 @@ func Insert(GradeParameters int64, Name, Status, DueDate string, Description sql
 // Update func ...
 func Update(GradeParameters, id int64, Name, Status, DueDate string, Description sql.NullString, tx *sqlx.Tx) error {
 
-	var result sql.Result
-	var err error
-	queryDescription := fmt.Sprintf("NULL")
-	if Description.Valid {
-		queryDescription = fmt.Sprintf(Description.String)
-	}
-	query := fmt.Sprintf(`
+	query := `
 		UPDATE 
 			assignments
 		SET
-				name = ('%s'),
-				status = ('%s'),
-				due_date = ('%s'),
-				grade_parameters_id = (%d),
-				description = ('%s'),
+				name = (?),
+				status = (?),
+				due_date = (?),
+				grade_parameters_id = (?),
+				description = (?),
 				updated_at = NOW()
 		WHERE
-			id = (%d);
-		`, Name, Status, DueDate, GradeParameters, queryDescription, id)
-	if tx != nil {
-		result, err = tx.Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+			id = (?);
+		`
+	_, err := conn.NewQuery(query, Name, Status, DueDate, GradeParameters, Description, id).WithTx(tx).ExecAffected()
+	return err
 }
 
 // IsFileIDExist func ...
 func IsFileIDExist(ID string) bool {
 	var x string
-	query := fmt.Sprintf(`
+	query := `
 		SELECT
 			id
 		FROM
 			files
 		WHERE
-			id = ('%s')
+			id = (?)
 		LIMIT 1;
-			`, ID)
-	err := conn.DB.Get(&x, query)
+			`
+	err := conn.NewQuery(query, ID).Get(&x)
 	if err == sql.ErrNoRows {
 		return false
 	}
 	return true
 }
 
-// SelectByPage func ...
-func SelectByPage(limit, offset uint16) ([]FileAssignment, error) {
+// SelectByPage returns the assignments, the assignments are filtered by the schedule if the schedule ids are given
+func SelectByPage(limit, offset uint16, scheduleID ...int64) ([]FileAssignment, error) {
 	var assignment []FileAssignment
+	args := []interface{}{}
+	var filter string
+	if len(scheduleID) > 0 {
+		filter = `
+			INNER JOIN
+				grade_parameters gp
+			ON
+				asg.grade_parameters_id = gp.id
+			WHERE
+				gp.schedules_id IN (?)`
+		args = append(args, scheduleID)
+	}
+	args = append(args, limit, offset)
+
 	query := fmt.Sprintf(`
 			SELECT
 				asg.grade_parameters_id,
@@ -180,13 +160,14 @@ func SelectByPage(limit, offset uint16) ([]FileAssignment, error) {
 				asg.due_date
 			FROM
 				assignments asg
-			LIMIT %d OFFSET %d;`, limit, offset)
+			%s
+			LIMIT ? OFFSET ?;`, filter)
 
-	rows, err := conn.DB.Queryx(query)
-	defer rows.Close()
+	rows, err := conn.NewQuery(query, args...).Queryx()
 	if err != nil {
 		return assignment, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var name string
@@ -216,7 +197,7 @@ func SelectByPage(limit, offset uint16) ([]FileAssignment, error) {
 func GetByAssignementID(assignmentID int64) (DetailAssignment, error) {
 
 	var assignment DetailAssignment
-	query := fmt.Sprintf(`
+	query := `
 			SELECT
 				asg.id,
 				asg.status,
@@ -239,10 +220,8 @@ func GetByAssignementID(assignmentID int64) (DetailAssignment, error) {
 			ON
 				gp.id = asg.grade_parameters_id)
 			WHERE
-				asg.id = (%d)
-			LIMIT 1;`, assignmentID)
-
-	rows := conn.DB.QueryRowx(query)
+				asg.id = (?)
+			LIMIT 1;`
 
 	// scan data to variable
 	var name, Type string
@@ -253,7 +232,7 @@ func GetByAssignementID(assignmentID int64) (DetailAssignment, error) {
 	var dueDate time.Time
 	var percentage float32
 
-	err := rows.Scan(&id, &status, &name, &gradeParameterID, &description, &dueDate, &nameFile, &mime, &Type, &percentage)
+	err := conn.NewQuery(query, assignmentID).Scan(&id, &status, &name, &gradeParameterID, &description, &dueDate, &nameFile, &mime, &Type, &percentage)
 	if err != nil {
 		return assignment, err
 	}
@@ -282,15 +261,15 @@ func GetByAssignementID(assignmentID int64) (DetailAssignment, error) {
 func IsAssignmentExist(AssignmentID int64) bool {
 
 	var x string
-	query := fmt.Sprintf(`
+	query := `
 		SELECT
 			'x'
 		FROM
 			assignments
 		WHERE
-			id = (%d)
-		LIMIT 1;`, AssignmentID)
-	err := conn.DB.Get(&x, query)
+			id = (?)
+		LIMIT 1;`
+	err := conn.NewQuery(query, AssignmentID).Get(&x)
 	if err != nil {
 		return false
 	}
@@ -300,14 +279,7 @@ func IsAssignmentExist(AssignmentID int64) bool {
 // UploadAssignment func ...
 func UploadAssignment(assignmentID, userID int64, description sql.NullString, tx *sqlx.Tx) error {
 
-	var result sql.Result
-	var err error
-
-	queryDescription := fmt.Sprintf("NULL")
-	if description.Valid {
-		queryDescription = fmt.Sprintf(description.String)
-	}
-	query := fmt.Sprintf(`
+	query := `
 		INSERT INTO 
 			p_users_assignments (
 				assignments_id,
@@ -317,34 +289,24 @@ func UploadAssignment(assignmentID, userID int64, description sql.NullString, tx
 				updated_at
 			)
 		VALUES(
-				(%d),
-				(%d),
-				('%s'),
+				(?),
+				(?),
+				(?),
 				NOW(),
 				NOW()
 			)
-		;`, assignmentID, userID, queryDescription)
-	if tx != nil {
-		result, err = tx.Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+		;`
+	_, err := conn.NewQuery(query, assignmentID, userID, description).WithTx(tx).ExecAffected()
+	return err
 }
 
 // GetUploadedAssignmentByID func ...
 func GetUploadedAssignmentByID(AssignmentID, UserID int64) (DetailUploadedAssignment, error) {
-	query := fmt.Sprintf(`
+	query := `
 		SELECT
 			pus.assignments_id,
 			pus.score,
+			pus.comment,
 			pus.description,
 			asg.name,
 			asg.description,
@@ -356,15 +318,14 @@ func GetUploadedAssignmentByID(AssignmentID, UserID int64) (DetailUploadedAssign
 		ON
 			asg.id=pus.assignments_id
 		WHERE
-			pus.assignments_id = (%d) AND pus.users_id = (%d)`, AssignmentID, UserID)
+			pus.assignments_id = (?) AND pus.users_id = (?)`
 
-	rows := conn.DB.QueryRowx(query)
 	var assignment DetailUploadedAssignment
 	var assignmentID int64
 	var name, dueDate string
-	var descriptionAssignnment, score, descriptionUser sql.NullString
+	var descriptionAssignnment, score, comment, descriptionUser sql.NullString
 
-	err := rows.Scan(&assignmentID, &score, &descriptionUser, &name, &descriptionAssignnment, &dueDate)
+	err := conn.NewQuery(query, AssignmentID, UserID).Scan(&assignmentID, &score, &comment, &descriptionUser, &name, &descriptionAssignnment, &dueDate)
 	if err != nil {
 		return assignment, err
 	}
@@ -375,6 +336,7 @@ func GetUploadedAssignmentByID(AssignmentID, UserID int64) (DetailUploadedAssign
 		DescriptionAssignment: descriptionAssignnment,
 		DescriptionUser:       descriptionUser,
 		Score:                 score,
+		Comment:               comment,
 		DueDate:               dueDate,
 	}, nil
 }
@@ -382,16 +344,16 @@ func GetUploadedAssignmentByID(AssignmentID, UserID int64) (DetailUploadedAssign
 // IsUserHaveUploadedAsssignment func ...
 func IsUserHaveUploadedAsssignment(AssignmentID int64) bool {
 	var x string
-	query := fmt.Sprintf(`
+	query := `
 		SELECT 
 			'x'
 		FROM
 			p_users_assignments
 		WHERE
-			assignments_id = (%d)
+			assignments_id = (?)
 		LIMIT 1;
-		`, AssignmentID)
-	err := conn.DB.Get(&x, query)
+		`
+	err := conn.NewQuery(query, AssignmentID).Get(&x)
 	if err != nil {
 		return false
 	}
@@ -400,36 +362,25 @@ func IsUserHaveUploadedAsssignment(AssignmentID int64) bool {
 
 // DeleteAssignment func ...
 func DeleteAssignment(AssignmentID int64, tx *sqlx.Tx) error {
-	query := fmt.Sprintf(`
+	query := `
 		DELETE FROM
 			assignments
 		WHERE
-			id=(%d)
-		;`, AssignmentID)
-
-	var result sql.Result
-	var err error
-	if tx != nil {
-		result, err = tx.Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+			id=(?)
+		;`
+
+	_, err := conn.NewQuery(query, AssignmentID).WithTx(tx).ExecAffected()
+	return err
 }
 
 // GetAllUserAssignmentByAssignmentID func ...
 func GetAllUserAssignmentByAssignmentID(AssignmentID, limit, offset int64) ([]DetailUploadedAssignment, error) {
-	query := fmt.Sprintf(`
+	query := `
 		SELECT 
 			pus.assignments_id,
+			usr.identity_code,
 			pus.score,
+			pus.comment,
 			pus.description,
 			asg.name,
 			asg.description,
@@ -440,32 +391,38 @@ func GetAllUserAssignmentByAssignmentID(AssignmentID, limit, offset int64) ([]De
 			assignments asg
 		ON
 			asg.id=pus.assignments_id
+		INNER JOIN
+			users usr
+		ON
+			usr.id=pus.users_id
 		WHERE
-			pus.assignments_id = (%d)
-		LIMIT %d OFFSET %d;
-			`, AssignmentID, limit, offset)
+			pus.assignments_id = (?)
+		LIMIT ? OFFSET ?;
+			`
 
 	var assignment []DetailUploadedAssignment
-	rows, err := conn.DB.Query(query)
+	rows, err := conn.NewQuery(query, AssignmentID, limit, offset).Queryx()
 	if err != nil {
 		return assignment, err
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var assignmentID int64
+		var assignmentID, identityCode int64
 		var name, dueDate string
-		var descriptionAssignnment, score, descriptionUser sql.NullString
+		var descriptionAssignnment, score, comment, descriptionUser sql.NullString
 
-		err := rows.Scan(&assignmentID, &score, &descriptionUser, &name, &descriptionAssignnment, &dueDate)
+		err := rows.Scan(&assignmentID, &identityCode, &score, &comment, &descriptionUser, &name, &descriptionAssignnment, &dueDate)
 		if err != nil {
 			return assignment, err
 		}
 		assignment = append(assignment, DetailUploadedAssignment{
 			AssignmentID: assignmentID,
+			IdentityCode: identityCode,
 			Name:         name,
 			DescriptionAssignment: descriptionAssignnment,
 			DescriptionUser:       descriptionUser,
 			Score:                 score,
+			Comment:               comment,
 			DueDate:               dueDate,
 		})
 	}
@@ -473,3 +430,52 @@ func GetAllUserAssignmentByAssignmentID(AssignmentID, limit, offset int64) ([]De
 	return assignment, nil
 
 }
+
+// SelectSubmittedUserID returns the id of the users who have uploaded the assignment
+func SelectSubmittedUserID(assignmentID int64) ([]int64, error) {
+	var userIDs []int64
+	query := `
+		SELECT
+			users_id
+		FROM
+			p_users_assignments
+		WHERE
+			assignments_id = (?);
+		`
+	err := conn.NewQuery(query, assignmentID).Select(&userIDs)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// UpdateScore sets the score and the grader comment of the uploaded assignment
+/*
+	@params:
+		assignmentID	= int64
+		userID			= int64
+		score			= float32
+		comment			= sql.NullString
+		tx				= *sqlx.Tx
+	@example:
+		assignmentID	= 3
+		userID			= 4
+		score			= 85.5
+		comment			= {Valid: true, String: Good analysis}
+	@return
+*/
+func UpdateScore(assignmentID, userID int64, score float32, comment sql.NullString, tx *sqlx.Tx) error {
+	query := `
+		UPDATE
+			p_users_assignments
+		SET
+			score = (?),
+			comment = (?),
+			updated_at = NOW()
+		WHERE
+			assignments_id = (?) AND
+			users_id = (?);
+		`
+	_, err := conn.NewQuery(query, score, comment, assignmentID, userID).WithTx(tx).Exec()
+	return err
+}
@@ -12,6 +12,8 @@ const (
 	StatusAssignmentInactive = 0
 	// StatusAssignmentActive const
 	StatusAssignmentActive = 1
+	// pStatusStudent is the student status of the p_users_schedules table
+	pStatusStudent = 1
 )
 
 // Assignment struct ...
@@ -22,6 +24,7 @@ type Assignment struct {
 	Description      sql.NullString `db:"description"`
 	GradeParameterID int32          `db:"grade_parameters_id"`
 	DueDate          time.Time      `db:"due_date"`
+	ScheduleID       int64          `db:"schedules_id"`
 }
 
 // File struct ...
@@ -59,10 +62,12 @@ type DetailAssignment struct {
 type DetailUploadedAssignment struct {
 	ScheudleID            int64          `json:"schdule_id"`
 	AssignmentID          int64          `json:"assignment_id"`
+	IdentityCode          int64          `json:"identity_code"`
 	Name                  string         `json:"name"`
 	DescriptionUser       sql.NullString `json:"description_user"`
 	DescriptionAssignment sql.NullString `json:"description_assignment"`
 	Score                 sql.NullString `json:"score"`
+	Comment               sql.NullString `json:"comment"`
 	DueDate               string         `json:"due_date"`
 	PathFile              sql.NullString `json:"path"`
 }
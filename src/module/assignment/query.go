@@ -2,36 +2,37 @@ package assignment
 
 const queryGetIncompleteByUserID = `
 	SELECT
-		id,
-		name,
-		status,
-		upload_date,
-		due_date
+		asg.id,
+		asg.name,
+		asg.status,
+		asg.description,
+		asg.grade_parameters_id,
+		asg.due_date,
+		gp.schedules_id
 	FROM
-		assigments
+		assignments asg
+	INNER JOIN
+		grade_parameters gp
+	ON
+		gp.id = asg.grade_parameters_id
+	INNER JOIN
+		p_users_schedules pus
+	ON
+		pus.schedules_id = gp.schedules_id
 	WHERE
-		EXISTS (
-			SELECT 
-				id
-			FROM
-				grade_parameters
-			WHERE
-				EXISTS (
-					SELECT
-						courses_id
-					FROM
-						p_users_courses
-					WHERE
-						users_id = (%d)
-				)
-		) AND id NOT IN (
+		pus.users_id = (?) AND
+		pus.status = (?) AND
+		asg.status = (?) AND
+		asg.id NOT IN (
 			SELECT
 				assignments_id
 			FROM
 				p_users_assignments
 			WHERE
-				users_id = (%d)
-		);
+				users_id = (?)
+		)
+	ORDER BY
+		asg.due_date ASC;
 `
 
 const queryGetByCourseID = `
@@ -50,7 +51,7 @@ const queryGetByCourseID = `
 			FROM
 				grade_parameters
 			WHERE
-				courses_id = (%d)
+				courses_id = (?)
 		);
 `
 
@@ -60,5 +61,5 @@ const queryGetCompleteByUserID = `
 	FROM
 		p_users_assignments
 	WHERE
-		users_id = (%d);
+		users_id = (?);
 `
@@ -3,16 +3,134 @@ package attendance
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/melodiez14/meiko/src/util/conn"
 )
 
-func GetByUserCourseID(userID, courseID int64) ([]Attendance, error) {
+// InsertMeeting opens a new meeting for the schedule and returns the meeting id
+/*
+	@params:
+		scheduleID	= int64
+		number		= uint8
+		subject		= string
+		description	= sql.NullString
+		date		= time.Time
+		tx			= optional *sqlx.Tx
+	@example:
+		scheduleID	= 12
+		number		= 1
+		subject		= Pengenalan Sistem Informasi Multimedia
+		description	= {Valid: false}
+		date		= 2017-09-04
+	@return
+		id			= 1
+*/
+func InsertMeeting(scheduleID int64, number uint8, subject string, description sql.NullString, date time.Time, tx ...*sqlx.Tx) (int64, error) {
+
+	var id int64
+	result, err := conn.NewQuery(queryInsertMeeting, number, subject, description, date, scheduleID).WithTx(tx...).ExecAffected()
+	if err != nil {
+		return id, err
+	}
+
+	id, err = result.LastInsertId()
+	if err != nil {
+		return id, fmt.Errorf("Cannot get last insert id")
+	}
+
+	return id, nil
+}
+
+// IsExistMeeting checks whether the meeting number is already used by the schedule
+func IsExistMeeting(scheduleID int64, number uint8) bool {
+	var x string
+	err := conn.NewQuery(queryIsExistMeeting, scheduleID, number).Get(&x)
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+// GetMeetingByID returns the meeting detail
+func GetMeetingByID(meetingID int64) (Meeting, error) {
+	var meeting Meeting
+	err := conn.NewQuery(queryGetMeetingByID, meetingID).Get(&meeting)
+	if err != nil {
+		return meeting, err
+	}
+	return meeting, nil
+}
+
+// SelectMeetingByScheduleID returns all of the meetings in the schedule ordered by the meeting number
+func SelectMeetingByScheduleID(scheduleID int64) ([]Meeting, error) {
+	var meetings []Meeting
+	err := conn.NewQuery(querySelectMeetingByScheduleID, scheduleID).Select(&meetings)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return meetings, nil
+}
+
+// SelectByMeetingID returns the recorded attendances of a meeting
+func SelectByMeetingID(meetingID int64) ([]Attendance, error) {
 	var attendances []Attendance
-	query := fmt.Sprintf(queryGetByUserCourse, userID, courseID)
-	err := conn.DB.Select(&attendances, query)
+	err := conn.NewQuery(querySelectByMeetingID, meetingID).Select(&attendances)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
 	return attendances, nil
 }
+
+// SelectByUserScheduleID returns the recorded attendances of a user in the schedule
+func SelectByUserScheduleID(userID, scheduleID int64) ([]Attendance, error) {
+	var attendances []Attendance
+	err := conn.NewQuery(querySelectByUserScheduleID, userID, scheduleID).Select(&attendances)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return attendances, nil
+}
+
+// Upsert records the attendance status of the user. The old status is replaced if it's already recorded
+/*
+	@params:
+		meetingID	= int64
+		userID		= int64
+		status		= int8
+		tx			= optional *sqlx.Tx
+	@example:
+		meetingID	= 1
+		userID		= 12
+		status		= 1
+	@return
+*/
+func Upsert(meetingID, userID int64, status int8, tx ...*sqlx.Tx) error {
+	_, err := conn.NewQuery(queryUpsert, meetingID, userID, status).WithTx(tx...).Exec()
+	return err
+}
+
+// SelectSummaryByUserID returns total meeting and total present of the user for each schedule
+/*
+	@params:
+		userID		= int64
+		scheduleID	= []int64
+	@example:
+		userID		= 12
+		scheduleID	= [1, 2]
+	@return
+		[]{schedule_id, meeting_total, attendance_total}
+*/
+func SelectSummaryByUserID(userID int64, scheduleID []int64) ([]Summary, error) {
+	var summaries []Summary
+	if len(scheduleID) < 1 {
+		return summaries, nil
+	}
+
+	err := conn.NewQuery(querySelectSummaryByUserID, userID, StatusPresent, scheduleID).Select(&summaries)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return summaries, nil
+}
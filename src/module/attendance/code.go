@@ -0,0 +1,104 @@
+package attendance
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/melodiez14/meiko/src/util/conn"
+)
+
+const (
+	codePrefix    = "attendance:code:"
+	codeCharacter = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	codeLength    = 6
+	codeRetry     = 5
+	// CodeExpire is the lifetime of the check-in code in seconds
+	CodeExpire = 60
+)
+
+// GenerateCode creates a new check-in code of the meeting and stores it in Redis for CodeExpire seconds.
+// The previous codes are kept until they are expired, so the students who are scanning the rotated code are still accepted
+/*
+	@params:
+		meetingID	= int64
+	@example:
+		meetingID	= 1
+	@return
+		code		= K7PX2M
+*/
+func GenerateCode(meetingID int64) (string, error) {
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	for i := 0; i < codeRetry; i++ {
+		code, err := randomCode()
+		if err != nil {
+			return "", err
+		}
+
+		// NX prevents the active code of the other meeting being replaced
+		_, err = redis.String(client.Do("SET", codePrefix+code, meetingID, "EX", CodeExpire, "NX"))
+		if err == redis.ErrNil {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		return code, nil
+	}
+
+	return "", fmt.Errorf("Failed to generate unique code")
+}
+
+// GetMeetingIDByCode returns the meeting id of the active check-in code
+/*
+	@params:
+		code		= string
+	@example:
+		code		= K7PX2M
+	@return
+		meetingID	= 1
+*/
+func GetMeetingIDByCode(code string) (int64, error) {
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	meetingID, err := redis.Int64(client.Do("GET", codePrefix+code))
+	if err != nil {
+		return 0, err
+	}
+
+	return meetingID, nil
+}
+
+// IsValidCode checks whether the code only contains the characters used by GenerateCode
+func IsValidCode(code string) bool {
+	if len(code) != codeLength {
+		return false
+	}
+	for _, c := range code {
+		if !strings.ContainsRune(codeCharacter, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func randomCode() (string, error) {
+	max := big.NewInt(int64(len(codeCharacter)))
+	code := make([]byte, codeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = codeCharacter[n.Int64()]
+	}
+	return string(code), nil
+}
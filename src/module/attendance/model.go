@@ -1,12 +1,34 @@
 package attendance
 
 import (
+	"database/sql"
 	"time"
 )
 
+const (
+	StatusAbsent    = 0
+	StatusPresent   = 1
+	StatusSick      = 2
+	StatusPermitted = 3
+)
+
+type Meeting struct {
+	ID          int64          `db:"id"`
+	Number      uint8          `db:"number"`
+	Subject     string         `db:"subject"`
+	Description sql.NullString `db:"description"`
+	Date        time.Time      `db:"date"`
+	ScheduleID  int64          `db:"schedules_id"`
+}
+
 type Attendance struct {
-	ID            int64     `db:"id"`
-	MeetingNumber int8      `db:"meeting_number"`
-	Status        int8      `db:"status"`
-	Date          time.Time `db:"meeting_date"`
+	MeetingID int64 `db:"meetings_id"`
+	UserID    int64 `db:"users_id"`
+	Status    int8  `db:"status"`
+}
+
+type Summary struct {
+	ScheduleID      int64 `db:"schedules_id"`
+	MeetingTotal    int64 `db:"meeting_total"`
+	AttendanceTotal int64 `db:"attendance_total"`
 }
@@ -1,14 +1,134 @@
 package attendance
 
-const queryGetByUserCourse = `
-	SELECT
-		id,
-		meeting_number,
-		status,
-		meeting_date
-	FROM
-		attendances
-	WHERE
-		p_users_courses_users_id = (%d) AND
-		p_users_courses_courses_id = (%d);
-`
+const (
+	queryInsertMeeting = `
+		INSERT INTO
+			meetings (
+				number,
+				subject,
+				description,
+				date,
+				schedules_id,
+				created_at,
+				updated_at
+			)
+		VALUES (
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
+			NOW(),
+			NOW()
+		);
+	`
+
+	queryIsExistMeeting = `
+		SELECT
+			'x'
+		FROM
+			meetings
+		WHERE
+			schedules_id = (?) AND
+			number = (?)
+		LIMIT 1;
+	`
+
+	queryGetMeetingByID = `
+		SELECT
+			id,
+			number,
+			subject,
+			description,
+			date,
+			schedules_id
+		FROM
+			meetings
+		WHERE
+			id = (?)
+		LIMIT 1;
+	`
+
+	querySelectMeetingByScheduleID = `
+		SELECT
+			id,
+			number,
+			subject,
+			description,
+			date,
+			schedules_id
+		FROM
+			meetings
+		WHERE
+			schedules_id = (?)
+		ORDER BY
+			number ASC;
+	`
+
+	querySelectByMeetingID = `
+		SELECT
+			meetings_id,
+			users_id,
+			status
+		FROM
+			attendances
+		WHERE
+			meetings_id = (?);
+	`
+
+	querySelectByUserScheduleID = `
+		SELECT
+			a.meetings_id,
+			a.users_id,
+			a.status
+		FROM
+			attendances a
+		INNER JOIN
+			meetings m
+		ON
+			m.id = a.meetings_id
+		WHERE
+			a.users_id = (?) AND
+			m.schedules_id = (?);
+	`
+
+	queryUpsert = `
+		INSERT INTO
+			attendances (
+				meetings_id,
+				users_id,
+				status,
+				created_at,
+				updated_at
+			)
+		VALUES (
+			(?),
+			(?),
+			(?),
+			NOW(),
+			NOW()
+		)
+		ON DUPLICATE KEY UPDATE
+			status = VALUES(status),
+			updated_at = NOW();
+	`
+
+	querySelectSummaryByUserID = `
+		SELECT
+			m.schedules_id,
+			COUNT(m.id) AS meeting_total,
+			COUNT(a.meetings_id) AS attendance_total
+		FROM
+			meetings m
+		LEFT JOIN
+			attendances a
+		ON
+			a.meetings_id = m.id AND
+			a.users_id = (?) AND
+			a.status = (?)
+		WHERE
+			m.schedules_id IN (?)
+		GROUP BY
+			m.schedules_id;
+	`
+)
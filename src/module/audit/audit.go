@@ -0,0 +1,153 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/melodiez14/meiko/src/util/conn"
+)
+
+// NewDiff keeps only the fields whose value is changed by the action. The before is nil for the created
+// resource and the after is nil for the deleted resource
+/*
+	@params:
+		before	= map[string]interface{}
+		after	= map[string]interface{}
+	@example:
+		before	= {"name": "Risal", "status": 1}
+		after	= {"name": "Risal", "status": 2}
+	@return
+		{before: {"status": 1}, after: {"status": 2}}
+*/
+func NewDiff(before, after map[string]interface{}) Diff {
+	diff := Diff{
+		Before: map[string]interface{}{},
+		After:  map[string]interface{}{},
+	}
+	for key, val := range before {
+		if newVal, ok := after[key]; !ok || !reflect.DeepEqual(val, newVal) {
+			diff.Before[key] = val
+		}
+	}
+	for key, val := range after {
+		if oldVal, ok := before[key]; !ok || !reflect.DeepEqual(val, oldVal) {
+			diff.After[key] = val
+		}
+	}
+	return diff
+}
+
+// Insert records the administrative action done by the user. The action should be recorded in the same
+// transaction as the change so the change is never left untraced
+/*
+	@params:
+		userID		= int64
+		action		= string
+		table		= string
+		targetID	= string
+		diff		= Diff
+		ip			= string
+		tx			= optional *sqlx.Tx
+	@example:
+		userID		= 12
+		action		= delete
+		table		= users
+		targetID	= 27
+		diff		= {before: {"name": "Risal"}, after: {}}
+		ip			= 10.0.0.1
+		tx			= nil
+	@return
+*/
+func Insert(userID int64, action, table, targetID string, diff Diff, ip string, tx ...*sqlx.Tx) error {
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+	_, err = conn.NewQuery(queryInsert, userID, action, table, targetID, string(data), ip).WithTx(tx...).Exec()
+	return err
+}
+
+// SelectByPage returns the audit logs which match the filter ordered from the newest log
+/*
+	@params:
+		filter	= Filter
+		limit	= uint16
+		offset	= uint16
+	@example:
+		filter	= {Action: delete, TargetTable: users}
+		limit	= 10
+		offset	= 0
+	@return
+		[]{id, users_id, name, identity_code, action, target_table, target_id, diff, ip, created_at}
+*/
+func SelectByPage(filter Filter, limit, offset uint16) ([]Log, error) {
+	logs := []Log{}
+	where, args := filter.where()
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(querySelectByPage, where)
+	err := conn.NewQuery(query, args...).Select(&logs)
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// Count returns the number of the audit logs which match the filter
+/*
+	@params:
+		filter	= Filter
+	@example:
+		filter	= {Action: delete, TargetTable: users}
+	@return
+		count	= 35
+*/
+func Count(filter Filter) (int, error) {
+	var count int
+	where, args := filter.where()
+
+	query := fmt.Sprintf(queryCount, where)
+	err := conn.NewQuery(query, args...).Get(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// where builds the where clause of the filter, the to date is exclusive
+func (f Filter) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	if f.IdentityCode > 0 {
+		conds = append(conds, "u.identity_code = (?)")
+		args = append(args, f.IdentityCode)
+	}
+	if f.Action != "" {
+		conds = append(conds, "al.action = (?)")
+		args = append(args, f.Action)
+	}
+	if f.TargetTable != "" {
+		conds = append(conds, "al.target_table = (?)")
+		args = append(args, f.TargetTable)
+	}
+	if f.TargetID != "" {
+		conds = append(conds, "al.target_id = (?)")
+		args = append(args, f.TargetID)
+	}
+	if !f.From.IsZero() {
+		conds = append(conds, "al.created_at >= (?)")
+		args = append(args, f.From)
+	}
+	if !f.To.IsZero() {
+		conds = append(conds, "al.created_at < (?)")
+		args = append(args, f.To)
+	}
+
+	if len(conds) < 1 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
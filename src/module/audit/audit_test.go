@@ -0,0 +1,139 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/melodiez14/meiko/src/util/conn"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestNewDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before map[string]interface{}
+		after  map[string]interface{}
+		want   Diff
+	}{
+		{
+			name:   "Test Case 1",
+			before: map[string]interface{}{"name": "Risal", "status": 1},
+			after:  map[string]interface{}{"name": "Risal", "status": 2},
+			want: Diff{
+				Before: map[string]interface{}{"status": 1},
+				After:  map[string]interface{}{"status": 2},
+			},
+		},
+		{
+			name:   "Test Case 2",
+			before: map[string]interface{}{"name": "Risal"},
+			after:  nil,
+			want: Diff{
+				Before: map[string]interface{}{"name": "Risal"},
+				After:  map[string]interface{}{},
+			},
+		},
+		{
+			name:   "Test Case 3",
+			before: map[string]interface{}{"UTS": 30},
+			after:  map[string]interface{}{"UTS": 30, "UAS": 40},
+			want: Diff{
+				Before: map[string]interface{}{},
+				After:  map[string]interface{}{"UAS": 40},
+			},
+		},
+		{
+			name:   "Test Case 4",
+			before: map[string]interface{}{"name": "Risal"},
+			after:  map[string]interface{}{"name": "Risal"},
+			want: Diff{
+				Before: map[string]interface{}{},
+				After:  map[string]interface{}{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewDiff(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsert(t *testing.T) {
+	db, _ := conn.InitDBMock()
+	db.ExpectExec(`^\s*INSERT(\s*)INTO(\s*)audit_logs`).
+		WithArgs(12, ActionDelete, TableUsers, "27", `{"before":{"name":"Risal"},"after":{}}`, "10.0.0.1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	diff := NewDiff(map[string]interface{}{"name": "Risal"}, nil)
+	if err := Insert(12, ActionDelete, TableUsers, "27", diff, "10.0.0.1"); err != nil {
+		t.Errorf("Insert() error = %v", err)
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFilter_where(t *testing.T) {
+	from := time.Date(2017, 10, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		filter    Filter
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "Test Case 1",
+			filter:    Filter{},
+			wantWhere: "",
+			wantArgs:  nil,
+		},
+		{
+			name:      "Test Case 2",
+			filter:    Filter{Action: ActionDelete, TargetTable: TableUsers, TargetID: "27"},
+			wantWhere: "WHERE al.action = (?) AND al.target_table = (?) AND al.target_id = (?)",
+			wantArgs:  []interface{}{ActionDelete, TableUsers, "27"},
+		},
+		{
+			name:      "Test Case 3",
+			filter:    Filter{IdentityCode: 140810140016, From: from},
+			wantWhere: "WHERE u.identity_code = (?) AND al.created_at >= (?)",
+			wantArgs:  []interface{}{int64(140810140016), from},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := tt.filter.where()
+			if where != tt.wantWhere {
+				t.Errorf("Filter.where() = %v, want %v", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("Filter.where() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSelectByPage(t *testing.T) {
+	now := time.Now()
+	db, _ := conn.InitDBMock()
+	db.ExpectQuery(`^\s*SELECT(.+)FROM(\s*)audit_logs(.+)WHERE(\s*)al.action(.+)LIMIT`).
+		WithArgs(ActionDelete, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "users_id", "name", "identity_code", "action", "target_table", "target_id", "diff", "ip", "created_at"}).
+			AddRow(3, 12, "Risal Falah", 140810140016, ActionDelete, TableUsers, "27", `{"before":{},"after":{}}`, "10.0.0.1", now).
+			AddRow(2, nil, nil, nil, ActionDelete, TableSchedules, "4", `{"before":{},"after":{}}`, "10.0.0.2", now))
+
+	got, err := SelectByPage(Filter{Action: ActionDelete}, 10, 0)
+	if err != nil {
+		t.Fatalf("SelectByPage() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Name.String != "Risal Falah" || got[1].UserID.Valid {
+		t.Errorf("SelectByPage() = %v", got)
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
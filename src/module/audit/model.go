@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"database/sql"
+	"time"
+)
+
+// the administrative actions
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// the tables which are changed by the administrative actions
+const (
+	TableUsers            = "users"
+	TableSchedules        = "schedules"
+	TableAssignments      = "assignments"
+	TableGradeParameters  = "grade_parameters"
+	TableRoleGroups       = "rolegroups"
+	TableRoleGroupModules = "rolegroups_modules"
+	TableUsersSchedules   = "p_users_schedules"
+	TableCapacities       = "schedules_capacities"
+)
+
+// Log is the trace of an administrative action. The actor is null if the account of the actor has been deleted
+type Log struct {
+	ID           int64          `db:"id"`
+	UserID       sql.NullInt64  `db:"users_id"`
+	Name         sql.NullString `db:"name"`
+	IdentityCode sql.NullInt64  `db:"identity_code"`
+	Action       string         `db:"action"`
+	TargetTable  string         `db:"target_table"`
+	TargetID     string         `db:"target_id"`
+	Diff         string         `db:"diff"`
+	IP           string         `db:"ip"`
+	CreatedAt    time.Time      `db:"created_at"`
+}
+
+// Diff holds the fields which are changed by the action, the created resource has no before fields
+// and the deleted resource has no after fields
+type Diff struct {
+	Before map[string]interface{} `json:"before"`
+	After  map[string]interface{} `json:"after"`
+}
+
+// Filter narrows down the audit logs, the zero value field isn't used
+type Filter struct {
+	IdentityCode int64
+	Action       string
+	TargetTable  string
+	TargetID     string
+	From         time.Time
+	To           time.Time
+}
@@ -0,0 +1,63 @@
+package audit
+
+const (
+	querySelectByPage = `
+		SELECT
+			al.id,
+			al.users_id,
+			u.name,
+			u.identity_code,
+			al.action,
+			al.target_table,
+			al.target_id,
+			al.diff,
+			al.ip,
+			al.created_at
+		FROM
+			audit_logs al
+		LEFT JOIN
+			users u
+		ON
+			u.id = al.users_id
+		%s
+		ORDER BY
+			al.created_at DESC,
+			al.id DESC
+		LIMIT ?
+		OFFSET ?;
+	`
+
+	queryCount = `
+		SELECT
+			COUNT(*)
+		FROM
+			audit_logs al
+		LEFT JOIN
+			users u
+		ON
+			u.id = al.users_id
+		%s;
+	`
+
+	queryInsert = `
+		INSERT INTO
+			audit_logs(
+				users_id,
+				action,
+				target_table,
+				target_id,
+				diff,
+				ip,
+				created_at
+			)
+		VALUES (
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
+			NOW()
+		);
+	`
+)
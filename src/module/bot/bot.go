@@ -26,14 +26,14 @@ func LoadByTime(t time.Time, isAfter bool, userID int64) ([]Log, error) {
 		FROM
 			bot_logs
 		WHERE
-			created_at %s ('%s') AND
-			users_id = (%d)
+			created_at %s (?) AND
+			users_id = (?)
 		ORDER BY
 			id %s
 		LIMIT 20;
-	`, opr, t, userID, order)
+	`, opr, order)
 
-	err := conn.DB.Select(&log, query)
+	err := conn.NewQuery(query, t, userID).Select(&log)
 	if err != nil {
 		return log, err
 	}
@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/melodiez14/meiko/src/util/conn"
+)
+
+const contextPrefix = "bot:context:"
+
+// GetContext returns the dialogue state of the user. The empty context is returned if the previous
+// context is already expired
+/*
+	@params:
+		userID	= int64
+	@example:
+		userID	= 1
+	@return
+		Context	= {intent: assistant, courses: [data warehouse]}
+*/
+func GetContext(userID int64) (Context, error) {
+
+	var ctx Context
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	val, err := redis.Bytes(client.Do("GET", fmt.Sprintf("%s%d", contextPrefix, userID)))
+	if err == redis.ErrNil {
+		return ctx, nil
+	}
+	if err != nil {
+		return ctx, err
+	}
+
+	err = json.Unmarshal(val, &ctx)
+	if err != nil {
+		return Context{}, err
+	}
+
+	return ctx, nil
+}
+
+// SaveContext replaces the dialogue state of the user, the context is removed after expire seconds of inactivity
+/*
+	@params:
+		userID	= int64
+		ctx		= Context
+		expire	= int64
+	@example:
+		userID	= 1
+		ctx		= {intent: assistant, courses: [data warehouse]}
+		expire	= 300
+	@return
+*/
+func SaveContext(userID int64, ctx Context, expire int64) error {
+
+	val, err := json.Marshal(ctx)
+	if err != nil {
+		return err
+	}
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	_, err = client.Do("SET", fmt.Sprintf("%s%d", contextPrefix, userID), val, "EX", expire)
+	return err
+}
@@ -16,3 +16,13 @@ type Log struct {
 	Status    uint8     `db:"status"`
 	CreatedAt time.Time `db:"created_at"`
 }
+
+// Context is the dialogue state of the user. It's carried to the next message, so the intent
+// and the entities which aren't mentioned are taken from the previous message
+type Context struct {
+	Intent     string      `json:"intent"`
+	Courses    []string    `json:"courses,omitempty"`
+	Days       []int8      `json:"days,omitempty"`
+	Dates      []time.Time `json:"dates,omitempty"`
+	Candidates []string    `json:"candidates,omitempty"`
+}
@@ -0,0 +1,248 @@
+package calendar
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/melodiez14/meiko/src/util/conn"
+)
+
+var (
+	location     = time.Local
+	oddSemester  = Term{Start: "08-01", End: "12-31", YearOffset: 0}
+	evenSemester = Term{Start: "02-01", End: "06-30", YearOffset: 1}
+)
+
+// Init sets the timezone and the semester dates, the default is used for the invalid config
+func Init(cfg Config) {
+	timezone := cfg.Timezone
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Printf("Invalid calendar timezone %s, the local timezone is used", timezone)
+		loc = time.Local
+	}
+	location = loc
+
+	if cfg.OddSemester.isValid() {
+		oddSemester = cfg.OddSemester
+	}
+	if cfg.EvenSemester.isValid() {
+		evenSemester = cfg.EvenSemester
+	}
+}
+
+// NewToken generates the token of the personal feed. The plain token is given to the user once and only
+// the hash is stored
+/*
+	@params:
+	@example:
+	@return
+		token	= Xa3sd9QmZ2...
+		hash	= 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae
+*/
+func NewToken() (token, hash string, err error) {
+	token, err = randomToken(tokenLength)
+	if err != nil {
+		return "", "", err
+	}
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the sha256 hex of the feed token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetUserIDByToken returns the owner of the personal feed by the hash of the token
+/*
+	@params:
+		hash	= string
+	@example:
+		hash	= 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae
+	@return
+		userID	= 12
+*/
+func GetUserIDByToken(hash string) (int64, error) {
+	var userID int64
+	err := conn.NewQuery(queryGetUserIDByToken, hash).Get(&userID)
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// UpsertToken sets the token of the personal feed, the previous token of the user can't be used anymore
+func UpsertToken(userID int64, hash string) error {
+	_, err := conn.NewQuery(queryUpsertToken, userID, hash).Exec()
+	return err
+}
+
+// DeleteToken disables the personal feed, it returns conn.ErrNoRowsAffected if the feed isn't enabled
+func DeleteToken(userID int64) error {
+	_, err := conn.NewQuery(queryDeleteToken, userID).ExecAffected()
+	return err
+}
+
+// GetScheduleIDByPublicToken returns the schedule of the public feed
+/*
+	@params:
+		token	= string
+	@example:
+		token	= Qm3a9sd0ZxK2...
+	@return
+		scheduleID	= 149
+*/
+func GetScheduleIDByPublicToken(token string) (int64, error) {
+	var scheduleID int64
+	err := conn.NewQuery(queryGetScheduleIDByPublicToken, token).Get(&scheduleID)
+	if err != nil {
+		return 0, err
+	}
+	return scheduleID, nil
+}
+
+// GetPublicToken returns the token of the public feed of the schedule, it returns sql.ErrNoRows if the public feed
+// isn't enabled
+func GetPublicToken(scheduleID int64) (string, error) {
+	var token string
+	err := conn.NewQuery(queryGetPublicToken, scheduleID).Get(&token)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// InsertPublicToken enables the public feed of the schedule and returns its token. The public token is stored as is
+// since the feed is meant to be shared
+func InsertPublicToken(scheduleID int64, tx ...*sqlx.Tx) (string, error) {
+	token, err := randomToken(publicTokenLength)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = conn.NewQuery(queryInsertPublicToken, scheduleID, token).WithTx(tx...).Exec()
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// DeletePublicToken disables the public feed of the schedule, it returns conn.ErrNoRowsAffected if the public feed
+// isn't enabled
+func DeletePublicToken(scheduleID int64) error {
+	_, err := conn.NewQuery(queryDeletePublicToken, scheduleID).ExecAffected()
+	return err
+}
+
+// SelectUserSchedule returns the active schedules created, assisted or taken by the user
+/*
+	@params:
+		userID	= int64
+	@example:
+		userID	= 12
+	@return
+		[]{id, name, class, day, start_time, end_time, places_id, semester, year}
+*/
+func SelectUserSchedule(userID int64) ([]Schedule, error) {
+	schedules := []Schedule{}
+	err := conn.NewQuery(querySelectUserSchedule,
+		statusScheduleActive,
+		userID,
+		userID,
+		[]int8{pStatusStudent, pStatusAssistant}).Select(&schedules)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// GetSchedule returns the schedule of the public feed
+func GetSchedule(scheduleID int64) (Schedule, error) {
+	var schedule Schedule
+	err := conn.NewQuery(queryGetSchedule, scheduleID).Get(&schedule)
+	if err != nil {
+		return Schedule{}, err
+	}
+	return schedule, nil
+}
+
+// SelectMeeting returns the meetings of the schedules ordered by the date
+func SelectMeeting(scheduleIDs []int64) ([]Meeting, error) {
+	meetings := []Meeting{}
+	if len(scheduleIDs) < 1 {
+		return meetings, nil
+	}
+	err := conn.NewQuery(querySelectMeeting, scheduleIDs).Select(&meetings)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return meetings, nil
+}
+
+// SelectAssignment returns the active assignments of the schedules ordered by the due date
+func SelectAssignment(scheduleIDs []int64) ([]Assignment, error) {
+	assignments := []Assignment{}
+	if len(scheduleIDs) < 1 {
+		return assignments, nil
+	}
+	err := conn.NewQuery(querySelectAssignment, scheduleIDs, statusAssignmentActive).Select(&assignments)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// SemesterRange returns the first and the last date of the semester of the schedule
+/*
+	@params:
+		year		= int16
+		semester	= int8
+	@example:
+		year		= 2017
+		semester	= 2
+	@return
+		start	= 2018-02-01 00:00:00
+		end		= 2018-06-30 00:00:00
+*/
+func SemesterRange(year int16, semester int8) (time.Time, time.Time) {
+	term := oddSemester
+	if semester%2 == 0 {
+		term = evenSemester
+	}
+	start, _ := time.Parse("01-02", term.Start)
+	end, _ := time.Parse("01-02", term.End)
+
+	startYear := int(year) + term.YearOffset
+	endYear := startYear
+	// the semester which ends before it starts continues to the next year
+	if end.Before(start) {
+		endYear++
+	}
+	return time.Date(startYear, start.Month(), start.Day(), 0, 0, 0, 0, location),
+		time.Date(endYear, end.Month(), end.Day(), 0, 0, 0, 0, location)
+}
+
+func (t Term) isValid() bool {
+	_, errStart := time.Parse("01-02", t.Start)
+	_, errEnd := time.Parse("01-02", t.End)
+	return errStart == nil && errEnd == nil
+}
+
+func randomToken(length int) (string, error) {
+	b := make([]byte, length)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
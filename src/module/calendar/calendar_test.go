@@ -0,0 +1,27 @@
+package calendar
+
+import (
+	"testing"
+
+	"github.com/melodiez14/meiko/src/util/conn"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestSelectUserSchedule(t *testing.T) {
+	db, _ := conn.InitDBMock()
+	db.ExpectQuery(`^\s*SELECT(.+)FROM\s*schedules sc(.+)WHERE\s*sc.status\s*=\s*\(\?\)\s*AND(.+)sc.created_by\s*=\s*\(\?\)\s*OR`).
+		WithArgs(statusScheduleActive, 12, 12, pStatusStudent, pStatusAssistant).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "class", "day", "start_time", "end_time", "places_id", "semester", "year"}).
+			AddRow(149, "Data Warehouse", "A", 1, 420, 520, "UDJT-102", 1, 2017))
+
+	got, err := SelectUserSchedule(12)
+	if err != nil {
+		t.Fatalf("SelectUserSchedule() error = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != 149 || got[0].PlaceID != "UDJT-102" {
+		t.Errorf("SelectUserSchedule() = %v", got)
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
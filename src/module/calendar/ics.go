@@ -0,0 +1,177 @@
+package calendar
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	formatDateTime    = "20060102T150405"
+	formatDateTimeUTC = "20060102T150405Z"
+)
+
+// Render returns the iCalendar (RFC 5545) feed. Every schedule is a weekly event bounded by its semester, the
+// meeting is an event at the time of its schedule and it replaces the weekly event of that date. The assignment
+// is an event at its due date
+/*
+	@params:
+		now	= time.Time, the time the feed is generated
+	@example:
+		now	= 2017-10-14 17:46:17
+	@return
+		BEGIN:VCALENDAR ... END:VCALENDAR
+*/
+func (c Calendar) Render(now time.Time) []byte {
+
+	w := &writer{}
+	stamp := now.UTC().Format(formatDateTimeUTC)
+	tzid := location.String()
+
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//Meiko//Schedule//EN")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.line("X-WR-CALNAME:" + escape(c.Name))
+	w.line("X-WR-TIMEZONE:" + tzid)
+	w.timezone(now)
+
+	schedules := map[int64]Schedule{}
+	meetingDates := map[int64][]time.Time{}
+	for _, val := range c.Schedules {
+		schedules[val.ID] = val
+	}
+	for _, val := range c.Meetings {
+		if _, ok := schedules[val.ScheduleID]; ok {
+			meetingDates[val.ScheduleID] = append(meetingDates[val.ScheduleID], val.Date)
+		}
+	}
+
+	for _, val := range c.Schedules {
+		start, end := SemesterRange(val.Year, val.Semester)
+		first := start
+		for int8(first.Weekday()) != val.Day {
+			first = first.AddDate(0, 0, 1)
+		}
+		if first.After(end) {
+			continue
+		}
+
+		// the rule ends at the end of the last date, the UNTIL must be in UTC since the DTSTART has the timezone
+		until := end.AddDate(0, 0, 1).Add(-time.Second)
+		w.line("BEGIN:VEVENT")
+		w.line(fmt.Sprintf("UID:schedule-%d@meiko", val.ID))
+		w.line("DTSTAMP:" + stamp)
+		w.line(fmt.Sprintf("DTSTART;TZID=%s:%s", tzid, atMinute(first, val.StartTime).Format(formatDateTime)))
+		w.line(fmt.Sprintf("DTEND;TZID=%s:%s", tzid, atMinute(first, val.EndTime).Format(formatDateTime)))
+		w.line("RRULE:FREQ=WEEKLY;UNTIL=" + until.UTC().Format(formatDateTimeUTC))
+		for _, date := range meetingDates[val.ID] {
+			date = atMinute(date, val.StartTime)
+			if int8(date.Weekday()) != val.Day || date.Before(first) || date.After(until) {
+				continue
+			}
+			w.line(fmt.Sprintf("EXDATE;TZID=%s:%s", tzid, date.Format(formatDateTime)))
+		}
+		w.line("SUMMARY:" + escape(scheduleTitle(val)))
+		w.line("LOCATION:" + escape(val.PlaceID))
+		w.line("END:VEVENT")
+	}
+
+	for _, val := range c.Meetings {
+		schedule, ok := schedules[val.ScheduleID]
+		if !ok {
+			continue
+		}
+		w.line("BEGIN:VEVENT")
+		w.line(fmt.Sprintf("UID:meeting-%d@meiko", val.ID))
+		w.line("DTSTAMP:" + stamp)
+		w.line(fmt.Sprintf("DTSTART;TZID=%s:%s", tzid, atMinute(val.Date, schedule.StartTime).Format(formatDateTime)))
+		w.line(fmt.Sprintf("DTEND;TZID=%s:%s", tzid, atMinute(val.Date, schedule.EndTime).Format(formatDateTime)))
+		w.line("SUMMARY:" + escape(fmt.Sprintf("%s - Meeting %d: %s", scheduleTitle(schedule), val.Number, val.Subject)))
+		w.line("LOCATION:" + escape(schedule.PlaceID))
+		w.line("END:VEVENT")
+	}
+
+	for _, val := range c.Assignments {
+		schedule, ok := schedules[val.ScheduleID]
+		if !ok {
+			continue
+		}
+		w.line("BEGIN:VEVENT")
+		w.line(fmt.Sprintf("UID:assignment-%d@meiko", val.ID))
+		w.line("DTSTAMP:" + stamp)
+		w.line("DTSTART:" + val.DueDate.UTC().Format(formatDateTimeUTC))
+		w.line("SUMMARY:" + escape(fmt.Sprintf("Due: %s - %s", val.Name, scheduleTitle(schedule))))
+		w.line("END:VEVENT")
+	}
+
+	w.line("END:VCALENDAR")
+	return w.Bytes()
+}
+
+// writer writes the content lines ended by CRLF
+type writer struct {
+	bytes.Buffer
+}
+
+// line writes the content line, the line longer than lineLength octets is folded without splitting the character
+func (w *writer) line(s string) {
+	n := lineLength
+	for len(s) > n {
+		i := n
+		for i > 0 && !utf8.RuneStart(s[i]) {
+			i--
+		}
+		w.WriteString(s[:i])
+		w.WriteString("\r\n ")
+		s = s[i:]
+		// the continuation line starts with a space, so it has one octet less
+		n = lineLength - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+// timezone writes the timezone definition using the offset of the timezone at the time the feed is generated
+func (w *writer) timezone(now time.Time) {
+	name, offset := now.In(location).Zone()
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	tzoffset := fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + location.String())
+	w.line("BEGIN:STANDARD")
+	w.line("DTSTART:19700101T000000")
+	w.line("TZOFFSETFROM:" + tzoffset)
+	w.line("TZOFFSETTO:" + tzoffset)
+	w.line("TZNAME:" + name)
+	w.line("END:STANDARD")
+	w.line("END:VTIMEZONE")
+}
+
+// atMinute returns the minute of the date in the calendar timezone
+func atMinute(date time.Time, minute uint16) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), int(minute/60), int(minute%60), 0, 0, location)
+}
+
+func scheduleTitle(schedule Schedule) string {
+	return fmt.Sprintf("%s (%s)", schedule.CourseName, schedule.Class)
+}
+
+// escape escapes the text value of the property
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
@@ -0,0 +1,109 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSemesterRange(t *testing.T) {
+	Init(Config{Timezone: "UTC"})
+	tests := []struct {
+		name      string
+		year      int16
+		semester  int8
+		wantStart string
+		wantEnd   string
+	}{
+		{name: "Test Case 1", year: 2017, semester: 1, wantStart: "2017-08-01", wantEnd: "2017-12-31"},
+		{name: "Test Case 2", year: 2017, semester: 2, wantStart: "2018-02-01", wantEnd: "2018-06-30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := SemesterRange(tt.year, tt.semester)
+			if start.Format("2006-01-02") != tt.wantStart || end.Format("2006-01-02") != tt.wantEnd {
+				t.Errorf("SemesterRange() = %v, %v, want %v, %v", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestCalendar_Render(t *testing.T) {
+	Init(Config{Timezone: "UTC"})
+	c := Calendar{
+		Name: "Risal Falah",
+		Schedules: []Schedule{
+			{ID: 149, CourseName: "Data Warehouse", Class: "A", Day: 1, StartTime: 420, EndTime: 520, PlaceID: "UDJT-102", Semester: 1, Year: 2017},
+		},
+		Meetings: []Meeting{
+			{ID: 3, Number: 2, Subject: "ETL, staging", Date: time.Date(2017, 8, 14, 0, 0, 0, 0, time.UTC), ScheduleID: 149},
+			{ID: 4, Number: 1, Subject: "Other schedule", Date: time.Date(2017, 8, 14, 0, 0, 0, 0, time.UTC), ScheduleID: 150},
+		},
+		Assignments: []Assignment{
+			{ID: 7, Name: "Star schema", DueDate: time.Date(2017, 8, 20, 10, 0, 0, 0, time.UTC), ScheduleID: 149},
+		},
+	}
+
+	got := string(c.Render(time.Date(2017, 10, 14, 17, 46, 17, 0, time.UTC)))
+	want := []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Risal Falah\r\n",
+		"TZOFFSETTO:+0000\r\n",
+		"UID:schedule-149@meiko\r\nDTSTAMP:20171014T174617Z\r\n",
+		"DTSTART;TZID=UTC:20170807T070000\r\nDTEND;TZID=UTC:20170807T084000\r\n",
+		"RRULE:FREQ=WEEKLY;UNTIL=20171231T235959Z\r\n",
+		"EXDATE;TZID=UTC:20170814T070000\r\n",
+		"SUMMARY:Data Warehouse (A)\r\nLOCATION:UDJT-102\r\n",
+		"UID:meeting-3@meiko\r\n",
+		"SUMMARY:Data Warehouse (A) - Meeting 2: ETL\\, staging\r\n",
+		"UID:assignment-7@meiko\r\nDTSTAMP:20171014T174617Z\r\nDTSTART:20170820T100000Z\r\n",
+		"END:VCALENDAR\r\n",
+	}
+	for _, val := range want {
+		if !strings.Contains(got, val) {
+			t.Errorf("Render() doesn't contain %q\n%s", val, got)
+		}
+	}
+	if strings.Contains(got, "meeting-4@meiko") {
+		t.Errorf("Render() contains the meeting of another schedule\n%s", got)
+	}
+}
+
+func TestWriter_line(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "Test Case 1",
+			line: "SUMMARY:Data Warehouse",
+			want: "SUMMARY:Data Warehouse\r\n",
+		},
+		{
+			name: "Test Case 2",
+			line: "SUMMARY:" + strings.Repeat("a", 150),
+			want: "SUMMARY:" + strings.Repeat("a", 67) + "\r\n " + strings.Repeat("a", 74) + "\r\n " + strings.Repeat("a", 9) + "\r\n",
+		},
+		{
+			name: "Test Case 3",
+			line: "SUMMARY:" + strings.Repeat("a", 66) + "é",
+			want: "SUMMARY:" + strings.Repeat("a", 66) + "\r\n é\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &writer{}
+			w.line(tt.line)
+			if got := w.String(); got != tt.want {
+				t.Errorf("writer.line() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got := escape("a;b,c\\d\ne"); got != `a\;b\,c\\d\ne` {
+		t.Errorf("escape() = %v", got)
+	}
+}
@@ -0,0 +1,76 @@
+package calendar
+
+import (
+	"time"
+)
+
+const (
+	// statusScheduleActive is the active status of the schedules table
+	statusScheduleActive = 1
+	// pStatusStudent and pStatusAssistant are the statuses of the p_users_schedules table
+	pStatusStudent   = 1
+	pStatusAssistant = 2
+	// statusAssignmentActive is the active status of the assignments table
+	statusAssignmentActive = 1
+
+	tokenLength       = 32
+	publicTokenLength = 24
+	// lineLength is the max octets of the content line, the longer line is folded
+	lineLength = 75
+
+	defaultTimezone = "Asia/Jakarta"
+)
+
+// Config is used for setting the timezone of the schedules and the dates of the semesters. The odd semesters start
+// in the year of the schedule, the even semesters start in the next year by default
+type Config struct {
+	Timezone     string `json:"timezone"`
+	OddSemester  Term   `json:"odd_semester"`
+	EvenSemester Term   `json:"even_semester"`
+}
+
+// Term is the first and the last date of the semester in MM-DD format. The year offset is added to the year of the
+// schedule
+type Term struct {
+	Start      string `json:"start"`
+	End        string `json:"end"`
+	YearOffset int    `json:"year_offset"`
+}
+
+// Schedule is the weekly class of the feed, the start and end time are the minutes of the day
+type Schedule struct {
+	ID         int64  `db:"id"`
+	CourseName string `db:"name"`
+	Class      string `db:"class"`
+	Day        int8   `db:"day"`
+	StartTime  uint16 `db:"start_time"`
+	EndTime    uint16 `db:"end_time"`
+	PlaceID    string `db:"places_id"`
+	Semester   int8   `db:"semester"`
+	Year       int16  `db:"year"`
+}
+
+// Meeting is held at the time of its schedule on the meeting date
+type Meeting struct {
+	ID         int64     `db:"id"`
+	Number     uint8     `db:"number"`
+	Subject    string    `db:"subject"`
+	Date       time.Time `db:"date"`
+	ScheduleID int64     `db:"schedules_id"`
+}
+
+// Assignment is shown at its due date
+type Assignment struct {
+	ID         int64     `db:"id"`
+	Name       string    `db:"name"`
+	DueDate    time.Time `db:"due_date"`
+	ScheduleID int64     `db:"schedules_id"`
+}
+
+// Calendar is the content of the iCalendar feed
+type Calendar struct {
+	Name        string
+	Schedules   []Schedule
+	Meetings    []Meeting
+	Assignments []Assignment
+}
@@ -0,0 +1,172 @@
+package calendar
+
+const (
+	queryGetUserIDByToken = `
+		SELECT
+			users_id
+		FROM
+			calendar_tokens
+		WHERE
+			token_hash = (?)
+		LIMIT 1;
+	`
+
+	queryUpsertToken = `
+		INSERT INTO
+			calendar_tokens (
+				users_id,
+				token_hash,
+				created_at,
+				updated_at
+			)
+		VALUES (
+			(?),
+			(?),
+			NOW(),
+			NOW()
+		)
+		ON DUPLICATE KEY UPDATE
+			token_hash = VALUES(token_hash),
+			updated_at = NOW();
+	`
+
+	queryDeleteToken = `
+		DELETE FROM
+			calendar_tokens
+		WHERE
+			users_id = (?);
+	`
+
+	queryGetScheduleIDByPublicToken = `
+		SELECT
+			schedules_id
+		FROM
+			schedules_calendars
+		WHERE
+			token = (?)
+		LIMIT 1;
+	`
+
+	queryGetPublicToken = `
+		SELECT
+			token
+		FROM
+			schedules_calendars
+		WHERE
+			schedules_id = (?)
+		LIMIT 1;
+	`
+
+	queryInsertPublicToken = `
+		INSERT INTO
+			schedules_calendars (
+				schedules_id,
+				token,
+				created_at
+			)
+		VALUES (
+			(?),
+			(?),
+			NOW()
+		);
+	`
+
+	queryDeletePublicToken = `
+		DELETE FROM
+			schedules_calendars
+		WHERE
+			schedules_id = (?);
+	`
+
+	querySelectUserSchedule = `
+		SELECT
+			sc.id,
+			cs.name,
+			sc.class,
+			sc.day,
+			sc.start_time,
+			sc.end_time,
+			sc.places_id,
+			sc.semester,
+			sc.year
+		FROM
+			schedules sc
+		INNER JOIN
+			courses cs
+		ON
+			cs.id = sc.courses_id
+		WHERE
+			sc.status = (?) AND
+			(
+				sc.created_by = (?) OR
+				sc.id IN (
+					SELECT
+						schedules_id
+					FROM
+						p_users_schedules
+					WHERE
+						users_id = (?) AND
+						status IN (?)
+				)
+			)
+		ORDER BY
+			sc.day ASC,
+			sc.start_time ASC;
+	`
+
+	queryGetSchedule = `
+		SELECT
+			sc.id,
+			cs.name,
+			sc.class,
+			sc.day,
+			sc.start_time,
+			sc.end_time,
+			sc.places_id,
+			sc.semester,
+			sc.year
+		FROM
+			schedules sc
+		INNER JOIN
+			courses cs
+		ON
+			cs.id = sc.courses_id
+		WHERE
+			sc.id = (?)
+		LIMIT 1;
+	`
+
+	querySelectMeeting = `
+		SELECT
+			id,
+			number,
+			subject,
+			date,
+			schedules_id
+		FROM
+			meetings
+		WHERE
+			schedules_id IN (?)
+		ORDER BY
+			date ASC;
+	`
+
+	querySelectAssignment = `
+		SELECT
+			asg.id,
+			asg.name,
+			asg.due_date,
+			gp.schedules_id
+		FROM
+			assignments asg
+		INNER JOIN
+			grade_parameters gp
+		ON
+			gp.id = asg.grade_parameters_id
+		WHERE
+			gp.schedules_id IN (?) AND
+			asg.status = (?)
+		ORDER BY
+			asg.due_date ASC;
+	`
+)
@@ -0,0 +1,142 @@
+package course
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/melodiez14/meiko/src/util/conn"
+)
+
+// InsertAssistant adds the user as the assistant of the schedule
+/*
+	@params:
+		userID		= int64
+		scheduleID	= int64
+		tx			= optional, *sqlx.Tx
+	@example:
+		userID		= 12
+		scheduleID	= 149
+	@return
+*/
+func InsertAssistant(userID, scheduleID int64, tx ...*sqlx.Tx) error {
+
+	query := `
+		INSERT INTO
+		p_users_schedules (
+			users_id,
+			schedules_id,
+			status,
+			created_at,
+			updated_at
+		)
+		VALUES (
+			(?),
+			(?),
+			(?),
+			NOW(),
+			NOW()
+		);
+		`
+
+	_, err := conn.NewQuery(query, userID, scheduleID, PStatusAssistant).WithTx(tx...).Exec()
+	return err
+}
+
+// DeleteAssistant removes the users from the assistants of the schedule, the students of the schedule aren't removed
+/*
+	@params:
+		userIDs		= []int64
+		scheduleID	= int64
+		tx			= optional *sqlx.Tx
+	@example:
+		userIDs		= [12, 13]
+		scheduleID	= 149
+		tx			= nil
+	@return
+		number of the removed assistants
+*/
+func DeleteAssistant(userIDs []int64, scheduleID int64, tx ...*sqlx.Tx) (int64, error) {
+
+	query := `
+		DELETE FROM
+			p_users_schedules
+		WHERE
+			users_id IN (?) AND
+			schedules_id = (?) AND
+			status = (?);
+		`
+
+	result, err := conn.NewQuery(query, userIDs, scheduleID, PStatusAssistant).WithTx(tx...).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// SelectAssistantSchedule returns the schedules which are assisted by the users. The users and the schedules are
+// used as the filter if they aren't empty
+/*
+	@params:
+		userIDs		= []int64
+		scheduleIDs	= []int64
+	@example:
+		userIDs		= [12]
+		scheduleIDs	= nil
+	@return
+		[]{users_id, schedules_id, name, class, semester, year, status}
+*/
+func SelectAssistantSchedule(userIDs, scheduleIDs []int64) ([]AssistantSchedule, error) {
+
+	schedules := []AssistantSchedule{}
+	args := []interface{}{PStatusAssistant}
+	var filter []string
+	if len(userIDs) > 0 {
+		filter = append(filter, "pus.users_id IN (?)")
+		args = append(args, userIDs)
+	}
+	if len(scheduleIDs) > 0 {
+		filter = append(filter, "pus.schedules_id IN (?)")
+		args = append(args, scheduleIDs)
+	}
+
+	var where string
+	if len(filter) > 0 {
+		where = fmt.Sprintf("AND %s", strings.Join(filter, " AND "))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			pus.users_id,
+			pus.schedules_id,
+			cs.name,
+			sc.class,
+			sc.semester,
+			sc.year,
+			sc.status
+		FROM
+			p_users_schedules pus
+		INNER JOIN
+			schedules sc
+		ON
+			sc.id = pus.schedules_id
+		INNER JOIN
+			courses cs
+		ON
+			cs.id = sc.courses_id
+		WHERE
+			pus.status = (?)
+			%s
+		ORDER BY
+			pus.users_id ASC,
+			sc.year DESC,
+			sc.semester DESC;
+		`, where)
+	err := conn.NewQuery(query, args...).Select(&schedules)
+	if err != nil && err != sql.ErrNoRows {
+		return schedules, err
+	}
+
+	return schedules, nil
+}
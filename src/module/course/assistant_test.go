@@ -0,0 +1,88 @@
+package course
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/melodiez14/meiko/src/util/conn"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestSelectAssistantSchedule(t *testing.T) {
+	columns := []string{"users_id", "schedules_id", "name", "class", "semester", "year", "status"}
+	tests := []struct {
+		name        string
+		userIDs     []int64
+		scheduleIDs []int64
+		query       string
+		args        []driver.Value
+	}{
+		{
+			name:  "Test Case 1",
+			query: `WHERE\s*pus.status\s*=\s*\(\?\)\s*ORDER BY`,
+			args:  []driver.Value{PStatusAssistant},
+		},
+		{
+			name:        "Test Case 2",
+			userIDs:     []int64{12},
+			scheduleIDs: []int64{149, 150},
+			query:       `WHERE\s*pus.status\s*=\s*\(\?\)\s*AND pus.users_id IN \(\?\) AND pus.schedules_id IN \(\?, \?\)\s*ORDER BY`,
+			args:        []driver.Value{PStatusAssistant, 12, 149, 150},
+		},
+	}
+	for _, tt := range tests {
+		db, _ := conn.InitDBMock()
+		db.ExpectQuery(tt.query).
+			WithArgs(tt.args...).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(12, 149, "Sistem Informasi Multimedia", "A", 1, 2017, StatusScheduleActive))
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectAssistantSchedule(tt.userIDs, tt.scheduleIDs)
+			if err != nil {
+				t.Fatalf("SelectAssistantSchedule() error = %v", err)
+			}
+			want := []AssistantSchedule{{
+				UserID:     12,
+				ScheduleID: 149,
+				CourseName: "Sistem Informasi Multimedia",
+				Class:      "A",
+				Semester:   1,
+				Year:       2017,
+				Status:     StatusScheduleActive,
+			}}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("SelectAssistantSchedule() = %v, want %v", got, want)
+			}
+			if err := db.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestSelectEnrollment(t *testing.T) {
+	got, err := SelectEnrollment(149, nil)
+	if err != nil || len(got) != 0 {
+		t.Errorf("SelectEnrollment() = %v, %v", got, err)
+	}
+
+	db, _ := conn.InitDBMock()
+	db.ExpectQuery(`^\s*SELECT(.+)FROM\s*p_users_schedules\s*WHERE\s*schedules_id\s*=\s*\(\?\)\s*AND\s*users_id\s*IN\s*\(\?, \?\);`).
+		WithArgs(149, 12, 13).
+		WillReturnRows(sqlmock.NewRows([]string{"users_id", "schedules_id", "status", "created_at"}).
+			AddRow(13, 149, PStatusStudent, time.Now()))
+
+	got, err = SelectEnrollment(149, []int64{12, 13})
+	if err != nil {
+		t.Fatalf("SelectEnrollment() error = %v", err)
+	}
+	if len(got) != 1 || got[0].UserID != 13 || got[0].Status != PStatusStudent {
+		t.Errorf("SelectEnrollment() = %v", got)
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
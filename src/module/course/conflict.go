@@ -0,0 +1,324 @@
+package course
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/melodiez14/meiko/src/util/conn"
+)
+
+// the active schedule of the same term whose time overlaps the checked slot, the last argument excludes the
+// schedule itself when it's updated
+const queryConflictFilter = `
+	sc.status = (?) AND
+	sc.year = (?) AND
+	sc.semester % 2 = (?) AND
+	sc.day = (?) AND
+	sc.start_time < (?) AND
+	sc.end_time > (?) AND
+	sc.id != (?)`
+
+// conflictArgs returns the arguments of queryConflictFilter
+func (t Term) conflictArgs(excludeID int64) []interface{} {
+	return []interface{}{
+		StatusScheduleActive,
+		t.Year,
+		t.Semester % 2,
+		t.Day,
+		t.Slot.EndTime,
+		t.Slot.StartTime,
+		excludeID,
+	}
+}
+
+// SelectPlaceConflict returns the active schedules of the term which use the place at the overlapping time
+/*
+	@params:
+		placeID		= string
+		term		= Term
+		excludeID	= int64, 0 if the schedule isn't exist yet
+	@example:
+		placeID		= UDJT-102
+		term		= {2017, 1, 1, {420, 520}}
+		excludeID	= 149
+	@return
+		[]{type, id, name, class, day, start_time, end_time, places_id}
+*/
+func SelectPlaceConflict(placeID string, term Term, excludeID int64) ([]Conflict, error) {
+
+	conflicts := []Conflict{}
+	query := fmt.Sprintf(`
+		SELECT
+			(?) AS type,
+			0 AS users_id,
+			sc.id,
+			cs.name,
+			sc.class,
+			sc.day,
+			sc.start_time,
+			sc.end_time,
+			sc.places_id
+		FROM
+			schedules sc
+		INNER JOIN
+			courses cs
+		ON
+			cs.id = sc.courses_id
+		WHERE
+			sc.places_id = (?) AND
+			%s;
+		`, queryConflictFilter)
+
+	args := append([]interface{}{ConflictPlace, placeID}, term.conflictArgs(excludeID)...)
+	err := conn.NewQuery(query, args...).Select(&conflicts)
+	if err != nil && err != sql.ErrNoRows {
+		return conflicts, err
+	}
+
+	return conflicts, nil
+}
+
+// SelectUserConflict returns the active schedules of the term at the overlapping time which are created, assisted
+// or taken by the users. The unapproved enrollments aren't included
+/*
+	@params:
+		userIDs		= []int64
+		term		= Term
+		excludeID	= int64, 0 if the schedule isn't exist yet
+	@example:
+		userIDs		= [12, 13]
+		term		= {2017, 1, 1, {420, 520}}
+		excludeID	= 149
+	@return
+		[]{type, users_id, id, name, class, day, start_time, end_time, places_id}
+*/
+func SelectUserConflict(userIDs []int64, term Term, excludeID int64) ([]Conflict, error) {
+
+	conflicts := []Conflict{}
+	if len(userIDs) < 1 {
+		return conflicts, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			(?) AS type,
+			sc.created_by AS users_id,
+			sc.id,
+			cs.name,
+			sc.class,
+			sc.day,
+			sc.start_time,
+			sc.end_time,
+			sc.places_id
+		FROM
+			schedules sc
+		INNER JOIN
+			courses cs
+		ON
+			cs.id = sc.courses_id
+		WHERE
+			sc.created_by IN (?) AND
+			%s
+		UNION ALL
+		SELECT
+			IF(pus.status = (?), (?), (?)) AS type,
+			pus.users_id,
+			sc.id,
+			cs.name,
+			sc.class,
+			sc.day,
+			sc.start_time,
+			sc.end_time,
+			sc.places_id
+		FROM
+			p_users_schedules pus
+		INNER JOIN
+			schedules sc
+		ON
+			sc.id = pus.schedules_id
+		INNER JOIN
+			courses cs
+		ON
+			cs.id = sc.courses_id
+		WHERE
+			pus.users_id IN (?) AND
+			pus.status IN (?) AND
+			%s;
+		`, queryConflictFilter, queryConflictFilter)
+
+	args := []interface{}{ConflictLecturer, userIDs}
+	args = append(args, term.conflictArgs(excludeID)...)
+	args = append(args, PStatusAssistant, ConflictAssistant, ConflictStudent, userIDs, []int8{PStatusStudent, PStatusAssistant})
+	args = append(args, term.conflictArgs(excludeID)...)
+	err := conn.NewQuery(query, args...).Select(&conflicts)
+	if err != nil && err != sql.ErrNoRows {
+		return conflicts, err
+	}
+
+	return conflicts, nil
+}
+
+// SelectPlaceSlot returns the slots of the active schedules of the term which use the place, mapped by the day
+/*
+	@params:
+		placeID		= string
+		year		= int16
+		semester	= int8
+	@example:
+		placeID		= UDJT-102
+		year		= 2017
+		semester	= 1
+	@return
+		map[day][]{start_time, end_time}
+*/
+func SelectPlaceSlot(placeID string, year int16, semester int8) (map[int8][]Slot, error) {
+
+	var rows []struct {
+		Day int8 `db:"day"`
+		Slot
+	}
+	query := `
+		SELECT
+			day,
+			start_time,
+			end_time
+		FROM
+			schedules
+		WHERE
+			places_id = (?) AND
+			status = (?) AND
+			year = (?) AND
+			semester % 2 = (?);
+		`
+	err := conn.NewQuery(query, placeID, StatusScheduleActive, year, semester%2).Select(&rows)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	slots := map[int8][]Slot{}
+	for _, val := range rows {
+		slots[val.Day] = append(slots[val.Day], val.Slot)
+	}
+	return slots, nil
+}
+
+// SelectUserSlot returns the slots of the active schedules of the term which are created, assisted or taken by the
+// users, mapped by the user and the day
+/*
+	@params:
+		userIDs		= []int64
+		year		= int16
+		semester	= int8
+	@example:
+		userIDs		= [12, 13]
+		year		= 2017
+		semester	= 1
+	@return
+		map[userID]map[day][]{start_time, end_time}
+*/
+func SelectUserSlot(userIDs []int64, year int16, semester int8) (map[int64]map[int8][]Slot, error) {
+
+	slots := map[int64]map[int8][]Slot{}
+	if len(userIDs) < 1 {
+		return slots, nil
+	}
+
+	var rows []struct {
+		UserID int64 `db:"users_id"`
+		Day    int8  `db:"day"`
+		Slot
+	}
+	query := `
+		SELECT
+			sc.created_by AS users_id,
+			sc.day,
+			sc.start_time,
+			sc.end_time
+		FROM
+			schedules sc
+		WHERE
+			sc.created_by IN (?) AND
+			sc.status = (?) AND
+			sc.year = (?) AND
+			sc.semester % 2 = (?)
+		UNION ALL
+		SELECT
+			pus.users_id,
+			sc.day,
+			sc.start_time,
+			sc.end_time
+		FROM
+			p_users_schedules pus
+		INNER JOIN
+			schedules sc
+		ON
+			sc.id = pus.schedules_id
+		WHERE
+			pus.users_id IN (?) AND
+			pus.status IN (?) AND
+			sc.status = (?) AND
+			sc.year = (?) AND
+			sc.semester % 2 = (?);
+		`
+	err := conn.NewQuery(query,
+		userIDs, StatusScheduleActive, year, semester%2,
+		userIDs, []int8{PStatusStudent, PStatusAssistant}, StatusScheduleActive, year, semester%2).Select(&rows)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	for _, val := range rows {
+		if _, ok := slots[val.UserID]; !ok {
+			slots[val.UserID] = map[int8][]Slot{}
+		}
+		slots[val.UserID][val.Day] = append(slots[val.UserID][val.Day], val.Slot)
+	}
+	return slots, nil
+}
+
+// IsOverlap checks whether the slots share any minute, the slot which ends at 08:40 doesn't overlap the slot
+// which starts at 08:40
+func (s Slot) IsOverlap(o Slot) bool {
+	return s.StartTime < o.EndTime && o.StartTime < s.EndTime
+}
+
+// FreeSlots returns the free slots between the open and the close time which aren't used by the busy slots
+/*
+	@params:
+		busy	= []Slot
+		open	= uint16
+		close	= uint16
+	@example:
+		busy	= [{480, 580}, {420, 500}, {700, 800}]
+		open	= 420
+		close	= 1080
+	@return
+		[{580, 700}, {800, 1080}]
+*/
+func FreeSlots(busy []Slot, open, close uint16) []Slot {
+
+	sorted := make([]Slot, len(busy))
+	copy(sorted, busy)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartTime < sorted[j].StartTime
+	})
+
+	free := []Slot{}
+	start := open
+	for _, val := range sorted {
+		if val.StartTime >= close {
+			break
+		}
+		if val.StartTime > start {
+			free = append(free, Slot{StartTime: start, EndTime: val.StartTime})
+		}
+		if val.EndTime > start {
+			start = val.EndTime
+		}
+	}
+	if start < close {
+		free = append(free, Slot{StartTime: start, EndTime: close})
+	}
+	return free
+}
@@ -0,0 +1,145 @@
+package course
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/melodiez14/meiko/src/util/conn"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestSlot_IsOverlap(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Slot
+		o    Slot
+		want bool
+	}{
+		{
+			name: "Test Case 1",
+			s:    Slot{StartTime: 420, EndTime: 520},
+			o:    Slot{StartTime: 500, EndTime: 600},
+			want: true,
+		},
+		{
+			name: "Test Case 2",
+			s:    Slot{StartTime: 420, EndTime: 520},
+			o:    Slot{StartTime: 520, EndTime: 600},
+			want: false,
+		},
+		{
+			name: "Test Case 3",
+			s:    Slot{StartTime: 420, EndTime: 600},
+			o:    Slot{StartTime: 450, EndTime: 500},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.IsOverlap(tt.o); got != tt.want {
+				t.Errorf("Slot.IsOverlap() = %v, want %v", got, tt.want)
+			}
+			if got := tt.o.IsOverlap(tt.s); got != tt.want {
+				t.Errorf("Slot.IsOverlap() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFreeSlots(t *testing.T) {
+	tests := []struct {
+		name string
+		busy []Slot
+		want []Slot
+	}{
+		{
+			name: "Test Case 1",
+			busy: nil,
+			want: []Slot{{StartTime: 420, EndTime: 1080}},
+		},
+		{
+			name: "Test Case 2",
+			busy: []Slot{{StartTime: 480, EndTime: 580}, {StartTime: 420, EndTime: 500}, {StartTime: 700, EndTime: 800}},
+			want: []Slot{{StartTime: 580, EndTime: 700}, {StartTime: 800, EndTime: 1080}},
+		},
+		{
+			name: "Test Case 3",
+			busy: []Slot{{StartTime: 300, EndTime: 600}, {StartTime: 450, EndTime: 500}, {StartTime: 1000, EndTime: 1200}},
+			want: []Slot{{StartTime: 600, EndTime: 1000}},
+		},
+		{
+			name: "Test Case 4",
+			busy: []Slot{{StartTime: 1100, EndTime: 1200}},
+			want: []Slot{{StartTime: 420, EndTime: 1080}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FreeSlots(tt.busy, 420, 1080); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FreeSlots() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectPlaceConflict(t *testing.T) {
+	db, _ := conn.InitDBMock()
+	db.ExpectQuery(`^\s*SELECT(.+)FROM\s*schedules sc(.+)WHERE\s*sc.places_id\s*=\s*\(\?\)\s*AND\s*sc.status\s*=\s*\(\?\)\s*AND\s*sc.year\s*=\s*\(\?\)\s*AND\s*sc.semester % 2\s*=\s*\(\?\)`).
+		WithArgs(ConflictPlace, "UDJT-102", StatusScheduleActive, 2017, 1, 1, 520, 420, 149).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "users_id", "id", "name", "class", "day", "start_time", "end_time", "places_id"}).
+			AddRow(ConflictPlace, 0, 150, "Data Warehouse", "B", 1, 480, 580, "UDJT-102"))
+
+	term := Term{Year: 2017, Semester: 3, Day: 1, Slot: Slot{StartTime: 420, EndTime: 520}}
+	got, err := SelectPlaceConflict("UDJT-102", term, 149)
+	if err != nil {
+		t.Fatalf("SelectPlaceConflict() error = %v", err)
+	}
+	want := []Conflict{{
+		Type:       ConflictPlace,
+		ScheduleID: 150,
+		CourseName: "Data Warehouse",
+		Class:      "B",
+		Day:        1,
+		StartTime:  480,
+		EndTime:    580,
+		PlaceID:    "UDJT-102",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SelectPlaceConflict() = %v, want %v", got, want)
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSelectUserConflict(t *testing.T) {
+	got, err := SelectUserConflict(nil, Term{}, 0)
+	if err != nil || len(got) != 0 {
+		t.Errorf("SelectUserConflict() = %v, %v", got, err)
+	}
+}
+
+func TestSelectUserSlot(t *testing.T) {
+	db, _ := conn.InitDBMock()
+	db.ExpectQuery(`^\s*SELECT(.+)FROM\s*schedules sc(.+)UNION ALL(.+)FROM\s*p_users_schedules pus`).
+		WithArgs(12, 13, StatusScheduleActive, 2017, 1, 12, 13, PStatusStudent, PStatusAssistant, StatusScheduleActive, 2017, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"users_id", "day", "start_time", "end_time"}).
+			AddRow(12, 1, 420, 520).
+			AddRow(13, 2, 480, 580).
+			AddRow(12, 1, 600, 700))
+
+	got, err := SelectUserSlot([]int64{12, 13}, 2017, 3)
+	if err != nil {
+		t.Fatalf("SelectUserSlot() error = %v", err)
+	}
+	want := map[int64]map[int8][]Slot{
+		12: {1: {{StartTime: 420, EndTime: 520}, {StartTime: 600, EndTime: 700}}},
+		13: {2: {{StartTime: 480, EndTime: 580}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SelectUserSlot() = %v, want %v", got, want)
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -3,9 +3,6 @@ package course
 import (
 	"fmt"
 	"strconv"
-	"strings"
-
-	"github.com/melodiez14/meiko/src/util/helper"
 
 	"database/sql"
 
@@ -14,14 +11,16 @@ import (
 )
 
 func SelectIDByUserID(userID int64, status ...int8) ([]int64, error) {
+	args := []interface{}{userID}
 	var st string
 	if len(status) == 1 {
-		st = fmt.Sprintf("AND status = (%d)", status[0])
+		st = "AND status = (?)"
+		args = append(args, status[0])
 	}
 
 	var scheduleID []int64
-	query := fmt.Sprintf(`SELECT schedules_id FROM p_users_schedules WHERE users_id = (%d) %s`, userID, st)
-	err := conn.DB.Select(&scheduleID, query)
+	query := fmt.Sprintf(`SELECT schedules_id FROM p_users_schedules WHERE users_id = (?) %s`, st)
+	err := conn.NewQuery(query, args...).Select(&scheduleID)
 	if err != nil && err != sql.ErrNoRows {
 		return scheduleID, err
 	}
@@ -31,14 +30,16 @@ func SelectIDByUserID(userID int64, status ...int8) ([]int64, error) {
 
 func SelectScheduleIDByUserID(userID int64, status ...int8) ([]int64, error) {
 
+	args := []interface{}{userID}
 	var st string
 	if len(status) == 1 {
-		st = fmt.Sprintf("AND status = (%d)", status[0])
+		st = "AND status = (?)"
+		args = append(args, status[0])
 	}
 
 	var scheduleIDs []int64
-	query := fmt.Sprintf(`SELECT schedules_id FROM p_users_schedules WHERE users_id = (%d) %s;`, userID, st)
-	err := conn.DB.Select(&scheduleIDs, query)
+	query := fmt.Sprintf(`SELECT schedules_id FROM p_users_schedules WHERE users_id = (?) %s;`, st)
+	err := conn.NewQuery(query, args...).Select(&scheduleIDs)
 	if err != nil && err != sql.ErrNoRows {
 		return scheduleIDs, err
 	}
@@ -47,8 +48,8 @@ func SelectScheduleIDByUserID(userID int64, status ...int8) ([]int64, error) {
 
 func IsEnrolled(userID, scheduleID int64) bool {
 	var x string
-	query := fmt.Sprintf("SELECT 'x' FROM p_users_schedules WHERE users_id = (%d) AND schedules_id = (%d) LIMIT 1", userID, scheduleID)
-	err := conn.DB.Get(&x, query)
+	query := "SELECT 'x' FROM p_users_schedules WHERE users_id = (?) AND schedules_id = (?) LIMIT 1"
+	err := conn.NewQuery(query, userID, scheduleID).Get(&x)
 	if err != nil {
 		return false
 	}
@@ -58,14 +59,88 @@ func IsEnrolled(userID, scheduleID int64) bool {
 func SelectAssistantID(scheduleID int64) ([]int64, error) {
 
 	userIDs := []int64{}
-	query := fmt.Sprintf(`SELECT
+	query := `SELECT
 		p.users_id
 	FROM
 		p_users_schedules p
 	WHERE 
-		p.status = (%d) AND
-		p.schedules_id = (%d);`, PStatusAssistant, scheduleID)
-	err := conn.DB.Select(&userIDs, query)
+		p.status = (?) AND
+		p.schedules_id = (?);`
+	err := conn.NewQuery(query, PStatusAssistant, scheduleID).Select(&userIDs)
+	if err != nil && err != sql.ErrNoRows {
+		return userIDs, err
+	}
+
+	return userIDs, nil
+}
+
+func SelectStudentID(scheduleID int64) ([]int64, error) {
+
+	userIDs := []int64{}
+	query := `SELECT
+		p.users_id
+	FROM
+		p_users_schedules p
+	WHERE
+		p.status = (?) AND
+		p.schedules_id = (?);`
+	err := conn.NewQuery(query, PStatusStudent, scheduleID).Select(&userIDs)
+	if err != nil && err != sql.ErrNoRows {
+		return userIDs, err
+	}
+
+	return userIDs, nil
+}
+
+func IsAssistant(userID, scheduleID int64) bool {
+	var x string
+	query := "SELECT 'x' FROM p_users_schedules WHERE users_id = (?) AND schedules_id = (?) AND status = (?) LIMIT 1"
+	err := conn.NewQuery(query, userID, scheduleID, PStatusAssistant).Get(&x)
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+// IsStudent checks whether the user is enrolled as an approved student in the schedule
+func IsStudent(userID, scheduleID int64) bool {
+	var x string
+	query := "SELECT 'x' FROM p_users_schedules WHERE users_id = (?) AND schedules_id = (?) AND status = (?) LIMIT 1"
+	err := conn.NewQuery(query, userID, scheduleID, PStatusStudent).Get(&x)
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+// SelectOwnerID returns the users who own the schedule, they are the creator and the assistants of the schedule
+/*
+	@params:
+		scheduleID	= int64
+	@example:
+		scheduleID	= 12
+	@return
+		[]userID
+*/
+func SelectOwnerID(scheduleID int64) ([]int64, error) {
+
+	userIDs := []int64{}
+	query := `
+		SELECT
+			created_by
+		FROM
+			schedules
+		WHERE
+			id = (?)
+		UNION
+		SELECT
+			users_id
+		FROM
+			p_users_schedules
+		WHERE
+			schedules_id = (?) AND
+			status = (?);`
+	err := conn.NewQuery(query, scheduleID, scheduleID, PStatusAssistant).Select(&userIDs)
 	if err != nil && err != sql.ErrNoRows {
 		return userIDs, err
 	}
@@ -73,16 +148,90 @@ func SelectAssistantID(scheduleID int64) ([]int64, error) {
 	return userIDs, nil
 }
 
+// SelectCourseOwnerID returns the users who own any schedule of the course
+/*
+	@params:
+		courseID	= string
+	@example:
+		courseID	= D10K-7D01
+	@return
+		[]userID
+*/
+func SelectCourseOwnerID(courseID string) ([]int64, error) {
+
+	userIDs := []int64{}
+	query := `
+		SELECT
+			created_by
+		FROM
+			schedules
+		WHERE
+			courses_id = (?)
+		UNION
+		SELECT
+			p.users_id
+		FROM
+			p_users_schedules p
+		INNER JOIN
+			schedules sc
+		ON
+			p.schedules_id = sc.id
+		WHERE
+			sc.courses_id = (?) AND
+			p.status = (?);`
+	err := conn.NewQuery(query, courseID, courseID, PStatusAssistant).Select(&userIDs)
+	if err != nil && err != sql.ErrNoRows {
+		return userIDs, err
+	}
+
+	return userIDs, nil
+}
+
+// SelectOwnedScheduleID returns the schedules created or assisted by the user
+/*
+	@params:
+		userID	= int64
+	@example:
+		userID	= 12
+	@return
+		[]scheduleID
+*/
+func SelectOwnedScheduleID(userID int64) ([]int64, error) {
+
+	scheduleIDs := []int64{}
+	query := `
+		SELECT
+			id
+		FROM
+			schedules
+		WHERE
+			created_by = (?)
+		UNION
+		SELECT
+			schedules_id
+		FROM
+			p_users_schedules
+		WHERE
+			users_id = (?) AND
+			status = (?);`
+	err := conn.NewQuery(query, userID, userID, PStatusAssistant).Select(&scheduleIDs)
+	if err != nil && err != sql.ErrNoRows {
+		return scheduleIDs, err
+	}
+
+	return scheduleIDs, nil
+}
+
 func SelectAllAssistantID() ([]int64, error) {
 
 	userIDs := []int64{}
-	query := fmt.Sprintf(`SELECT
+	query := `SELECT
 		users_id
 	FROM
 		p_users_schedules
 	WHERE 
-		status = (%d);`, PStatusAssistant)
-	err := conn.DB.Select(&userIDs, query)
+		status = (?);`
+	err := conn.NewQuery(query, PStatusAssistant).Select(&userIDs)
 	if err != nil && err != sql.ErrNoRows {
 		return userIDs, err
 	}
@@ -93,8 +242,8 @@ func SelectAllAssistantID() ([]int64, error) {
 func SelectAllName() ([]string, error) {
 
 	var names []string
-	query := fmt.Sprintf(`SELECT name FROM courses;`)
-	err := conn.DB.Select(&names, query)
+	query := `SELECT name FROM courses;`
+	err := conn.NewQuery(query).Select(&names)
 	if err != nil && err != sql.ErrNoRows {
 		return names, err
 	}
@@ -105,8 +254,8 @@ func SelectAllName() ([]string, error) {
 func IsExist(courseID string) bool {
 
 	var x string
-	query := fmt.Sprintf(`SELECT 'x' FROM courses WHERE id = ('%s') LIMIT 1;`, courseID)
-	err := conn.DB.Get(&x, query)
+	query := `SELECT 'x' FROM courses WHERE id = (?) LIMIT 1;`
+	err := conn.NewQuery(query, courseID).Get(&x)
 	if err != nil {
 		return false
 	}
@@ -116,50 +265,25 @@ func IsExist(courseID string) bool {
 
 func Update(courseID, name string, description sql.NullString, ucu int8, tx ...*sqlx.Tx) error {
 
-	queryDescription := fmt.Sprintf("NULL")
-	if description.Valid {
-		queryDescription = fmt.Sprintf("('%s')", description.String)
-	}
-
-	query := fmt.Sprintf(`
+	query := `
 		UPDATE
 			courses
 		SET
-			name = ('%s'),
-			description = %s,
-			ucu = (%d),
+			name = (?),
+			description = (?),
+			ucu = (?),
 			updated_at = NOW()
 		WHERE
-			id = ('%s');
-		`, name, queryDescription, ucu, courseID)
+			id = (?);
+		`
 
-	var result sql.Result
-	var err error
-	if len(tx) == 1 {
-		result, err = tx[0].Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
-
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-
-	return nil
+	_, err := conn.NewQuery(query, name, description, ucu, courseID).WithTx(tx...).ExecAffected()
+	return err
 }
 
 func Insert(courseID, name string, description sql.NullString, ucu int8, tx ...*sqlx.Tx) error {
 
-	queryDescription := fmt.Sprintf("NULL")
-	if description.Valid {
-		queryDescription = fmt.Sprintf("('%s')", description.String)
-	}
-
-	query := fmt.Sprintf(`
+	query := `
 		INSERT INTO
 			courses (
 				id,
@@ -170,38 +294,25 @@ func Insert(courseID, name string, description sql.NullString, ucu int8, tx ...*
 				updated_at
 			)
 		VALUES (
-			('%s'),
-			('%s'),
-			%s,
-			(%d),
+			(?),
+			(?),
+			(?),
+			(?),
 			NOW(),
 			NOW()
-		);`, courseID, name, queryDescription, ucu)
-
-	var result sql.Result
-	var err error
-	if len(tx) == 1 {
-		result, err = tx[0].Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
-
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
+		);`
 
-	return nil
+	_, err := conn.NewQuery(query, courseID, name, description, ucu).WithTx(tx...).ExecAffected()
+	return err
 }
 
 func IsExistSchedule(semester int8, year int16, courseID, class string, scheduleID ...int64) bool {
 
+	args := []interface{}{semester, year, courseID, class}
 	var sc string
 	if len(scheduleID) == 1 {
-		sc = fmt.Sprintf(" AND id != (%d) ", scheduleID[0])
+		sc = " AND id != (?) "
+		args = append(args, scheduleID[0])
 	}
 
 	var x string
@@ -211,12 +322,12 @@ func IsExistSchedule(semester int8, year int16, courseID, class string, schedule
 		FROM
 			schedules
 		WHERE
-			semester = (%d) AND
-			year = (%d) AND
-			courses_id = ('%s') AND
-			class = ('%s') %s
-		LIMIT 1;`, semester, year, courseID, class, sc)
-	err := conn.DB.Get(&x, query)
+			semester = (?) AND
+			year = (?) AND
+			courses_id = (?) AND
+			class = (?) %s
+		LIMIT 1;`, sc)
+	err := conn.NewQuery(query, args...).Get(&x)
 	if err != nil {
 		return false
 	}
@@ -226,7 +337,7 @@ func IsExistSchedule(semester int8, year int16, courseID, class string, schedule
 func InsertSchedule(userID int64, startTime, endTime, year int16, semester, day, status int8, class, courseID, placeID string, tx ...*sqlx.Tx) (int64, error) {
 
 	var id int64
-	query := fmt.Sprintf(`
+	query := `
 		INSERT INTO
 			schedules (
 				status,
@@ -243,28 +354,21 @@ func InsertSchedule(userID int64, startTime, endTime, year int16, semester, day,
 				updated_at
 			)
 		VALUES (
-			(%d),
-			(%d),
-			(%d),
-			(%d),
-			('%s'),
-			(%d),
-			(%d),
-			('%s'),
-			('%s'),
-			(%d),
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
 			NOW(),
 			NOW()
-		)`, status, startTime, endTime, day, class, semester, year, courseID, placeID, userID)
+		)`
 
-	var result sql.Result
-	var err error
-	switch len(tx) {
-	case 1:
-		result, err = tx[0].Exec(query)
-	default:
-		result, err = conn.DB.Exec(query)
-	}
+	result, err := conn.NewQuery(query, status, startTime, endTime, day, class, semester, year, courseID, placeID, userID).WithTx(tx...).Exec()
 	if err != nil {
 		return id, err
 	}
@@ -277,9 +381,18 @@ func InsertSchedule(userID int64, startTime, endTime, year int16, semester, day,
 	return id, nil
 }
 
-func SelectByPage(limit, offset uint16) ([]CourseSchedule, error) {
+// SelectByPage returns the schedules of the courses, the schedules are filtered if the schedule ids are given
+func SelectByPage(limit, offset uint16, scheduleID ...int64) ([]CourseSchedule, error) {
 
 	var course []CourseSchedule
+	args := []interface{}{}
+	var filter string
+	if len(scheduleID) > 0 {
+		filter = "WHERE sc.id IN (?)"
+		args = append(args, scheduleID)
+	}
+	args = append(args, limit, offset)
+
 	query := fmt.Sprintf(`
 		SELECT
 			cs.id,
@@ -302,12 +415,13 @@ func SelectByPage(limit, offset uint16) ([]CourseSchedule, error) {
 			schedules sc
 		ON
 			cs.id = sc.courses_id
-		LIMIT %d OFFSET %d;`, limit, offset)
-	rows, err := conn.DB.Queryx(query)
-	defer rows.Close()
+		%s
+		LIMIT ? OFFSET ?;`, filter)
+	rows, err := conn.NewQuery(query, args...).Queryx()
 	if err != nil {
 		return course, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var id, name, class, placeID string
@@ -350,7 +464,7 @@ func SelectByPage(limit, offset uint16) ([]CourseSchedule, error) {
 func GetByScheduleID(scheduleID int64) (CourseSchedule, error) {
 
 	var course CourseSchedule
-	query := fmt.Sprintf(`
+	query := `
 		SELECT
 			cs.id,
 			cs.name,
@@ -373,10 +487,8 @@ func GetByScheduleID(scheduleID int64) (CourseSchedule, error) {
 		ON
 			cs.id = sc.courses_id
 		WHERE
-			sc.id = (%d)
-		LIMIT 1;`, scheduleID)
-
-	rows := conn.DB.QueryRowx(query)
+			sc.id = (?)
+		LIMIT 1;`
 
 	// scan data to variable
 	var id, name, class, placeID string
@@ -386,7 +498,7 @@ func GetByScheduleID(scheduleID int64) (CourseSchedule, error) {
 	var year int16
 	var createdBy int64
 
-	err := rows.Scan(&id, &name, &description, &ucu, &scheduleID, &status, &startTime, &endTime, &day, &class, &semester, &year, &placeID, &createdBy)
+	err := conn.NewQuery(query, scheduleID).Scan(&id, &name, &description, &ucu, &scheduleID, &status, &startTime, &endTime, &day, &class, &semester, &year, &placeID, &createdBy)
 	if err != nil {
 		return course, err
 	}
@@ -415,15 +527,15 @@ func GetByScheduleID(scheduleID int64) (CourseSchedule, error) {
 
 func IsExistScheduleID(scheduleID int64) bool {
 	var x string
-	query := fmt.Sprintf(`
+	query := `
 		SELECT
 			'x'
 		FROM
 			schedules
 		WHERE
-			id = (%d)
-		LIMIT 1;`, scheduleID)
-	err := conn.DB.Get(&x, query)
+			id = (?)
+		LIMIT 1;`
+	err := conn.NewQuery(query, scheduleID).Get(&x)
 	if err != nil {
 		return false
 	}
@@ -432,41 +544,25 @@ func IsExistScheduleID(scheduleID int64) bool {
 
 func UpdateSchedule(scheduleID int64, startTime, endTime, year int16, semester, day, status int8, class, courseID, placeID string, tx ...*sqlx.Tx) error {
 
-	query := fmt.Sprintf(`
+	query := `
 		UPDATE 
 			schedules
 		SET
-			status = (%d),
-			start_time = (%d),
-			end_time = (%d),
-			day = (%d),
-			class = ('%s'),
-			semester = (%d),
-			year = (%d),
-			courses_id = ('%s'),
-			places_id = ('%s'),
+			status = (?),
+			start_time = (?),
+			end_time = (?),
+			day = (?),
+			class = (?),
+			semester = (?),
+			year = (?),
+			courses_id = (?),
+			places_id = (?),
 			updated_at = NOW()
 		WHERE
-			id = (%d);`, status, startTime, endTime, day, class, semester, year, courseID, placeID, scheduleID)
+			id = (?);`
 
-	var result sql.Result
-	var err error
-	switch len(tx) {
-	case 1:
-		result, err = tx[0].Exec(query)
-	default:
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
-
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-
-	return nil
+	_, err := conn.NewQuery(query, status, startTime, endTime, day, class, semester, year, courseID, placeID, scheduleID).WithTx(tx...).ExecAffected()
+	return err
 }
 
 func SelectByScheduleID(scheduleID []int64, status int8) ([]CourseSchedule, error) {
@@ -476,10 +572,7 @@ func SelectByScheduleID(scheduleID []int64, status int8) ([]CourseSchedule, erro
 		return course, nil
 	}
 
-	d := helper.Int64ToStringSlice(scheduleID)
-	ids := strings.Join(d, ", ")
-
-	query := fmt.Sprintf(`
+	query := `
 		SELECT
 			cs.id,
 			cs.name,
@@ -502,14 +595,14 @@ func SelectByScheduleID(scheduleID []int64, status int8) ([]CourseSchedule, erro
 		ON
 			cs.id = sc.courses_id
 		WHERE
-			sc.id IN (%s) AND
-			sc.status = (%d)`, ids, status)
+			sc.id IN (?) AND
+			sc.status = (?)`
 
-	rows, err := conn.DB.Queryx(query)
-	defer rows.Close()
+	rows, err := conn.NewQuery(query, scheduleID, status).Queryx()
 	if err != nil {
 		return course, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var id, name, class, placeID string
@@ -552,7 +645,7 @@ func SelectByScheduleID(scheduleID []int64, status int8) ([]CourseSchedule, erro
 func SelectByStatus(status int8) ([]CourseSchedule, error) {
 
 	var course []CourseSchedule
-	query := fmt.Sprintf(`
+	query := `
 		SELECT
 			cs.id,
 			cs.name,
@@ -575,9 +668,9 @@ func SelectByStatus(status int8) ([]CourseSchedule, error) {
 		ON
 			cs.id = sc.courses_id
 		WHERE
-			sc.status = (%d)`, status)
+			sc.status = (?)`
 
-	rows, err := conn.DB.Queryx(query)
+	rows, err := conn.NewQuery(query, status).Queryx()
 	defer rows.Close()
 	if err != nil {
 		return course, err
@@ -623,35 +716,20 @@ func SelectByStatus(status int8) ([]CourseSchedule, error) {
 
 func DeleteSchedule(scheduleID int64, tx ...*sqlx.Tx) error {
 
-	query := fmt.Sprintf(`
+	query := `
 		DELETE FROM
 			schedules
 		WHERE
-			id = (%d);
-		`, scheduleID)
+			id = (?);
+		`
 
-	var result sql.Result
-	var err error
-	if len(tx) == 1 {
-		result, err = tx[0].Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
-
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-
-	return nil
+	_, err := conn.NewQuery(query, scheduleID).WithTx(tx...).ExecAffected()
+	return err
 }
 
 func SelectByName(name string) ([]Course, error) {
 	var courses []Course
-	query := fmt.Sprintf(`
+	query := `
 		SELECT
 			id,
 			name,
@@ -660,10 +738,10 @@ func SelectByName(name string) ([]Course, error) {
 		FROM
 			courses
 		WHERE
-			name LIKE ('%%%s%%')
+			name LIKE (?)
 		LIMIT 5;
-	`, name)
-	err := conn.DB.Select(&courses, query)
+	`
+	err := conn.NewQuery(query, "%"+name+"%").Select(&courses)
 	if err != nil && err != sql.ErrNoRows {
 		return courses, err
 	}
@@ -673,7 +751,7 @@ func SelectByName(name string) ([]Course, error) {
 
 func InsertGradeParameter(typ string, percentage float32, statusChange uint8, scheduleID int64, tx *sqlx.Tx) error {
 
-	query := fmt.Sprintf(`
+	query := `
 		INSERT INTO
 		grade_parameters (
 			type,
@@ -684,37 +762,22 @@ func InsertGradeParameter(typ string, percentage float32, statusChange uint8, sc
 			updated_at
 		)
 		VALUES (
-			('%s'),
-			(%f),
-			(%d),
-			(%d),
+			(?),
+			(?),
+			(?),
+			(?),
 			NOW(),
 			NOW()
 		);
-		`, typ, percentage, statusChange, scheduleID)
-
-	var result sql.Result
-	var err error
-	if tx != nil {
-		result, err = tx.Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
+		`
 
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-
-	return nil
+	_, err := conn.NewQuery(query, typ, percentage, statusChange, scheduleID).WithTx(tx).ExecAffected()
+	return err
 }
 
 func SelectGradeParameterByScheduleID(scheduleID int64) ([]GradeParameter, error) {
 	var gps []GradeParameter
-	query := fmt.Sprintf(`
+	query := `
 		SELECT
 			id,
 			type,
@@ -723,9 +786,9 @@ func SelectGradeParameterByScheduleID(scheduleID int64) ([]GradeParameter, error
 		FROM
 			grade_parameters
 		WHERE
-			schedules_id = (%d);
-		`, scheduleID)
-	err := conn.DB.Select(&gps, query)
+			schedules_id = (?);
+		`
+	err := conn.NewQuery(query, scheduleID).Select(&gps)
 	if err != nil && err != sql.ErrNoRows {
 		return gps, err
 	}
@@ -735,77 +798,47 @@ func SelectGradeParameterByScheduleID(scheduleID int64) ([]GradeParameter, error
 
 func DeleteGradeParameter(id int64, tx *sqlx.Tx) error {
 
-	query := fmt.Sprintf(`
+	query := `
 			DELETE FROM
 				grade_parameters
 			WHERE
-				id = (%d);
-			`, id)
-
-	var result sql.Result
-	var err error
-	if tx != nil {
-		result, err = tx.Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
-
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
+				id = (?);
+			`
 
-	return nil
+	_, err := conn.NewQuery(query, id).WithTx(tx).ExecAffected()
+	return err
 }
 
 func UpdateGradeParameter(typ string, percentage float32, statusChange uint8, scheduleID int64, tx *sqlx.Tx) error {
 
-	query := fmt.Sprintf(`
+	query := `
 		UPDATE
 			grade_parameters
 		SET
-			percentage = (%f),
-			status_change = (%d),
+			percentage = (?),
+			status_change = (?),
 			updated_at = NOW()
 		WHERE
-			type = ('%s') AND
-			schedules_id = (%d);
-		`, percentage, statusChange, typ, scheduleID)
-
-	var result sql.Result
-	var err error
-	if tx != nil {
-		result, err = tx.Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
-
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
+			type = (?) AND
+			schedules_id = (?);
+		`
 
-	return nil
+	_, err := conn.NewQuery(query, percentage, statusChange, typ, scheduleID).WithTx(tx).ExecAffected()
+	return err
 }
 
 // GetScheduleID func ...
 func GetScheduleID(gradeParametersID int64) int64 {
-	query := fmt.Sprintf(`
+	query := `
 		SELECT 
 			gp.schedules_id
 		FROM
 			grade_parameters gp
 		WHERE
-			id = (%d)
-		`, gradeParametersID)
+			id = (?)
+		`
 	var scheduleID string
-	err := conn.DB.QueryRow(query).Scan(&scheduleID)
+	err := conn.NewQuery(query, gradeParametersID).Scan(&scheduleID)
 	if err != nil {
 		return 0
 	}
@@ -819,16 +852,16 @@ func GetScheduleID(gradeParametersID int64) int64 {
 
 // GetGradeParametersID func ...
 func GetGradeParametersID(AssignmentID int64) int64 {
-	query := fmt.Sprintf(`
+	query := `
 		SELECT 
 			asg.grade_parameters_id
 		FROM
 			assignments asg
 		WHERE
-			id = (%d)
-		`, AssignmentID)
+			id = (?)
+		`
 	var assignmentID string
-	err := conn.DB.QueryRow(query).Scan(&assignmentID)
+	err := conn.NewQuery(query, AssignmentID).Scan(&assignmentID)
 	if err != nil {
 		return 0
 	}
@@ -839,3 +872,37 @@ func GetGradeParametersID(AssignmentID int64) int64 {
 
 	return assignmentid
 }
+
+// SelectClass returns the classes of the course which are used in the semester
+/*
+	@params:
+		courseID	= string
+		semester	= int8
+		year		= int16
+	@example:
+		courseID	= D10K-7D02
+		semester	= 1
+		year		= 2017
+	@return
+		[]class
+*/
+func SelectClass(courseID string, semester int8, year int16) ([]string, error) {
+
+	classes := []string{}
+	query := `
+		SELECT
+			class
+		FROM
+			schedules
+		WHERE
+			courses_id = (?) AND
+			semester = (?) AND
+			year = (?);
+		`
+	err := conn.NewQuery(query, courseID, semester, year).Select(&classes)
+	if err != nil && err != sql.ErrNoRows {
+		return classes, err
+	}
+
+	return classes, nil
+}
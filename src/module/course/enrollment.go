@@ -0,0 +1,294 @@
+package course
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/melodiez14/meiko/src/util/conn"
+)
+
+// RequestEnrollment inserts the unapproved enrollment of the user to the schedule
+/*
+	@params:
+		userID		= int64
+		scheduleID	= int64
+	@example:
+		userID		= 12
+		scheduleID	= 149
+	@return
+*/
+func RequestEnrollment(userID, scheduleID int64) error {
+
+	query := `
+		INSERT INTO
+		p_users_schedules (
+			users_id,
+			schedules_id,
+			status,
+			created_at,
+			updated_at
+		)
+		VALUES (
+			(?),
+			(?),
+			(?),
+			NOW(),
+			NOW()
+		);
+		`
+
+	_, err := conn.NewQuery(query, userID, scheduleID, PStatusUnapproved).Exec()
+	return err
+}
+
+// WithdrawEnrollment deletes the enrollment of the user which hasn't been approved. The approved enrollment
+// can't be withdrawn, it returns conn.ErrNoRowsAffected
+func WithdrawEnrollment(userID, scheduleID int64) error {
+
+	query := `
+		DELETE FROM
+			p_users_schedules
+		WHERE
+			users_id = (?) AND
+			schedules_id = (?) AND
+			status = (?);
+		`
+
+	_, err := conn.NewQuery(query, userID, scheduleID, PStatusUnapproved).ExecAffected()
+	return err
+}
+
+// SelectEnrollmentRequest returns the unapproved enrollments of the schedule from the oldest request
+/*
+	@params:
+		scheduleID	= int64
+	@example:
+		scheduleID	= 149
+	@return
+		[]{users_id, schedules_id, status, created_at}
+*/
+func SelectEnrollmentRequest(scheduleID int64) ([]Enrollment, error) {
+
+	enrollments := []Enrollment{}
+	query := `
+		SELECT
+			users_id,
+			schedules_id,
+			status,
+			created_at
+		FROM
+			p_users_schedules
+		WHERE
+			schedules_id = (?) AND
+			status = (?)
+		ORDER BY
+			created_at ASC;
+		`
+	err := conn.NewQuery(query, scheduleID, PStatusUnapproved).Select(&enrollments)
+	if err != nil && err != sql.ErrNoRows {
+		return enrollments, err
+	}
+
+	return enrollments, nil
+}
+
+// SelectEnrollment returns the enrollments of the users to the schedule whatever their status are
+/*
+	@params:
+		scheduleID	= int64
+		userIDs		= []int64
+	@example:
+		scheduleID	= 149
+		userIDs		= [12, 13]
+	@return
+		[]{users_id, schedules_id, status, created_at}
+*/
+func SelectEnrollment(scheduleID int64, userIDs []int64) ([]Enrollment, error) {
+
+	enrollments := []Enrollment{}
+	if len(userIDs) < 1 {
+		return enrollments, nil
+	}
+
+	query := `
+		SELECT
+			users_id,
+			schedules_id,
+			status,
+			created_at
+		FROM
+			p_users_schedules
+		WHERE
+			schedules_id = (?) AND
+			users_id IN (?);
+		`
+	err := conn.NewQuery(query, scheduleID, userIDs).Select(&enrollments)
+	if err != nil && err != sql.ErrNoRows {
+		return enrollments, err
+	}
+
+	return enrollments, nil
+}
+
+// ApproveEnrollment changes the unapproved enrollments of the users into the student of the schedule
+/*
+	@params:
+		userIDs		= []int64
+		scheduleID	= int64
+		tx			= optional, *sqlx.Tx
+	@example:
+		userIDs		= [12, 13]
+		scheduleID	= 149
+	@return
+		number of the approved enrollments
+*/
+func ApproveEnrollment(userIDs []int64, scheduleID int64, tx ...*sqlx.Tx) (int64, error) {
+
+	query := `
+		UPDATE
+			p_users_schedules
+		SET
+			status = (?),
+			updated_at = NOW()
+		WHERE
+			users_id IN (?) AND
+			schedules_id = (?) AND
+			status = (?);
+		`
+
+	result, err := conn.NewQuery(query, PStatusStudent, userIDs, scheduleID, PStatusUnapproved).WithTx(tx...).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// RejectEnrollment deletes the unapproved enrollments of the users from the schedule
+/*
+	@params:
+		userIDs		= []int64
+		scheduleID	= int64
+		tx			= optional *sqlx.Tx
+	@example:
+		userIDs		= [12, 13]
+		scheduleID	= 149
+		tx			= nil
+	@return
+		number of the rejected enrollments
+*/
+func RejectEnrollment(userIDs []int64, scheduleID int64, tx ...*sqlx.Tx) (int64, error) {
+
+	query := `
+		DELETE FROM
+			p_users_schedules
+		WHERE
+			users_id IN (?) AND
+			schedules_id = (?) AND
+			status = (?);
+		`
+
+	result, err := conn.NewQuery(query, userIDs, scheduleID, PStatusUnapproved).WithTx(tx...).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// CountStudent returns the number of the approved students of the schedule
+func CountStudent(scheduleID int64, tx ...*sqlx.Tx) (int64, error) {
+
+	var count int64
+	query := `
+		SELECT
+			COUNT(*)
+		FROM
+			p_users_schedules
+		WHERE
+			schedules_id = (?) AND
+			status = (?);
+		`
+	err := conn.NewQuery(query, scheduleID, PStatusStudent).WithTx(tx...).Get(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetCapacity returns the maximum number of the students of the schedule, 0 means unlimited. Inside the
+// transaction the capacity is locked, so the concurrent approvals can't exceed it
+/*
+	@params:
+		scheduleID	= int64
+		tx			= optional, *sqlx.Tx
+	@example:
+		scheduleID	= 149
+	@return
+		40
+*/
+func GetCapacity(scheduleID int64, tx ...*sqlx.Tx) (uint16, error) {
+
+	var lock string
+	if len(tx) == 1 && tx[0] != nil {
+		lock = "FOR UPDATE"
+	}
+
+	var capacity uint16
+	query := fmt.Sprintf(`
+		SELECT
+			capacity
+		FROM
+			schedules_capacities
+		WHERE
+			schedules_id = (?)
+		LIMIT 1
+		%s;
+		`, lock)
+	err := conn.NewQuery(query, scheduleID).WithTx(tx...).Get(&capacity)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return capacity, nil
+}
+
+// UpdateCapacity sets the maximum number of the students of the schedule, 0 means unlimited
+/*
+	@params:
+		scheduleID	= int64
+		capacity	= uint16
+		tx			= optional *sqlx.Tx
+	@example:
+		scheduleID	= 149
+		capacity	= 40
+		tx			= nil
+	@return
+*/
+func UpdateCapacity(scheduleID int64, capacity uint16, tx ...*sqlx.Tx) error {
+
+	query := `
+		INSERT INTO
+		schedules_capacities (
+			schedules_id,
+			capacity,
+			created_at,
+			updated_at
+		)
+		VALUES (
+			(?),
+			(?),
+			NOW(),
+			NOW()
+		)
+		ON DUPLICATE KEY UPDATE
+			capacity = VALUES(capacity),
+			updated_at = NOW();
+		`
+
+	_, err := conn.NewQuery(query, scheduleID, capacity).WithTx(tx...).Exec()
+	return err
+}
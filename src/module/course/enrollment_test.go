@@ -0,0 +1,131 @@
+package course
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/melodiez14/meiko/src/util/conn"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestWithdrawEnrollment(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{
+			name:     "Test Case 1",
+			affected: 1,
+			wantErr:  nil,
+		},
+		{
+			name:     "Test Case 2",
+			affected: 0,
+			wantErr:  conn.ErrNoRowsAffected,
+		},
+	}
+	for _, tt := range tests {
+		db, _ := conn.InitDBMock()
+		db.ExpectExec(`^\s*DELETE\s*FROM\s*p_users_schedules\s*WHERE\s*users_id\s*=\s*\(\?\)\s*AND\s*schedules_id\s*=\s*\(\?\)\s*AND\s*status\s*=\s*\(\?\);`).
+			WithArgs(12, 149, PStatusUnapproved).
+			WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+		t.Run(tt.name, func(t *testing.T) {
+			if err := WithdrawEnrollment(12, 149); err != tt.wantErr {
+				t.Errorf("WithdrawEnrollment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApproveEnrollment(t *testing.T) {
+	db, _ := conn.InitDBMock()
+	db.ExpectExec(`^\s*UPDATE\s*p_users_schedules\s*SET(.+)WHERE\s*users_id\s*IN\s*\(\?, \?\)\s*AND\s*schedules_id\s*=\s*\(\?\)\s*AND\s*status\s*=\s*\(\?\);`).
+		WithArgs(PStatusStudent, 12, 13, 149, PStatusUnapproved).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	got, err := ApproveEnrollment([]int64{12, 13}, 149)
+	if err != nil {
+		t.Fatalf("ApproveEnrollment() error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("ApproveEnrollment() = %v, want %v", got, 2)
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetCapacity(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    *sqlmock.Rows
+		err     error
+		want    uint16
+		wantErr bool
+	}{
+		{
+			name:    "Test Case 1",
+			rows:    sqlmock.NewRows([]string{"capacity"}).AddRow(40),
+			want:    40,
+			wantErr: false,
+		},
+		{
+			name:    "Test Case 2",
+			err:     sql.ErrNoRows,
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name:    "Test Case 3",
+			err:     fmt.Errorf("Error connection"),
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		db, _ := conn.InitDBMock()
+		q := db.ExpectQuery(`^\s*SELECT\s*capacity\s*FROM\s*schedules_capacities\s*WHERE\s*schedules_id\s*=\s*\(\?\)\s*LIMIT 1\s*;`).
+			WithArgs(149)
+		if tt.err == nil {
+			q.WillReturnRows(tt.rows)
+		} else {
+			q.WillReturnError(tt.err)
+		}
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetCapacity(149)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetCapacity() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetCapacity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCapacityWithTx(t *testing.T) {
+	db, _ := conn.InitDBMock()
+	db.ExpectBegin()
+	db.ExpectQuery(`^\s*SELECT\s*capacity\s*FROM\s*schedules_capacities\s*WHERE\s*schedules_id\s*=\s*\(\?\)\s*LIMIT 1\s*FOR UPDATE;`).
+		WithArgs(149).
+		WillReturnRows(sqlmock.NewRows([]string{"capacity"}).AddRow(40))
+	db.ExpectRollback()
+
+	tx := conn.DB.MustBegin()
+	got, err := GetCapacity(149, tx)
+	tx.Rollback()
+	if err != nil {
+		t.Fatalf("GetCapacity() error = %v", err)
+	}
+	if got != 40 {
+		t.Errorf("GetCapacity() = %v, want %v", got, 40)
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
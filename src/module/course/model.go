@@ -2,6 +2,7 @@ package course
 
 import (
 	"database/sql"
+	"time"
 )
 
 const (
@@ -23,6 +24,11 @@ const (
 
 	GradeParameterStatusUnchange = 0
 	GradeParameterStatusChange   = 1
+
+	ConflictPlace     = "place"
+	ConflictLecturer  = "lecturer"
+	ConflictAssistant = "assistant"
+	ConflictStudent   = "student"
 )
 
 type Course struct {
@@ -58,3 +64,50 @@ type GradeParameter struct {
 	ScheduleID   int64   `db:"schedules_id"`
 	StatusChange uint8   `db:"status_change"`
 }
+
+// Enrollment is the request of the student to join the schedule
+type Enrollment struct {
+	UserID     int64     `db:"users_id"`
+	ScheduleID int64     `db:"schedules_id"`
+	Status     int8      `db:"status"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// AssistantSchedule is the schedule which is assisted by the user
+type AssistantSchedule struct {
+	UserID     int64  `db:"users_id"`
+	ScheduleID int64  `db:"schedules_id"`
+	CourseName string `db:"name"`
+	Class      string `db:"class"`
+	Semester   int8   `db:"semester"`
+	Year       int16  `db:"year"`
+	Status     int8   `db:"status"`
+}
+
+// Slot is the time range of the schedule in minutes, e.g. 07:00 - 08:40 is 420 - 520
+type Slot struct {
+	StartTime uint16 `db:"start_time"`
+	EndTime   uint16 `db:"end_time"`
+}
+
+// Term is the time of the schedule which is checked for the conflict. The odd semesters run together,
+// so do the even semesters
+type Term struct {
+	Year     int16
+	Semester int8
+	Day      int8
+	Slot     Slot
+}
+
+// Conflict is the active schedule which overlaps the checked term, Type is the reason of the conflict
+type Conflict struct {
+	Type       string `db:"type"`
+	UserID     int64  `db:"users_id"`
+	ScheduleID int64  `db:"id"`
+	CourseName string `db:"name"`
+	Class      string `db:"class"`
+	Day        int8   `db:"day"`
+	StartTime  uint16 `db:"start_time"`
+	EndTime    uint16 `db:"end_time"`
+	PlaceID    string `db:"places_id"`
+}
@@ -1,7 +1,6 @@
 package file
 
 import (
-	"database/sql"
 	"fmt"
 	"strings"
 
@@ -32,8 +31,8 @@ func GetByID(id int64, column ...string) (File, error) {
 	}
 
 	cols := strings.Join(c, ", ")
-	query := fmt.Sprintf(`SELECT %s FROM files WHERE id = (%d) LIMIT 1;`, cols, id)
-	err := conn.DB.Get(&file, query)
+	query := fmt.Sprintf(`SELECT %s FROM files WHERE id = (?) LIMIT 1;`, cols)
+	err := conn.NewQuery(query, id).Get(&file)
 	if err != nil {
 		return file, err
 	}
@@ -61,8 +60,8 @@ func GetByTypeUserID(userID int64, typ string, column ...string) (File, error) {
 	}
 
 	cols := strings.Join(c, ", ")
-	query := fmt.Sprintf(`SELECT %s FROM files WHERE users_id = (%d) AND type = ('%s') AND status = (%d) LIMIT 1;`, cols, userID, typ, StatusExist)
-	err := conn.DB.Get(&file, query)
+	query := fmt.Sprintf(`SELECT %s FROM files WHERE users_id = (?) AND type = (?) AND status = (?) LIMIT 1;`, cols)
+	err := conn.NewQuery(query, userID, typ, StatusExist).Get(&file)
 	if err != nil {
 		return file, err
 	}
@@ -70,22 +69,17 @@ func GetByTypeUserID(userID int64, typ string, column ...string) (File, error) {
 }
 
 func DeleteProfileImage(userID int64, tx *sqlx.Tx) error {
-	query := fmt.Sprintf(`
+	query := `
 		UPDATE
 			files
 		SET
-			status = (%d),
+			status = (?),
 			updated_at = NOW()
 		WHERE
-			users_id = (%d) AND
-			type IN ('%s', '%s');`, StatusDeleted, userID, TypProfPict, TypProfPictThumb)
+			users_id = (?) AND
+			type IN (?, ?);`
 
-	var err error
-	if tx != nil {
-		_, err = tx.Exec(query)
-	} else {
-		_, err = conn.DB.Exec(query)
-	}
+	_, err := conn.NewQuery(query, StatusDeleted, userID, TypProfPict, TypProfPictThumb).WithTx(tx).Exec()
 	if err != nil {
 		return err
 	}
@@ -94,9 +88,7 @@ func DeleteProfileImage(userID int64, tx *sqlx.Tx) error {
 
 func Insert(id, name, mime, extension string, userID int64, typ string, tx *sqlx.Tx) error {
 
-	var result sql.Result
-	var err error
-	query := fmt.Sprintf(`
+	query := `
 		INSERT INTO
 		files (
 			id,
@@ -108,103 +100,66 @@ func Insert(id, name, mime, extension string, userID int64, typ string, tx *sqlx
 			created_at,
 			updated_at
 		) VALUES (
-			('%s'),
-			('%s'),
-			('%s'),
-			('%s'),
-			('%s'),
-			(%d),
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
 			NOW(),
 			NOW()
-		);`, id, name, mime, extension, typ, userID)
+		);`
 
-	if tx != nil {
-		result, err = tx.Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+	_, err := conn.NewQuery(query, id, name, mime, extension, typ, userID).WithTx(tx).ExecAffected()
+	return err
 }
 
 func UpdateRelation(id, tableName, tableID string, tx *sqlx.Tx) error {
 
-	var result sql.Result
-	var err error
-	query := fmt.Sprintf(`
+	query := `
 		UPDATE
 			files
 		SET
-			table_name = ('%s'),
-			table_id = ('%s'),
+			table_name = (?),
+			table_id = (?),
 			updated_at = NOW()
 		WHERE
-			id = ('%s');`, tableName, tableID, id)
+			id = (?);`
 
-	if tx != nil {
-		result, err = tx.Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+	_, err := conn.NewQuery(query, tableName, tableID, id).WithTx(tx).ExecAffected()
+	return err
 }
 
 // UpdateStatusFiles func ...
 func UpdateStatusFiles(id string, status int, tx *sqlx.Tx) error {
 
-	var result sql.Result
-	var err error
-	query := fmt.Sprintf(`
+	query := `
 		UPDATE 
 			files
 		SET
-			status = (%d)
+			status = (?)
 		WHERE
-			id = ('%s')
-		;`, status, id)
+			id = (?)
+		;`
 
-	if tx != nil {
-		result, err = tx.Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+	_, err := conn.NewQuery(query, status, id).WithTx(tx).ExecAffected()
+	return err
 }
 
 // GetByStatus func ...
 func GetByStatus(status int, tableID int64) ([]string, error) {
 
 	var files []string
-	query := fmt.Sprintf(`
+	query := `
 		SELECT 
 			id
 		FROM
 			files
 		WHERE
-			status = (%d) AND table_id = (%d) 
-		;`, status, tableID)
+			status = (?) AND table_id = (?) 
+		;`
 
-	rows, err := conn.DB.Query(query)
+	rows, err := conn.NewQuery(query, status, tableID).Queryx()
 	if err != nil {
 		return files, err
 	}
@@ -223,16 +178,16 @@ func GetByStatus(status int, tableID int64) ([]string, error) {
 func IsIDActive(status int, filesID, tableID string) bool {
 
 	var x string
-	query := fmt.Sprintf(`
+	query := `
 		SELECT 
 			id
 		FROM
 			files
 		WHERE
-			status = (%d) AND id = ('%s') AND table_id =('%s')
-		;`, status, filesID, tableID)
+			status = (?) AND id = (?) AND table_id =(?)
+		;`
 
-	err := conn.DB.Get(&x, query)
+	err := conn.NewQuery(query, status, filesID, tableID).Get(&x)
 	if err != nil {
 		return false
 	}
@@ -242,16 +197,16 @@ func IsIDActive(status int, filesID, tableID string) bool {
 // GetByUserIDTableIDName func ...
 func GetByUserIDTableIDName(UserID, TableID int64, TableName string) ([]File, error) {
 	var files []File
-	query := fmt.Sprintf(`
+	query := `
 		SELECT 
 			id,
 			extension
 		FROM
 			files
 		WHERE
-			users_id = (%d) AND table_name=('%s') AND table_id=(%d)
-		`, UserID, TableName, TableID)
-	rows, err := conn.DB.Query(query)
+			users_id = (?) AND table_name=(?) AND table_id=(?)
+		`
+	rows, err := conn.NewQuery(query, UserID, TableName, TableID).Queryx()
 	if err != nil {
 		return files, err
 	}
@@ -273,45 +228,33 @@ func GetByUserIDTableIDName(UserID, TableID int64, TableName string) ([]File, er
 
 // UpdateStatusFilesByNameID func ...
 func UpdateStatusFilesByNameID(TableName string, Status, TableID int64, tx *sqlx.Tx) error {
-	query := fmt.Sprintf(`
+	query := `
 		UPDATE
 			files
 		SET
-			status=(%d)
+			status=(?)
 		WHERE
-			table_name=('%s') AND table_id=(%d)
-		;`, Status, TableName, TableID)
-	var result sql.Result
-	var err error
-	if tx != nil {
-		result, err = tx.Exec(query)
-	} else {
-		result, err = conn.DB.Exec(query)
-	}
-	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+			table_name=(?) AND table_id=(?)
+		;`
+
+	_, err := conn.NewQuery(query, Status, TableName, TableID).WithTx(tx).ExecAffected()
+	return err
 }
 
 // GetByTableIDName func ...
 func GetByTableIDName(TableID int64, TableName string) ([]File, error) {
 	var files []File
-	query := fmt.Sprintf(`
+	query := `
 		SELECT 
 			id,
 			extension
 		FROM
 			files
 		WHERE
-			table_name=('%s') AND table_id LIKE ('%d%%')
-		`, TableName, TableID)
+			table_name=(?) AND table_id LIKE (?)
+		`
 
-	rows, err := conn.DB.Query(query)
+	rows, err := conn.NewQuery(query, TableName, fmt.Sprintf("%d%%", TableID)).Queryx()
 	if err != nil {
 		return files, err
 	}
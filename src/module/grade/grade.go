@@ -0,0 +1,272 @@
+package grade
+
+import (
+	"database/sql"
+	"math"
+	"sort"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/melodiez14/meiko/src/util/conn"
+)
+
+var (
+	scale = []Scale{}
+	// defaultScale is used when the scale isn't set in the configuration
+	defaultScale = []Scale{
+		{Letter: "A", Minimum: 80},
+		{Letter: "B", Minimum: 68},
+		{Letter: "C", Minimum: 56},
+		{Letter: "D", Minimum: 45},
+		{Letter: "E", Minimum: 0},
+	}
+)
+
+// Init sets the letter grade scale. The scale is sorted from the highest minimum score
+func Init(cfg Config) {
+	scale = cfg.Scale
+	if len(scale) < 1 {
+		scale = defaultScale
+	}
+	sort.SliceStable(scale, func(i, j int) bool {
+		return scale[i].Minimum > scale[j].Minimum
+	})
+}
+
+// Letter converts the final score into the letter grade using the configured scale
+/*
+	@params:
+		score	= float32
+	@example:
+		score	= 79.5
+	@return
+		letter	= B
+*/
+func Letter(score float32) string {
+	s := scale
+	if len(s) < 1 {
+		s = defaultScale
+	}
+	for _, val := range s {
+		if score >= val.Minimum {
+			return val.Letter
+		}
+	}
+	return s[len(s)-1].Letter
+}
+
+// Calculate returns the weighted final score of the graded components. The weight is normalized
+// by the total percentage of the graded components, so the score is always in 0 - 100 range
+/*
+	@params:
+		components	= []Component
+	@example:
+		components	= [{MID, 40, 80, true}, {FINAL, 60, 0, false}]
+	@return
+		score		= 80
+*/
+func Calculate(components []Component) float32 {
+
+	var score, weight float64
+	for _, val := range components {
+		if !val.IsGraded || val.Percentage <= 0 {
+			continue
+		}
+		score += float64(val.Percentage) * float64(val.Score)
+		weight += float64(val.Percentage)
+	}
+
+	if weight <= 0 {
+		return 0
+	}
+
+	score = score / weight
+	if score > MaximumScore {
+		score = MaximumScore
+	}
+	return float32(math.Round(score*100) / 100)
+}
+
+// SelectComponentByUserID returns the achieved score of every grade parameter of the user in the schedule
+func SelectComponentByUserID(scheduleID, userID int64) ([]Component, error) {
+	report, err := selectReport(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	return report.components(userID), nil
+}
+
+// Recompute recalculates the final score of every student in the schedule. It should be called
+// after the grade parameters, the assignment scores, or the attendances of the schedule are changed
+/*
+	@params:
+		scheduleID	= int64
+		tx			= optional *sqlx.Tx
+	@example:
+		scheduleID	= 12
+	@return
+*/
+func Recompute(scheduleID int64, tx ...*sqlx.Tx) error {
+
+	report, err := selectReport(scheduleID, tx...)
+	if err != nil {
+		return err
+	}
+
+	var userIDs []int64
+	err = conn.NewQuery(querySelectStudentID, scheduleID, pStatusStudent).WithTx(tx...).Select(&userIDs)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	// remove the grades of the users who aren't the students anymore
+	_, err = conn.NewQuery(queryDeleteByScheduleID, scheduleID).WithTx(tx...).Exec()
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		score := Calculate(report.components(userID))
+		_, err = conn.NewQuery(queryUpsert, scheduleID, userID, score).WithTx(tx...).Exec()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RecomputeUser recalculates the final score of a student in the schedule
+/*
+	@params:
+		scheduleID	= int64
+		userID		= int64
+		tx			= optional *sqlx.Tx
+	@example:
+		scheduleID	= 12
+		userID		= 4
+	@return
+*/
+func RecomputeUser(scheduleID, userID int64, tx ...*sqlx.Tx) error {
+
+	report, err := selectReport(scheduleID, tx...)
+	if err != nil {
+		return err
+	}
+
+	score := Calculate(report.components(userID))
+	_, err = conn.NewQuery(queryUpsert, scheduleID, userID, score).WithTx(tx...).Exec()
+	return err
+}
+
+// SelectByScheduleID returns the final score of every student in the schedule
+func SelectByScheduleID(scheduleID int64) ([]Grade, error) {
+	var grades []Grade
+	err := conn.NewQuery(querySelectByScheduleID, scheduleID).Select(&grades)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return grades, nil
+}
+
+// SelectByUserID returns the final score of the user for each schedule
+/*
+	@params:
+		userID		= int64
+		scheduleID	= []int64
+	@example:
+		userID		= 4
+		scheduleID	= [1, 2]
+	@return
+		[]{schedules_id, users_id, score}
+*/
+func SelectByUserID(userID int64, scheduleID []int64) ([]Grade, error) {
+	var grades []Grade
+	if len(scheduleID) < 1 {
+		return grades, nil
+	}
+
+	err := conn.NewQuery(querySelectByUserID, userID, scheduleID).Select(&grades)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return grades, nil
+}
+
+// report holds the grade parameters of the schedule and the achievement of the students
+type report struct {
+	parameters   []parameter
+	meetingTotal int64
+	assignment   map[int64]map[int64]float32
+	presence     map[int64]int64
+}
+
+func selectReport(scheduleID int64, tx ...*sqlx.Tx) (report, error) {
+
+	r := report{
+		assignment: map[int64]map[int64]float32{},
+		presence:   map[int64]int64{},
+	}
+
+	err := conn.NewQuery(querySelectParameter, scheduleID).WithTx(tx...).Select(&r.parameters)
+	if err != nil && err != sql.ErrNoRows {
+		return r, err
+	}
+
+	var scores []assignmentScore
+	err = conn.NewQuery(querySelectAssignmentScore, scheduleID).WithTx(tx...).Select(&scores)
+	if err != nil && err != sql.ErrNoRows {
+		return r, err
+	}
+	for _, val := range scores {
+		if _, ok := r.assignment[val.UserID]; !ok {
+			r.assignment[val.UserID] = map[int64]float32{}
+		}
+		r.assignment[val.UserID][val.GradeParameterID] = val.ScoreTotal
+	}
+
+	err = conn.NewQuery(queryCountMeeting, scheduleID).WithTx(tx...).Get(&r.meetingTotal)
+	if err != nil {
+		return r, err
+	}
+
+	var presences []presence
+	err = conn.NewQuery(querySelectPresence, scheduleID, statusPresent).WithTx(tx...).Select(&presences)
+	if err != nil && err != sql.ErrNoRows {
+		return r, err
+	}
+	for _, val := range presences {
+		r.presence[val.UserID] = val.PresentTotal
+	}
+
+	return r, nil
+}
+
+// components returns the score of every grade parameter of the user. The assignment based score is
+// the average of the assignment scores, the missing submission is counted as zero
+func (r report) components(userID int64) []Component {
+
+	components := []Component{}
+	for _, val := range r.parameters {
+		c := Component{
+			Type:       val.Type,
+			Percentage: val.Percentage,
+		}
+
+		switch val.Type {
+		case TypeAttendance:
+			if r.meetingTotal > 0 {
+				c.IsGraded = true
+				c.Score = float32(r.presence[userID]) * MaximumScore / float32(r.meetingTotal)
+			}
+		default:
+			if val.AssignmentTotal > 0 {
+				c.IsGraded = true
+				c.Score = r.assignment[userID][val.ID] / float32(val.AssignmentTotal)
+			}
+		}
+
+		components = append(components, c)
+	}
+
+	return components
+}
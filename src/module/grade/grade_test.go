@@ -0,0 +1,131 @@
+package grade
+
+import "testing"
+
+func TestLetter(t *testing.T) {
+	Init(Config{})
+	cases := []struct {
+		score    float32
+		expected string
+	}{
+		{score: 100, expected: "A"},
+		{score: 80, expected: "A"},
+		{score: 79.99, expected: "B"},
+		{score: 56, expected: "C"},
+		{score: 44.5, expected: "E"},
+		{score: 0, expected: "E"},
+	}
+
+	for _, val := range cases {
+		if got := Letter(val.score); got != val.expected {
+			t.Errorf("Letter(%v) expect: %s got: %s", val.score, val.expected, got)
+		}
+	}
+}
+
+func TestLetterCustomScale(t *testing.T) {
+	Init(Config{
+		Scale: []Scale{
+			{Letter: "C", Minimum: 50},
+			{Letter: "A", Minimum: 85},
+			{Letter: "B", Minimum: 70},
+		},
+	})
+	defer Init(Config{})
+
+	cases := []struct {
+		score    float32
+		expected string
+	}{
+		{score: 90, expected: "A"},
+		{score: 70, expected: "B"},
+		{score: 50, expected: "C"},
+		// lower than the lowest minimum uses the lowest letter
+		{score: 10, expected: "C"},
+	}
+
+	for _, val := range cases {
+		if got := Letter(val.score); got != val.expected {
+			t.Errorf("Letter(%v) expect: %s got: %s", val.score, val.expected, got)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	cases := []struct {
+		name       string
+		components []Component
+		expected   float32
+	}{
+		{
+			name:       "No component",
+			components: []Component{},
+			expected:   0,
+		},
+		{
+			name: "All graded",
+			components: []Component{
+				{Type: TypeAttendance, Percentage: 10, Score: 100, IsGraded: true},
+				{Type: TypeAssignment, Percentage: 20, Score: 75, IsGraded: true},
+				{Type: TypeMid, Percentage: 30, Score: 60, IsGraded: true},
+				{Type: TypeFinal, Percentage: 40, Score: 80, IsGraded: true},
+			},
+			expected: 75,
+		},
+		{
+			name: "Ungraded component is excluded",
+			components: []Component{
+				{Type: TypeMid, Percentage: 40, Score: 80, IsGraded: true},
+				{Type: TypeFinal, Percentage: 60, Score: 0, IsGraded: false},
+			},
+			expected: 80,
+		},
+		{
+			name: "Rounded into two decimals",
+			components: []Component{
+				{Type: TypeQuiz, Percentage: 30, Score: 70, IsGraded: true},
+				{Type: TypeMid, Percentage: 30, Score: 65, IsGraded: true},
+				{Type: TypeFinal, Percentage: 30, Score: 66, IsGraded: true},
+			},
+			expected: 67,
+		},
+	}
+
+	for _, val := range cases {
+		if got := Calculate(val.components); got != val.expected {
+			t.Errorf("%s expect: %v got: %v", val.name, val.expected, got)
+		}
+	}
+}
+
+func TestReportComponents(t *testing.T) {
+	r := report{
+		parameters: []parameter{
+			{ID: 1, Type: TypeAttendance, Percentage: 10},
+			{ID: 2, Type: TypeAssignment, Percentage: 30, AssignmentTotal: 2},
+			{ID: 3, Type: TypeFinal, Percentage: 60},
+		},
+		meetingTotal: 4,
+		assignment: map[int64]map[int64]float32{
+			7: {2: 150},
+		},
+		presence: map[int64]int64{
+			7: 3,
+		},
+	}
+
+	got := r.components(7)
+	expected := []Component{
+		{Type: TypeAttendance, Percentage: 10, Score: 75, IsGraded: true},
+		{Type: TypeAssignment, Percentage: 30, Score: 75, IsGraded: true},
+		{Type: TypeFinal, Percentage: 60, Score: 0, IsGraded: false},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expect: %d components got: %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expect: %v got: %v", expected[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package grade
+
+// grade parameter types, it must be the same as the types stored by the course module
+const (
+	TypeFinal      = "FINAL"
+	TypeMid        = "MID"
+	TypeAssignment = "ASSIGNMENT"
+	TypeAttendance = "ATTENDANCE"
+	TypeQuiz       = "QUIZ"
+
+	// statusPresent is the present status of the attendances table
+	statusPresent = 1
+	// pStatusStudent is the student status of the p_users_schedules table
+	pStatusStudent = 1
+
+	MaximumScore = 100
+)
+
+// Config is used for setting the letter grade scale
+type Config struct {
+	Scale []Scale `json:"scale"`
+}
+
+// Scale is the minimum final score needed for getting the letter grade
+type Scale struct {
+	Letter  string  `json:"letter"`
+	Minimum float32 `json:"minimum"`
+}
+
+type Grade struct {
+	ScheduleID int64   `db:"schedules_id"`
+	UserID     int64   `db:"users_id"`
+	Score      float32 `db:"score"`
+}
+
+// Component is the achieved score of a grade parameter. The component is not graded yet
+// if there is no assignment or meeting for the parameter, so it's excluded from the final score
+type Component struct {
+	Type       string
+	Percentage float32
+	Score      float32
+	IsGraded   bool
+}
+
+type parameter struct {
+	ID              int64   `db:"id"`
+	Type            string  `db:"type"`
+	Percentage      float32 `db:"percentage"`
+	AssignmentTotal int64   `db:"assignment_total"`
+}
+
+type assignmentScore struct {
+	UserID           int64   `db:"users_id"`
+	GradeParameterID int64   `db:"grade_parameters_id"`
+	ScoreTotal       float32 `db:"score_total"`
+}
+
+type presence struct {
+	UserID       int64 `db:"users_id"`
+	PresentTotal int64 `db:"present_total"`
+}
@@ -0,0 +1,133 @@
+package grade
+
+const (
+	querySelectParameter = `
+		SELECT
+			gp.id,
+			gp.type,
+			gp.percentage,
+			COUNT(asg.id) AS assignment_total
+		FROM
+			grade_parameters gp
+		LEFT JOIN
+			assignments asg
+		ON
+			asg.grade_parameters_id = gp.id
+		WHERE
+			gp.schedules_id = (?)
+		GROUP BY
+			gp.id,
+			gp.type,
+			gp.percentage;
+	`
+
+	querySelectAssignmentScore = `
+		SELECT
+			pua.users_id,
+			asg.grade_parameters_id,
+			SUM(pua.score) AS score_total
+		FROM
+			p_users_assignments pua
+		INNER JOIN
+			assignments asg
+		ON
+			asg.id = pua.assignments_id
+		INNER JOIN
+			grade_parameters gp
+		ON
+			gp.id = asg.grade_parameters_id
+		WHERE
+			gp.schedules_id = (?) AND
+			pua.score IS NOT NULL
+		GROUP BY
+			pua.users_id,
+			asg.grade_parameters_id;
+	`
+
+	queryCountMeeting = `
+		SELECT
+			COUNT(id)
+		FROM
+			meetings
+		WHERE
+			schedules_id = (?);
+	`
+
+	querySelectPresence = `
+		SELECT
+			a.users_id,
+			COUNT(a.meetings_id) AS present_total
+		FROM
+			attendances a
+		INNER JOIN
+			meetings m
+		ON
+			m.id = a.meetings_id
+		WHERE
+			m.schedules_id = (?) AND
+			a.status = (?)
+		GROUP BY
+			a.users_id;
+	`
+
+	querySelectStudentID = `
+		SELECT
+			users_id
+		FROM
+			p_users_schedules
+		WHERE
+			schedules_id = (?) AND
+			status = (?);
+	`
+
+	queryDeleteByScheduleID = `
+		DELETE FROM
+			grades
+		WHERE
+			schedules_id = (?);
+	`
+
+	queryUpsert = `
+		INSERT INTO
+			grades (
+				schedules_id,
+				users_id,
+				score,
+				created_at,
+				updated_at
+			)
+		VALUES (
+			(?),
+			(?),
+			(?),
+			NOW(),
+			NOW()
+		)
+		ON DUPLICATE KEY UPDATE
+			score = VALUES(score),
+			updated_at = NOW();
+	`
+
+	querySelectByScheduleID = `
+		SELECT
+			schedules_id,
+			users_id,
+			score
+		FROM
+			grades
+		WHERE
+			schedules_id = (?);
+	`
+
+	querySelectByUserID = `
+		SELECT
+			schedules_id,
+			users_id,
+			score
+		FROM
+			grades
+		WHERE
+			users_id = (?) AND
+			schedules_id IN (?);
+	`
+)
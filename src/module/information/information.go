@@ -5,14 +5,12 @@ import (
 	"strings"
 
 	"github.com/melodiez14/meiko/src/util/conn"
-	"github.com/melodiez14/meiko/src/util/helper"
 )
 
 func SelectByScheduleID(scheduleID []int64, column ...string) ([]Information, error) {
 
 	var info []Information
 	var c []string
-	d := helper.Int64ToStringSlice(scheduleID)
 
 	if len(column) < 1 {
 		c = []string{
@@ -28,7 +26,14 @@ func SelectByScheduleID(scheduleID []int64, column ...string) ([]Information, er
 			c = append(c, val)
 		}
 	}
-	ids := strings.Join(d, ", ")
+	// the general information is always selected, the empty schedule only selects the general one
+	args := []interface{}{}
+	where := "schedules_id IS NULL"
+	if len(scheduleID) > 0 {
+		where = "schedules_id IS NULL OR schedules_id IN (?)"
+		args = append(args, scheduleID)
+	}
+
 	cols := strings.Join(c, ", ")
 	query := fmt.Sprintf(`
 		SELECT
@@ -36,12 +41,10 @@ func SelectByScheduleID(scheduleID []int64, column ...string) ([]Information, er
 		FROM
 			informations
 		WHERE
-			schedules_id IS NULL
-		OR
-			schedules_id IN (%s)
+			%s
 		ORDER BY created_at DESC
-		LIMIT 100`, cols, ids)
-	err := conn.DB.Select(&info, query)
+		LIMIT 100`, cols, where)
+	err := conn.NewQuery(query, args...).Select(&info)
 	if err != nil {
 		return info, err
 	}
@@ -1,9 +1,6 @@
 package log
 
 import (
-	"database/sql"
-	"fmt"
-
 	"github.com/jmoiron/sqlx"
 	"github.com/melodiez14/meiko/src/util/conn"
 )
@@ -11,10 +8,7 @@ import (
 // Insert used for logging the data and inserting the log into bot_logs table
 func Insert(text string, userID int64, status uint8, tx *sqlx.Tx) error {
 
-	var result sql.Result
-	var err error
-
-	query := fmt.Sprintf(`
+	query := `
 		INSERT INTO
 			bot_logs(
 				message,
@@ -22,24 +16,12 @@ func Insert(text string, userID int64, status uint8, tx *sqlx.Tx) error {
 				status,
 				created_at
 			) VALUES (
-				('%s'),
-				(%d),
-				(%d),
+				(?),
+				(?),
+				(?),
 				NOW()
-			);`, text, userID, status)
-
-	if tx != nil {
-		result, err = conn.DB.Exec(query)
-	} else {
-		result, err = tx.Exec(query)
-	}
+			);`
 
-	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+	_, err := conn.NewQuery(query, text, userID, status).WithTx(tx).ExecAffected()
+	return err
 }
@@ -12,3 +12,8 @@ type Notification struct {
 	ReadAt      mysql.NullTime `db:"read_at"`
 	CreatedAt   time.Time      `db:"created_at"`
 }
+
+// the tables which are referred by the notification
+const (
+	TableSchedules = "schedules"
+)
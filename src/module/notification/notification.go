@@ -2,8 +2,8 @@ package notification
 
 import (
 	"database/sql"
-	"fmt"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/melodiez14/meiko/src/util/conn"
 )
 
@@ -12,8 +12,7 @@ func Get(userID int64, page uint16, limit uint8) ([]Notification, error) {
 
 	startRow := uint32(page-1) * uint32(limit)
 
-	query := fmt.Sprintf(queryGet, userID, startRow, limit)
-	err := conn.DB.Select(&notifications, query)
+	err := conn.NewQuery(queryGet, userID, startRow, limit).Select(&notifications)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
@@ -21,6 +20,28 @@ func Get(userID int64, page uint16, limit uint8) ([]Notification, error) {
 	return notifications, nil
 }
 
+// Insert adds the unread notification of the user which refers to the row of the table
+/*
+	@params:
+		userID		= int64
+		name		= string
+		description	= string
+		tableName	= string
+		tableID		= string
+		tx			= optional, *sqlx.Tx
+	@example:
+		userID		= 12
+		name		= Enrollment approved
+		description	= Your enrollment to Sistem Informasi Multimedia A has been approved
+		tableName	= schedules
+		tableID		= 149
+	@return
+*/
+func Insert(userID int64, name, description, tableName, tableID string, tx ...*sqlx.Tx) error {
+	_, err := conn.NewQuery(queryInsert, name, description, tableID, tableName, userID).WithTx(tx...).Exec()
+	return err
+}
+
 func (n Notification) GetURL() string {
 	return "http://URL.com"
 }
@@ -12,8 +12,30 @@ const queryGet = `
 	FROM
 		notifications
 	WHERE
-		users_id = (%d)
+		users_id = (?)
 	ORDER BY
 		created_at DESC
-	LIMIT %d, %d
+	LIMIT ?, ?
+`
+
+const queryInsert = `
+	INSERT INTO
+	notifications (
+		name,
+		descriptions,
+		table_id,
+		table_name,
+		users_id,
+		created_at,
+		updated_at
+	)
+	VALUES (
+		(?),
+		(?),
+		(?),
+		(?),
+		(?),
+		NOW(),
+		NOW()
+	);
 `
@@ -1,12 +1,14 @@
 package rolegroup
 
 const (
-	ModuleUser       = "users"
-	ModuleCourse     = "courses"
-	ModuleRole       = "roles"
-	ModuleAttendance = "attendances"
-	ModuleSchedule   = "schedules"
-	ModuleAssignment = "assignments"
+	ModuleUser        = "users"
+	ModuleCourse      = "courses"
+	ModuleRole        = "roles"
+	ModuleAttendance  = "attendances"
+	ModuleSchedule    = "schedules"
+	ModuleAssignment  = "assignments"
+	ModuleInformation = "informations"
+	ModuleAudit       = "audits"
 
 	RoleCreate  = "CREATE"
 	RoleRead    = "READ"
@@ -19,6 +21,7 @@ const (
 )
 
 type RoleGroup struct {
-	ID   int64  `db:"id"`
-	Name string `db:"name"`
+	ID                  int64  `db:"id"`
+	Name                string `db:"name"`
+	IsTwoFactorRequired bool   `db:"is_2fa_required"`
 }
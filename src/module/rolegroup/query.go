@@ -1,17 +1,43 @@
 package rolegroup
 
 const (
-	getQuery = `
+	querySelectByPage = `
 		SELECT
 			id,
-			name
+			name,
+			is_2fa_required
 		FROM
 			rolegroups
-		LIMIT %d
-		OFFSET %d
+		ORDER BY
+			id ASC
+		LIMIT ?
+		OFFSET ?;
 	`
 
-	insertQuery = `
+	queryGet = `
+		SELECT
+			id,
+			name,
+			is_2fa_required
+		FROM
+			rolegroups
+		WHERE
+			id = (?)
+		LIMIT 1;
+	`
+
+	queryIsNameExist = `
+		SELECT
+			'x'
+		FROM
+			rolegroups
+		WHERE
+			name = (?) AND
+			id != (?)
+		LIMIT 1;
+	`
+
+	queryInsert = `
 		INSERT INTO
 			rolegroups(
 				name,
@@ -19,19 +45,91 @@ const (
 				updated_at
 			)
 		VALUES (
-			('%s'),
+			(?),
 			NOW(),
 			NOW()
-		)
+		);
+	`
+
+	queryUpdate = `
+		UPDATE
+			rolegroups
+		SET
+			name = (?),
+			updated_at = NOW()
+		WHERE
+			id = (?);
+	`
+
+	queryDelete = `
+		DELETE FROM
+			rolegroups
+		WHERE
+			id = (?);
+	`
+
+	queryDeleteModuleAccess = `
+		DELETE FROM
+			rolegroups_modules
+		WHERE
+			rolegroups_id = (?);
+	`
+
+	queryGrantModuleAccess = `
+		INSERT IGNORE INTO
+			rolegroups_modules(
+				rolegroups_id,
+				modules,
+				ability,
+				created_at,
+				updated_at
+			)
+		VALUES (
+			(?),
+			(?),
+			(?),
+			NOW(),
+			NOW()
+		);
+	`
+
+	queryRevokeModuleAccess = `
+		DELETE FROM
+			rolegroups_modules
+		WHERE
+			rolegroups_id = (?) AND
+			modules = (?) AND
+			ability IN (?);
+	`
+
+	queryIsExist = `
+		SELECT
+			'x'
+		FROM
+			rolegroups
+		WHERE
+			id = (?)
+		LIMIT 1;
+	`
+
+	queryIsTwoFactorRequired = `
+		SELECT
+			is_2fa_required
+		FROM
+			rolegroups
+		WHERE
+			id = (?)
+		LIMIT 1;
 	`
 
-	updateQuery = `
+	queryUpdateTwoFactorRequired = `
 		UPDATE
 			rolegroups
 		SET
-			name = ('%s')
+			is_2fa_required = (?),
+			updated_at = NOW()
 		WHERE
-			id = (%d)
+			id = (?);
 	`
 
 	queryGetModuleAccess = `
@@ -41,6 +139,6 @@ const (
 		FROM
 			rolegroups_modules
 		WHERE
-			rolegroups_id = (%d)
+			rolegroups_id = (?)
 	`
 )
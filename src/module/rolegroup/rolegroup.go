@@ -1,16 +1,24 @@
 package rolegroup
 
 import (
-	"fmt"
-	"log"
-
+	"github.com/jmoiron/sqlx"
 	"github.com/melodiez14/meiko/src/util/conn"
 )
 
-func GetByPage(page, offset uint16) ([]RoleGroup, error) {
+// SelectByPage returns the rolegroups ordered by id
+/*
+	@params:
+		limit	= uint16
+		offset	= uint16
+	@example:
+		limit	= 10
+		offset	= 0
+	@return
+		[]{id, name, is_2fa_required}
+*/
+func SelectByPage(limit, offset uint16) ([]RoleGroup, error) {
 	rolegroups := []RoleGroup{}
-	query := fmt.Sprintf(getQuery, page, offset)
-	err := conn.DB.Select(&rolegroups, query)
+	err := conn.NewQuery(querySelectByPage, limit, offset).Select(&rolegroups)
 	if err != nil {
 		return nil, err
 	}
@@ -18,30 +26,157 @@ func GetByPage(page, offset uint16) ([]RoleGroup, error) {
 	return rolegroups, nil
 }
 
-func Insert(name string) error {
-	query := fmt.Sprintf(insertQuery, name)
-	_, err := conn.DB.Exec(query)
+// Get returns the rolegroup by id, it returns sql.ErrNoRows if the rolegroup doesn't exist
+/*
+	@params:
+		id	= int64
+	@example:
+		id	= 2
+	@return
+		{id, name, is_2fa_required}
+*/
+func Get(id int64) (RoleGroup, error) {
+	var rolegroup RoleGroup
+	err := conn.NewQuery(queryGet, id).Get(&rolegroup)
 	if err != nil {
-		return err
+		return RoleGroup{}, err
 	}
-	return nil
+	return rolegroup, nil
 }
 
-func Update(id int64, name string) error {
-	query := fmt.Sprintf(updateQuery, name, id)
-	_, err := conn.DB.Exec(query)
+// IsNameExist checks whether the name is used by the other rolegroup
+/*
+	@params:
+		name		= string
+		exceptID	= int64
+	@example:
+		name		= Lecturer
+		exceptID	= 0
+	@return
+		true/false
+*/
+func IsNameExist(name string, exceptID int64) bool {
+	var x string
+	err := conn.NewQuery(queryIsNameExist, name, exceptID).Get(&x)
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+// Insert creates the rolegroup without any privilege and returns its id
+/*
+	@params:
+		name	= string
+		tx		= optional *sqlx.Tx
+	@example:
+		name	= Lecturer
+		tx		= nil
+	@return
+		id	= 3
+*/
+func Insert(name string, tx ...*sqlx.Tx) (int64, error) {
+	result, err := conn.NewQuery(queryInsert, name).WithTx(tx...).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// Update changes the name of the rolegroup
+/*
+	@params:
+		id		= int64
+		name	= string
+		tx		= optional *sqlx.Tx
+	@example:
+		id		= 2
+		name	= Lecturer
+		tx		= nil
+	@return
+*/
+func Update(id int64, name string, tx ...*sqlx.Tx) error {
+	_, err := conn.NewQuery(queryUpdate, name, id).WithTx(tx...).Exec()
+	return err
+}
+
+// Delete removes the rolegroup and its privileges, it returns conn.ErrNoRowsAffected if the rolegroup doesn't exist.
+// The members of the rolegroup must be unassigned before
+/*
+	@params:
+		id	= int64
+		tx	= optional *sqlx.Tx
+	@example:
+		id	= 3
+		tx	= nil
+	@return
+*/
+func Delete(id int64, tx ...*sqlx.Tx) error {
+	_, err := conn.NewQuery(queryDeleteModuleAccess, id).WithTx(tx...).Exec()
 	if err != nil {
 		return err
 	}
+
+	_, err = conn.NewQuery(queryDelete, id).WithTx(tx...).ExecAffected()
+	return err
+}
+
+// GrantModuleAccess gives the abilities of the module to the rolegroup, the granted abilities are ignored
+/*
+	@params:
+		id			= int64
+		module		= string
+		abilities	= []string
+		tx			= optional *sqlx.Tx
+	@example:
+		id			= 2
+		module		= courses
+		abilities	= [XREAD, XUPDATE]
+		tx			= nil
+	@return
+*/
+func GrantModuleAccess(id int64, module string, abilities []string, tx ...*sqlx.Tx) error {
+	for _, ability := range abilities {
+		_, err := conn.NewQuery(queryGrantModuleAccess, id, module, ability).WithTx(tx...).Exec()
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// RevokeModuleAccess removes the abilities of the module from the rolegroup
+/*
+	@params:
+		id			= int64
+		module		= string
+		abilities	= []string
+		tx			= optional *sqlx.Tx
+	@example:
+		id			= 2
+		module		= courses
+		abilities	= [XUPDATE]
+		tx			= nil
+	@return
+*/
+func RevokeModuleAccess(id int64, module string, abilities []string, tx ...*sqlx.Tx) error {
+	if len(abilities) < 1 {
+		return nil
+	}
+	_, err := conn.NewQuery(queryRevokeModuleAccess, id, module, abilities).WithTx(tx...).Exec()
+	return err
+}
+
 func GetModuleList() []string {
 	return []string{
 		ModuleUser,
 		ModuleCourse,
 		ModuleRole,
 		ModuleAttendance,
+		ModuleSchedule,
+		ModuleAssignment,
+		ModuleInformation,
+		ModuleAudit,
 	}
 }
 
@@ -58,24 +193,90 @@ func GetRoleList() []string {
 	}
 }
 
-func GetModuleAccess(id int64) map[string][]string {
+// GetModuleAccess returns the abilities of every module granted to the rolegroup. The privilege is never returned
+// partially, any error returns nil so the caller can't mistake the failure for the rolegroup without privilege
+/*
+	@params:
+		id	= int64
+	@example:
+		id	= 2
+	@return
+		{"courses": ["READ", "XREAD"]}
+*/
+func GetModuleAccess(id int64) (map[string][]string, error) {
 
 	var module string
 	var ability string
 
 	privilege := make(map[string][]string)
-	query := fmt.Sprintf(queryGetModuleAccess, id)
-	rows, err := conn.DB.Query(query)
+	rows, err := conn.NewQuery(queryGetModuleAccess, id).Queryx()
 	if err != nil {
-		return privilege
+		return nil, err
 	}
 	defer rows.Close()
 	for rows.Next() {
 		if err := rows.Scan(&module, &ability); err != nil {
-			log.Fatalln(err.Error())
+			return nil, err
 		}
 		privilege[module] = append(privilege[module], ability)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return privilege, nil
+}
+
+// IsTwoFactorRequired returns whether the members of the rolegroup must sign in using the two factor
+/*
+	@params:
+		id	= int64
+	@example:
+		id	= 2
+	@return
+		true/false
+*/
+func IsTwoFactorRequired(id int64) bool {
+	var isRequired bool
+	err := conn.NewQuery(queryIsTwoFactorRequired, id).Get(&isRequired)
+	if err != nil {
+		return false
+	}
+	return isRequired
+}
+
+// IsExist checks whether the rolegroup exists
+/*
+	@params:
+		id	= int64
+	@example:
+		id	= 2
+	@return
+		true/false
+*/
+func IsExist(id int64) bool {
+	var x string
+	err := conn.NewQuery(queryIsExist, id).Get(&x)
+	if err != nil {
+		return false
+	}
+	return true
+}
 
-	return privilege
+// UpdateTwoFactorRequired makes the two factor mandatory or optional for the members of the rolegroup,
+// it returns conn.ErrNoRowsAffected if the rolegroup doesn't exist or the value isn't changed
+/*
+	@params:
+		id			= int64
+		isRequired	= bool
+		tx			= optional *sqlx.Tx
+	@example:
+		id			= 2
+		isRequired	= true
+		tx			= nil
+	@return
+*/
+func UpdateTwoFactorRequired(id int64, isRequired bool, tx ...*sqlx.Tx) error {
+	_, err := conn.NewQuery(queryUpdateTwoFactorRequired, isRequired, id).WithTx(tx...).ExecAffected()
+	return err
 }
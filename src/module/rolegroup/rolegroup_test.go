@@ -0,0 +1,122 @@
+package rolegroup
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/melodiez14/meiko/src/util/conn"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		wantErr      error
+	}{
+		{
+			name:         "Test Case 1",
+			rowsAffected: 1,
+			wantErr:      nil,
+		},
+		{
+			name:         "Test Case 2",
+			rowsAffected: 0,
+			wantErr:      conn.ErrNoRowsAffected,
+		},
+	}
+	for _, tt := range tests {
+		db, _ := conn.InitDBMock()
+		db.ExpectExec(`^\s*DELETE(\s*)FROM(\s*)rolegroups_modules(\s*)WHERE(\s*)rolegroups_id`).
+			WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		db.ExpectExec(`^\s*DELETE(\s*)FROM(\s*)rolegroups(\s*)WHERE(\s*)id`).
+			WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Delete(3); err != tt.wantErr {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := db.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestModuleAccess(t *testing.T) {
+	db, _ := conn.InitDBMock()
+	db.ExpectExec(`^\s*INSERT(\s*)IGNORE(\s*)INTO(\s*)rolegroups_modules`).
+		WithArgs(2, ModuleCourse, RoleXRead).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	db.ExpectExec(`^\s*INSERT(\s*)IGNORE(\s*)INTO(\s*)rolegroups_modules`).
+		WithArgs(2, ModuleCourse, RoleXUpdate).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	db.ExpectExec(`^\s*DELETE(\s*)FROM(\s*)rolegroups_modules(.+)ability(\s*)IN`).
+		WithArgs(2, ModuleCourse, RoleXRead, RoleXUpdate).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if err := GrantModuleAccess(2, ModuleCourse, []string{RoleXRead, RoleXUpdate}); err != nil {
+		t.Errorf("GrantModuleAccess() error = %v", err)
+	}
+	if err := RevokeModuleAccess(2, ModuleCourse, []string{RoleXRead, RoleXUpdate}); err != nil {
+		t.Errorf("RevokeModuleAccess() error = %v", err)
+	}
+	if err := RevokeModuleAccess(2, ModuleCourse, []string{}); err != nil {
+		t.Errorf("RevokeModuleAccess() error = %v", err)
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetModuleAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     *sqlmock.Rows
+		queryErr error
+		want     map[string][]string
+		wantErr  bool
+	}{
+		{
+			name: "Test Case 1",
+			rows: sqlmock.NewRows([]string{"modules", "ability"}).
+				AddRow(ModuleCourse, RoleRead).
+				AddRow(ModuleCourse, RoleXRead),
+			want: map[string][]string{ModuleCourse: {RoleRead, RoleXRead}},
+		},
+		{
+			name:     "Test Case 2",
+			queryErr: sql.ErrConnDone,
+			wantErr:  true,
+		},
+		{
+			name: "Test Case 3",
+			rows: sqlmock.NewRows([]string{"modules", "ability"}).
+				AddRow(ModuleCourse, RoleRead).
+				RowError(0, sql.ErrConnDone),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		db, _ := conn.InitDBMock()
+		query := db.ExpectQuery(`^\s*SELECT(.+)FROM(\s*)rolegroups_modules`).WithArgs(2)
+		if tt.queryErr != nil {
+			query.WillReturnError(tt.queryErr)
+		} else {
+			query.WillReturnRows(tt.rows)
+		}
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetModuleAccess(2)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetModuleAccess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetModuleAccess() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package timetable
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/melodiez14/meiko/src/util/conn"
+)
+
+// SaveDraft stores the draft for draftTimeout and returns its id
+/*
+	@params:
+		draft	= Draft
+	@example:
+		draft	= {UserID: 12, Year: 2017, Semester: 5, Schedules: [...]}
+	@return
+		id	= Zx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq
+*/
+func SaveDraft(draft Draft) (string, error) {
+
+	b := make([]byte, draftIDLength)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	draft.ID = base64.RawURLEncoding.EncodeToString(b)
+	draft.CreatedAt = time.Now()
+	data, err := json.Marshal(draft)
+	if err != nil {
+		return "", err
+	}
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	_, err = client.Do("SET", draftPrefix+draft.ID, data, "EX", draftTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	return draft.ID, nil
+}
+
+// GetDraft returns the stored draft, it returns ErrDraftNotFound if the draft is expired or committed
+/*
+	@params:
+		id	= string
+	@example:
+		id	= Zx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq
+	@return
+		{id, users_id, year, semester, schedules, unscheduled, created_at}
+*/
+func GetDraft(id string) (Draft, error) {
+
+	var draft Draft
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return draft, ErrDraftNotFound
+	}
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	data, err := redis.Bytes(client.Do("GET", draftPrefix+id))
+	if err == redis.ErrNil {
+		return draft, ErrDraftNotFound
+	}
+	if err != nil {
+		return draft, err
+	}
+
+	err = json.Unmarshal(data, &draft)
+	if err != nil {
+		return draft, err
+	}
+
+	return draft, nil
+}
+
+// DeleteDraft removes the committed draft. It returns ErrDraftNotFound if the draft has been removed by the
+// concurrent request so the draft can't be committed twice
+func DeleteDraft(id string) error {
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	deleted, err := redis.Int64(client.Do("DEL", draftPrefix+id))
+	if err != nil {
+		return err
+	}
+	if deleted < 1 {
+		return ErrDraftNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package timetable
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/rafaeljusto/redigomock"
+)
+
+func initRedisMock() *redigomock.Conn {
+	mock := redigomock.NewConn()
+	conn.Redis = &redis.Pool{
+		MaxIdle:     3,
+		IdleTimeout: 10 * time.Second,
+		Dial:        func() (redis.Conn, error) { return mock, nil },
+	}
+	return mock
+}
+
+func TestGetDraft(t *testing.T) {
+	draft := Draft{
+		ID:       "abc",
+		UserID:   12,
+		Year:     2017,
+		Semester: 5,
+		Schedules: []Schedule{
+			{CourseID: "D10K-7D02", Class: "A", Day: 1, StartTime: 420, EndTime: 520, PlaceID: "UDJT-102"},
+		},
+	}
+	data, _ := json.Marshal(draft)
+
+	tests := []struct {
+		name    string
+		id      string
+		reply   interface{}
+		wantErr error
+	}{
+		{
+			name:    "Test Case 1",
+			id:      "abc",
+			reply:   data,
+			wantErr: nil,
+		},
+		{
+			name:    "Test Case 2",
+			id:      "expired",
+			reply:   nil,
+			wantErr: ErrDraftNotFound,
+		},
+		{
+			name:    "Test Case 3",
+			id:      " ",
+			wantErr: ErrDraftNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := initRedisMock()
+			mock.Command("GET", draftPrefix+tt.id).Expect(tt.reply)
+
+			got, err := GetDraft(tt.id)
+			if err != tt.wantErr {
+				t.Errorf("GetDraft() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (got.UserID != draft.UserID || len(got.Schedules) != 1) {
+				t.Errorf("GetDraft() = %v, want %v", got, draft)
+			}
+		})
+	}
+}
+
+func TestDeleteDraft(t *testing.T) {
+	tests := []struct {
+		name    string
+		deleted int64
+		wantErr error
+	}{
+		{name: "Test Case 1", deleted: 1, wantErr: nil},
+		{name: "Test Case 2", deleted: 0, wantErr: ErrDraftNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := initRedisMock()
+			mock.Command("DEL", draftPrefix+"abc").Expect(tt.deleted)
+			if err := DeleteDraft("abc"); err != tt.wantErr {
+				t.Errorf("DeleteDraft() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
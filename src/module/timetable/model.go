@@ -0,0 +1,76 @@
+package timetable
+
+import (
+	"errors"
+	"time"
+
+	cs "github.com/melodiez14/meiko/src/module/course"
+)
+
+const (
+	draftPrefix = "timetable:draft:"
+	// draftTimeout is the lifetime in seconds of the draft which isn't committed
+	draftTimeout  = 24 * 60 * 60
+	draftIDLength = 24
+	// maxSteps limits the backtracking of the solver, the greedy result is used when it's exceeded
+	maxSteps = 20000
+)
+
+// ErrDraftNotFound is returned when the draft doesn't exist or it's expired
+var ErrDraftNotFound = errors.New("Draft is not found or expired")
+
+// Course is the course whose classes are scheduled, every class is assisted by the assistants
+type Course struct {
+	ID         string
+	Classes    []string
+	Duration   uint16
+	Assistants []int64
+}
+
+// Availability is the time when the assistant is able to assist
+type Availability struct {
+	Day  int8
+	Slot cs.Slot
+}
+
+// Request is the input of the solver. The classes are placed in the windows of the days, the start time is moved by
+// the step. The assistant without availability is able to assist anytime. The busy places and users are the slots of
+// the existing schedules mapped by the day
+type Request struct {
+	Courses      []Course
+	Places       []string
+	Days         []int8
+	Windows      []cs.Slot
+	Step         uint16
+	Availability map[int64][]Availability
+	BusyPlaces   map[string]map[int8][]cs.Slot
+	BusyUsers    map[int64]map[int8][]cs.Slot
+}
+
+// Schedule is the proposed schedule of the class
+type Schedule struct {
+	CourseID   string  `json:"course_id"`
+	Class      string  `json:"class"`
+	Day        int8    `json:"day"`
+	StartTime  uint16  `json:"start_time"`
+	EndTime    uint16  `json:"end_time"`
+	PlaceID    string  `json:"place_id"`
+	Assistants []int64 `json:"assistants"`
+}
+
+// Unscheduled is the class which can't be placed without conflict
+type Unscheduled struct {
+	CourseID string `json:"course_id"`
+	Class    string `json:"class"`
+}
+
+// Draft is the result of the solver which is reviewed before it's committed by its creator
+type Draft struct {
+	ID          string        `json:"id"`
+	UserID      int64         `json:"users_id"`
+	Year        int16         `json:"year"`
+	Semester    int8          `json:"semester"`
+	Schedules   []Schedule    `json:"schedules"`
+	Unscheduled []Unscheduled `json:"unscheduled"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
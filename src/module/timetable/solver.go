@@ -0,0 +1,294 @@
+package timetable
+
+import (
+	"sort"
+
+	cs "github.com/melodiez14/meiko/src/module/course"
+)
+
+// unit is the class which needs a slot, the candidates are grouped by the day
+type unit struct {
+	index      int
+	course     Course
+	class      string
+	candidates map[int8][]candidate
+	total      int
+}
+
+type candidate struct {
+	day   int8
+	slot  cs.Slot
+	place string
+}
+
+// solver keeps the slots used by the proposed schedules
+type solver struct {
+	req     Request
+	days    []int8
+	units   []*unit
+	places  map[string]map[int8][]cs.Slot
+	users   map[int64]map[int8][]cs.Slot
+	daily   map[int8]int
+	result  []*candidate
+	steps   int
+	aborted bool
+}
+
+// Solve proposes the schedules of the classes which don't use the same place or assistant at the same time, neither
+// conflict with the existing schedules. The classes with the fewest candidates are placed first and the days with
+// the fewest classes are tried first, so the classes are spread over the days. The classes which can't be placed
+// are returned as unscheduled
+/*
+	@params:
+		req	= Request
+	@example:
+		req	= {Courses: [{D10K-7D02, [A, B], 100, [12]}], Places: [UDJT-102], Days: [1, 2], Windows: [{420, 720}], Step: 30}
+	@return
+		[]{course_id, class, day, start_time, end_time, place_id, assistants}, []{course_id, class}
+*/
+func Solve(req Request) ([]Schedule, []Unscheduled) {
+
+	s := newSolver(req)
+	if !s.search(0) {
+		s.greedy()
+	}
+
+	schedules := []Schedule{}
+	unscheduled := []Unscheduled{}
+	sorted := make([]int, len(s.units))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return s.units[sorted[i]].index < s.units[sorted[j]].index
+	})
+
+	for _, i := range sorted {
+		u := s.units[i]
+		c := s.result[i]
+		if c == nil {
+			unscheduled = append(unscheduled, Unscheduled{
+				CourseID: u.course.ID,
+				Class:    u.class,
+			})
+			continue
+		}
+		schedules = append(schedules, Schedule{
+			CourseID:   u.course.ID,
+			Class:      u.class,
+			Day:        c.day,
+			StartTime:  c.slot.StartTime,
+			EndTime:    c.slot.EndTime,
+			PlaceID:    c.place,
+			Assistants: u.course.Assistants,
+		})
+	}
+	return schedules, unscheduled
+}
+
+func newSolver(req Request) *solver {
+
+	if req.Step < 1 {
+		req.Step = 1
+	}
+
+	s := &solver{
+		req:    req,
+		places: map[string]map[int8][]cs.Slot{},
+		users:  map[int64]map[int8][]cs.Slot{},
+		daily:  map[int8]int{},
+	}
+
+	for _, val := range req.Days {
+		if _, ok := s.daily[val]; !ok {
+			s.daily[val] = 0
+			s.days = append(s.days, val)
+		}
+	}
+
+	index := 0
+	for _, course := range req.Courses {
+		for _, class := range course.Classes {
+			u := &unit{
+				index:      index,
+				course:     course,
+				class:      class,
+				candidates: map[int8][]candidate{},
+			}
+			s.addCandidates(u)
+			s.units = append(s.units, u)
+			index++
+		}
+	}
+
+	// the most constrained classes are placed first
+	sort.SliceStable(s.units, func(i, j int) bool {
+		return s.units[i].total < s.units[j].total
+	})
+	s.result = make([]*candidate, len(s.units))
+	return s
+}
+
+// addCandidates adds the slots of the unit which don't conflict with the existing schedules
+func (s *solver) addCandidates(u *unit) {
+	duration := u.course.Duration
+	for _, day := range s.days {
+		for _, window := range s.req.Windows {
+			for start := window.StartTime; start+duration <= window.EndTime; start += s.req.Step {
+				slot := cs.Slot{StartTime: start, EndTime: start + duration}
+				if !s.isAssistantFree(u.course.Assistants, day, slot, true) {
+					continue
+				}
+				for _, place := range s.req.Places {
+					if isBusy(s.req.BusyPlaces[place][day], slot) {
+						continue
+					}
+					u.candidates[day] = append(u.candidates[day], candidate{
+						day:   day,
+						slot:  slot,
+						place: place,
+					})
+					u.total++
+				}
+			}
+		}
+	}
+}
+
+// isAssistantFree checks the availability and the existing schedules of the assistants if isStatic, otherwise
+// it checks the proposed schedules
+func (s *solver) isAssistantFree(assistants []int64, day int8, slot cs.Slot, isStatic bool) bool {
+	for _, val := range assistants {
+		if isStatic {
+			if !s.isAvailable(val, day, slot) || isBusy(s.req.BusyUsers[val][day], slot) {
+				return false
+			}
+			continue
+		}
+		if isBusy(s.users[val][day], slot) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *solver) isAvailable(userID int64, day int8, slot cs.Slot) bool {
+	availability, ok := s.req.Availability[userID]
+	if !ok || len(availability) < 1 {
+		return true
+	}
+	for _, val := range availability {
+		if val.Day == day && val.Slot.StartTime <= slot.StartTime && slot.EndTime <= val.Slot.EndTime {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *solver) fits(u *unit, c candidate) bool {
+	return !isBusy(s.places[c.place][c.day], c.slot) && s.isAssistantFree(u.course.Assistants, c.day, c.slot, false)
+}
+
+func (s *solver) place(i int, c *candidate) {
+	u := s.units[i]
+	if _, ok := s.places[c.place]; !ok {
+		s.places[c.place] = map[int8][]cs.Slot{}
+	}
+	s.places[c.place][c.day] = append(s.places[c.place][c.day], c.slot)
+	for _, val := range u.course.Assistants {
+		if _, ok := s.users[val]; !ok {
+			s.users[val] = map[int8][]cs.Slot{}
+		}
+		s.users[val][c.day] = append(s.users[val][c.day], c.slot)
+	}
+	s.daily[c.day]++
+	s.result[i] = c
+}
+
+// remove removes the last placed candidate, the slots are removed from the end since they're placed last
+func (s *solver) remove(i int) {
+	u := s.units[i]
+	c := s.result[i]
+	slots := s.places[c.place][c.day]
+	s.places[c.place][c.day] = slots[:len(slots)-1]
+	for _, val := range u.course.Assistants {
+		slots := s.users[val][c.day]
+		s.users[val][c.day] = slots[:len(slots)-1]
+	}
+	s.daily[c.day]--
+	s.result[i] = nil
+}
+
+// orderedDays returns the days from the day which has the fewest proposed schedules
+func (s *solver) orderedDays() []int8 {
+	days := make([]int8, len(s.days))
+	copy(days, s.days)
+	sort.SliceStable(days, func(i, j int) bool {
+		return s.daily[days[i]] < s.daily[days[j]]
+	})
+	return days
+}
+
+// search places the units from the i-th unit using the backtracking
+func (s *solver) search(i int) bool {
+	if i == len(s.units) {
+		return true
+	}
+
+	s.steps++
+	if s.steps > maxSteps {
+		s.aborted = true
+		return false
+	}
+
+	u := s.units[i]
+	for _, day := range s.orderedDays() {
+		for j := range u.candidates[day] {
+			c := &u.candidates[day][j]
+			if !s.fits(u, *c) {
+				continue
+			}
+			s.place(i, c)
+			if s.search(i + 1) {
+				return true
+			}
+			s.remove(i)
+			if s.aborted {
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// greedy places every unit into its first fitting candidate and leaves the unit which doesn't fit
+func (s *solver) greedy() {
+	for i := range s.units {
+		if s.result[i] != nil {
+			s.remove(i)
+		}
+	}
+
+	for i, u := range s.units {
+	next:
+		for _, day := range s.orderedDays() {
+			for j := range u.candidates[day] {
+				c := &u.candidates[day][j]
+				if s.fits(u, *c) {
+					s.place(i, c)
+					break next
+				}
+			}
+		}
+	}
+}
+
+// isBusy checks whether the slot overlaps any of the busy slots
+func isBusy(busy []cs.Slot, slot cs.Slot) bool {
+	for _, val := range busy {
+		if val.IsOverlap(slot) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package timetable
+
+import (
+	"testing"
+
+	cs "github.com/melodiez14/meiko/src/module/course"
+)
+
+// isConflictFree checks that no schedules use the same place or assistant at the same time
+func isConflictFree(schedules []Schedule) bool {
+	for i, a := range schedules {
+		for _, b := range schedules[i+1:] {
+			if a.Day != b.Day {
+				continue
+			}
+			slotA := cs.Slot{StartTime: a.StartTime, EndTime: a.EndTime}
+			slotB := cs.Slot{StartTime: b.StartTime, EndTime: b.EndTime}
+			if !slotA.IsOverlap(slotB) {
+				continue
+			}
+			if a.PlaceID == b.PlaceID {
+				return false
+			}
+			for _, x := range a.Assistants {
+				for _, y := range b.Assistants {
+					if x == y {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+func TestSolve(t *testing.T) {
+	window := []cs.Slot{{StartTime: 420, EndTime: 720}}
+	tests := []struct {
+		name            string
+		req             Request
+		wantScheduled   int
+		wantUnscheduled int
+	}{
+		{
+			name: "Test Case 1",
+			req: Request{
+				Courses: []Course{
+					{ID: "D10K-7D02", Classes: []string{"A", "B"}, Duration: 150, Assistants: []int64{1}},
+					{ID: "D10K-7D03", Classes: []string{"A"}, Duration: 150, Assistants: []int64{1}},
+				},
+				Places:  []string{"UDJT-102"},
+				Days:    []int8{1},
+				Windows: window,
+				Step:    30,
+			},
+			wantScheduled:   2,
+			wantUnscheduled: 1,
+		},
+		{
+			name: "Test Case 2",
+			req: Request{
+				Courses: []Course{
+					{ID: "D10K-7D02", Classes: []string{"A", "B"}, Duration: 100, Assistants: []int64{1}},
+					{ID: "D10K-7D03", Classes: []string{"A", "B"}, Duration: 100, Assistants: []int64{2}},
+				},
+				Places:  []string{"UDJT-102", "UDJT-103"},
+				Days:    []int8{1, 2},
+				Windows: window,
+				Step:    30,
+				Availability: map[int64][]Availability{
+					2: {{Day: 2, Slot: cs.Slot{StartTime: 420, EndTime: 720}}},
+				},
+				BusyPlaces: map[string]map[int8][]cs.Slot{
+					"UDJT-103": {2: {{StartTime: 420, EndTime: 720}}},
+				},
+			},
+			wantScheduled:   4,
+			wantUnscheduled: 0,
+		},
+		{
+			name: "Test Case 3",
+			req: Request{
+				Courses: []Course{
+					{ID: "D10K-7D02", Classes: []string{"A"}, Duration: 100, Assistants: []int64{1}},
+				},
+				Places:    []string{"UDJT-102"},
+				Days:      []int8{1},
+				Windows:   window,
+				Step:      30,
+				BusyUsers: map[int64]map[int8][]cs.Slot{1: {1: {{StartTime: 420, EndTime: 720}}}},
+			},
+			wantScheduled:   0,
+			wantUnscheduled: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedules, unscheduled := Solve(tt.req)
+			if len(schedules) != tt.wantScheduled || len(unscheduled) != tt.wantUnscheduled {
+				t.Errorf("Solve() = %v, %v, want %d scheduled and %d unscheduled", schedules, unscheduled, tt.wantScheduled, tt.wantUnscheduled)
+				return
+			}
+			if !isConflictFree(schedules) {
+				t.Errorf("Solve() = %v, the schedules conflict", schedules)
+			}
+			for _, val := range schedules {
+				for _, a := range val.Assistants {
+					if a == 2 && val.Day != 2 {
+						t.Errorf("Solve() = %v, the assistant isn't available", val)
+					}
+				}
+				if val.PlaceID == "UDJT-103" && val.Day == 2 {
+					t.Errorf("Solve() = %v, the place is used", val)
+				}
+			}
+		})
+	}
+}
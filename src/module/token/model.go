@@ -0,0 +1,25 @@
+package token
+
+import (
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	// MaxTokenPerUser is the maximum number of the api tokens owned by a user
+	MaxTokenPerUser = 20
+)
+
+// Token is the personal api token. Only the hash of the token is stored, the plain token
+// is shown once when it's created
+type Token struct {
+	ID         int64          `db:"id"`
+	UserID     int64          `db:"users_id"`
+	Name       string         `db:"name"`
+	Prefix     string         `db:"prefix"`
+	Scopes     string         `db:"scopes"`
+	ExpiresAt  mysql.NullTime `db:"expires_at"`
+	LastUsedAt mysql.NullTime `db:"last_used_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
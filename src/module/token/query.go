@@ -0,0 +1,80 @@
+package token
+
+const (
+	querySelectByUserID = `
+		SELECT
+			id,
+			users_id,
+			name,
+			prefix,
+			scopes,
+			expires_at,
+			last_used_at,
+			created_at
+		FROM
+			api_tokens
+		WHERE
+			users_id = (?)
+		ORDER BY
+			created_at DESC;
+	`
+
+	queryGetByHash = `
+		SELECT
+			id,
+			users_id,
+			name,
+			prefix,
+			scopes,
+			expires_at,
+			last_used_at,
+			created_at
+		FROM
+			api_tokens
+		WHERE
+			token_hash = (?) AND
+			(expires_at IS NULL OR expires_at > NOW())
+		LIMIT 1;
+	`
+
+	queryInsert = `
+		INSERT INTO
+			api_tokens(
+				users_id,
+				name,
+				prefix,
+				token_hash,
+				scopes,
+				expires_at,
+				created_at,
+				updated_at
+			)
+		VALUES (
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
+			(?),
+			NOW(),
+			NOW()
+		);
+	`
+
+	queryDelete = `
+		DELETE FROM
+			api_tokens
+		WHERE
+			id = (?) AND
+			users_id = (?);
+	`
+
+	queryUpdateLastUsed = `
+		UPDATE
+			api_tokens
+		SET
+			last_used_at = NOW()
+		WHERE
+			id = (?);
+	`
+)
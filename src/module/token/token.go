@@ -0,0 +1,162 @@
+package token
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/melodiez14/meiko/src/util/conn"
+)
+
+// SelectByUserID returns the api tokens of the user ordered from the newest token
+/*
+	@params:
+		userID	= int64
+	@example:
+		userID	= 12
+	@return
+		[]{id, users_id, name, prefix, scopes, expires_at, last_used_at, created_at}
+*/
+func SelectByUserID(userID int64) ([]Token, error) {
+	tokens := []Token{}
+	err := conn.NewQuery(querySelectByUserID, userID).Select(&tokens)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// GetByHash returns the unexpired api token by the hash of the plain token
+/*
+	@params:
+		hash	= string
+	@example:
+		hash	= 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae
+	@return
+		{id, users_id, name, prefix, scopes, expires_at, last_used_at, created_at}
+*/
+func GetByHash(hash string) (Token, error) {
+	var token Token
+	err := conn.NewQuery(queryGetByHash, hash).Get(&token)
+	if err != nil {
+		return Token{}, err
+	}
+	return token, nil
+}
+
+// Insert stores the new api token and returns its id
+/*
+	@params:
+		userID		= int64
+		name		= string
+		prefix		= string
+		hash		= string
+		scopes		= string
+		expiresAt	= mysql.NullTime
+	@example:
+		userID		= 12
+		name		= mobile app
+		prefix		= mk_Xa3sd9Qm
+		hash		= 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae
+		scopes		= courses:XREAD,users:READ
+		expiresAt	= {Valid: false}
+	@return
+		id	= 3
+*/
+func Insert(userID int64, name, prefix, hash, scopes string, expiresAt mysql.NullTime) (int64, error) {
+	result, err := conn.NewQuery(queryInsert, userID, name, prefix, hash, scopes, expiresAt).Exec()
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// Delete revokes the api token of the user, it returns conn.ErrNoRowsAffected if the user doesn't own the token
+/*
+	@params:
+		id		= int64
+		userID	= int64
+	@example:
+		id		= 3
+		userID	= 12
+	@return
+*/
+func Delete(id, userID int64) error {
+	_, err := conn.NewQuery(queryDelete, id, userID).ExecAffected()
+	return err
+}
+
+// UpdateLastUsed sets the last used time of the api token to now
+func UpdateLastUsed(id int64) error {
+	_, err := conn.NewQuery(queryUpdateLastUsed, id).Exec()
+	return err
+}
+
+// ParseScope converts the stored scopes into the module abilities
+/*
+	@params:
+		scopes	= string
+	@example:
+		scopes	= courses:XREAD,users:READ,users:UPDATE
+	@return
+		map[module][]ability = {courses: [XREAD], users: [READ, UPDATE]}
+*/
+func ParseScope(scopes string) map[string][]string {
+	abilities := map[string][]string{}
+	for _, val := range strings.Split(scopes, ",") {
+		scope := strings.SplitN(strings.TrimSpace(val), ":", 2)
+		if len(scope) != 2 || scope[0] == "" || scope[1] == "" {
+			continue
+		}
+		abilities[scope[0]] = append(abilities[scope[0]], scope[1])
+	}
+	return abilities
+}
+
+// JoinScope converts the module abilities into the stored scopes, the scopes are sorted
+/*
+	@params:
+		abilities	= map[module][]ability
+	@example:
+		abilities	= {users: [UPDATE, READ], courses: [XREAD]}
+	@return
+		scopes	= courses:XREAD,users:READ,users:UPDATE
+*/
+func JoinScope(abilities map[string][]string) string {
+	scopes := []string{}
+	for module, val := range abilities {
+		for _, ability := range val {
+			scopes = append(scopes, module+":"+ability)
+		}
+	}
+	sort.Strings(scopes)
+	return strings.Join(scopes, ",")
+}
+
+// Restrict returns the roles which are granted by the scopes. The token can't have the ability
+// which isn't owned by its user
+/*
+	@params:
+		roles	= map[module][]ability
+		scopes	= map[module][]ability
+	@example:
+		roles	= {users: [READ, UPDATE], courses: [XREAD]}
+		scopes	= {users: [READ, DELETE]}
+	@return
+		map[module][]ability = {users: [READ]}
+*/
+func Restrict(roles, scopes map[string][]string) map[string][]string {
+	restricted := map[string][]string{}
+	for module, abilities := range scopes {
+		for _, ability := range abilities {
+			for _, val := range roles[module] {
+				if val == ability {
+					restricted[module] = append(restricted[module], ability)
+					break
+				}
+			}
+		}
+	}
+	return restricted
+}
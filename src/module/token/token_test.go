@@ -0,0 +1,160 @@
+package token
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/melodiez14/meiko/src/util/conn"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestGetByHash(t *testing.T) {
+	now := time.Now()
+	type mock struct {
+		query  string
+		column []string
+		result []driver.Value
+		err    error
+	}
+	tests := []struct {
+		name    string
+		hash    string
+		mock    mock
+		want    Token
+		wantErr bool
+	}{
+		{
+			name: "Test Case 1",
+			hash: "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+			mock: mock{
+				query:  `^\s*SELECT(.+)FROM(\s*)api_tokens(\s*)WHERE(\s*)token_hash(\s*)=(.+)expires_at(\s*)IS(\s*)NULL(.+)LIMIT(\s*)1`,
+				column: []string{"id", "users_id", "name", "prefix", "scopes", "created_at"},
+				result: []driver.Value{"3", "12", "mobile app", "mk_Xa3sd9Qm", "users:READ", now},
+			},
+			want: Token{
+				ID:        3,
+				UserID:    12,
+				Name:      "mobile app",
+				Prefix:    "mk_Xa3sd9Qm",
+				Scopes:    "users:READ",
+				CreatedAt: now,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test Case 2",
+			hash: "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+			mock: mock{
+				query: `^\s*SELECT(.+)FROM(\s*)api_tokens(\s*)WHERE(\s*)token_hash(\s*)=(.+)LIMIT(\s*)1`,
+				err:   sqlmock.ErrCancelled,
+			},
+			want:    Token{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		db, _ := conn.InitDBMock()
+		q := db.ExpectQuery(tt.mock.query).WithArgs(tt.hash)
+		if tt.mock.err == nil {
+			q.WillReturnRows(sqlmock.NewRows(tt.mock.column).
+				AddRow(tt.mock.result...))
+		} else {
+			q.WillReturnError(tt.mock.err)
+		}
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetByHash(tt.hash)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetByHash() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetByHash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		wantErr      error
+	}{
+		{
+			name:         "Test Case 1",
+			rowsAffected: 1,
+			wantErr:      nil,
+		},
+		{
+			name:         "Test Case 2",
+			rowsAffected: 0,
+			wantErr:      conn.ErrNoRowsAffected,
+		},
+	}
+	for _, tt := range tests {
+		db, _ := conn.InitDBMock()
+		db.ExpectExec(`^\s*DELETE(\s*)FROM(\s*)api_tokens(\s*)WHERE(\s*)id(.+)users_id`).
+			WithArgs(3, 12).
+			WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Delete(3, 12); err != tt.wantErr {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		scopes     string
+		roles      map[string][]string
+		wantScope  map[string][]string
+		wantRoles  map[string][]string
+		wantString string
+	}{
+		{
+			name:       "Test Case 1",
+			scopes:     "",
+			roles:      map[string][]string{"users": {"READ"}},
+			wantScope:  map[string][]string{},
+			wantRoles:  map[string][]string{},
+			wantString: "",
+		},
+		{
+			name:   "Test Case 2",
+			scopes: "users:READ,users:DELETE,courses:XREAD,invalid",
+			roles: map[string][]string{
+				"users":   {"READ", "UPDATE"},
+				"courses": {"XREAD"},
+			},
+			wantScope: map[string][]string{
+				"users":   {"READ", "DELETE"},
+				"courses": {"XREAD"},
+			},
+			wantRoles: map[string][]string{
+				"users":   {"READ"},
+				"courses": {"XREAD"},
+			},
+			wantString: "courses:XREAD,users:DELETE,users:READ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := ParseScope(tt.scopes)
+			if !reflect.DeepEqual(scope, tt.wantScope) {
+				t.Errorf("ParseScope() = %v, want %v", scope, tt.wantScope)
+			}
+			if got := Restrict(tt.roles, scope); !reflect.DeepEqual(got, tt.wantRoles) {
+				t.Errorf("Restrict() = %v, want %v", got, tt.wantRoles)
+			}
+			if got := JoinScope(scope); got != tt.wantString {
+				t.Errorf("JoinScope() = %v, want %v", got, tt.wantString)
+			}
+		})
+	}
+}
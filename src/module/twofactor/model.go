@@ -0,0 +1,19 @@
+package twofactor
+
+import (
+	"github.com/go-sql-driver/mysql"
+)
+
+// TOTP is the authenticator app secret of the user. The two factor is enabled once the enrollment
+// is confirmed, the last step is the last accepted time step so the code can't be replayed
+type TOTP struct {
+	UserID    int64          `db:"users_id"`
+	Secret    string         `db:"secret"`
+	LastStep  int64          `db:"last_step"`
+	EnabledAt mysql.NullTime `db:"enabled_at"`
+}
+
+// IsEnabled returns whether the enrollment has been confirmed
+func (t TOTP) IsEnabled() bool {
+	return t.EnabledAt.Valid
+}
@@ -0,0 +1,113 @@
+package twofactor
+
+const (
+	queryGet = `
+		SELECT
+			users_id,
+			secret,
+			last_step,
+			enabled_at
+		FROM
+			users_totp
+		WHERE
+			users_id = (?)
+		LIMIT 1;
+	`
+
+	queryEnroll = `
+		INSERT INTO
+			users_totp(
+				users_id,
+				secret,
+				last_step,
+				enabled_at,
+				created_at,
+				updated_at
+			)
+		VALUES (
+			(?),
+			(?),
+			0,
+			NULL,
+			NOW(),
+			NOW()
+		)
+		ON DUPLICATE KEY UPDATE
+			secret = IF(enabled_at IS NULL, VALUES(secret), secret),
+			last_step = IF(enabled_at IS NULL, 0, last_step),
+			updated_at = IF(enabled_at IS NULL, NOW(), updated_at);
+	`
+
+	queryEnable = `
+		UPDATE
+			users_totp
+		SET
+			last_step = (?),
+			enabled_at = NOW(),
+			updated_at = NOW()
+		WHERE
+			users_id = (?) AND
+			enabled_at IS NULL AND
+			last_step < (?);
+	`
+
+	queryUpdateLastStep = `
+		UPDATE
+			users_totp
+		SET
+			last_step = (?),
+			updated_at = NOW()
+		WHERE
+			users_id = (?) AND
+			last_step < (?);
+	`
+
+	queryDelete = `
+		DELETE FROM
+			users_totp
+		WHERE
+			users_id = (?);
+	`
+
+	queryDeleteRecoveryCode = `
+		DELETE FROM
+			users_recovery_codes
+		WHERE
+			users_id = (?);
+	`
+
+	queryInsertRecoveryCode = `
+		INSERT INTO
+			users_recovery_codes(
+				users_id,
+				code_hash,
+				created_at
+			)
+		VALUES (
+			(?),
+			(?),
+			NOW()
+		);
+	`
+
+	queryUseRecoveryCode = `
+		UPDATE
+			users_recovery_codes
+		SET
+			used_at = NOW()
+		WHERE
+			users_id = (?) AND
+			code_hash = (?) AND
+			used_at IS NULL;
+	`
+
+	queryCountRecoveryCode = `
+		SELECT
+			COUNT(*)
+		FROM
+			users_recovery_codes
+		WHERE
+			users_id = (?) AND
+			used_at IS NULL;
+	`
+)
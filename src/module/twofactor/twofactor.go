@@ -0,0 +1,167 @@
+package twofactor
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/melodiez14/meiko/src/util/conn"
+)
+
+// Get returns the authenticator app secret of the user
+/*
+	@params:
+		userID	= int64
+	@example:
+		userID	= 12
+	@return
+		{users_id, secret, last_step, enabled_at}
+*/
+func Get(userID int64) (TOTP, error) {
+	var t TOTP
+	err := conn.NewQuery(queryGet, userID).Get(&t)
+	if err != nil {
+		return TOTP{}, err
+	}
+	return t, nil
+}
+
+// Enroll stores the new secret of the user which isn't enabled until it's confirmed. The secret of the enabled
+// two factor is never replaced, it returns conn.ErrNoRowsAffected instead
+/*
+	@params:
+		userID	= int64
+		secret	= string
+	@example:
+		userID	= 12
+		secret	= JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP
+	@return
+*/
+func Enroll(userID int64, secret string) error {
+	_, err := conn.NewQuery(queryEnroll, userID, secret).ExecAffected()
+	return err
+}
+
+// Enable confirms the enrollment using the accepted time step, it returns conn.ErrNoRowsAffected
+// if the two factor has been enabled or the time step has been used
+/*
+	@params:
+		userID	= int64
+		step	= int64
+		tx		= optional *sqlx.Tx
+	@example:
+		userID	= 12
+		step	= 50276150
+		tx		= nil
+	@return
+*/
+func Enable(userID, step int64, tx ...*sqlx.Tx) error {
+	_, err := conn.NewQuery(queryEnable, step, userID, step).WithTx(tx...).ExecAffected()
+	return err
+}
+
+// UpdateLastStep marks the time step as used, it returns conn.ErrNoRowsAffected if the time step
+// isn't newer than the last used step
+/*
+	@params:
+		userID	= int64
+		step	= int64
+	@example:
+		userID	= 12
+		step	= 50276150
+	@return
+*/
+func UpdateLastStep(userID, step int64) error {
+	_, err := conn.NewQuery(queryUpdateLastStep, step, userID, step).ExecAffected()
+	return err
+}
+
+// Delete disables the two factor of the user
+/*
+	@params:
+		userID	= int64
+		tx		= optional *sqlx.Tx
+	@example:
+		userID	= 12
+		tx		= nil
+	@return
+*/
+func Delete(userID int64, tx ...*sqlx.Tx) error {
+	_, err := conn.NewQuery(queryDelete, userID).WithTx(tx...).Exec()
+	return err
+}
+
+// ReplaceRecoveryCode removes the old recovery codes of the user and stores the new code hashes
+/*
+	@params:
+		userID	= int64
+		hashes	= []string
+		tx		= optional *sqlx.Tx
+	@example:
+		userID	= 12
+		hashes	= [2c26b46b68ff..., fcde2b2edba5...]
+		tx		= nil
+	@return
+*/
+func ReplaceRecoveryCode(userID int64, hashes []string, tx ...*sqlx.Tx) error {
+
+	err := DeleteRecoveryCode(userID, tx...)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		_, err = conn.NewQuery(queryInsertRecoveryCode, userID, hash).WithTx(tx...).Exec()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteRecoveryCode removes every recovery code of the user
+/*
+	@params:
+		userID	= int64
+		tx		= optional *sqlx.Tx
+	@example:
+		userID	= 12
+		tx		= nil
+	@return
+*/
+func DeleteRecoveryCode(userID int64, tx ...*sqlx.Tx) error {
+	_, err := conn.NewQuery(queryDeleteRecoveryCode, userID).WithTx(tx...).Exec()
+	return err
+}
+
+// UseRecoveryCode marks the unused recovery code as used, it returns conn.ErrNoRowsAffected
+// if the code doesn't exist or it has been used
+/*
+	@params:
+		userID	= int64
+		hash	= string
+	@example:
+		userID	= 12
+		hash	= 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae
+	@return
+*/
+func UseRecoveryCode(userID int64, hash string) error {
+	_, err := conn.NewQuery(queryUseRecoveryCode, userID, hash).ExecAffected()
+	return err
+}
+
+// CountRecoveryCode returns the number of the unused recovery codes of the user
+/*
+	@params:
+		userID	= int64
+	@example:
+		userID	= 12
+	@return
+		count	= 8
+*/
+func CountRecoveryCode(userID int64) (int, error) {
+	var count int
+	err := conn.NewQuery(queryCountRecoveryCode, userID).Get(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
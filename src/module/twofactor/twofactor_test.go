@@ -0,0 +1,138 @@
+package twofactor
+
+import (
+	"testing"
+
+	"github.com/melodiez14/meiko/src/util/conn"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestEnroll(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		wantErr      error
+	}{
+		{
+			name:         "Test Case 1",
+			rowsAffected: 1,
+			wantErr:      nil,
+		},
+		{
+			name:         "Test Case 2",
+			rowsAffected: 2,
+			wantErr:      nil,
+		},
+		{
+			name:         "Test Case 3",
+			rowsAffected: 0,
+			wantErr:      conn.ErrNoRowsAffected,
+		},
+	}
+	for _, tt := range tests {
+		db, _ := conn.InitDBMock()
+		db.ExpectExec(`^\s*INSERT(\s*)INTO(\s*)users_totp(.+)ON(\s*)DUPLICATE(\s*)KEY(\s*)UPDATE(\s*)secret(\s*)=(\s*)IF\(enabled_at(\s*)IS(\s*)NULL`).
+			WithArgs(12, "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP").
+			WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Enroll(12, "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"); err != tt.wantErr {
+				t.Errorf("Enroll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnable(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		wantErr      error
+	}{
+		{
+			name:         "Test Case 1",
+			rowsAffected: 1,
+			wantErr:      nil,
+		},
+		{
+			name:         "Test Case 2",
+			rowsAffected: 0,
+			wantErr:      conn.ErrNoRowsAffected,
+		},
+	}
+	for _, tt := range tests {
+		db, _ := conn.InitDBMock()
+		db.ExpectExec(`^\s*UPDATE(\s*)users_totp(\s*)SET(.+)WHERE(\s*)users_id(.+)enabled_at(\s*)IS(\s*)NULL(.+)last_step(\s*)<`).
+			WithArgs(50276150, 12, 50276150).
+			WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Enable(12, 50276150); err != tt.wantErr {
+				t.Errorf("Enable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateLastStep(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		wantErr      error
+	}{
+		{
+			name:         "Test Case 1",
+			rowsAffected: 1,
+			wantErr:      nil,
+		},
+		{
+			name:         "Test Case 2",
+			rowsAffected: 0,
+			wantErr:      conn.ErrNoRowsAffected,
+		},
+	}
+	for _, tt := range tests {
+		db, _ := conn.InitDBMock()
+		db.ExpectExec(`^\s*UPDATE(\s*)users_totp(\s*)SET(\s*)last_step(.+)WHERE(\s*)users_id(.+)last_step(\s*)<`).
+			WithArgs(50276150, 12, 50276150).
+			WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateLastStep(12, 50276150); err != tt.wantErr {
+				t.Errorf("UpdateLastStep() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	hash := "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		wantErr      error
+	}{
+		{
+			name:         "Test Case 1",
+			rowsAffected: 1,
+			wantErr:      nil,
+		},
+		{
+			name:         "Test Case 2",
+			rowsAffected: 0,
+			wantErr:      conn.ErrNoRowsAffected,
+		},
+	}
+	for _, tt := range tests {
+		db, _ := conn.InitDBMock()
+		db.ExpectExec(`^\s*UPDATE(\s*)users_recovery_codes(\s*)SET(.+)WHERE(\s*)users_id(.+)code_hash`).
+			WithArgs(12, hash).
+			WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UseRecoveryCode(12, hash); err != tt.wantErr {
+				t.Errorf("UseRecoveryCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
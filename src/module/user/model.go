@@ -2,9 +2,13 @@ package user
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 )
 
+// ErrInvalidPassword is returned when the password doesn't match the stored hash
+var ErrInvalidPassword = errors.New("Invalid password")
+
 // const const for specify variable in user
 /*
 	@params:
@@ -48,6 +52,9 @@ const (
 	StatusVerified   = 1
 	StatusActivated  = 2
 
+	// MaxVerificationAttempt is the number of checks allowed for a verification code
+	MaxVerificationAttempt = 3
+
 	GenderUndefined = 0
 	GenderMale      = 1
 	GenderFemale    = 2
@@ -107,6 +114,7 @@ type User struct {
 	LineID       sql.NullString `db:"line_id"`
 	Phone        sql.NullString `db:"phone"`
 	RoleGroupsID sql.NullInt64  `db:"rolegroups_id"`
+	Password     string         `db:"password" json:"-"`
 }
 
 // Verification struct for verify account confirmation through email
@@ -11,7 +11,7 @@ const (
 		FROM
 			users
 		WHERE
-			id IN (%s);
+			id IN (?);
 	`
 	queryGetByEmail = `
 		SELECT
@@ -19,7 +19,7 @@ const (
 		FROM
 			users
 		WHERE
-			email = ('%s')
+			email = (?)
 		LIMIT 1;
 	`
 	queryGetByIdentityCode = `
@@ -28,7 +28,16 @@ const (
 		FROM
 			users
 		WHERE
-			identity_code = (%d)
+			identity_code = (?)
+		LIMIT 1;
+	`
+	queryGetByLineID = `
+		SELECT
+			%s
+		FROM
+			users
+		WHERE
+			line_id = (?)
 		LIMIT 1;
 	`
 	querySignIn = `
@@ -42,12 +51,12 @@ const (
 			identity_code,
 			line_id,
 			phone,
-			rolegroups_id
+			rolegroups_id,
+			password
 		FROM
 			users
 		WHERE
-			email = ('%s') AND
-			password = ('%s')
+			email = (?)
 		LIMIT 1;
 	`
 	querySignUp = `
@@ -60,10 +69,10 @@ const (
 				created_at,
 				updated_at
 			) VALUES (
-				('%s'),
-				('%s'),
-				('%s'),
-				(%d),
+				(?),
+				(?),
+				(?),
+				(?),
 				NOW(),
 				NOW()
 			);
@@ -72,22 +81,22 @@ const (
 		UPDATE
 			users
 		SET
-			status = (%d),
+			status = (?),
 			email_verification_code = NULL,
 			email_verification_expire_date = NULL,
 			email_verification_attempt = NULL,
 			updated_at = NOW()
 		WHERE
-			identity_code = (%d);
+			identity_code = (?);
 	`
 	queryUpdateStatus = `
 		UPDATE
 			users
 		SET
-			status = (%d),
+			status = (?),
 			updated_at = NOW()
 		WHERE
-			identity_code = (%d);
+			identity_code = (?);
 	`
 	querySelectDashboard = `
 		SELECT
@@ -98,22 +107,22 @@ const (
 		FROM
 			users
 		WHERE
-			(status = (%d) OR status = (%d)) AND
-			id != (%d)
-		LIMIT %d
-		OFFSET %d;
+			(status = (?) OR status = (?)) AND
+			id != (?)
+		LIMIT ?
+		OFFSET ?;
 	`
 
 	generateVerificationQuery = `
 		UPDATE
 			users
 		SET
-			email_verification_code = (%d),
+			email_verification_code = (?),
 			email_verification_expire_date = (DATE_ADD(NOW(), INTERVAL 30 MINUTE)),
 			email_verification_attempt = 0,
 			updated_at = NOW()
 		WHERE
-			identity_code = (%d);
+			identity_code = (?);
 	`
 
 	getConfirmationQuery = `
@@ -124,7 +133,7 @@ const (
 		FROM
 			users
 		WHERE
-			email = ('%s') AND
+			email = (?) AND
 			NOW() < email_verification_expire_date
 		LIMIT 1;
 	`
@@ -136,19 +145,93 @@ const (
 			email_verification_attempt = email_verification_attempt + 1,
 			updated_at = NOW()
 		WHERE
-			id = (%d)
+			id = (?) AND
+			email_verification_attempt < (?)
 	`
 
 	queryForgotNewPassword = `
 		UPDATE
 			users
 		SET
-			password = ('%s'),
+			password = (?),
 			email_verification_code = NULL,
 			email_verification_expire_date = NULL,
 			email_verification_attempt = NULL,
 			updated_at = NOW()
 		WHERE
-			email = ('%s');
+			email = (?);
+	`
+
+	querySelectByRoleGroup = `
+		SELECT
+			%s
+		FROM
+			users
+		WHERE
+			rolegroups_id = (?)
+		ORDER BY
+			identity_code ASC
+		LIMIT ?
+		OFFSET ?;
+	`
+
+	queryCountByRoleGroup = `
+		SELECT
+			COUNT(*)
+		FROM
+			users
+		WHERE
+			rolegroups_id = (?);
+	`
+
+	queryUpdateRoleGroup = `
+		UPDATE
+			users
+		SET
+			rolegroups_id = (?),
+			updated_at = NOW()
+		WHERE
+			identity_code IN (?);
+	`
+
+	queryRemoveRoleGroup = `
+		UPDATE
+			users
+		SET
+			rolegroups_id = NULL,
+			updated_at = NOW()
+		WHERE
+			rolegroups_id = (?) AND
+			identity_code IN (?);
+	`
+
+	querySelectIDByRoleGroup = `
+		SELECT
+			id
+		FROM
+			users
+		WHERE
+			rolegroups_id = (?)
+			%s;
+	`
+
+	querySelectRegistered = `
+		SELECT
+			identity_code,
+			email
+		FROM
+			users
+		WHERE
+			identity_code IN (?) OR
+			email IN (?);
+	`
+
+	querySelectByIdentityCode = `
+		SELECT
+			%s
+		FROM
+			users
+		WHERE
+			identity_code IN (?);
 	`
 )
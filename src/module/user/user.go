@@ -1,14 +1,15 @@
 package user
 
 import (
+	"crypto/rand"
 	"database/sql"
 	"fmt"
-	"math/rand"
+	"math/big"
 	"strings"
 	"time"
 
-	"github.com/melodiez14/meiko/src/util/helper"
-
+	"github.com/jmoiron/sqlx"
+	"github.com/melodiez14/meiko/src/util/auth"
 	"github.com/melodiez14/meiko/src/util/conn"
 )
 
@@ -33,7 +34,9 @@ import (
 func SelectByID(id []int64, column ...string) ([]User, error) {
 	var user []User
 	var c []string
-	d := helper.Int64ToStringSlice(id)
+	if len(id) < 1 {
+		return user, nil
+	}
 
 	if len(column) < 1 {
 		c = []string{
@@ -53,10 +56,9 @@ func SelectByID(id []int64, column ...string) ([]User, error) {
 			c = append(c, val)
 		}
 	}
-	ids := strings.Join(d, ", ")
 	cols := strings.Join(c, ", ")
-	query := fmt.Sprintf(querySelectByID, cols, ids)
-	err := conn.DB.Select(&user, query)
+	query := fmt.Sprintf(querySelectByID, cols)
+	err := conn.NewQuery(query, id).Select(&user)
 	if err != nil {
 		return user, err
 	}
@@ -103,8 +105,8 @@ func GetByEmail(email string, column ...string) (User, error) {
 		}
 	}
 	cols := strings.Join(c, ", ")
-	query := fmt.Sprintf(queryGetByEmail, cols, email)
-	err := conn.DB.Get(&user, query)
+	query := fmt.Sprintf(queryGetByEmail, cols)
+	err := conn.NewQuery(query, email).Get(&user)
 	if err != nil {
 		return user, err
 	}
@@ -149,8 +151,50 @@ func GetByIdentityCode(identityCode int64, column ...string) (User, error) {
 		}
 	}
 	cols := strings.Join(c, ", ")
-	query := fmt.Sprintf(queryGetByIdentityCode, cols, identityCode)
-	err := conn.DB.Get(&user, query)
+	query := fmt.Sprintf(queryGetByIdentityCode, cols)
+	err := conn.NewQuery(query, identityCode).Get(&user)
+	if err != nil {
+		return user, err
+	}
+	return user, nil
+}
+
+// GetByLineID function to get user information from database using the line id which is set in the user profile
+/*
+	@params:
+		lineID			= string
+	@example:
+		lineID			= U4af4980629
+	@return:
+		ID				= 140810140060
+		Name			= kharil azmi ashari
+		Status			= 2
+		LineID			= U4af4980629
+*/
+func GetByLineID(lineID string, column ...string) (User, error) {
+	var user User
+	var c []string
+	if len(column) < 1 {
+		c = []string{
+			ColID,
+			ColName,
+			ColEmail,
+			ColGender,
+			ColNote,
+			ColStatus,
+			ColIdentityCode,
+			ColLineID,
+			ColPhone,
+			ColRoleGroupsID,
+		}
+	} else {
+		for _, val := range column {
+			c = append(c, val)
+		}
+	}
+	cols := strings.Join(c, ", ")
+	query := fmt.Sprintf(queryGetByLineID, cols)
+	err := conn.NewQuery(query, lineID).Get(&user)
 	if err != nil {
 		return user, err
 	}
@@ -179,14 +223,43 @@ func GetByIdentityCode(identityCode int64, column ...string) (User, error) {
 */
 func SignIn(email, password string) (User, error) {
 	var user User
-	query := fmt.Sprintf(querySignIn, email, password)
-	err := conn.DB.Get(&user, query)
+	err := conn.NewQuery(querySignIn, email).Get(&user)
 	if err != nil {
-		return user, err
+		return User{}, err
+	}
+
+	isMatch, isNeedRehash := auth.ComparePassword(user.Password, password)
+	if !isMatch {
+		return User{}, ErrInvalidPassword
+	}
+	user.Password = ""
+
+	// the legacy MD5 hash is upgraded on the successful sign in, the failure doesn't block the sign in
+	// since the old hash is still valid
+	if isNeedRehash {
+		hash, err := auth.HashPassword(password)
+		if err == nil {
+			updatePassword(user.ID, hash)
+		}
 	}
+
 	return user, nil
 }
 
+// updatePassword replaces the password hash of the user
+func updatePassword(id int64, hash string) error {
+	query := `
+		UPDATE
+			users
+		SET
+			password = (?)
+		WHERE
+			id = (?);
+		`
+	_, err := conn.NewQuery(query, hash, id).Exec()
+	return err
+}
+
 // SignUp function to sign up an account by fill with identity code, name, email, and password.
 //then check to database and save the information in database if there is valid new account
 /*
@@ -203,16 +276,12 @@ func SignIn(email, password string) (User, error) {
 	@return
 */
 func SignUp(identityCode int64, name, email, password string) error {
-	query := fmt.Sprintf(querySignUp, name, email, password, identityCode)
-	result, err := conn.DB.Exec(query)
+	hash, err := auth.HashPassword(password)
 	if err != nil {
 		return err
 	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+	_, err = conn.NewQuery(querySignUp, name, email, hash, identityCode).ExecAffected()
+	return err
 }
 
 // IsPhoneExist check is phone number exist in database
@@ -228,17 +297,17 @@ func SignUp(identityCode int64, name, email, password string) error {
 */
 func IsPhoneExist(identityCode int64, phone string) bool {
 	var user User
-	query := fmt.Sprintf(`
+	query := `
 			SELECT
 				phone
 			FROM
 				 users
 			WHERE
-				phone = ('%s') AND
-				identity_code != (%d)
+				phone = (?) AND
+				identity_code != (?)
 			LIMIT 1
-		`, phone, identityCode)
-	err := conn.DB.Get(&user, query)
+		`
+	err := conn.NewQuery(query, phone, identityCode).Get(&user)
 	if err == sql.ErrNoRows {
 		return false
 	}
@@ -258,17 +327,17 @@ func IsPhoneExist(identityCode int64, phone string) bool {
 */
 func IsLineIDExist(identityCode int64, lineID string) bool {
 	var x string
-	query := fmt.Sprintf(`
+	query := `
 			SELECT
 				'x'
 			FROM
 				 users
 			WHERE
-				line_id = ('%s') AND
-				identity_code != (%d)
+				line_id = (?) AND
+				identity_code != (?)
 			LIMIT 1;
-		`, lineID, identityCode)
-	err := conn.DB.Get(&x, query)
+		`
+	err := conn.NewQuery(query, lineID, identityCode).Get(&x)
 	if err == sql.ErrNoRows {
 		return false
 	}
@@ -299,62 +368,50 @@ func UpdateProfile(identityCode int64, name, note string, phone, lineID sql.Null
 		gender = GenderUndefined
 	}
 
-	queryLineID := fmt.Sprintf("NULL")
-	if lineID.Valid {
-		queryLineID = fmt.Sprintf("('%s')", lineID.String)
-	}
-
-	queryPhone := fmt.Sprintf("NULL")
-	if phone.Valid {
-		queryPhone = fmt.Sprintf("('%s')", phone.String)
-	}
-
-	query := fmt.Sprintf(`
+	query := `
 		UPDATE
 			users
 		SET
-			name = ('%s'),
-			phone = %s,
-			line_id = %s,
-			note = ('%s'),
-			gender = (%d),
+			name = (?),
+			phone = (?),
+			line_id = (?),
+			note = (?),
+			gender = (?),
 			updated_at = NOW()
 		WHERE
-			identity_code = (%d);
-		`, name, queryPhone, queryLineID, note, gender, identityCode)
-	result, err := conn.DB.Exec(query)
-	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+			identity_code = (?);
+		`
+	_, err := conn.NewQuery(query, name, phone, lineID, note, gender, identityCode).ExecAffected()
+	return err
 }
 
 // GenerateVerification ganarate verifocation code to get confirmation from valid email
 /*
 	@params:
 		identity	= int64
+		tx			= optional *sqlx.Tx
 	@example:
 		identity	= 140810140060
+		tx			= nil
 	@return
 */
-func GenerateVerification(identity int64) (Verification, error) {
+func GenerateVerification(identity int64, tx ...*sqlx.Tx) (Verification, error) {
+
+	// the code is generated using crypto/rand so it can't be predicted from the time
+	n, err := rand.Int(rand.Reader, big.NewInt(9000))
+	if err != nil {
+		return Verification{}, fmt.Errorf("Error generating code")
+	}
 
-	rand.Seed(time.Now().UTC().UnixNano())
 	v := Verification{
-		Code:           uint16(rand.Intn(8999) + 1000),
+		Code:           uint16(n.Int64() + 1000),
 		ExpireDuration: "30 Minutes",
 		ExpireDate:     time.Now().Add(30 * time.Minute),
 		Attempt:        0,
 	}
 
-	query := fmt.Sprintf(generateVerificationQuery, v.Code, identity)
-	result := conn.DB.MustExec(query)
-	count, _ := result.RowsAffected()
-	if count < 1 {
+	_, err = conn.NewQuery(generateVerificationQuery, v.Code, identity).WithTx(tx...).ExecAffected()
+	if err != nil {
 		return v, fmt.Errorf("Error executing query")
 	}
 
@@ -374,23 +431,23 @@ func GenerateVerification(identity int64) (Verification, error) {
 */
 func IsValidConfirmationCode(email string, code uint16) bool {
 	var c Confirmation
-	query := fmt.Sprintf(getConfirmationQuery, email)
-	err := conn.DB.Get(&c, query)
+	err := conn.NewQuery(getConfirmationQuery, email).Get(&c)
 	if err != nil {
 		return false
 	}
 
-	if !c.Attempt.Valid || c.Attempt.Int64 >= 3 {
+	if !c.Attempt.Valid || c.Attempt.Int64 >= MaxVerificationAttempt {
 		return false
 	}
 
-	if !c.Code.Valid || c.Code.Int64 != int64(code) {
-		query = fmt.Sprintf(attemptIncrementQuery, c.ID)
-		_ = conn.DB.MustExec(query)
+	// every check uses one attempt before the code is compared, the conditional update makes sure
+	// the concurrent requests can't use more than the maximum attempt
+	_, err = conn.NewQuery(attemptIncrementQuery, c.ID, MaxVerificationAttempt).ExecAffected()
+	if err != nil {
 		return false
 	}
 
-	return true
+	return c.Code.Valid && c.Code.Int64 == int64(code)
 }
 
 // UpdateToVerified function to change account status to be verified after do email verification
@@ -402,16 +459,8 @@ func IsValidConfirmationCode(email string, code uint16) bool {
 	@return
 */
 func UpdateToVerified(identityCode int64) error {
-	query := fmt.Sprintf(queryUpdateToVerified, StatusVerified, identityCode)
-	result, err := conn.DB.Exec(query)
-	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+	_, err := conn.NewQuery(queryUpdateToVerified, StatusVerified, identityCode).ExecAffected()
+	return err
 }
 
 // UpdateStatus function to update status and send update to database
@@ -419,22 +468,16 @@ func UpdateToVerified(identityCode int64) error {
 	@params:
 		identityCode	= string
 		status			= string
+		tx				= optional *sqlx.Tx
 	@example:
 		identityCode	= 140810140060
 		status			= i'm single, thanks you
+		tx				= nil
 	@return
 */
-func UpdateStatus(identityCode int64, status int8) error {
-	query := fmt.Sprintf(queryUpdateStatus, status, identityCode)
-	result, err := conn.DB.Exec(query)
-	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+func UpdateStatus(identityCode int64, status int8, tx ...*sqlx.Tx) error {
+	_, err := conn.NewQuery(queryUpdateStatus, status, identityCode).WithTx(tx...).ExecAffected()
+	return err
 }
 
 // SelectDashboard function to show dashboard and send user information
@@ -461,8 +504,7 @@ func UpdateStatus(identityCode int64, status int8) error {
 */
 func SelectDashboard(id int64, limit, offset uint16) ([]User, error) {
 	var user []User
-	query := fmt.Sprintf(querySelectDashboard, StatusVerified, StatusActivated, id, limit, offset)
-	err := conn.DB.Select(&user, query)
+	err := conn.NewQuery(querySelectDashboard, StatusVerified, StatusActivated, id, limit, offset).Select(&user)
 	if err != nil {
 		return user, err
 	}
@@ -482,24 +524,42 @@ func SelectDashboard(id int64, limit, offset uint16) ([]User, error) {
 	@return
 */
 func ChangePassword(identityCode int64, password, oldPassword string) error {
-	query := fmt.Sprintf(`
-		UPDATE
+
+	var current string
+	query := `
+		SELECT
+			password
+		FROM
 			users
-		SET
-			password = ('%s')
 		WHERE
-			identity_code = (%d) AND
-			password = ('%s');
-		`, password, identityCode, oldPassword)
-	result, err := conn.DB.Exec(query)
+			identity_code = (?)
+		LIMIT 1;
+		`
+	err := conn.NewQuery(query, identityCode).Get(&current)
 	if err != nil {
 		return err
 	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
+
+	isMatch, _ := auth.ComparePassword(current, oldPassword)
+	if !isMatch {
+		return ErrInvalidPassword
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
 	}
-	return nil
+
+	query = `
+		UPDATE
+			users
+		SET
+			password = (?)
+		WHERE
+			identity_code = (?);
+		`
+	_, err = conn.NewQuery(query, hash, identityCode).ExecAffected()
+	return err
 }
 
 // ForgotNewPassword function to renew password when user forget their own password
@@ -514,16 +574,12 @@ func ChangePassword(identityCode int64, password, oldPassword string) error {
 	@return
 */
 func ForgotNewPassword(email, password string) error {
-	query := fmt.Sprintf(queryForgotNewPassword, password, email)
-	result, err := conn.DB.Exec(query)
+	hash, err := auth.HashPassword(password)
 	if err != nil {
 		return err
 	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+	_, err = conn.NewQuery(queryForgotNewPassword, hash, email).ExecAffected()
+	return err
 }
 
 // Update function to update user information from inputed data
@@ -535,6 +591,7 @@ func ForgotNewPassword(email, password string) error {
 		lineID			= sql.String
 		gender			= int8
 		status			= int8
+		tx				= optional *sqlx.Tx
 	@example:
 		identityCode	= 140810140060
 		name			= kharil azmi ashari
@@ -542,91 +599,71 @@ func ForgotNewPassword(email, password string) error {
 		lineID			= khaazas
 		gender			= 1
 		status			= 1
+		tx				= nil
 	@return
 */
-func Update(identityCode int64, name, note string, phone, lineID sql.NullString, gender, status int8) error {
+func Update(identityCode int64, name, note string, phone, lineID sql.NullString, gender, status int8, tx ...*sqlx.Tx) error {
 
 	if gender != GenderMale && gender != GenderFemale {
 		gender = GenderUndefined
 	}
 
-	queryLineID := fmt.Sprintf("NULL")
-	if lineID.Valid {
-		queryLineID = fmt.Sprintf("('%s')", lineID.String)
-	}
-
-	queryPhone := fmt.Sprintf("NULL")
-	if phone.Valid {
-		queryPhone = fmt.Sprintf("('%s')", phone.String)
-	}
-
-	query := fmt.Sprintf(`
+	query := `
 			UPDATE
 				users
 			SET
-				name = ('%s'),
-				phone = %s,
-				line_id = %s,
-				note = ('%s'),
-				gender = (%d),
-				status = (%d),
+				name = (?),
+				phone = (?),
+				line_id = (?),
+				note = (?),
+				gender = (?),
+				status = (?),
 				updated_at = NOW()
 			WHERE
-				identity_code = (%d);
-			`, name, queryPhone, queryLineID, note, gender, status, identityCode)
-	result, err := conn.DB.Exec(query)
-	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-	return nil
+				identity_code = (?);
+			`
+	_, err := conn.NewQuery(query, name, phone, lineID, note, gender, status, identityCode).WithTx(tx...).ExecAffected()
+	return err
 }
 
 // Delete function to delete user using identity code
 /*
 	@params:
 		identityCode	= int64
+		tx				= optional *sqlx.Tx
 	@example:
 		identityCode	= 140810140060
+		tx				= nil
 	@return
 */
-func Delete(identityCode int64) error {
-	query := fmt.Sprintf(`
+func Delete(identityCode int64, tx ...*sqlx.Tx) error {
+	query := `
 		DELETE FROM
 			users
 		WHERE
-			identity_code = (%d);
-		`, identityCode)
-
-	result, err := conn.DB.Exec(query)
-	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
-	}
-
-	return nil
+			identity_code = (?);
+		`
+	_, err := conn.NewQuery(query, identityCode).WithTx(tx...).ExecAffected()
+	return err
 }
 
-// Create function to create user to database from valid singup process
+// Create function to create user to database from valid singup process, it returns the id of the user
 /*
 	@params:
 		identityCode	= int64
 		name			= string
 		email			= khairil_azmi_ashari@yahoo.com
+		tx				= optional *sqlx.Tx
 	@example:
 		identityCode	= 140810140060
 		name			= kharil azmi ashari
 		email			= khairil_azmi_ashari@yahoo.com
+		tx				= nil
 	@return
+		id	= 27
 */
-func Create(identityCode int64, name, email string) error {
-	query := fmt.Sprintf(`
+func Create(identityCode int64, name, email string, tx ...*sqlx.Tx) (int64, error) {
+	query := `
 		INSERT INTO
 		users (
 			name,
@@ -637,39 +674,35 @@ func Create(identityCode int64, name, email string) error {
 			created_at,
 			updated_at
 		) VALUES (
-			('%s'),
-			('%s'),
+			(?),
+			(?),
 			('x'),
-			(%d),
-			(%d),
+			(?),
+			(?),
 			NOW(),
 			NOW()
 		);
-		`, name, email, identityCode, StatusActivated)
-	result, err := conn.DB.Exec(query)
+		`
+	result, err := conn.NewQuery(query, name, email, identityCode, StatusActivated).WithTx(tx...).ExecAffected()
 	if err != nil {
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if rows == 0 {
-		return fmt.Errorf("No rows affected")
+		return 0, err
 	}
-	return nil
+	return result.LastInsertId()
 }
 
 // IsUserExist func ...
 func IsUserExist(UserID int64) bool {
 
 	var x string
-	query := fmt.Sprintf(`
+	query := `
 			SELECT
 				'x'
 			FROM
 				users
 			WHERE
-				identity_code = (%d)
-			LIMIT 1;`, UserID)
-	err := conn.DB.Get(&x, query)
+				identity_code = (?)
+			LIMIT 1;`
+	err := conn.NewQuery(query, UserID).Get(&x)
 	if err != nil {
 		return false
 	}
@@ -679,17 +712,203 @@ func IsUserExist(UserID int64) bool {
 // IsUserTakeSchedule func ...
 func IsUserTakeSchedule(UserID, ScheduleID int64) bool {
 	var x string
-	query := fmt.Sprintf(`
+	query := `
 			SELECT
 				'x'
 			FROM
 				p_users_schedules pus
 			WHERE
-				pus.users_id = (%d) AND schedules_id = (%d)
-			LIMIT 1;`, UserID, ScheduleID)
-	err := conn.DB.Get(&x, query)
+				pus.users_id = (?) AND schedules_id = (?)
+			LIMIT 1;`
+	err := conn.NewQuery(query, UserID, ScheduleID).Get(&x)
 	if err != nil {
 		return false
 	}
 	return true
 }
+
+// SelectByRoleGroup returns the members of the rolegroup ordered by identity code
+/*
+	@params:
+		rolegroupID	= int64
+		limit		= uint16
+		offset		= uint16
+		column		= optional, default id, name, email, identity_code
+	@example:
+		rolegroupID	= 2
+		limit		= 10
+		offset		= 0
+	@return
+		[]{id, name, email, identity_code}
+*/
+func SelectByRoleGroup(rolegroupID int64, limit, offset uint16, column ...string) ([]User, error) {
+	users := []User{}
+	if len(column) < 1 {
+		column = []string{
+			ColID,
+			ColName,
+			ColEmail,
+			ColIdentityCode,
+		}
+	}
+
+	query := fmt.Sprintf(querySelectByRoleGroup, strings.Join(column, ", "))
+	err := conn.NewQuery(query, rolegroupID, limit, offset).Select(&users)
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// CountByRoleGroup returns the number of the members of the rolegroup
+/*
+	@params:
+		rolegroupID	= int64
+	@example:
+		rolegroupID	= 2
+	@return
+		count	= 15
+*/
+func CountByRoleGroup(rolegroupID int64) (int, error) {
+	var count int
+	err := conn.NewQuery(queryCountByRoleGroup, rolegroupID).Get(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// UpdateRoleGroup assigns the users to the rolegroup, it returns conn.ErrNoRowsAffected if none of the users is changed
+/*
+	@params:
+		identityCodes	= []int64
+		rolegroupID		= int64
+		tx				= optional *sqlx.Tx
+	@example:
+		identityCodes	= [140810140016, 140810140060]
+		rolegroupID		= 2
+		tx				= nil
+	@return
+*/
+func UpdateRoleGroup(identityCodes []int64, rolegroupID int64, tx ...*sqlx.Tx) error {
+	if len(identityCodes) < 1 {
+		return conn.ErrNoRowsAffected
+	}
+	_, err := conn.NewQuery(queryUpdateRoleGroup, rolegroupID, identityCodes).WithTx(tx...).ExecAffected()
+	return err
+}
+
+// RemoveRoleGroup unassigns the users from the rolegroup, the users of the other rolegroup aren't changed.
+// It returns conn.ErrNoRowsAffected if none of the users is the member
+/*
+	@params:
+		identityCodes	= []int64
+		rolegroupID		= int64
+		tx				= optional *sqlx.Tx
+	@example:
+		identityCodes	= [140810140016]
+		rolegroupID		= 2
+		tx				= nil
+	@return
+*/
+func RemoveRoleGroup(identityCodes []int64, rolegroupID int64, tx ...*sqlx.Tx) error {
+	if len(identityCodes) < 1 {
+		return conn.ErrNoRowsAffected
+	}
+	_, err := conn.NewQuery(queryRemoveRoleGroup, rolegroupID, identityCodes).WithTx(tx...).ExecAffected()
+	return err
+}
+
+// SelectIDByRoleGroup returns the user id of the members of the rolegroup. If the identity codes are given,
+// only the members among them are returned
+/*
+	@params:
+		rolegroupID		= int64
+		identityCodes	= optional []int64
+	@example:
+		rolegroupID		= 2
+		identityCodes	= [140810140016, 140810140060]
+	@return
+		[]int64{12, 15}
+*/
+func SelectIDByRoleGroup(rolegroupID int64, identityCodes ...int64) ([]int64, error) {
+	ids := []int64{}
+	args := []interface{}{rolegroupID}
+	var filter string
+	if len(identityCodes) > 0 {
+		filter = "AND identity_code IN (?)"
+		args = append(args, identityCodes)
+	}
+
+	query := fmt.Sprintf(querySelectIDByRoleGroup, filter)
+	err := conn.NewQuery(query, args...).Select(&ids)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// SelectRegistered returns the identity code and the email of the users who have registered either
+// one of the identity codes or one of the emails
+/*
+	@params:
+		identityCodes	= []int64
+		emails			= []string
+	@example:
+		identityCodes	= [140810140016, 140810140060]
+		emails			= [risal@live.com, asep@live.com]
+	@return
+		[]{identity_code, email}
+*/
+func SelectRegistered(identityCodes []int64, emails []string) ([]User, error) {
+	users := []User{}
+	if len(identityCodes) < 1 || len(emails) < 1 {
+		return users, nil
+	}
+	err := conn.NewQuery(querySelectRegistered, identityCodes, emails).Select(&users)
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// SelectByIdentityCode returns the users of the identity codes with the selected columns, all columns are selected if
+// the column is empty
+/*
+	@params:
+		identityCodes	= []int64
+		column			= optional, []string
+	@example:
+		identityCodes	= [140810140016, 140810140060]
+		column			= [id, name]
+	@return
+		[]{id, name}
+*/
+func SelectByIdentityCode(identityCodes []int64, column ...string) ([]User, error) {
+	users := []User{}
+	if len(identityCodes) < 1 {
+		return users, nil
+	}
+
+	c := column
+	if len(c) < 1 {
+		c = []string{
+			ColID,
+			ColName,
+			ColEmail,
+			ColGender,
+			ColNote,
+			ColStatus,
+			ColIdentityCode,
+			ColLineID,
+			ColPhone,
+			ColRoleGroupsID,
+		}
+	}
+	query := fmt.Sprintf(querySelectByIdentityCode, strings.Join(c, ", "))
+	err := conn.NewQuery(query, identityCodes).Select(&users)
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
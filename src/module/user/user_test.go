@@ -8,7 +8,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/melodiez14/meiko/src/util/auth"
 	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/melodiez14/meiko/src/util/helper"
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
 
@@ -93,7 +95,7 @@ func TestGetByEmail(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectQuery(tt.mock.query)
+		q := db.ExpectQuery(tt.mock.query).WithArgs(tt.args.email)
 		if tt.mock.err == nil {
 			q.WillReturnRows(sqlmock.NewRows(tt.mock.column).
 				AddRow(tt.mock.result...))
@@ -196,7 +198,7 @@ func TestGetByIdentityCode(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectQuery(tt.mock.query)
+		q := db.ExpectQuery(tt.mock.query).WithArgs(tt.args.identityCode)
 		if tt.mock.err == nil {
 			q.WillReturnRows(sqlmock.NewRows(tt.mock.column).
 				AddRow(tt.mock.result...))
@@ -217,10 +219,10 @@ func TestGetByIdentityCode(t *testing.T) {
 	}
 }
 
-func TestSignIn(t *testing.T) {
+func TestGetByLineID(t *testing.T) {
 	type args struct {
-		email    string
-		password string
+		lineID string
+		column []string
 	}
 	type mock struct {
 		query  string
@@ -228,6 +230,96 @@ func TestSignIn(t *testing.T) {
 		result []driver.Value
 		err    error
 	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    mock
+		want    User
+		wantErr bool
+	}{
+		{
+			name: "Test Case 1",
+			args: args{
+				lineID: "U4af4980629",
+				column: []string{ColID, ColName, ColStatus},
+			},
+			mock: mock{
+				query:  `^\s*SELECT(\s*)(.+)(\s*)FROM(\s*)users(\s*)WHERE(\s*)line_id(\s*)=(\s*)(.+)(\s*)LIMIT(\s*)1`,
+				column: []string{"id", "name", "status"},
+				result: []driver.Value{"1", "Risal Falah", "2"},
+				err:    nil,
+			},
+			want: User{
+				ID:     1,
+				Name:   "Risal Falah",
+				Status: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test Case 2",
+			args: args{
+				lineID: "U4af4980629",
+				column: []string{ColID, ColName, ColStatus},
+			},
+			mock: mock{
+				query: `^\s*SELECT(\s*)(.+)(\s*)FROM(\s*)users(\s*)WHERE(\s*)line_id(\s*)=(\s*)(.+)(\s*)LIMIT(\s*)1`,
+				err:   sql.ErrNoRows,
+			},
+			want:    User{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		db, _ := conn.InitDBMock()
+		q := db.ExpectQuery(tt.mock.query).WithArgs(tt.args.lineID)
+		if tt.mock.err == nil {
+			q.WillReturnRows(sqlmock.NewRows(tt.mock.column).
+				AddRow(tt.mock.result...))
+		} else {
+			q.WillReturnError(tt.mock.err)
+		}
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetByLineID(tt.args.lineID, tt.args.column...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetByLineID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetByLineID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// passwordArg matches the password hash argument of the query
+type passwordArg string
+
+func (p passwordArg) Match(v driver.Value) bool {
+	hash, ok := v.(string)
+	if !ok {
+		return false
+	}
+	isMatch, isNeedRehash := auth.ComparePassword(hash, string(p))
+	return isMatch && !isNeedRehash
+}
+
+func TestSignIn(t *testing.T) {
+	bcryptHash, _ := auth.HashPassword("Qwerty123")
+	md5Hash := helper.StringToMD5("Qwerty123")
+
+	type args struct {
+		email    string
+		password string
+	}
+	type mock struct {
+		query    string
+		column   []string
+		result   []driver.Value
+		err      error
+		isRehash bool
+	}
 	tests := []struct {
 		name    string
 		args    args
@@ -239,12 +331,12 @@ func TestSignIn(t *testing.T) {
 			name: "Test Case 1",
 			args: args{
 				email:    "risal@live.com",
-				password: "2af9b1ba42dc5eb01743e6b3759b6e4b",
+				password: "Qwerty123",
 			},
 			mock: mock{
-				query:  `^\s*SELECT(\s*)id,(\s*)name,(\s*)email,(\s*)gender,(\s*)note,(\s*)status,(\s*)identity_code,(\s*)line_id,(\s*)phone,(\s*)rolegroups_id(\s*)FROM(\s*)users(\s*)WHERE(\s*)email(\s*)=(\s*)(.+)AND(\s*)password(\s*)=(\s*)(.+)(\s*)LIMIT(\s*)1;$`,
-				column: []string{"id", "name", "email", "gender", "note", "status", "identity_code", "line_id", "phone", "rolegroups_id"},
-				result: []driver.Value{"1", "Risal Falah", "risal@live.com", "1", "", "2", "140810140016", nil, nil, "1"},
+				query:  `^\s*SELECT(\s*)id,(\s*)name,(\s*)email,(\s*)gender,(\s*)note,(\s*)status,(\s*)identity_code,(\s*)line_id,(\s*)phone,(\s*)rolegroups_id,(\s*)password(\s*)FROM(\s*)users(\s*)WHERE(\s*)email(\s*)=(\s*)(.+)(\s*)LIMIT(\s*)1;$`,
+				column: []string{"id", "name", "email", "gender", "note", "status", "identity_code", "line_id", "phone", "rolegroups_id", "password"},
+				result: []driver.Value{"1", "Risal Falah", "risal@live.com", "1", "", "2", "140810140016", nil, nil, "1", bcryptHash},
 				err:    nil,
 			},
 			want: User{
@@ -262,14 +354,56 @@ func TestSignIn(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "Test Case 1",
+			name: "Test Case 2",
+			args: args{
+				email:    "risal@live.com",
+				password: "Qwerty123",
+			},
+			mock: mock{
+				query:    `^\s*SELECT(\s*)id,(\s*)name,(\s*)email,(\s*)gender,(\s*)note,(\s*)status,(\s*)identity_code,(\s*)line_id,(\s*)phone,(\s*)rolegroups_id,(\s*)password(\s*)FROM(\s*)users(\s*)WHERE(\s*)email(\s*)=(\s*)(.+)(\s*)LIMIT(\s*)1;$`,
+				column:   []string{"id", "name", "email", "gender", "note", "status", "identity_code", "line_id", "phone", "rolegroups_id", "password"},
+				result:   []driver.Value{"1", "Risal Falah", "risal@live.com", "1", "", "2", "140810140016", nil, nil, "1", md5Hash},
+				err:      nil,
+				isRehash: true,
+			},
+			want: User{
+				ID:           1,
+				Name:         "Risal Falah",
+				Email:        "risal@live.com",
+				Gender:       1,
+				Note:         "",
+				Status:       2,
+				IdentityCode: 140810140016,
+				LineID:       sql.NullString{},
+				Phone:        sql.NullString{},
+				RoleGroupsID: sql.NullInt64{Valid: true, Int64: 1},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test Case 3",
+			args: args{
+				email:    "risal@live.com",
+				password: "Qwerty321",
+			},
+			mock: mock{
+				query:  `^\s*SELECT(\s*)id,(\s*)name,(\s*)email,(\s*)gender,(\s*)note,(\s*)status,(\s*)identity_code,(\s*)line_id,(\s*)phone,(\s*)rolegroups_id,(\s*)password(\s*)FROM(\s*)users(\s*)WHERE(\s*)email(\s*)=(\s*)(.+)(\s*)LIMIT(\s*)1;$`,
+				column: []string{"id", "name", "email", "gender", "note", "status", "identity_code", "line_id", "phone", "rolegroups_id", "password"},
+				result: []driver.Value{"1", "Risal Falah", "risal@live.com", "1", "", "2", "140810140016", nil, nil, "1", md5Hash},
+				err:    nil,
+			},
+			want:    User{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 4",
 			args: args{
 				email:    "viavallen@metal.com",
-				password: "2af9b1ba42dc5eb01743e6b3759b6e4b",
+				password: "Qwerty123",
 			},
 			mock: mock{
-				query:  `^\s*SELECT(\s*)id,(\s*)name,(\s*)email,(\s*)gender,(\s*)note,(\s*)status,(\s*)identity_code,(\s*)line_id,(\s*)phone,(\s*)rolegroups_id(\s*)FROM(\s*)users(\s*)WHERE(\s*)email(\s*)=(\s*)(.+)AND(\s*)password(\s*)=(\s*)(.+)(\s*)LIMIT(\s*)1;$`,
-				column: []string{"id", "name", "email", "gender", "note", "status", "identity_code", "line_id", "phone", "rolegroups_id"},
+				query:  `^\s*SELECT(\s*)id,(\s*)name,(\s*)email,(\s*)gender,(\s*)note,(\s*)status,(\s*)identity_code,(\s*)line_id,(\s*)phone,(\s*)rolegroups_id,(\s*)password(\s*)FROM(\s*)users(\s*)WHERE(\s*)email(\s*)=(\s*)(.+)(\s*)LIMIT(\s*)1;$`,
+				column: []string{"id", "name", "email", "gender", "note", "status", "identity_code", "line_id", "phone", "rolegroups_id", "password"},
 				result: []driver.Value{},
 				err:    sql.ErrNoRows,
 			},
@@ -280,7 +414,7 @@ func TestSignIn(t *testing.T) {
 
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectQuery(tt.mock.query)
+		q := db.ExpectQuery(tt.mock.query).WithArgs(tt.args.email)
 		if tt.mock.err == nil {
 			q.WillReturnRows(sqlmock.NewRows(tt.mock.column).
 				AddRow(tt.mock.result...))
@@ -288,6 +422,13 @@ func TestSignIn(t *testing.T) {
 			q.WillReturnError(tt.mock.err)
 		}
 
+		// the legacy hash is replaced with the bcrypt hash
+		if tt.mock.isRehash {
+			db.ExpectExec(`^\s*UPDATE\s*users\s*SET\s*password\s=\s\(\?\)\s*WHERE\s*id\s=\s\(\?\);$`).
+				WithArgs(passwordArg(tt.args.password), int64(1)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := SignIn(tt.args.email, tt.args.password)
 			if (err != nil) != tt.wantErr {
@@ -297,6 +438,9 @@ func TestSignIn(t *testing.T) {
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("SignIn() = %v, want %v", got, tt.want)
 			}
+			if err := db.ExpectationsWereMet(); err != nil {
+				t.Errorf("SignIn() expectation error = %v", err)
+			}
 		})
 	}
 }
@@ -349,7 +493,7 @@ func TestIsPhoneExist(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectQuery(tt.mock.query)
+		q := db.ExpectQuery(tt.mock.query).WithArgs(tt.args.phone, tt.args.identityCode)
 		if tt.mock.err == nil {
 			q.WillReturnRows(sqlmock.NewRows(tt.mock.column).
 				AddRow(tt.mock.result...))
@@ -413,7 +557,7 @@ func TestIsLineIDExist(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectQuery(tt.mock.query)
+		q := db.ExpectQuery(tt.mock.query).WithArgs(tt.args.lineID, tt.args.identityCode)
 		if tt.mock.err == nil {
 			q.WillReturnRows(sqlmock.NewRows(tt.mock.column).
 				AddRow(tt.mock.result...))
@@ -465,6 +609,12 @@ func TestIsValidConfirmationCode(t *testing.T) {
 				result: []driver.Value{"0", "1234"},
 				err:    nil,
 			},
+			mockUpdate: mockUpdate{
+				query:        `UPDATE(\s*)users(\s*)SET(\s*)email_verification_attempt(\s*)=(\s*)email_verification_attempt \+ 1,(\s*)updated_at = NOW\(\)(\s*)WHERE(\s*)id(\s*)=(\s*)(.+)AND(\s*)email_verification_attempt(\s*)<(\s*)(.+)`,
+				lastInsertID: 1,
+				rowsAffected: 1,
+				err:          nil,
+			},
 			want: true,
 		},
 		{
@@ -529,10 +679,30 @@ func TestIsValidConfirmationCode(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "Test Case 6",
+			args: args{
+				email: "risal@live.com",
+				code:  1234,
+			},
+			mockSelect: mockSelect{
+				query:  `^\s*SELECT(\s*)id,(\s*)email_verification_attempt,(\s*)email_verification_code(\s*)FROM(\s*)users(\s*)WHERE(\s*)email(\s*)=(\s*)(.+)AND(\s*)NOW\(\)(\s*)<(\s*)email_verification_expire_date(\s*)LIMIT(\s*)1`,
+				column: []string{"email_verification_attempt", "email_verification_code"},
+				result: []driver.Value{"2", "1234"},
+				err:    nil,
+			},
+			mockUpdate: mockUpdate{
+				query:        `UPDATE(\s*)users(\s*)SET(\s*)email_verification_attempt(\s*)=(\s*)email_verification_attempt \+ 1,(\s*)updated_at = NOW\(\)(\s*)WHERE(\s*)id(\s*)=(\s*)(.+)AND(\s*)email_verification_attempt(\s*)<(\s*)(.+)`,
+				lastInsertID: 1,
+				rowsAffected: 0,
+				err:          nil,
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectQuery(tt.mockSelect.query)
+		q := db.ExpectQuery(tt.mockSelect.query).WithArgs(tt.args.email)
 		// QuerySelect Mock
 		if tt.mockSelect.err == nil {
 			q.WillReturnRows(sqlmock.NewRows(tt.mockSelect.column).
@@ -639,7 +809,7 @@ func TestSelectDashboard(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectQuery(tt.mock.query)
+		q := db.ExpectQuery(tt.mock.query).WithArgs(StatusVerified, StatusActivated, tt.args.id, tt.args.limit, tt.args.offset)
 		if tt.mock.err == nil {
 			rows := sqlmock.NewRows(tt.mock.column)
 			for _, val := range tt.mock.result {
@@ -686,7 +856,7 @@ func TestUpdateStatus(t *testing.T) {
 				status:       1,
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*status\s*=\s*\(\d\),\s*updated_at\s*=\s*NOW\(\)\s*WHERE\s*identity_code\s*=\s*\(\d*\);$`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*status\s*=\s*\(\?\),\s*updated_at\s*=\s*NOW\(\)\s*WHERE\s*identity_code\s*=\s*\(\?\);$`,
 				lastInsertID: 1,
 				rowsAffected: 1,
 				err:          nil,
@@ -700,7 +870,7 @@ func TestUpdateStatus(t *testing.T) {
 				status:       1,
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*status\s*=\s*\(\d\),\s*updated_at\s*=\s*NOW\(\)\s*WHERE\s*identity_code\s*=\s*\(\d*\);$`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*status\s*=\s*\(\?\),\s*updated_at\s*=\s*NOW\(\)\s*WHERE\s*identity_code\s*=\s*\(\?\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          fmt.Errorf("Error connection"),
@@ -714,7 +884,7 @@ func TestUpdateStatus(t *testing.T) {
 				status:       1,
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*status\s*=\s*\(\d\),\s*updated_at\s*=\s*NOW\(\)\s*WHERE\s*identity_code\s*=\s*\(\d*\);$`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*status\s*=\s*\(\?\),\s*updated_at\s*=\s*NOW\(\)\s*WHERE\s*identity_code\s*=\s*\(\?\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          nil,
@@ -724,7 +894,7 @@ func TestUpdateStatus(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectExec(tt.mock.query)
+		q := db.ExpectExec(tt.mock.query).WithArgs(tt.args.status, tt.args.identityCode)
 		if tt.mock.err == nil {
 			q.WillReturnResult(sqlmock.NewResult(tt.mock.lastInsertID, tt.mock.rowsAffected))
 		} else {
@@ -797,7 +967,7 @@ func TestUpdateToVerified(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectExec(tt.mock.query)
+		q := db.ExpectExec(tt.mock.query).WithArgs(StatusVerified, tt.args.identityCode)
 		if tt.mock.err == nil {
 			q.WillReturnResult(sqlmock.NewResult(tt.mock.lastInsertID, tt.mock.rowsAffected))
 		} else {
@@ -813,12 +983,15 @@ func TestUpdateToVerified(t *testing.T) {
 }
 
 func TestChangePassword(t *testing.T) {
+	md5Hash := helper.StringToMD5("Qwerty123")
+
 	type args struct {
 		identityCode int64
 		password     string
 		oldPassword  string
 	}
 	type mock struct {
+		current      string
 		query        string
 		lastInsertID int64
 		rowsAffected int64
@@ -834,11 +1007,12 @@ func TestChangePassword(t *testing.T) {
 			name: "Test Case 1",
 			args: args{
 				identityCode: 140810140016,
-				password:     "f1cf8402f0fb0511a8054c697fc4bee1",
-				oldPassword:  "f6ec409a28c6d93c11c056f1409ed887",
+				password:     "Qwerty321",
+				oldPassword:  "Qwerty123",
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*password\s=\s\('([\w]*)'\)\s*WHERE\s*identity_code\s=\s\((\d+)\)\sAND\s*password\s=\s\('(\w*)'\);$`,
+				current:      md5Hash,
+				query:        `^\s*UPDATE\s*users\s*SET\s*password\s=\s\(\?\)\s*WHERE\s*identity_code\s=\s\(\?\);$`,
 				lastInsertID: 1,
 				rowsAffected: 1,
 				err:          nil,
@@ -849,11 +1023,12 @@ func TestChangePassword(t *testing.T) {
 			name: "Test Case 2",
 			args: args{
 				identityCode: 140810140016,
-				password:     "f1cf8402f0fb0511a8054c697fc4bee1",
-				oldPassword:  "f6ec409a28c6d93c11c056f1409ed887",
+				password:     "Qwerty321",
+				oldPassword:  "Qwerty123",
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*password\s=\s\('([\w]*)'\)\s*WHERE\s*identity_code\s=\s\((\d+)\)\sAND\s*password\s=\s\('(\w*)'\);$`,
+				current:      md5Hash,
+				query:        `^\s*UPDATE\s*users\s*SET\s*password\s=\s\(\?\)\s*WHERE\s*identity_code\s=\s\(\?\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          nil,
@@ -864,25 +1039,43 @@ func TestChangePassword(t *testing.T) {
 			name: "Test Case 3",
 			args: args{
 				identityCode: 140810140016,
-				password:     "f1cf8402f0fb0511a8054c697fc4bee1",
-				oldPassword:  "f6ec409a28c6d93c11c056f1409ed887",
+				password:     "Qwerty321",
+				oldPassword:  "Qwerty123",
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*password\s=\s\('([\w]*)'\)\s*WHERE\s*identity_code\s=\s\((\d+)\)\sAND\s*password\s=\s\('(\w*)'\);$`,
+				current:      md5Hash,
+				query:        `^\s*UPDATE\s*users\s*SET\s*password\s=\s\(\?\)\s*WHERE\s*identity_code\s=\s\(\?\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          fmt.Errorf("Error connection"),
 			},
 			wantErr: true,
 		},
+		{
+			name: "Test Case 4",
+			args: args{
+				identityCode: 140810140016,
+				password:     "Qwerty321",
+				oldPassword:  "Qwerty000",
+			},
+			mock: mock{
+				current: md5Hash,
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectExec(tt.mock.query)
-		if tt.mock.err == nil {
-			q.WillReturnResult(sqlmock.NewResult(tt.mock.lastInsertID, tt.mock.rowsAffected))
-		} else {
-			q.WillReturnError(tt.mock.err)
+		db.ExpectQuery(`^\s*SELECT\s*password\s*FROM\s*users\s*WHERE\s*identity_code\s=\s\(\?\)\s*LIMIT\s1;$`).
+			WithArgs(tt.args.identityCode).
+			WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow(tt.mock.current))
+		if tt.mock.query != "" {
+			q := db.ExpectExec(tt.mock.query).WithArgs(passwordArg(tt.args.password), tt.args.identityCode)
+			if tt.mock.err == nil {
+				q.WillReturnResult(sqlmock.NewResult(tt.mock.lastInsertID, tt.mock.rowsAffected))
+			} else {
+				q.WillReturnError(tt.mock.err)
+			}
 		}
 
 		t.Run(tt.name, func(t *testing.T) {
@@ -917,7 +1110,7 @@ func TestForgotNewPassword(t *testing.T) {
 				password: "f1cf8402f0fb0511a8054c697fc4bee1",
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*password\s=\s\('([\w]*)'\),\s*email_verification_code\s=\sNULL,\s*email_verification_expire_date\s=\sNULL,\s*email_verification_attempt\s=\sNULL,\s*updated_at\s=\sNOW\(\)\s*WHERE\s*email\s=\s\('([\w@.]*)'\);$`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*password\s=\s\(\?\),\s*email_verification_code\s=\sNULL,\s*email_verification_expire_date\s=\sNULL,\s*email_verification_attempt\s=\sNULL,\s*updated_at\s=\sNOW\(\)\s*WHERE\s*email\s=\s\(\?\);$`,
 				lastInsertID: 1,
 				rowsAffected: 1,
 				err:          nil,
@@ -931,7 +1124,7 @@ func TestForgotNewPassword(t *testing.T) {
 				password: "f1cf8402f0fb0511a8054c697fc4bee1",
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*password\s=\s\('([\w]*)'\),\s*email_verification_code\s=\sNULL,\s*email_verification_expire_date\s=\sNULL,\s*email_verification_attempt\s=\sNULL,\s*updated_at\s=\sNOW\(\)\s*WHERE\s*email\s=\s\('([\w@.]*)'\);$`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*password\s=\s\(\?\),\s*email_verification_code\s=\sNULL,\s*email_verification_expire_date\s=\sNULL,\s*email_verification_attempt\s=\sNULL,\s*updated_at\s=\sNOW\(\)\s*WHERE\s*email\s=\s\(\?\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          nil,
@@ -945,7 +1138,7 @@ func TestForgotNewPassword(t *testing.T) {
 				password: "f1cf8402f0fb0511a8054c697fc4bee1",
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*password\s=\s\('([\w]*)'\),\s*email_verification_code\s=\sNULL,\s*email_verification_expire_date\s=\sNULL,\s*email_verification_attempt\s=\sNULL,\s*updated_at\s=\sNOW\(\)\s*WHERE\s*email\s=\s\('([\w@.]*)'\);$`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*password\s=\s\(\?\),\s*email_verification_code\s=\sNULL,\s*email_verification_expire_date\s=\sNULL,\s*email_verification_attempt\s=\sNULL,\s*updated_at\s=\sNOW\(\)\s*WHERE\s*email\s=\s\(\?\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          fmt.Errorf("Error connection"),
@@ -955,7 +1148,7 @@ func TestForgotNewPassword(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectExec(tt.mock.query)
+		q := db.ExpectExec(tt.mock.query).WithArgs(passwordArg(tt.args.password), tt.args.email)
 		if tt.mock.err == nil {
 			q.WillReturnResult(sqlmock.NewResult(tt.mock.lastInsertID, tt.mock.rowsAffected))
 		} else {
@@ -993,7 +1186,7 @@ func TestGenerateVerification(t *testing.T) {
 				identity: 140810140016,
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*email_verification_code\s=\s\(\d+\),\s*email_verification_expire_date\s=\s\(DATE_ADD\(NOW\(\), INTERVAL 30 MINUTE\)\),\s*email_verification_attempt\s*=\s*0,\s*updated_at\s=\sNOW\(\)\s*WHERE\s*identity_code\s=\s\(\d+\);`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*email_verification_code\s=\s\(\?\),\s*email_verification_expire_date\s=\s\(DATE_ADD\(NOW\(\), INTERVAL 30 MINUTE\)\),\s*email_verification_attempt\s*=\s*0,\s*updated_at\s=\sNOW\(\)\s*WHERE\s*identity_code\s=\s\(\?\);`,
 				lastInsertID: 1,
 				rowsAffected: 1,
 				err:          nil,
@@ -1012,7 +1205,7 @@ func TestGenerateVerification(t *testing.T) {
 				identity: 140810140016,
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*email_verification_code\s=\s\(\d+\),\s*email_verification_expire_date\s=\s\(DATE_ADD\(NOW\(\), INTERVAL 30 MINUTE\)\),\s*email_verification_attempt\s*=\s*0,\s*updated_at\s=\sNOW\(\)\s*WHERE\s*identity_code\s=\s\(\d+\);`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*email_verification_code\s=\s\(\?\),\s*email_verification_expire_date\s=\s\(DATE_ADD\(NOW\(\), INTERVAL 30 MINUTE\)\),\s*email_verification_attempt\s*=\s*0,\s*updated_at\s=\sNOW\(\)\s*WHERE\s*identity_code\s=\s\(\?\);`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          nil,
@@ -1028,7 +1221,7 @@ func TestGenerateVerification(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectExec(tt.mock.query)
+		q := db.ExpectExec(tt.mock.query).WithArgs(sqlmock.AnyArg(), tt.args.identity)
 		if tt.mock.err == nil {
 			q.WillReturnResult(sqlmock.NewResult(tt.mock.lastInsertID, tt.mock.rowsAffected))
 		} else {
@@ -1082,7 +1275,7 @@ func TestUpdateProfile(t *testing.T) {
 				gender:       1,
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*name\s=\s\('([\w ]*)'\),\s*phone\s=\s\('\d+'\),\s*line_id\s=\s\('(.+)'\),\s*note\s=\s\('(.+),\s*gender\s=\s\(\d\),\s*updated_at\s=\sNOW\(\)\s*WHERE\s*identity_code\s=\s\(\d+\);$`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*name\s=\s\(\?\),\s*phone\s=\s\(\?\),\s*line_id\s=\s\(\?\),\s*note\s=\s\(\?\),\s*gender\s=\s\(\?\),\s*updated_at\s=\sNOW\(\)\s*WHERE\s*identity_code\s=\s\(\?\);$`,
 				lastInsertID: 1,
 				rowsAffected: 1,
 				err:          nil,
@@ -1100,7 +1293,7 @@ func TestUpdateProfile(t *testing.T) {
 				gender:       1,
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*name\s=\s\('([\w ]*)'\),\s*phone\s=\s\('\d+'\),\s*line_id\s=\s\('(.+)'\),\s*note\s=\s\('(.+),\s*gender\s=\s\(\d\),\s*updated_at\s=\sNOW\(\)\s*WHERE\s*identity_code\s=\s\(\d+\);$`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*name\s=\s\(\?\),\s*phone\s=\s\(\?\),\s*line_id\s=\s\(\?\),\s*note\s=\s\(\?\),\s*gender\s=\s\(\?\),\s*updated_at\s=\sNOW\(\)\s*WHERE\s*identity_code\s=\s\(\?\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          nil,
@@ -1118,7 +1311,7 @@ func TestUpdateProfile(t *testing.T) {
 				gender:       1,
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*name\s=\s\('([\w ]*)'\),\s*phone\s=\s\('\d+'\),\s*line_id\s=\s\('(.+)'\),\s*note\s=\s\('(.+),\s*gender\s=\s\(\d\),\s*updated_at\s=\sNOW\(\)\s*WHERE\s*identity_code\s=\s\(\d+\);$`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*name\s=\s\(\?\),\s*phone\s=\s\(\?\),\s*line_id\s=\s\(\?\),\s*note\s=\s\(\?\),\s*gender\s=\s\(\?\),\s*updated_at\s=\sNOW\(\)\s*WHERE\s*identity_code\s=\s\(\?\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          fmt.Errorf("Error connection"),
@@ -1136,7 +1329,7 @@ func TestUpdateProfile(t *testing.T) {
 				gender:       3,
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*name\s=\s\('([\w ]*)'\),\s*phone\s=\s\('\d+'\),\s*line_id\s=\s\('(.+)'\),\s*note\s=\s\('(.+),\s*gender\s=\s\(\d\),\s*updated_at\s=\sNOW\(\)\s*WHERE\s*identity_code\s=\s\(\d+\);$`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*name\s=\s\(\?\),\s*phone\s=\s\(\?\),\s*line_id\s=\s\(\?\),\s*note\s=\s\(\?\),\s*gender\s=\s\(\?\),\s*updated_at\s=\sNOW\(\)\s*WHERE\s*identity_code\s=\s\(\?\);$`,
 				lastInsertID: 1,
 				rowsAffected: 1,
 				err:          nil,
@@ -1146,7 +1339,11 @@ func TestUpdateProfile(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectExec(tt.mock.query)
+		gender := tt.args.gender
+		if gender != GenderMale && gender != GenderFemale {
+			gender = GenderUndefined
+		}
+		q := db.ExpectExec(tt.mock.query).WithArgs(tt.args.name, tt.args.phone, tt.args.lineID, tt.args.note, gender, tt.args.identityCode)
 		if tt.mock.err == nil {
 			q.WillReturnResult(sqlmock.NewResult(tt.mock.lastInsertID, tt.mock.rowsAffected))
 		} else {
@@ -1189,7 +1386,7 @@ func TestSignUp(t *testing.T) {
 				password:     "2af9b1ba42dc5eb01743e6b3759b6e4b",
 			},
 			mock: mock{
-				query:        `^\s*INSERT\sINTO\s*users\s\(\s*name,\s*email,\s*password,\s*identity_code,\s*created_at,\s*updated_at\s*\)\sVALUES\s\(\s*\('([\w ]*)'\),\s*\('([\w@.]+)'\),\s*\('([\w]+)'\),\s*\(\d+\)\s*,\s*NOW\(\),\s*NOW\(\)\s*\);$`,
+				query:        `^\s*INSERT\sINTO\s*users\s\(\s*name,\s*email,\s*password,\s*identity_code,\s*created_at,\s*updated_at\s*\)\sVALUES\s\(\s*\(\?\),\s*\(\?\),\s*\(\?\),\s*\(\?\)\s*,\s*NOW\(\),\s*NOW\(\)\s*\);$`,
 				lastInsertID: 1,
 				rowsAffected: 1,
 				err:          nil,
@@ -1205,7 +1402,7 @@ func TestSignUp(t *testing.T) {
 				password:     "2af9b1ba42dc5eb01743e6b3759b6e4b",
 			},
 			mock: mock{
-				query:        `^\s*INSERT\sINTO\s*users\s\(\s*name,\s*email,\s*password,\s*identity_code,\s*created_at,\s*updated_at\s*\)\sVALUES\s\(\s*\('([\w ]*)'\),\s*\('([\w@.]+)'\),\s*\('([\w]+)'\),\s*\(\d+\)\s*,\s*NOW\(\),\s*NOW\(\)\s*\);$`,
+				query:        `^\s*INSERT\sINTO\s*users\s\(\s*name,\s*email,\s*password,\s*identity_code,\s*created_at,\s*updated_at\s*\)\sVALUES\s\(\s*\(\?\),\s*\(\?\),\s*\(\?\),\s*\(\?\)\s*,\s*NOW\(\),\s*NOW\(\)\s*\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          nil,
@@ -1221,7 +1418,7 @@ func TestSignUp(t *testing.T) {
 				password:     "2af9b1ba42dc5eb01743e6b3759b6e4b",
 			},
 			mock: mock{
-				query:        `^\s*INSERT\sINTO\s*users\s\(\s*name,\s*email,\s*password,\s*identity_code,\s*created_at,\s*updated_at\s*\)\sVALUES\s\(\s*\('([\w ]*)'\),\s*\('([\w@.]+)'\),\s*\('([\w]+)'\),\s*\(\d+\)\s*,\s*NOW\(\),\s*NOW\(\)\s*\);$`,
+				query:        `^\s*INSERT\sINTO\s*users\s\(\s*name,\s*email,\s*password,\s*identity_code,\s*created_at,\s*updated_at\s*\)\sVALUES\s\(\s*\(\?\),\s*\(\?\),\s*\(\?\),\s*\(\?\)\s*,\s*NOW\(\),\s*NOW\(\)\s*\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          fmt.Errorf("Error connection"),
@@ -1231,7 +1428,7 @@ func TestSignUp(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectExec(tt.mock.query)
+		q := db.ExpectExec(tt.mock.query).WithArgs(tt.args.name, tt.args.email, passwordArg(tt.args.password), tt.args.identityCode)
 		if tt.mock.err == nil {
 			q.WillReturnResult(sqlmock.NewResult(tt.mock.lastInsertID, tt.mock.rowsAffected))
 		} else {
@@ -1271,7 +1468,7 @@ func TestSelectByID(t *testing.T) {
 				column: []string{ColID, ColName, ColEmail, ColPhone},
 			},
 			mock: mock{
-				query:  `^\s*SELECT\s*(.+)\s*FROM\s*users\s*WHERE\s*id\s*IN\s*\(([\d, ]*)\);$`,
+				query:  `^\s*SELECT\s*(.+)\s*FROM\s*users\s*WHERE\s*id\s*IN\s*\(\?(,\s\?)*\);$`,
 				column: []string{"id", "name", "email", "phone"},
 				result: [][]driver.Value{
 					[]driver.Value{
@@ -1315,7 +1512,7 @@ func TestSelectByID(t *testing.T) {
 				column: []string{},
 			},
 			mock: mock{
-				query:  `^\s*SELECT\s*(.+)\s*FROM\s*users\s*WHERE\s*id\s*IN\s*\(([\d, ]*)\);$`,
+				query:  `^\s*SELECT\s*(.+)\s*FROM\s*users\s*WHERE\s*id\s*IN\s*\(\?(,\s\?)*\);$`,
 				column: []string{"id", "name", "email", "gender", "note", "status", "identity_code", "line_id", "phone", "rolegroups_id"},
 				result: [][]driver.Value{
 					[]driver.Value{"1", "Risal Falah", "risal@live.com", "1", "", "2", "140810140016", nil, nil, nil},
@@ -1345,7 +1542,7 @@ func TestSelectByID(t *testing.T) {
 				column: []string{},
 			},
 			mock: mock{
-				query:  `^\s*SELECT\s*(.+)\s*FROM\s*users\s*WHERE\s*id\s*IN\s*\(([\d, ]*)\);$`,
+				query:  `^\s*SELECT\s*(.+)\s*FROM\s*users\s*WHERE\s*id\s*IN\s*\(\?(,\s\?)*\);$`,
 				column: []string{"id", "name", "email", "gender", "note", "status", "identity_code", "line_id", "phone", "rolegroups_id"},
 				result: nil,
 				err:    fmt.Errorf("Error connection"),
@@ -1356,7 +1553,11 @@ func TestSelectByID(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectQuery(tt.mock.query)
+		var ids []driver.Value
+		for _, val := range tt.args.id {
+			ids = append(ids, val)
+		}
+		q := db.ExpectQuery(tt.mock.query).WithArgs(ids...)
 		if tt.mock.err == nil {
 			rows := sqlmock.NewRows(tt.mock.column)
 			for _, val := range tt.mock.result {
@@ -1414,7 +1615,7 @@ func TestUpdate(t *testing.T) {
 				status:       1,
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*name\s*=\s*\('[\w ]*'\),\s*phone\s*=\s*\('\d*'\),\s*line_id\s*=\s*\('\w*'\),\s*note\s*=\s*(.+),\s*gender\s*=\s*\([012]\),\s*status\s*=\s*\([012]\),\s*updated_at\s*=\s*NOW\(\)\s*WHERE\s*identity_code\s*=\s*\(\d*\);$`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*name\s*=\s*\(\?\),\s*phone\s*=\s*\(\?\),\s*line_id\s*=\s*\(\?\),\s*note\s*=\s*\(\?\),\s*gender\s*=\s*\(\?\),\s*status\s*=\s*\(\?\),\s*updated_at\s*=\s*NOW\(\)\s*WHERE\s*identity_code\s*=\s*\(\?\);$`,
 				lastInsertID: 1,
 				rowsAffected: 1,
 				err:          nil,
@@ -1433,7 +1634,7 @@ func TestUpdate(t *testing.T) {
 				status:       1,
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*name\s*=\s*\('[\w ]*'\),\s*phone\s*=\s*\('\d*'\),\s*line_id\s*=\s*\('\w*'\),\s*note\s*=\s*(.+),\s*gender\s*=\s*\([012]\),\s*status\s*=\s*\([012]\),\s*updated_at\s*=\s*NOW\(\)\s*WHERE\s*identity_code\s*=\s*\(\d*\);$`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*name\s*=\s*\(\?\),\s*phone\s*=\s*\(\?\),\s*line_id\s*=\s*\(\?\),\s*note\s*=\s*\(\?\),\s*gender\s*=\s*\(\?\),\s*status\s*=\s*\(\?\),\s*updated_at\s*=\s*NOW\(\)\s*WHERE\s*identity_code\s*=\s*\(\?\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          nil,
@@ -1452,7 +1653,7 @@ func TestUpdate(t *testing.T) {
 				status:       1,
 			},
 			mock: mock{
-				query:        `^\s*UPDATE\s*users\s*SET\s*name\s*=\s*\('[\w ]*'\),\s*phone\s*=\s*\('\d*'\),\s*line_id\s*=\s*\('\w*'\),\s*note\s*=\s*(.+),\s*gender\s*=\s*\([012]\),\s*status\s*=\s*\([012]\),\s*updated_at\s*=\s*NOW\(\)\s*WHERE\s*identity_code\s*=\s*\(\d*\);$`,
+				query:        `^\s*UPDATE\s*users\s*SET\s*name\s*=\s*\(\?\),\s*phone\s*=\s*\(\?\),\s*line_id\s*=\s*\(\?\),\s*note\s*=\s*\(\?\),\s*gender\s*=\s*\(\?\),\s*status\s*=\s*\(\?\),\s*updated_at\s*=\s*NOW\(\)\s*WHERE\s*identity_code\s*=\s*\(\?\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          fmt.Errorf("Error connection"),
@@ -1462,7 +1663,11 @@ func TestUpdate(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectExec(tt.mock.query)
+		gender := tt.args.gender
+		if gender != GenderMale && gender != GenderFemale {
+			gender = GenderUndefined
+		}
+		q := db.ExpectExec(tt.mock.query).WithArgs(tt.args.name, tt.args.phone, tt.args.lineID, tt.args.note, gender, tt.args.status, tt.args.identityCode)
 		if tt.mock.err == nil {
 			q.WillReturnResult(sqlmock.NewResult(tt.mock.lastInsertID, tt.mock.rowsAffected))
 		} else {
@@ -1498,7 +1703,7 @@ func TestDelete(t *testing.T) {
 				identityCode: 140810140016,
 			},
 			mock: mock{
-				query:        `^\s*DELETE\s*FROM\s*users\s*WHERE\s*identity_code\s*=\s*\(\d*\);$`,
+				query:        `^\s*DELETE\s*FROM\s*users\s*WHERE\s*identity_code\s*=\s*\(\?\);$`,
 				lastInsertID: 1,
 				rowsAffected: 1,
 				err:          nil,
@@ -1511,7 +1716,7 @@ func TestDelete(t *testing.T) {
 				identityCode: 140810140016,
 			},
 			mock: mock{
-				query:        `^\s*DELETE\s*FROM\s*users\s*WHERE\s*identity_code\s*=\s*\(\d*\);$`,
+				query:        `^\s*DELETE\s*FROM\s*users\s*WHERE\s*identity_code\s*=\s*\(\?\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          nil,
@@ -1524,7 +1729,7 @@ func TestDelete(t *testing.T) {
 				identityCode: 140810140016,
 			},
 			mock: mock{
-				query:        `^\s*DELETE\s*FROM\s*users\s*WHERE\s*identity_code\s*=\s*\(\d*\);$`,
+				query:        `^\s*DELETE\s*FROM\s*users\s*WHERE\s*identity_code\s*=\s*\(\?\);$`,
 				lastInsertID: 1,
 				rowsAffected: 1,
 				err:          fmt.Errorf("Error connection"),
@@ -1534,7 +1739,7 @@ func TestDelete(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectExec(tt.mock.query)
+		q := db.ExpectExec(tt.mock.query).WithArgs(tt.args.identityCode)
 		if tt.mock.err == nil {
 			q.WillReturnResult(sqlmock.NewResult(tt.mock.lastInsertID, tt.mock.rowsAffected))
 		} else {
@@ -1574,7 +1779,7 @@ func TestCreate(t *testing.T) {
 				email:        "risal@live.com",
 			},
 			mock: mock{
-				query:        `^\s*INSERT\s*INTO\s*users\s*\(\s*name,\s*email,\s*password,\s*identity_code,\s*status,\s*created_at,\s*updated_at\s*\)\s*VALUES\s*\(\s*\('[\w ]*'\),\s*\('[\w@.]*'\),\s*\('[\S]'\),\s*\(\d*\),\s*\([012]\),\s*NOW\(\),\s*NOW\(\)\s*\);$`,
+				query:        `^\s*INSERT\s*INTO\s*users\s*\(\s*name,\s*email,\s*password,\s*identity_code,\s*status,\s*created_at,\s*updated_at\s*\)\s*VALUES\s*\(\s*\(\?\),\s*\(\?\),\s*\('[\S]'\),\s*\(\?\),\s*\(\?\),\s*NOW\(\),\s*NOW\(\)\s*\);$`,
 				lastInsertID: 1,
 				rowsAffected: 1,
 				err:          nil,
@@ -1589,7 +1794,7 @@ func TestCreate(t *testing.T) {
 				email:        "risal@live.com",
 			},
 			mock: mock{
-				query:        `^\s*INSERT\s*INTO\s*users\s*\(\s*name,\s*email,\s*password,\s*identity_code,\s*status,\s*created_at,\s*updated_at\s*\)\s*VALUES\s*\(\s*\('[\w ]*'\),\s*\('[\w@.]*'\),\s*\('[\S]'\),\s*\(\d*\),\s*\([012]\),\s*NOW\(\),\s*NOW\(\)\s*\);$`,
+				query:        `^\s*INSERT\s*INTO\s*users\s*\(\s*name,\s*email,\s*password,\s*identity_code,\s*status,\s*created_at,\s*updated_at\s*\)\s*VALUES\s*\(\s*\(\?\),\s*\(\?\),\s*\('[\S]'\),\s*\(\?\),\s*\(\?\),\s*NOW\(\),\s*NOW\(\)\s*\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          nil,
@@ -1604,7 +1809,7 @@ func TestCreate(t *testing.T) {
 				email:        "risal@live.com",
 			},
 			mock: mock{
-				query:        `^\s*INSERT\s*INTO\s*users\s*\(\s*name,\s*email,\s*password,\s*identity_code,\s*status,\s*created_at,\s*updated_at\s*\)\s*VALUES\s*\(\s*\('[\w ]*'\),\s*\('[\w@.]*'\),\s*\('[\S]'\),\s*\(\d*\),\s*\([012]\),\s*NOW\(\),\s*NOW\(\)\s*\);$`,
+				query:        `^\s*INSERT\s*INTO\s*users\s*\(\s*name,\s*email,\s*password,\s*identity_code,\s*status,\s*created_at,\s*updated_at\s*\)\s*VALUES\s*\(\s*\(\?\),\s*\(\?\),\s*\('[\S]'\),\s*\(\?\),\s*\(\?\),\s*NOW\(\),\s*NOW\(\)\s*\);$`,
 				lastInsertID: 0,
 				rowsAffected: 0,
 				err:          fmt.Errorf("Error connection"),
@@ -1614,16 +1819,156 @@ func TestCreate(t *testing.T) {
 	}
 	for _, tt := range tests {
 		db, _ := conn.InitDBMock()
-		q := db.ExpectExec(tt.mock.query)
+		q := db.ExpectExec(tt.mock.query).WithArgs(tt.args.name, tt.args.email, tt.args.identityCode, StatusActivated)
 		if tt.mock.err == nil {
 			q.WillReturnResult(sqlmock.NewResult(tt.mock.lastInsertID, tt.mock.rowsAffected))
 		} else {
 			q.WillReturnError(tt.mock.err)
 		}
 		t.Run(tt.name, func(t *testing.T) {
-			if err := Create(tt.args.identityCode, tt.args.name, tt.args.email); (err != nil) != tt.wantErr {
+			if _, err := Create(tt.args.identityCode, tt.args.name, tt.args.email); (err != nil) != tt.wantErr {
 				t.Errorf("Create() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestSelectIDByRoleGroup(t *testing.T) {
+	type args struct {
+		rolegroupID   int64
+		identityCodes []int64
+	}
+	type mock struct {
+		query  string
+		args   []driver.Value
+		result [][]driver.Value
+		err    error
+	}
+	tests := []struct {
+		name    string
+		args    args
+		mock    mock
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "Test Case 1",
+			args: args{
+				rolegroupID: 2,
+			},
+			mock: mock{
+				query: `^\s*SELECT\s*id\s*FROM\s*users\s*WHERE\s*rolegroups_id\s*=\s*\(\?\)\s*;$`,
+				args:  []driver.Value{int64(2)},
+				result: [][]driver.Value{
+					[]driver.Value{"12"},
+					[]driver.Value{"15"},
+				},
+			},
+			want:    []int64{12, 15},
+			wantErr: false,
+		},
+		{
+			name: "Test Case 2",
+			args: args{
+				rolegroupID:   2,
+				identityCodes: []int64{140810140016, 140810140060},
+			},
+			mock: mock{
+				query: `^\s*SELECT\s*id\s*FROM\s*users\s*WHERE\s*rolegroups_id\s*=\s*\(\?\)\s*AND\s*identity_code\s*IN\s*\(\?(,\s\?)*\);$`,
+				args:  []driver.Value{int64(2), int64(140810140016), int64(140810140060)},
+				result: [][]driver.Value{
+					[]driver.Value{"12"},
+				},
+			},
+			want:    []int64{12},
+			wantErr: false,
+		},
+		{
+			name: "Test Case 3",
+			args: args{
+				rolegroupID: 2,
+			},
+			mock: mock{
+				query: `^\s*SELECT\s*id\s*FROM\s*users\s*WHERE\s*rolegroups_id\s*=\s*\(\?\)\s*;$`,
+				args:  []driver.Value{int64(2)},
+				err:   fmt.Errorf("Error connection"),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		db, _ := conn.InitDBMock()
+		q := db.ExpectQuery(tt.mock.query).WithArgs(tt.mock.args...)
+		if tt.mock.err == nil {
+			rows := sqlmock.NewRows([]string{"id"})
+			for _, val := range tt.mock.result {
+				rows.AddRow(val...)
+			}
+			q.WillReturnRows(rows)
+		} else {
+			q.WillReturnError(tt.mock.err)
+		}
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectIDByRoleGroup(tt.args.rolegroupID, tt.args.identityCodes...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SelectIDByRoleGroup() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectIDByRoleGroup() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectRegistered(t *testing.T) {
+	db, _ := conn.InitDBMock()
+	db.ExpectQuery(`^\s*SELECT\s*identity_code,\s*email\s*FROM\s*users\s*WHERE\s*identity_code\s*IN\s*\(\?(,\s\?)*\)\s*OR\s*email\s*IN\s*\(\?(,\s\?)*\);$`).
+		WithArgs(int64(140810140016), int64(140810140060), "risal@live.com", "asep@live.com").
+		WillReturnRows(sqlmock.NewRows([]string{"identity_code", "email"}).
+			AddRow("140810140016", "risal@live.com"))
+
+	got, err := SelectRegistered([]int64{140810140016, 140810140060}, []string{"risal@live.com", "asep@live.com"})
+	if err != nil {
+		t.Fatalf("SelectRegistered() error = %v", err)
+	}
+	want := []User{{IdentityCode: 140810140016, Email: "risal@live.com"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SelectRegistered() = %v, want %v", got, want)
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	got, err = SelectRegistered(nil, nil)
+	if err != nil || len(got) != 0 {
+		t.Errorf("SelectRegistered() = %v, %v", got, err)
+	}
+}
+
+func TestSelectByIdentityCode(t *testing.T) {
+	db, _ := conn.InitDBMock()
+	db.ExpectQuery(`^\s*SELECT\s*id, name\s*FROM\s*users\s*WHERE\s*identity_code\s*IN\s*\(\?(,\s\?)*\);$`).
+		WithArgs(int64(140810140016), int64(140810140060)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow("12", "Risal Falah"))
+
+	got, err := SelectByIdentityCode([]int64{140810140016, 140810140060}, ColID, ColName)
+	if err != nil {
+		t.Fatalf("SelectByIdentityCode() error = %v", err)
+	}
+	want := []User{{ID: 12, Name: "Risal Falah"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SelectByIdentityCode() = %v, want %v", got, want)
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	got, err = SelectByIdentityCode(nil)
+	if err != nil || len(got) != 0 {
+		t.Errorf("SelectByIdentityCode() = %v, %v", got, err)
+	}
+}
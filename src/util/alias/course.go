@@ -3,4 +3,16 @@ package alias
 const (
 	CourseInactive = 0
 	CourseActive   = 1
+
+	// CourseEnrollmentMax is the max number of the students or assistants decided in a request
+	CourseEnrollmentMax = 100
+	// CourseCapacityMax is the max capacity of the schedule, 0 means unlimited
+	CourseCapacityMax = 500
+
+	// TimetableCourseMax is the max number of the courses scheduled by the timetable generator
+	TimetableCourseMax = 50
+	// TimetablePlaceMax is the max number of the places used by the timetable generator
+	TimetablePlaceMax = 20
+	// TimetableStep is the minutes between the start times tried by the timetable generator
+	TimetableStep = 30
 )
@@ -0,0 +1,8 @@
+package alias
+
+const (
+	// PlaceOpenTime is the minute of the day when the places start to be used, 07:00
+	PlaceOpenTime = 420
+	// PlaceCloseTime is the minute of the day when the places stop to be used, 18:00
+	PlaceCloseTime = 1080
+)
@@ -0,0 +1,7 @@
+package alias
+
+const (
+	RoleGroupNameLengthMax = 15
+	// RoleGroupUserMax is the max number of the users assigned in a request
+	RoleGroupUserMax = 100
+)
@@ -0,0 +1,6 @@
+package alias
+
+const (
+	TokenNameLengthMax = 45
+	TokenExpireMax     = 365
+)
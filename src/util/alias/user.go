@@ -11,9 +11,16 @@ const (
 
 	UserNameLengthMax     = 50
 	UserPasswordLengthMin = 6
+	UserPasswordLengthMax = 72
 	UserCodeLength        = 4
 	UserLineIDLengthMax   = 45
 	UserEmailLengthMax    = 45
 	UserCollegeLengthMax  = 45
 	UserNoteLengthMax     = 100
+	UserSessionIDLength   = 32
+
+	// UserImportMax is the max number of the accounts created by an imported file
+	UserImportMax = 500
+	// UserImportSizeMax is the max size of the imported file in bytes
+	UserImportSizeMax = 2 << 20
 )
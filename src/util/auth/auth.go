@@ -2,10 +2,11 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/rand"
 	"net/http"
 	"strings"
 	"time"
@@ -21,37 +22,57 @@ import (
 type (
 	Config struct {
 		SessionKey string `json:"sessionkey"`
+		// PasswordCost is the bcrypt cost of the password hash, the default cost is used if it's not set
+		PasswordCost int `json:"password_cost"`
+		// IdleTimeout is the session lifetime in seconds since the last request
+		IdleTimeout int64 `json:"idle_timeout"`
+		// AbsoluteTimeout is the maximum session lifetime in seconds since the sign in
+		AbsoluteTimeout int64 `json:"absolute_timeout"`
+		// CookieSecure sends the session cookie over https only
+		CookieSecure bool `json:"cookie_secure"`
+		// CookieSameSite is the SameSite attribute of the session cookie, the value is strict, lax or none
+		CookieSameSite string `json:"cookie_samesite"`
 	}
 )
 
 const (
 	sessionPrefix     = "session:"
-	character         = "!QAZ@WSX#EDC$RFV%TGB^YHN&UJM*IK<(OL>)P:?_{+}|1qaz2wsx3edc4rfv5tgb6yhn7ujm8ik,9ol.0p-[=]"
 	listPrefixSession = "session:list:"
+	activityPrefix    = "session:activity:"
+	sessionIDLength   = 32
+	publicIDLength    = 16
+	// maxUpdateAttempt is the number of attempts to update the session which is changed concurrently
+	maxUpdateAttempt = 5
+
+	defaultIdleTimeout     = 7 * 24 * 60 * 60
+	defaultAbsoluteTimeout = 30 * 24 * 60 * 60
 )
 
 var (
 	c                  Config
 	errSessionNotlogin = errors.New("SessionNotLogin")
-	charMaxIndex       = len(character)
+	// ErrSessionNotFound is returned when the session isn't the active session of the user
+	ErrSessionNotFound = errors.New("Session not found")
 )
 
 func Init(cfg Config) {
 	c = cfg
+	if c.AbsoluteTimeout <= 0 {
+		c.AbsoluteTimeout = defaultAbsoluteTimeout
+	}
+	if c.IdleTimeout <= 0 {
+		c.IdleTimeout = defaultIdleTimeout
+	}
+	if c.IdleTimeout > c.AbsoluteTimeout {
+		c.IdleTimeout = c.AbsoluteTimeout
+	}
 }
 
-// MustAuthorize you must provide the Bearer token on header if you're using this middleware
+// MustAuthorize you must provide the session cookie or the Bearer token on header if you're using this middleware.
+// The Bearer token is either the api token or the session id
 func MustAuthorize(h httprouter.Handle) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-		cookie, err := r.Cookie(c.SessionKey)
-		if err != nil {
-			template.RenderJSONResponse(w, new(template.Response).
-				SetCode(http.StatusForbidden).
-				AddError("Invalid Session"))
-			return
-		}
-
-		userData, err := getUserInfo(cookie.Value)
+		userData, err := authenticate(r)
 		if err != nil {
 			template.RenderJSONResponse(w, new(template.Response).
 				SetCode(http.StatusForbidden).
@@ -70,10 +91,9 @@ func MustAuthorize(h httprouter.Handle) httprouter.Handle {
 // OptionalAuthorize you don't really have to pass the Bearer token if using this middleware
 func OptionalAuthorize(h httprouter.Handle) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-		var userData *User
-		cookie, err := r.Cookie(c.SessionKey)
-		if err == nil {
-			userData, _ = getUserInfo(cookie.Value)
+		userData, err := authenticate(r)
+		if err != nil {
+			userData = nil
 		}
 
 		r = r.WithContext(context.WithValue(r.Context(), "User", userData))
@@ -81,43 +101,65 @@ func OptionalAuthorize(h httprouter.Handle) httprouter.Handle {
 	}
 }
 
-func getUserInfo(session string) (*User, error) {
+// getUserInfo returns the user of the session and renews the session lifetime. The session is renewed
+// for the idle timeout but it can't pass the absolute timeout. The last activity is stored on the
+// separated key so it doesn't overwrite the user updated by UpdateSession
+func getUserInfo(sessionID string, r *http.Request) (*User, error) {
 
-	session = strings.Trim(session, " ")
+	sessionID = strings.Trim(sessionID, " ")
 	client := conn.Redis.Get()
 	defer client.Close()
 
-	key := sessionPrefix + session
-	jsd, err := redis.String(client.Do("GET", key))
-
-	if err != nil && err != redis.ErrNil {
+	key := sessionPrefix + sessionID
+	jsd, err := redis.Bytes(client.Do("GET", key))
+	if err == redis.ErrNil {
+		return nil, errSessionNotlogin
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	res := &User{}
-	err = json.Unmarshal([]byte(jsd), res)
+	// the session created before the expiry support doesn't have the sign in time
+	sess := session{}
+	err = json.Unmarshal(jsd, &sess)
+	if err != nil || sess.User == nil {
+		client.Do("DEL", key)
+		return nil, errSessionNotlogin
+	}
+
+	ttl := sessionTTL(sess.CreatedAt, time.Now())
+	if ttl <= 0 {
+		client.Do("DEL", key)
+		return nil, errSessionNotlogin
+	}
+
+	_, err = client.Do("EXPIRE", key, ttl)
 	if err != nil {
 		return nil, err
 	}
 
-	if res == nil {
-		return nil, errSessionNotlogin
+	err = setActivity(client, sessionID, r, ttl)
+	if err != nil {
+		return nil, err
 	}
 
-	return res, nil
+	return sess.User, nil
 }
 
 // DestroySession is used for destroying logged in user session
 func (u User) DestroySession(r *http.Request) (*http.Cookie, error) {
-	cookie, _ := r.Cookie(c.SessionKey)
-	session := strings.Trim(cookie.Value, " ")
+	session := requestSessionID(r)
+	if session == "" {
+		return nil, errSessionNotlogin
+	}
+
 	client := conn.Redis.Get()
 	defer client.Close()
 
 	key := sessionPrefix + session
 	keyList := fmt.Sprintf("%s%d", listPrefixSession, u.ID)
 
-	_, err := redis.Bool(client.Do("DEL", key))
+	_, err := redis.Bool(client.Do("DEL", key, activityPrefix+session))
 	if err != nil && err != redis.ErrNil {
 		return nil, err
 	}
@@ -127,12 +169,9 @@ func (u User) DestroySession(r *http.Request) (*http.Cookie, error) {
 		return nil, err
 	}
 
-	return &http.Cookie{
-		Name:    c.SessionKey,
-		Expires: time.Now(),
-		Value:   "unuse",
-		Path:    "/",
-	}, nil
+	cookie := newCookie("unuse", time.Unix(0, 0))
+	cookie.MaxAge = -1
+	return cookie, nil
 }
 
 // DestroyAllSession is used for destroying all listed session of user
@@ -149,7 +188,7 @@ func DestroyAllSession(id int64) error {
 
 	// delete all logged in session
 	for _, key := range keys {
-		_, err = redis.Bool(client.Do("DEL", key))
+		_, err = redis.Bool(client.Do("DEL", key, activityKey(key)))
 		if err != nil && err != redis.ErrNil {
 			return err
 		}
@@ -164,46 +203,156 @@ func DestroyAllSession(id int64) error {
 	return nil
 }
 
-// UpdateSession will updates the cookies and listsession
+// UpdateSession will updates the cookies and listsession. The remaining lifetime of every session is kept. The
+// privileges stored in the session are kept too, they're only replaced by UpdateSessionRoles, so the profile
+// update which is computed before the rolegroup change can't bring the revoked privileges back
 func (u User) UpdateSession() {
-	listSession := fmt.Sprintf("%s%d", listPrefixSession, u.ID)
-	data, err := json.Marshal(u)
+	err := updateSession(u.ID, func(sessUser *User) {
+		roles := sessUser.Roles
+		*sessUser = u
+		sessUser.Roles = roles
+	})
+	if err != nil {
+		fmt.Printf("Error func UpdateSession: %s", err.Error())
+	}
+}
+
+// UpdateSessionRoles replaces the privileges stored in every active session of the users, so the changed
+// rolegroup takes effect without signing in again. The remaining lifetime of every session is kept. The nil roles
+// are the privileges which failed to be loaded and are rejected, the empty roles revoke every privilege
+/*
+	@params:
+		userIDs	= []int64
+		roles	= map[string][]string
+	@example:
+		userIDs	= [12, 15]
+		roles	= {"courses": ["READ", "XREAD"]}
+	@return
+		err	= nil
+*/
+func UpdateSessionRoles(userIDs []int64, roles map[string][]string) error {
+	if roles == nil {
+		return fmt.Errorf("Roles of the session can't be nil")
+	}
+	for _, id := range userIDs {
+		err := updateSession(id, func(sessUser *User) {
+			sessUser.Roles = roles
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateSession applies the change to the user of every active session of the user id
+func updateSession(userID int64, change func(*User)) error {
+	listSession := fmt.Sprintf("%s%d", listPrefixSession, userID)
 
 	client := conn.Redis.Get()
 	defer client.Close()
 
-	keys, err := redis.Strings(client.Do("SMEMBERS", listSession))
+	keys, err := pruneSessionList(client, listSession)
 	if err != nil {
-		fmt.Printf("Error func UpdateSession: %s", err.Error())
+		return err
 	}
 
 	for _, key := range keys {
-		_, err = redis.String(client.Do("SET", key, data))
+		err = updateSessionKey(client, key, change)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateSessionKey applies the change to the session of the key. The key is watched while it's read and written,
+// so the session which is changed by the concurrent update is read again instead of being overwritten
+func updateSessionKey(client redis.Conn, key string, change func(*User)) error {
+	for i := 0; i < maxUpdateAttempt; i++ {
+		_, err := client.Do("WATCH", key)
+		if err != nil {
+			return err
+		}
+
+		ttl, err := redis.Int64(client.Do("TTL", key))
+		if err != nil || ttl <= 0 {
+			client.Do("UNWATCH")
+			return err
+		}
+
+		jsd, err := redis.Bytes(client.Do("GET", key))
+		if err != nil {
+			client.Do("UNWATCH")
+			if err == redis.ErrNil {
+				return nil
+			}
+			return err
+		}
+
+		// the malformed session can't be used for signing in, so it's left as is
+		sess := session{}
+		err = json.Unmarshal(jsd, &sess)
 		if err != nil {
-			fmt.Printf("Error func UpdateSession: %s", err.Error())
+			client.Do("UNWATCH")
+			return nil
+		}
+
+		if sess.User == nil {
+			sess.User = &User{}
 		}
+		change(sess.User)
+		data, err := json.Marshal(sess)
+		if err != nil {
+			client.Do("UNWATCH")
+			return err
+		}
+
+		// XX prevents the session which is expired in the meantime being recreated
+		client.Send("MULTI")
+		client.Send("SET", key, data, "EX", ttl, "XX")
+		_, err = redis.Values(client.Do("EXEC"))
+		if err == redis.ErrNil {
+			continue
+		}
+		return err
 	}
+	return fmt.Errorf("Session is changed concurrently, please try again")
 }
 
-func (u User) SetSession() (*http.Cookie, error) {
+// SetSession creates the new session of the user. The session id is generated using crypto/rand
+// and the session is expired after the idle timeout unless it's renewed by the request. The device
+// and the ip address of the request are stored for listing the active sessions
+func (u User) SetSession(r *http.Request) (*http.Cookie, error) {
 
-	var cookie string
-	rand.Seed(time.Now().UTC().UnixNano())
-	for i := 0; i < 32; i++ {
-		cookie = cookie + string(character[rand.Intn(charMaxIndex)])
+	id, err := newSessionID()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate session id")
 	}
 
-	key := sessionPrefix + cookie
-	data, err := json.Marshal(u)
+	now := time.Now()
+	key := sessionPrefix + id
+	data, err := json.Marshal(session{
+		User:      &u,
+		CreatedAt: now.Unix(),
+		UserAgent: r.UserAgent(),
+		IP:        helper.ClientIP(r),
+	})
 	if err != nil {
-		return nil, fmt.Errorf(err.Error())
+		return nil, err
 	}
 
 	client := conn.Redis.Get()
 	defer client.Close()
 
 	// Session cookie
-	_, err = redis.String(client.Do("SET", key, data))
+	ttl := sessionTTL(now.Unix(), now)
+	_, err = redis.String(client.Do("SET", key, data, "EX", ttl))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to set session to Redis")
+	}
+
+	err = setActivity(client, id, r, ttl)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to set session to Redis")
 	}
@@ -216,12 +365,95 @@ func (u User) SetSession() (*http.Cookie, error) {
 		return nil, fmt.Errorf("Failed to add list session to Redis")
 	}
 
+	// the list lives as long as the newest session
+	_, err = client.Do("EXPIRE", key, c.AbsoluteTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to add list session to Redis")
+	}
+
+	_, err = pruneSessionList(client, key)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to add list session to Redis")
+	}
+
+	return newCookie(id, now.Add(time.Duration(c.AbsoluteTimeout)*time.Second)), nil
+}
+
+// pruneSessionList removes the expired session from the session list and returns the active session keys
+func pruneSessionList(client redis.Conn, listSession string) ([]string, error) {
+
+	keys, err := redis.Strings(client.Do("SMEMBERS", listSession))
+	if err != nil {
+		return nil, err
+	}
+
+	active := []string{}
+	for _, key := range keys {
+		isExist, err := redis.Bool(client.Do("EXISTS", key))
+		if err != nil {
+			return nil, err
+		}
+
+		if isExist {
+			active = append(active, key)
+			continue
+		}
+
+		_, err = client.Do("SREM", listSession, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return active, nil
+}
+
+// sessionTTL returns the remaining session lifetime in seconds. It's the idle timeout
+// unless the absolute timeout comes first
+func sessionTTL(createdAt int64, now time.Time) int64 {
+	remaining := createdAt + c.AbsoluteTimeout - now.Unix()
+	if remaining > c.IdleTimeout {
+		return c.IdleTimeout
+	}
+	return remaining
+}
+
+// newSessionID returns the url safe base64 of the random bytes
+func newSessionID() (string, error) {
+	b := make([]byte, sessionIDLength)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// newCookie returns the session cookie, the javascript can't read the cookie
+// and the Secure and SameSite attribute are set by the environment config
+func newCookie(value string, expires time.Time) *http.Cookie {
 	return &http.Cookie{
-		Name:    c.SessionKey,
-		Expires: time.Now().AddDate(0, 1, 0),
-		Value:   cookie,
-		Path:    "/",
-	}, nil
+		Name:     c.SessionKey,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   c.CookieSecure,
+		SameSite: sameSite(),
+	}
+}
+
+// sameSite converts the config into the cookie attribute, SameSite=None is only allowed
+// for the secure cookie so lax is used for the insecure cookie
+func sameSite() http.SameSite {
+	switch strings.ToLower(c.CookieSameSite) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		if c.CookieSecure {
+			return http.SameSiteNoneMode
+		}
+	}
+	return http.SameSiteLaxMode
 }
 
 func (u User) IsHasRoles(module string, roles ...string) bool {
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/melodiez14/meiko/src/util/helper"
+)
+
+// The scope of the ability returned by Scope
+const (
+	// ScopeNone means the user can't do the action
+	ScopeNone = iota
+	// ScopeOwn means the user can only do the action on the owned resources
+	ScopeOwn
+	// ScopeAny means the user can do the action on any resource
+	ScopeAny
+)
+
+// xPrefix turns the ability on the owned resources into the ability on any resource, e.g. READ into XREAD
+const xPrefix = "X"
+
+// Authorize checks whether the user can do the action on the resource of the module. The X ability allows
+// the action on any resource while the plain ability only allows it on the resource owned by the user.
+// The owners are the users who own the resource, e.g. the creator and the assistants of the schedule or the
+// owner of the file. The new resource is owned by its creator, so the creator passes its own id
+/*
+	@params:
+		module		= string
+		action		= string, the plain ability
+		ownerIDs	= []int64
+	@example:
+		module		= assignments
+		action		= UPDATE
+		ownerIDs	= [12, 15]
+	@return
+		true/false
+*/
+func (u User) Authorize(module, action string, ownerIDs ...int64) bool {
+	switch u.Scope(module, action) {
+	case ScopeAny:
+		return true
+	case ScopeOwn:
+		return helper.Int64InSlice(u.ID, ownerIDs)
+	}
+	return false
+}
+
+// Scope returns how far the user can do the action on the module. It's used before the resource is loaded
+// and by the handler which lists the resources, the owned scope must only list the resources owned by the user
+/*
+	@params:
+		module	= string
+		action	= string, the plain ability
+	@example:
+		module	= courses
+		action	= READ
+	@return
+		scope	= ScopeOwn
+*/
+func (u User) Scope(module, action string) int {
+	if u.IsHasRoles(module, xPrefix+action) {
+		return ScopeAny
+	}
+	if u.IsHasRoles(module, action) {
+		return ScopeOwn
+	}
+	return ScopeNone
+}
+
+// Abilities returns the abilities of the user for the client. Every X ability also gives its plain ability so
+// the client which only knows the plain abilities keeps working, while the X ability tells it the user can do
+// the action on any resource
+/*
+	@example:
+		Roles	= {"users": ["XREAD", "UPDATE"]}
+	@return
+		{"users": ["READ", "XREAD", "UPDATE"]}
+*/
+func (u User) Abilities() map[string][]string {
+	abilities := map[string][]string{}
+	for module, roles := range u.Roles {
+		for _, role := range roles {
+			ability := strings.TrimPrefix(role, xPrefix)
+			if !helper.IsStringInSlice(ability, abilities[module]) {
+				abilities[module] = append(abilities[module], ability)
+			}
+			if ability != role && !helper.IsStringInSlice(role, abilities[module]) {
+				abilities[module] = append(abilities[module], role)
+			}
+		}
+	}
+	return abilities
+}
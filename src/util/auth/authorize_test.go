@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestUser_Authorize(t *testing.T) {
+	type args struct {
+		module   string
+		action   string
+		ownerIDs []int64
+	}
+	tests := []struct {
+		name string
+		user User
+		args args
+		want bool
+	}{
+		{
+			name: "Test Case 1",
+			user: User{ID: 1, Roles: map[string][]string{"assignments": {"XUPDATE"}}},
+			args: args{module: "assignments", action: "UPDATE", ownerIDs: []int64{2, 3}},
+			want: true,
+		},
+		{
+			name: "Test Case 2",
+			user: User{ID: 1, Roles: map[string][]string{"assignments": {"XUPDATE"}}},
+			args: args{module: "assignments", action: "UPDATE"},
+			want: true,
+		},
+		{
+			name: "Test Case 3",
+			user: User{ID: 1, Roles: map[string][]string{"assignments": {"UPDATE"}}},
+			args: args{module: "assignments", action: "UPDATE", ownerIDs: []int64{2, 1}},
+			want: true,
+		},
+		{
+			name: "Test Case 4",
+			user: User{ID: 1, Roles: map[string][]string{"assignments": {"UPDATE"}}},
+			args: args{module: "assignments", action: "UPDATE", ownerIDs: []int64{2, 3}},
+			want: false,
+		},
+		{
+			name: "Test Case 5",
+			user: User{ID: 1, Roles: map[string][]string{"assignments": {"UPDATE"}}},
+			args: args{module: "assignments", action: "UPDATE"},
+			want: false,
+		},
+		{
+			name: "Test Case 6",
+			user: User{ID: 1, Roles: map[string][]string{"assignments": {"XREAD"}}},
+			args: args{module: "assignments", action: "UPDATE", ownerIDs: []int64{1}},
+			want: false,
+		},
+		{
+			name: "Test Case 7",
+			user: User{ID: 1, Roles: map[string][]string{"courses": {"XUPDATE"}}},
+			args: args{module: "assignments", action: "UPDATE", ownerIDs: []int64{1}},
+			want: false,
+		},
+		{
+			name: "Test Case 8",
+			user: User{ID: 1},
+			args: args{module: "assignments", action: "UPDATE", ownerIDs: []int64{1}},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.Authorize(tt.args.module, tt.args.action, tt.args.ownerIDs...); got != tt.want {
+				t.Errorf("User.Authorize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUser_Scope(t *testing.T) {
+	tests := []struct {
+		name   string
+		user   User
+		module string
+		action string
+		want   int
+	}{
+		{
+			name:   "Test Case 1",
+			user:   User{Roles: map[string][]string{"courses": {"READ", "XREAD"}}},
+			module: "courses",
+			action: "READ",
+			want:   ScopeAny,
+		},
+		{
+			name:   "Test Case 2",
+			user:   User{Roles: map[string][]string{"courses": {"READ"}}},
+			module: "courses",
+			action: "READ",
+			want:   ScopeOwn,
+		},
+		{
+			name:   "Test Case 3",
+			user:   User{Roles: map[string][]string{"courses": {"XCREATE"}}},
+			module: "courses",
+			action: "READ",
+			want:   ScopeNone,
+		},
+		{
+			name:   "Test Case 4",
+			user:   User{Roles: map[string][]string{"users": {"XREAD"}}},
+			module: "courses",
+			action: "READ",
+			want:   ScopeNone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.Scope(tt.module, tt.action); got != tt.want {
+				t.Errorf("User.Scope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUser_Abilities(t *testing.T) {
+	tests := []struct {
+		name string
+		user User
+		want map[string][]string
+	}{
+		{
+			name: "Test Case 1",
+			user: User{Roles: map[string][]string{"users": {"XREAD", "UPDATE"}}},
+			want: map[string][]string{"users": {"READ", "UPDATE", "XREAD"}},
+		},
+		{
+			name: "Test Case 2",
+			user: User{Roles: map[string][]string{"courses": {"READ", "XREAD"}, "roles": {"XDELETE"}}},
+			want: map[string][]string{"courses": {"READ", "XREAD"}, "roles": {"DELETE", "XDELETE"}},
+		},
+		{
+			name: "Test Case 3",
+			user: User{},
+			want: map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.user.Abilities()
+			for module := range got {
+				sort.Strings(got[module])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("User.Abilities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/melodiez14/meiko/src/util/conn"
+)
+
+const (
+	challengePrefix = "signin:challenge:"
+	// challengeTimeout is the lifetime in seconds of the second sign in step
+	challengeTimeout = 5 * 60
+)
+
+// ErrInvalidChallenge is returned when the sign in challenge doesn't exist or it's expired
+var ErrInvalidChallenge = errors.New("Invalid or expired challenge")
+
+// NewChallenge creates the second sign in step of the user whose password has been checked.
+// The session is created after the challenge is solved
+/*
+	@params:
+		userID	= int64
+	@example:
+		userID	= 12
+	@return
+		challenge	= 7Jx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq5Q0pMmJ7tXA
+*/
+func NewChallenge(userID int64) (string, error) {
+
+	id, err := newSessionID()
+	if err != nil {
+		return "", err
+	}
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	_, err = client.Do("SET", challengePrefix+id, userID, "EX", challengeTimeout)
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// GetChallenge returns the user id of the sign in challenge
+/*
+	@params:
+		challenge	= string
+	@example:
+		challenge	= 7Jx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq5Q0pMmJ7tXA
+	@return
+		userID	= 12
+*/
+func GetChallenge(challenge string) (int64, error) {
+
+	challenge = strings.TrimSpace(challenge)
+	if challenge == "" {
+		return 0, ErrInvalidChallenge
+	}
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	userID, err := redis.Int64(client.Do("GET", challengePrefix+challenge))
+	if err == redis.ErrNil {
+		return 0, ErrInvalidChallenge
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+// DestroyChallenge removes the solved sign in challenge. It returns ErrInvalidChallenge if the challenge
+// has been removed by the concurrent request so the challenge can't be used twice
+func DestroyChallenge(challenge string) error {
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	n, err := redis.Int64(client.Do("DEL", challengePrefix+strings.TrimSpace(challenge)))
+	if err != nil {
+		return err
+	}
+	if n < 1 {
+		return ErrInvalidChallenge
+	}
+	return nil
+}
@@ -1,5 +1,7 @@
 package auth
 
+import "time"
+
 type User struct {
 	ID           int64               `json:"id"`
 	Name         string              `json:"name"`
@@ -11,4 +13,30 @@ type User struct {
 	LineID       string              `json:"line_id"`
 	Phone        string              `json:"phone"`
 	Status       int8                `json:"active"`
+	// TokenID is the api token used by the request, it's zero for the session
+	TokenID int64 `json:"-"`
+}
+
+// session is the value stored in Redis, the sign in time is used for the absolute timeout
+type session struct {
+	User      *User  `json:"user"`
+	CreatedAt int64  `json:"created_at"`
+	UserAgent string `json:"user_agent"`
+	IP        string `json:"ip"`
+}
+
+// activity is the last request of the session
+type activity struct {
+	LastSeenAt int64  `json:"last_seen_at"`
+	IP         string `json:"ip"`
+}
+
+// Session is the active session of the user. The ID is derived from the session cookie
+// so the cookie value is never exposed
+type Session struct {
+	ID         string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
 }
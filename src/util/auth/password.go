@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/melodiez14/meiko/src/util/helper"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// bcryptPrefix is the prefix of every bcrypt hash, the other hash is the legacy unsalted MD5
+const bcryptPrefix = "$2"
+
+// HashPassword returns the bcrypt hash of the password using the configured cost
+/*
+	@params:
+		password	= string
+	@example:
+		password	= Qwerty123
+	@return
+		hash		= $2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy
+*/
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordCost())
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// ComparePassword checks whether the password matches the stored hash. The stored hash can be the bcrypt
+// hash or the legacy MD5 hash. isNeedRehash is true if the matched hash must be replaced with the new bcrypt hash,
+// it happens for the MD5 hash and the bcrypt hash with the different cost
+/*
+	@params:
+		hash		= string
+		password	= string
+	@example:
+		hash		= 2af9b1ba42dc5eb01743e6b3759b6e4b
+		password	= Qwerty123
+	@return
+		isMatch		= true
+		isNeedRehash= true
+*/
+func ComparePassword(hash, password string) (isMatch, isNeedRehash bool) {
+
+	if !strings.HasPrefix(hash, bcryptPrefix) {
+		md5 := helper.StringToMD5(password)
+		isMatch = subtle.ConstantTimeCompare([]byte(hash), []byte(md5)) == 1
+		return isMatch, isMatch
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if err != nil {
+		return false, false
+	}
+
+	cost, err := bcrypt.Cost([]byte(hash))
+	return true, err != nil || cost != passwordCost()
+}
+
+// passwordCost returns the configured bcrypt cost, the default cost is used if it's out of the bcrypt range
+func passwordCost() int {
+	if c.PasswordCost < bcrypt.MinCost || c.PasswordCost > bcrypt.MaxCost {
+		return bcrypt.DefaultCost
+	}
+	return c.PasswordCost
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/melodiez14/meiko/src/util/helper"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestComparePassword(t *testing.T) {
+	c = Config{PasswordCost: bcrypt.MinCost}
+	current, _ := HashPassword("Qwerty123")
+	weak, _ := bcrypt.GenerateFromPassword([]byte("Qwerty123"), bcrypt.MinCost+1)
+
+	type args struct {
+		hash     string
+		password string
+	}
+	tests := []struct {
+		name             string
+		args             args
+		wantIsMatch      bool
+		wantIsNeedRehash bool
+	}{
+		{
+			name: "Test Case 1",
+			args: args{
+				hash:     current,
+				password: "Qwerty123",
+			},
+			wantIsMatch:      true,
+			wantIsNeedRehash: false,
+		},
+		{
+			name: "Test Case 2",
+			args: args{
+				hash:     current,
+				password: "Qwerty321",
+			},
+			wantIsMatch:      false,
+			wantIsNeedRehash: false,
+		},
+		{
+			name: "Test Case 3",
+			args: args{
+				hash:     helper.StringToMD5("Qwerty123"),
+				password: "Qwerty123",
+			},
+			wantIsMatch:      true,
+			wantIsNeedRehash: true,
+		},
+		{
+			name: "Test Case 4",
+			args: args{
+				hash:     helper.StringToMD5("Qwerty123"),
+				password: "Qwerty321",
+			},
+			wantIsMatch:      false,
+			wantIsNeedRehash: false,
+		},
+		{
+			name: "Test Case 5",
+			args: args{
+				hash:     string(weak),
+				password: "Qwerty123",
+			},
+			wantIsMatch:      true,
+			wantIsNeedRehash: true,
+		},
+		{
+			name: "Test Case 6",
+			args: args{
+				hash:     "",
+				password: "",
+			},
+			wantIsMatch:      false,
+			wantIsNeedRehash: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIsMatch, gotIsNeedRehash := ComparePassword(tt.args.hash, tt.args.password)
+			if gotIsMatch != tt.wantIsMatch {
+				t.Errorf("ComparePassword() isMatch = %v, want %v", gotIsMatch, tt.wantIsMatch)
+			}
+			if gotIsNeedRehash != tt.wantIsNeedRehash {
+				t.Errorf("ComparePassword() isNeedRehash = %v, want %v", gotIsNeedRehash, tt.wantIsNeedRehash)
+			}
+		})
+	}
+}
+
+func TestPasswordCost(t *testing.T) {
+	tests := []struct {
+		name string
+		cost int
+		want int
+	}{
+		{name: "Test Case 1", cost: 12, want: 12},
+		{name: "Test Case 2", cost: 0, want: bcrypt.DefaultCost},
+		{name: "Test Case 3", cost: 100, want: bcrypt.DefaultCost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c = Config{PasswordCost: tt.cost}
+			if got := passwordCost(); got != tt.want {
+				t.Errorf("passwordCost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/melodiez14/meiko/src/util/helper"
+)
+
+// SessionID returns the public id of the request session, it's empty for the api token
+/*
+	@params:
+		r	= *http.Request
+	@example:
+		r	= request with the session cookie
+	@return
+		id	= 9f86d081884c7d659a2feaa0c55ad015
+*/
+func SessionID(r *http.Request) string {
+	sessionID := requestSessionID(r)
+	if sessionID == "" {
+		return ""
+	}
+	return publicID(sessionID)
+}
+
+// SelectSession returns the active sessions of the user ordered by the last activity
+/*
+	@params:
+		userID	= int64
+	@example:
+		userID	= 12
+	@return
+		[]{id, user_agent, ip, created_at, last_seen_at}
+*/
+func SelectSession(userID int64) ([]Session, error) {
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	keys, err := pruneSessionList(client, fmt.Sprintf("%s%d", listPrefixSession, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []Session{}
+	for _, key := range keys {
+		jsd, err := redis.Bytes(client.Do("GET", key))
+		if err == redis.ErrNil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		sess := session{}
+		err = json.Unmarshal(jsd, &sess)
+		if err != nil || sess.User == nil {
+			continue
+		}
+
+		act := activity{
+			LastSeenAt: sess.CreatedAt,
+			IP:         sess.IP,
+		}
+		jsd, err = redis.Bytes(client.Do("GET", activityKey(key)))
+		if err == nil {
+			json.Unmarshal(jsd, &act)
+		}
+
+		sessions = append(sessions, Session{
+			ID:         publicID(strings.TrimPrefix(key, sessionPrefix)),
+			UserAgent:  sess.UserAgent,
+			IP:         act.IP,
+			CreatedAt:  time.Unix(sess.CreatedAt, 0),
+			LastSeenAt: time.Unix(act.LastSeenAt, 0),
+		})
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+// DestroySessionByID destroys the active session of the user by its public id
+/*
+	@params:
+		userID	= int64
+		id		= string
+	@example:
+		userID	= 12
+		id		= 9f86d081884c7d659a2feaa0c55ad015
+	@return
+		error	= ErrSessionNotFound if the session isn't the active session of the user
+*/
+func DestroySessionByID(userID int64, id string) error {
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	listSession := fmt.Sprintf("%s%d", listPrefixSession, userID)
+	keys, err := pruneSessionList(client, listSession)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if publicID(strings.TrimPrefix(key, sessionPrefix)) != id {
+			continue
+		}
+		return destroySessionKey(client, listSession, key)
+	}
+
+	return ErrSessionNotFound
+}
+
+// DestroyOtherSession destroys every active session of the user except the given public id
+/*
+	@params:
+		userID	= int64
+		id		= string
+	@example:
+		userID	= 12
+		id		= 9f86d081884c7d659a2feaa0c55ad015
+	@return
+		count	= the number of destroyed sessions
+*/
+func DestroyOtherSession(userID int64, id string) (int, error) {
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	listSession := fmt.Sprintf("%s%d", listPrefixSession, userID)
+	keys, err := pruneSessionList(client, listSession)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, key := range keys {
+		if publicID(strings.TrimPrefix(key, sessionPrefix)) == id {
+			continue
+		}
+		err = destroySessionKey(client, listSession, key)
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// destroySessionKey deletes the session with its activity and removes it from the session list
+func destroySessionKey(client redis.Conn, listSession, key string) error {
+
+	_, err := client.Do("DEL", key, activityKey(key))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Do("SREM", listSession, key)
+	return err
+}
+
+// setActivity stores the last request time and ip address of the session with the session lifetime
+func setActivity(client redis.Conn, sessionID string, r *http.Request, ttl int64) error {
+
+	data, err := json.Marshal(activity{
+		LastSeenAt: time.Now().Unix(),
+		IP:         helper.ClientIP(r),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Do("SET", activityPrefix+sessionID, data, "EX", ttl)
+	return err
+}
+
+// activityKey returns the activity key of the session key
+func activityKey(key string) string {
+	return activityPrefix + strings.TrimPrefix(key, sessionPrefix)
+}
+
+// publicID returns the hash of the session id, it's used for identifying the session in the response
+func publicID(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(sum[:publicIDLength])
+}
@@ -0,0 +1,402 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/rafaeljusto/redigomock"
+)
+
+func initRedisMock() *redigomock.Conn {
+	mock := redigomock.NewConn()
+	conn.Redis = &redis.Pool{
+		MaxIdle:     3,
+		IdleTimeout: 10 * time.Second,
+		Dial:        func() (redis.Conn, error) { return mock, nil },
+	}
+	return mock
+}
+
+func TestSessionTTL(t *testing.T) {
+	Init(Config{IdleTimeout: 3600, AbsoluteTimeout: 86400})
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		createdAt int64
+		want      int64
+	}{
+		{
+			name:      "Test Case 1",
+			createdAt: now.Unix(),
+			want:      3600,
+		},
+		{
+			name:      "Test Case 2",
+			createdAt: now.Unix() - 86400 + 60,
+			want:      60,
+		},
+		{
+			name:      "Test Case 3",
+			createdAt: now.Unix() - 86400 - 1,
+			want:      -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sessionTTL(tt.createdAt, now); got != tt.want {
+				t.Errorf("sessionTTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameSite(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   http.SameSite
+	}{
+		{
+			name:   "Test Case 1",
+			config: Config{CookieSameSite: "strict"},
+			want:   http.SameSiteStrictMode,
+		},
+		{
+			name:   "Test Case 2",
+			config: Config{CookieSameSite: "none", CookieSecure: true},
+			want:   http.SameSiteNoneMode,
+		},
+		{
+			name:   "Test Case 3",
+			config: Config{CookieSameSite: "none"},
+			want:   http.SameSiteLaxMode,
+		},
+		{
+			name:   "Test Case 4",
+			config: Config{},
+			want:   http.SameSiteLaxMode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Init(tt.config)
+			if got := sameSite(); got != tt.want {
+				t.Errorf("sameSite() = %v, want %v", got, tt.want)
+			}
+			if cookie := newCookie("x", time.Now()); !cookie.HttpOnly || cookie.Secure != tt.config.CookieSecure {
+				t.Errorf("newCookie() = %+v", cookie)
+			}
+		})
+	}
+}
+
+func TestNewSessionID(t *testing.T) {
+	a, err := newSessionID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := newSessionID()
+	if len(a) != 43 || a == b {
+		t.Errorf("newSessionID() = %s, %s", a, b)
+	}
+}
+
+func TestGetUserInfo(t *testing.T) {
+	Init(Config{IdleTimeout: 3600, AbsoluteTimeout: 86400})
+	now := time.Now().Unix()
+	user := &User{ID: 1, Name: "Risal Falah"}
+
+	active, _ := json.Marshal(session{User: user, CreatedAt: now})
+	expired, _ := json.Marshal(session{User: user, CreatedAt: now - 86400 - 60})
+	legacy, _ := json.Marshal(user)
+
+	tests := []struct {
+		name     string
+		value    interface{}
+		want     *User
+		wantErr  bool
+		wantCall string
+	}{
+		{
+			name:     "Test Case 1",
+			value:    active,
+			want:     user,
+			wantCall: "EXPIRE",
+		},
+		{
+			name:     "Test Case 2",
+			value:    expired,
+			wantErr:  true,
+			wantCall: "DEL",
+		},
+		{
+			name:     "Test Case 3",
+			value:    legacy,
+			wantErr:  true,
+			wantCall: "DEL",
+		},
+		{
+			name:    "Test Case 4",
+			value:   nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := initRedisMock()
+			mock.Command("GET", "session:abc").Expect(tt.value)
+			expire := mock.Command("EXPIRE", "session:abc", int64(3600)).Expect(int64(1))
+			del := mock.Command("DEL", "session:abc").Expect(int64(1))
+			activity := mock.GenericCommand("SET").Expect("OK")
+
+			r := httptest.NewRequest("GET", "/", nil)
+			got, err := getUserInfo("abc", r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getUserInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getUserInfo() = %v, want %v", got, tt.want)
+			}
+			if tt.wantCall == "EXPIRE" && (mock.Stats(expire) != 1 || mock.Stats(activity) != 1) {
+				t.Error("getUserInfo() doesn't renew the session")
+			}
+			if tt.wantCall == "DEL" && mock.Stats(del) != 1 {
+				t.Error("getUserInfo() doesn't delete the session")
+			}
+		})
+	}
+}
+
+func TestPruneSessionList(t *testing.T) {
+	mock := initRedisMock()
+	mock.Command("SMEMBERS", "session:list:1").ExpectSlice([]byte("session:a"), []byte("session:b"))
+	mock.Command("EXISTS", "session:a").Expect(int64(1))
+	mock.Command("EXISTS", "session:b").Expect(int64(0))
+	srem := mock.Command("SREM", "session:list:1", "session:b").Expect(int64(1))
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	got, err := pruneSessionList(client, "session:list:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []string{"session:a"}) {
+		t.Errorf("pruneSessionList() = %v", got)
+	}
+	if mock.Stats(srem) != 1 {
+		t.Error("pruneSessionList() doesn't remove the expired session")
+	}
+}
+
+func TestSelectSession(t *testing.T) {
+	now := time.Now().Unix()
+	user := &User{ID: 1, Name: "Risal Falah"}
+	a, _ := json.Marshal(session{User: user, CreatedAt: now - 600, UserAgent: "Firefox", IP: "10.0.0.1"})
+	b, _ := json.Marshal(session{User: user, CreatedAt: now - 300, UserAgent: "Chrome", IP: "10.0.0.2"})
+	act, _ := json.Marshal(activity{LastSeenAt: now, IP: "10.0.0.3"})
+
+	mock := initRedisMock()
+	mock.Command("SMEMBERS", "session:list:1").ExpectSlice([]byte("session:a"), []byte("session:b"))
+	mock.Command("EXISTS", "session:a").Expect(int64(1))
+	mock.Command("EXISTS", "session:b").Expect(int64(1))
+	mock.Command("GET", "session:a").Expect(a)
+	mock.Command("GET", "session:b").Expect(b)
+	mock.Command("GET", "session:activity:a").Expect(act)
+	mock.Command("GET", "session:activity:b").Expect(nil)
+
+	got, err := SelectSession(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Session{
+		{
+			ID:         publicID("a"),
+			UserAgent:  "Firefox",
+			IP:         "10.0.0.3",
+			CreatedAt:  time.Unix(now-600, 0),
+			LastSeenAt: time.Unix(now, 0),
+		},
+		{
+			ID:         publicID("b"),
+			UserAgent:  "Chrome",
+			IP:         "10.0.0.2",
+			CreatedAt:  time.Unix(now-300, 0),
+			LastSeenAt: time.Unix(now-300, 0),
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SelectSession() = %v, want %v", got, want)
+	}
+}
+
+func TestDestroySessionByID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		wantErr error
+		wantDel int
+	}{
+		{
+			name:    "Test Case 1",
+			id:      publicID("b"),
+			wantDel: 1,
+		},
+		{
+			name:    "Test Case 2",
+			id:      publicID("c"),
+			wantErr: ErrSessionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := initRedisMock()
+			mock.Command("SMEMBERS", "session:list:1").ExpectSlice([]byte("session:a"), []byte("session:b"))
+			mock.Command("EXISTS", "session:a").Expect(int64(1))
+			mock.Command("EXISTS", "session:b").Expect(int64(1))
+			delA := mock.Command("DEL", "session:a", "session:activity:a").Expect(int64(2))
+			delB := mock.Command("DEL", "session:b", "session:activity:b").Expect(int64(2))
+			mock.Command("SREM", "session:list:1", "session:b").Expect(int64(1))
+
+			err := DestroySessionByID(1, tt.id)
+			if err != tt.wantErr {
+				t.Fatalf("DestroySessionByID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if mock.Stats(delA) != 0 || mock.Stats(delB) != tt.wantDel {
+				t.Error("DestroySessionByID() deletes the wrong session")
+			}
+		})
+	}
+}
+
+func TestDestroyOtherSession(t *testing.T) {
+	mock := initRedisMock()
+	mock.Command("SMEMBERS", "session:list:1").ExpectSlice([]byte("session:a"), []byte("session:b"))
+	mock.Command("EXISTS", "session:a").Expect(int64(1))
+	mock.Command("EXISTS", "session:b").Expect(int64(1))
+	delA := mock.Command("DEL", "session:a", "session:activity:a").Expect(int64(2))
+	mock.Command("SREM", "session:list:1", "session:a").Expect(int64(1))
+
+	count, err := DestroyOtherSession(1, publicID("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 || mock.Stats(delA) != 1 {
+		t.Errorf("DestroyOtherSession() = %d", count)
+	}
+}
+
+func TestUpdateSessionRoles(t *testing.T) {
+	now := time.Now().Unix()
+	old, _ := json.Marshal(session{
+		User:      &User{ID: 1, Name: "Risal Falah", Roles: map[string][]string{"courses": {"XREAD"}}},
+		CreatedAt: now,
+		UserAgent: "Firefox",
+		IP:        "10.0.0.1",
+	})
+	roles := map[string][]string{"courses": {"READ"}}
+	want, _ := json.Marshal(session{
+		User:      &User{ID: 1, Name: "Risal Falah", Roles: roles},
+		CreatedAt: now,
+		UserAgent: "Firefox",
+		IP:        "10.0.0.1",
+	})
+
+	tests := []struct {
+		name     string
+		exec     []interface{}
+		wantExec int
+		wantErr  bool
+	}{
+		{
+			name:     "Test Case 1",
+			exec:     []interface{}{[]interface{}{"OK"}},
+			wantExec: 1,
+			wantErr:  false,
+		},
+		{
+			// the session is changed concurrently, so it's read again
+			name:     "Test Case 2",
+			exec:     []interface{}{nil, []interface{}{"OK"}},
+			wantExec: 2,
+			wantErr:  false,
+		},
+		{
+			name:     "Test Case 3",
+			exec:     []interface{}{nil, nil, nil, nil, nil},
+			wantExec: maxUpdateAttempt,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := initRedisMock()
+			mock.Command("SMEMBERS", "session:list:1").ExpectSlice([]byte("session:a"))
+			mock.Command("EXISTS", "session:a").Expect(int64(1))
+			mock.Command("WATCH", "session:a").Expect("OK")
+			mock.Command("TTL", "session:a").Expect(int64(600))
+			mock.Command("GET", "session:a").Expect(old)
+			mock.Command("MULTI").Expect("OK")
+			mock.Command("SET", "session:a", want, "EX", int64(600), "XX").Expect("QUEUED")
+			exec := mock.Command("EXEC")
+			for _, val := range tt.exec {
+				exec.Expect(val)
+			}
+
+			err := UpdateSessionRoles([]int64{1}, roles)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateSessionRoles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if mock.Stats(exec) != tt.wantExec {
+				t.Errorf("UpdateSessionRoles() executes %d times, want %d", mock.Stats(exec), tt.wantExec)
+			}
+		})
+	}
+
+	// the roles which failed to be loaded never replace the sessions
+	mock := initRedisMock()
+	members := mock.Command("SMEMBERS", "session:list:1").ExpectSlice([]byte("session:a"))
+	if err := UpdateSessionRoles([]int64{1}, nil); err == nil {
+		t.Error("UpdateSessionRoles() error = nil, wantErr true")
+	}
+	if mock.Stats(members) != 0 {
+		t.Errorf("UpdateSessionRoles() reads the sessions %d times, want 0", mock.Stats(members))
+	}
+}
+
+func TestUpdateSession(t *testing.T) {
+	now := time.Now().Unix()
+	roles := map[string][]string{"courses": {"READ"}}
+	old, _ := json.Marshal(session{
+		User:      &User{ID: 1, Name: "Risal", Roles: roles},
+		CreatedAt: now,
+	})
+	// the privileges of the session are kept although the user has the stale privileges
+	want, _ := json.Marshal(session{
+		User:      &User{ID: 1, Name: "Risal Falah", Roles: roles},
+		CreatedAt: now,
+	})
+
+	mock := initRedisMock()
+	mock.Command("SMEMBERS", "session:list:1").ExpectSlice([]byte("session:a"))
+	mock.Command("EXISTS", "session:a").Expect(int64(1))
+	mock.Command("WATCH", "session:a").Expect("OK")
+	mock.Command("TTL", "session:a").Expect(int64(600))
+	mock.Command("GET", "session:a").Expect(old)
+	mock.Command("MULTI").Expect("OK")
+	set := mock.Command("SET", "session:a", want, "EX", int64(600), "XX").Expect("QUEUED")
+	mock.Command("EXEC").Expect([]interface{}{"OK"})
+
+	User{ID: 1, Name: "Risal Falah", Roles: map[string][]string{"courses": {"READ", "XREAD"}}}.UpdateSession()
+	if mock.Stats(set) != 1 {
+		t.Error("UpdateSession() doesn't keep the privileges of the session")
+	}
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	apiTokenPrefix       = "mk_"
+	apiTokenLength       = 32
+	apiTokenPrefixLength = 11
+)
+
+// TokenValidator returns the user of the api token by the hash of the token. The roles of the returned
+// user must be restricted to the token scopes
+type TokenValidator func(hash string) (*User, error)
+
+var (
+	tokenValidator     TokenValidator
+	errInvalidAPIToken = errors.New("Invalid api token")
+)
+
+// RegisterTokenValidator sets the validator used by the middleware for the api token
+func RegisterTokenValidator(validator TokenValidator) {
+	tokenValidator = validator
+}
+
+// NewAPIToken generates the personal api token. The plain token is given to the user once,
+// the prefix is used for recognizing the token and only the hash is stored
+/*
+	@params:
+	@example:
+	@return
+		token	= mk_Xa3sd9QmZ2...
+		prefix	= mk_Xa3sd9Qm
+		hash	= 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae
+*/
+func NewAPIToken() (token, prefix, hash string, err error) {
+	b := make([]byte, apiTokenLength)
+	_, err = rand.Read(b)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	token = apiTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, token[:apiTokenPrefixLength], HashAPIToken(token), nil
+}
+
+// HashAPIToken returns the sha256 hex of the api token. The token has the high entropy
+// so the slow password hash isn't needed
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// authenticate returns the user of the request. The Authorization header is used when it's provided,
+// the bearer token is either the personal api token or the session id. Otherwise the session cookie is used
+func authenticate(r *http.Request) (*User, error) {
+
+	bearer := bearerToken(r)
+	if isAPIToken(bearer) {
+		if tokenValidator == nil {
+			return nil, errInvalidAPIToken
+		}
+		return tokenValidator(HashAPIToken(bearer))
+	}
+
+	sessionID := requestSessionID(r)
+	if sessionID == "" {
+		return nil, errSessionNotlogin
+	}
+	return getUserInfo(sessionID, r)
+}
+
+// requestSessionID returns the session id of the bearer token or the session cookie
+func requestSessionID(r *http.Request) string {
+	bearer := bearerToken(r)
+	if bearer != "" {
+		if isAPIToken(bearer) {
+			return ""
+		}
+		return bearer
+	}
+
+	cookie, err := r.Cookie(c.SessionKey)
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(cookie.Value, " ")
+}
+
+// isAPIToken checks whether the bearer token is the personal api token. The session id is base64url as well and may
+// start with the prefix, but it's always shorter than the api token, so the length tells them apart
+func isAPIToken(bearer string) bool {
+	return len(bearer) == len(apiTokenPrefix)+base64.RawURLEncoding.EncodedLen(apiTokenLength) &&
+		strings.HasPrefix(bearer, apiTokenPrefix)
+}
+
+// bearerToken returns the token of the Authorization header
+func bearerToken(r *http.Request) string {
+	header := strings.TrimSpace(r.Header.Get("Authorization"))
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[7:])
+}
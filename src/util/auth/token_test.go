@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewAPIToken(t *testing.T) {
+	token, prefix, hash, err := NewAPIToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token, apiTokenPrefix) || !strings.HasPrefix(token, prefix) || len(prefix) != apiTokenPrefixLength {
+		t.Errorf("NewAPIToken() = %s, %s", token, prefix)
+	}
+	if hash != HashAPIToken(token) || len(hash) != 64 {
+		t.Errorf("NewAPIToken() hash = %s", hash)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	Init(Config{SessionKey: "sess"})
+	token, _, hash, _ := NewAPIToken()
+	RegisterTokenValidator(func(h string) (*User, error) {
+		if h != hash {
+			return nil, errInvalidAPIToken
+		}
+		return &User{ID: 1, TokenID: 3}, nil
+	})
+	defer RegisterTokenValidator(nil)
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantErr       bool
+		wantSession   string
+	}{
+		{
+			name:          "Test Case 1",
+			authorization: "Bearer " + token,
+			wantErr:       false,
+		},
+		{
+			name:          "Test Case 2",
+			authorization: "bearer mk_" + strings.Repeat("A", 43),
+			wantErr:       true,
+		},
+		{
+			name:          "Test Case 3",
+			authorization: "",
+			wantErr:       true,
+		},
+		{
+			name:          "Test Case 4",
+			authorization: "Basic abc",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			got, err := authenticate(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("authenticate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.TokenID != 3 {
+				t.Errorf("authenticate() = %v", got)
+			}
+			if SessionID(r) != "" {
+				t.Errorf("SessionID() of the api token must be empty")
+			}
+		})
+	}
+}
+
+func TestRequestSessionID(t *testing.T) {
+	Init(Config{SessionKey: "sess"})
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Cookie", "sess=abc")
+	if got := requestSessionID(r); got != "abc" {
+		t.Errorf("requestSessionID() = %v, want abc", got)
+	}
+
+	r.Header.Set("Authorization", "Bearer def")
+	if got := requestSessionID(r); got != "def" {
+		t.Errorf("requestSessionID() = %v, want def", got)
+	}
+
+	// the session id may start with the prefix of the api token
+	sessionID := "mk_" + strings.Repeat("A", 40)
+	r.Header.Set("Authorization", "Bearer "+sessionID)
+	if got := requestSessionID(r); got != sessionID {
+		t.Errorf("requestSessionID() = %v, want %v", got, sessionID)
+	}
+}
+
+func TestIsAPIToken(t *testing.T) {
+	token, _, _, _ := NewAPIToken()
+	sessionID, _ := newSessionID()
+	tests := []struct {
+		name   string
+		bearer string
+		want   bool
+	}{
+		{name: "Test Case 1", bearer: token, want: true},
+		{name: "Test Case 2", bearer: sessionID, want: false},
+		{name: "Test Case 3", bearer: "mk_" + sessionID[3:], want: false},
+		{name: "Test Case 4", bearer: "mk_invalid", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAPIToken(tt.bearer); got != tt.want {
+				t.Errorf("isAPIToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
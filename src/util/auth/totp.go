@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	totpIssuer       = "Meiko"
+	totpSecretLength = 20
+	totpDigits       = 6
+	totpPeriod       = 30
+	// totpSkew is the number of the time steps accepted before and after the current step
+	totpSkew = 1
+
+	recoveryCodeLength = 10
+	// RecoveryCodeTotal is the number of the recovery codes generated when the two factor is enabled
+	RecoveryCodeTotal = 10
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewTOTPSecret generates the base32 secret of the time based one time password
+/*
+	@params:
+	@example:
+	@return
+		secret	= JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP
+*/
+func NewTOTPSecret() (string, error) {
+	b := make([]byte, totpSecretLength)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// TOTPURI returns the otpauth uri which is encoded in the QR code scanned by the authenticator app
+/*
+	@params:
+		secret	= string
+		account	= string
+	@example:
+		secret	= JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP
+		account	= risal@live.com
+	@return
+		uri	= otpauth://totp/Meiko:risal@live.com?secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP&issuer=Meiko&...
+*/
+func TOTPURI(secret, account string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", totpIssuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprintf("%d", totpDigits))
+	v.Set("period", fmt.Sprintf("%d", totpPeriod))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + totpIssuer + ":" + account,
+		RawQuery: v.Encode(),
+	}
+	return u.String()
+}
+
+// ValidateTOTP checks the code against the time steps around the given time. It returns the matched time step,
+// the caller must reject the step which isn't newer than the last used step so the code can't be replayed
+/*
+	@params:
+		secret	= string
+		code	= string
+		t		= time.Time
+	@example:
+		secret	= JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP
+		code	= 492039
+		t		= time.Now()
+	@return
+		step	= 50276150
+		isValid	= true
+*/
+func ValidateTOTP(secret, code string, t time.Time) (int64, bool) {
+
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := t.Unix() / totpPeriod
+	for i := -totpSkew; i <= totpSkew; i++ {
+		step := current + int64(i)
+		expected := totp(key, step)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// NewRecoveryCodes generates the one time recovery codes and their hash. The plain codes are shown
+// to the user once and only the hash is stored
+/*
+	@params:
+		n	= int
+	@example:
+		n	= 2
+	@return
+		codes	= [k3pxp-jbswy, 3dpeh-pk3px]
+		hashes	= [2c26b46b68ff..., fcde2b2edba5...]
+*/
+func NewRecoveryCodes(n int) ([]string, []string, error) {
+
+	codes := []string{}
+	hashes := []string{}
+	for i := 0; i < n; i++ {
+		b := make([]byte, recoveryCodeLength*5/8)
+		_, err := rand.Read(b)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(totpEncoding.EncodeToString(b))
+		code = code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:]
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode returns the sha256 hex of the normalized recovery code, the dash and the case are ignored
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.Replace(strings.TrimSpace(code), "-", "", -1))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// totp returns the HOTP value of the time step as described in RFC 4226 and RFC 6238
+func totp(key []byte, step int64) string {
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// secret of the RFC 6238 test vectors, "12345678901234567890" in base32
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTP(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		t        time.Time
+		wantStep int64
+		wantOK   bool
+	}{
+		{
+			name:     "Test Case 1",
+			secret:   testTOTPSecret,
+			code:     "287082",
+			t:        time.Unix(59, 0),
+			wantStep: 1,
+			wantOK:   true,
+		},
+		{
+			name:     "Test Case 2",
+			secret:   testTOTPSecret,
+			code:     "287082",
+			t:        time.Unix(89, 0),
+			wantStep: 1,
+			wantOK:   true,
+		},
+		{
+			name:   "Test Case 3",
+			secret: testTOTPSecret,
+			code:   "287082",
+			t:      time.Unix(120, 0),
+			wantOK: false,
+		},
+		{
+			name:     "Test Case 4",
+			secret:   testTOTPSecret,
+			code:     "081804",
+			t:        time.Unix(1111111109, 0),
+			wantStep: 37037036,
+			wantOK:   true,
+		},
+		{
+			name:   "Test Case 5",
+			secret: "invalid secret!",
+			code:   "287082",
+			t:      time.Unix(59, 0),
+			wantOK: false,
+		},
+		{
+			name:   "Test Case 6",
+			secret: testTOTPSecret,
+			code:   "28708",
+			t:      time.Unix(59, 0),
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(tt.secret, tt.code, tt.t)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("ValidateTOTP() = %d, %v, want %d, %v", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewTOTPSecret(t *testing.T) {
+	secret, err := NewTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(key) != totpSecretLength {
+		t.Errorf("NewTOTPSecret() = %s", secret)
+	}
+}
+
+func TestNewRecoveryCodes(t *testing.T) {
+	codes, hashes, err := NewRecoveryCodes(RecoveryCodeTotal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != RecoveryCodeTotal || len(hashes) != RecoveryCodeTotal {
+		t.Fatalf("NewRecoveryCodes() returns %d codes and %d hashes", len(codes), len(hashes))
+	}
+	for i, code := range codes {
+		if len(code) != recoveryCodeLength+1 || code[recoveryCodeLength/2] != '-' {
+			t.Errorf("NewRecoveryCodes() code = %s", code)
+		}
+		if hashes[i] != HashRecoveryCode(code) {
+			t.Errorf("NewRecoveryCodes() hash of %s = %s", code, hashes[i])
+		}
+	}
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	want := HashRecoveryCode("k3pxp-jbswy")
+	for _, code := range []string{"K3PXP-JBSWY", " k3pxpjbswy ", "k3pxp-jbswy"} {
+		if got := HashRecoveryCode(code); got != want {
+			t.Errorf("HashRecoveryCode(%q) = %s, want %s", code, got, want)
+		}
+	}
+	if HashRecoveryCode("k3pxp-jbswz") == want {
+		t.Errorf("HashRecoveryCode() collides")
+	}
+}
+
+func TestChallenge(t *testing.T) {
+	tests := []struct {
+		name      string
+		challenge string
+		value     interface{}
+		want      int64
+		wantErr   error
+	}{
+		{
+			name:      "Test Case 1",
+			challenge: "abc",
+			value:     []byte("12"),
+			want:      12,
+			wantErr:   nil,
+		},
+		{
+			name:      "Test Case 2",
+			challenge: "abc",
+			value:     nil,
+			want:      0,
+			wantErr:   ErrInvalidChallenge,
+		},
+		{
+			name:      "Test Case 3",
+			challenge: " ",
+			want:      0,
+			wantErr:   ErrInvalidChallenge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := initRedisMock()
+			mock.Command("GET", challengePrefix+tt.challenge).Expect(tt.value)
+
+			got, err := GetChallenge(tt.challenge)
+			if err != tt.wantErr {
+				t.Fatalf("GetChallenge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetChallenge() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDestroyChallenge(t *testing.T) {
+	tests := []struct {
+		name    string
+		deleted int64
+		wantErr error
+	}{
+		{
+			name:    "Test Case 1",
+			deleted: 1,
+			wantErr: nil,
+		},
+		{
+			name:    "Test Case 2",
+			deleted: 0,
+			wantErr: ErrInvalidChallenge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := initRedisMock()
+			mock.Command("DEL", challengePrefix+"abc").Expect(tt.deleted)
+
+			if err := DestroyChallenge("abc"); err != tt.wantErr {
+				t.Errorf("DestroyChallenge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package classifier
+
+import (
+	"math"
+)
+
+// defaultAlpha is the Laplace smoothing used when the alpha isn't set
+const defaultAlpha = 1.0
+
+// NaiveBayes is the multinomial naive Bayes classifier over the unigram and bigram tokens
+type NaiveBayes struct {
+	Alpha float64
+
+	sampleTotal int
+	intentTotal map[string]int
+	tokenTotal  map[string]int
+	tokenCount  map[string]map[string]int
+	vocabulary  map[string]bool
+}
+
+// NewNaiveBayes returns the untrained naive Bayes classifier with the default smoothing
+func NewNaiveBayes() *NaiveBayes {
+	return &NaiveBayes{
+		Alpha: defaultAlpha,
+	}
+}
+
+// Train replaces the learned model with the given samples
+func (nb *NaiveBayes) Train(samples []Sample) {
+
+	nb.sampleTotal = 0
+	nb.intentTotal = map[string]int{}
+	nb.tokenTotal = map[string]int{}
+	nb.tokenCount = map[string]map[string]int{}
+	nb.vocabulary = map[string]bool{}
+
+	for _, val := range samples {
+		tokens := Tokenize(val.Text)
+		if len(tokens) < 1 {
+			continue
+		}
+
+		nb.sampleTotal++
+		nb.intentTotal[val.Intent]++
+		if _, ok := nb.tokenCount[val.Intent]; !ok {
+			nb.tokenCount[val.Intent] = map[string]int{}
+		}
+
+		for _, token := range tokens {
+			nb.tokenCount[val.Intent][token]++
+			nb.tokenTotal[val.Intent]++
+			nb.vocabulary[token] = true
+		}
+	}
+}
+
+// Classify returns every learned intent ranked by the confidence. It returns empty prediction
+// if none of the words is known, so the caller can treat the text as unknown intent
+/*
+	@params:
+		text	= string
+	@example:
+		text	= siapa asisten data warehouse?
+	@return
+		[]{intent, confidence} = [{assistant, 0.97}, {schedule, 0.02}, ...]
+*/
+func (nb *NaiveBayes) Classify(text string) []Prediction {
+
+	predictions := []Prediction{}
+	if nb.sampleTotal < 1 {
+		return predictions
+	}
+
+	var tokens []string
+	for _, val := range Tokenize(text) {
+		if nb.vocabulary[val] {
+			tokens = append(tokens, val)
+		}
+	}
+	if len(tokens) < 1 {
+		return predictions
+	}
+
+	alpha := nb.Alpha
+	if alpha <= 0 {
+		alpha = defaultAlpha
+	}
+	vocabularyTotal := float64(len(nb.vocabulary))
+
+	// log probability of each intent
+	logProb := map[string]float64{}
+	maxLogProb := math.Inf(-1)
+	for intent, total := range nb.intentTotal {
+		p := math.Log(float64(total) / float64(nb.sampleTotal))
+		denominator := float64(nb.tokenTotal[intent]) + alpha*vocabularyTotal
+		for _, token := range tokens {
+			p += math.Log((float64(nb.tokenCount[intent][token]) + alpha) / denominator)
+		}
+		logProb[intent] = p
+		if p > maxLogProb {
+			maxLogProb = p
+		}
+	}
+
+	// normalize the probability using softmax, the max is substracted to avoid underflow
+	var sum float64
+	for intent, p := range logProb {
+		logProb[intent] = math.Exp(p - maxLogProb)
+		sum += logProb[intent]
+	}
+
+	for intent, p := range logProb {
+		predictions = append(predictions, Prediction{
+			Intent:     intent,
+			Confidence: p / sum,
+		})
+	}
+
+	sortPrediction(predictions)
+	return predictions
+}
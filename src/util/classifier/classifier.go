@@ -0,0 +1,61 @@
+// Package classifier contains the text classifier used for detecting the intent of the bot message
+package classifier
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Classifier is the interface of the intent classifier. Every implementation must be safe
+// for concurrent Classify calls after it's trained
+type Classifier interface {
+	Train(samples []Sample)
+	Classify(text string) []Prediction
+}
+
+// Sample is the labeled utterance used for training and evaluating the classifier
+type Sample struct {
+	Text   string `json:"text"`
+	Intent string `json:"intent"`
+}
+
+// Prediction is the detected intent with its confidence, the confidence is between 0 and 1
+type Prediction struct {
+	Intent     string  `json:"intent"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Tokenize converts the text into lowercase words and the bigram of the adjacent words
+/*
+	@params:
+		text	= string
+	@example:
+		text	= Jadwal hari ini?
+	@return
+		[]string = [jadwal, hari, ini, jadwal_hari, hari_ini]
+*/
+func Tokenize(text string) []string {
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(words)*2)
+	tokens = append(tokens, words...)
+	for i := 1; i < len(words); i++ {
+		tokens = append(tokens, words[i-1]+"_"+words[i])
+	}
+
+	return tokens
+}
+
+// sortPrediction sorts the prediction from the highest confidence, the intent name is used for the same confidence
+func sortPrediction(predictions []Prediction) {
+	sort.Slice(predictions, func(i, j int) bool {
+		if predictions[i].Confidence != predictions[j].Confidence {
+			return predictions[i].Confidence > predictions[j].Confidence
+		}
+		return predictions[i].Intent < predictions[j].Intent
+	})
+}
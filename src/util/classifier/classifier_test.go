@@ -0,0 +1,156 @@
+package classifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	type args struct {
+		text string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "Test Case 1",
+			args: args{
+				text: "Jadwal hari ini?",
+			},
+			want: []string{"jadwal", "hari", "ini", "jadwal_hari", "hari_ini"},
+		},
+		{
+			name: "Test Case 2",
+			args: args{
+				text: "nilai",
+			},
+			want: []string{"nilai"},
+		},
+		{
+			name: "Test Case 3",
+			args: args{
+				text: " ?! ",
+			},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.args.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNaiveBayesClassify(t *testing.T) {
+	nb := NewNaiveBayes()
+	nb.Train([]Sample{
+		{Text: "jadwal kuliah hari ini", Intent: "schedule"},
+		{Text: "kuliah apa besok", Intent: "schedule"},
+		{Text: "berapa nilai saya", Intent: "grade"},
+		{Text: "nilai akhir data warehouse", Intent: "grade"},
+	})
+
+	tests := []struct {
+		name       string
+		text       string
+		wantIntent string
+		wantTotal  int
+	}{
+		{
+			name:       "Test Case 1",
+			text:       "jadwal besok apa?",
+			wantIntent: "schedule",
+			wantTotal:  2,
+		},
+		{
+			name:       "Test Case 2",
+			text:       "nilai saya berapa",
+			wantIntent: "grade",
+			wantTotal:  2,
+		},
+		{
+			name:      "Test Case 3",
+			text:      "halo meiko",
+			wantTotal: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nb.Classify(tt.text)
+			if len(got) != tt.wantTotal {
+				t.Fatalf("Classify() total = %d, want %d", len(got), tt.wantTotal)
+			}
+			if tt.wantTotal < 1 {
+				return
+			}
+			if got[0].Intent != tt.wantIntent {
+				t.Errorf("Classify() intent = %s, want %s", got[0].Intent, tt.wantIntent)
+			}
+			var sum float64
+			for i, val := range got {
+				sum += val.Confidence
+				if i > 0 && got[i-1].Confidence < val.Confidence {
+					t.Errorf("Classify() is not sorted by confidence: %v", got)
+				}
+			}
+			if sum < 0.999 || sum > 1.001 {
+				t.Errorf("Classify() confidence sum = %f, want 1", sum)
+			}
+		})
+	}
+}
+
+func TestNaiveBayesUntrained(t *testing.T) {
+	if got := NewNaiveBayes().Classify("jadwal"); len(got) != 0 {
+		t.Errorf("Classify() = %v, want empty", got)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	nb := NewNaiveBayes()
+	nb.Train([]Sample{
+		{Text: "jadwal kuliah", Intent: "schedule"},
+		{Text: "nilai saya", Intent: "grade"},
+	})
+
+	report := Evaluate(nb, []Sample{
+		{Text: "jadwal", Intent: "schedule"},
+		{Text: "nilai", Intent: "grade"},
+		{Text: "halo", Intent: "grade"},
+	})
+
+	if report.Total != 3 || report.Correct != 2 {
+		t.Fatalf("Evaluate() = %d/%d, want 2/3", report.Correct, report.Total)
+	}
+	if report.Intents["grade"].Recall() != 0.5 {
+		t.Errorf("Recall() = %f, want 0.5", report.Intents["grade"].Recall())
+	}
+	if report.Intents["grade"].Precision() != 1 {
+		t.Errorf("Precision() = %f, want 1", report.Intents["grade"].Precision())
+	}
+	if report.Intents[unknownIntent].Predicted != 1 {
+		t.Errorf("Predicted unknown = %d, want 1", report.Intents[unknownIntent].Predicted)
+	}
+}
+
+func TestCorpus(t *testing.T) {
+	corpus, err := LoadCorpus("../../../files/etc/meiko/bot/intent.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nb := NewNaiveBayes()
+	nb.Train(corpus.TrainSamples())
+	report := Evaluate(nb, corpus.TestSamples())
+	if report.Accuracy < 0.8 {
+		t.Errorf("Accuracy of corpus %s = %f, want at least 0.8", corpus.Version, report.Accuracy)
+	}
+
+	if _, err := LoadCorpus("not_found.json"); err == nil {
+		t.Error("LoadCorpus() expected error for missing file")
+	}
+}
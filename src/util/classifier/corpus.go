@@ -0,0 +1,78 @@
+package classifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Corpus is the versioned training data. Every intent has the training utterances
+// and the held-out utterances used only for the evaluation
+type Corpus struct {
+	Version string         `json:"version"`
+	Intents []CorpusIntent `json:"intents"`
+}
+
+// CorpusIntent holds the utterances of an intent
+type CorpusIntent struct {
+	Name  string   `json:"name"`
+	Train []string `json:"train"`
+	Test  []string `json:"test"`
+}
+
+// LoadCorpus reads the json corpus file
+/*
+	@params:
+		path	= string
+	@example:
+		path	= files/etc/meiko/bot/intent.json
+	@return
+		Corpus
+*/
+func LoadCorpus(path string) (Corpus, error) {
+
+	var corpus Corpus
+	file, err := os.Open(path)
+	if err != nil {
+		return corpus, err
+	}
+	defer file.Close()
+
+	err = json.NewDecoder(file).Decode(&corpus)
+	if err != nil {
+		return corpus, fmt.Errorf("Invalid corpus %s: %s", path, err.Error())
+	}
+
+	if len(corpus.Intents) < 1 {
+		return corpus, fmt.Errorf("Corpus %s doesn't have any intent", path)
+	}
+
+	sort.SliceStable(corpus.Intents, func(i, j int) bool {
+		return corpus.Intents[i].Name < corpus.Intents[j].Name
+	})
+
+	return corpus, nil
+}
+
+// TrainSamples returns the training utterances as the labeled samples
+func (c Corpus) TrainSamples() []Sample {
+	samples := []Sample{}
+	for _, intent := range c.Intents {
+		for _, text := range intent.Train {
+			samples = append(samples, Sample{Text: text, Intent: intent.Name})
+		}
+	}
+	return samples
+}
+
+// TestSamples returns the held-out utterances as the labeled samples
+func (c Corpus) TestSamples() []Sample {
+	samples := []Sample{}
+	for _, intent := range c.Intents {
+		for _, text := range intent.Test {
+			samples = append(samples, Sample{Text: text, Intent: intent.Name})
+		}
+	}
+	return samples
+}
@@ -0,0 +1,73 @@
+package classifier
+
+// unknownIntent is used in the report when the classifier can't predict the sample
+const unknownIntent = "unknown"
+
+// Report is the evaluation result of the classifier
+type Report struct {
+	Total    int
+	Correct  int
+	Accuracy float64
+	Intents  map[string]*IntentReport
+}
+
+// IntentReport is the evaluation result of an intent
+type IntentReport struct {
+	// Total is the number of the samples labeled with the intent
+	Total int
+	// Predicted is the number of the samples predicted as the intent
+	Predicted int
+	Correct   int
+}
+
+// Precision returns the correct prediction ratio of the samples predicted as the intent
+func (r IntentReport) Precision() float64 {
+	if r.Predicted < 1 {
+		return 0
+	}
+	return float64(r.Correct) / float64(r.Predicted)
+}
+
+// Recall returns the correct prediction ratio of the samples labeled with the intent
+func (r IntentReport) Recall() float64 {
+	if r.Total < 1 {
+		return 0
+	}
+	return float64(r.Correct) / float64(r.Total)
+}
+
+// Evaluate classifies the samples and compares the top prediction with the label
+func Evaluate(c Classifier, samples []Sample) Report {
+
+	report := Report{
+		Intents: map[string]*IntentReport{},
+	}
+
+	intent := func(name string) *IntentReport {
+		if _, ok := report.Intents[name]; !ok {
+			report.Intents[name] = &IntentReport{}
+		}
+		return report.Intents[name]
+	}
+
+	for _, val := range samples {
+		predicted := unknownIntent
+		if predictions := c.Classify(val.Text); len(predictions) > 0 {
+			predicted = predictions[0].Intent
+		}
+
+		report.Total++
+		intent(val.Intent).Total++
+		intent(predicted).Predicted++
+		if predicted == val.Intent {
+			report.Correct++
+			intent(val.Intent).Correct++
+		}
+	}
+
+	if report.Total > 0 {
+		report.Accuracy = float64(report.Correct) / float64(report.Total)
+	}
+
+	return report
+}
@@ -0,0 +1,155 @@
+package conn
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrNoRowsAffected is returned by ExecAffected when the statement doesn't change any row
+var ErrNoRowsAffected = errors.New("No rows affected")
+
+// Query is a parameterized statement. The values are never formatted into the query string,
+// they are sent to the database as bind parameters using the ? placeholder
+type Query struct {
+	query string
+	args  []interface{}
+	tx    *sqlx.Tx
+	err   error
+}
+
+// NewQuery is used for building a new parameterized query. A slice argument is expanded,
+// so it can be used for the IN (?) clause
+/*
+	@params:
+		query	= string
+		args	= ...interface{}
+	@example:
+		query	= SELECT id FROM users WHERE email = (?) AND status IN (?)
+		args	= risal@live.com, []int8{1, 2}
+	@return
+		*Query
+*/
+func NewQuery(query string, args ...interface{}) *Query {
+
+	q := &Query{
+		query: query,
+		args:  args,
+	}
+
+	if !hasSliceArg(args) {
+		return q
+	}
+
+	q.query, q.args, q.err = sqlx.In(query, args...)
+	return q
+}
+
+// WithTx sets the transaction used by the query. It receives the same optional tx used by
+// the module functions, so the nil or empty tx means the query will be executed on DB
+func (q *Query) WithTx(tx ...*sqlx.Tx) *Query {
+	for _, val := range tx {
+		if val != nil {
+			q.tx = val
+			break
+		}
+	}
+	return q
+}
+
+// Get scans a single row into dest
+func (q *Query) Get(dest interface{}) error {
+	if q.err != nil {
+		return q.err
+	}
+
+	if q.tx != nil {
+		return q.tx.Get(dest, q.tx.Rebind(q.query), q.args...)
+	}
+	return DB.Get(dest, DB.Rebind(q.query), q.args...)
+}
+
+// Select scans all of the rows into dest
+func (q *Query) Select(dest interface{}) error {
+	if q.err != nil {
+		return q.err
+	}
+
+	if q.tx != nil {
+		return q.tx.Select(dest, q.tx.Rebind(q.query), q.args...)
+	}
+	return DB.Select(dest, DB.Rebind(q.query), q.args...)
+}
+
+// Scan scans the first row into the dest arguments
+func (q *Query) Scan(dest ...interface{}) error {
+	if q.err != nil {
+		return q.err
+	}
+
+	if q.tx != nil {
+		return q.tx.QueryRowx(q.tx.Rebind(q.query), q.args...).Scan(dest...)
+	}
+	return DB.QueryRowx(DB.Rebind(q.query), q.args...).Scan(dest...)
+}
+
+// Queryx returns the rows of the query. The caller must close the rows
+func (q *Query) Queryx() (*sqlx.Rows, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+
+	if q.tx != nil {
+		return q.tx.Queryx(q.tx.Rebind(q.query), q.args...)
+	}
+	return DB.Queryx(DB.Rebind(q.query), q.args...)
+}
+
+// Exec executes the statement without returning any rows
+func (q *Query) Exec() (sql.Result, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+
+	if q.tx != nil {
+		return q.tx.Exec(q.tx.Rebind(q.query), q.args...)
+	}
+	return DB.Exec(DB.Rebind(q.query), q.args...)
+}
+
+// ExecAffected executes the statement and returns ErrNoRowsAffected if nothing is changed
+func (q *Query) ExecAffected() (sql.Result, error) {
+	result, err := q.Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if rows == 0 {
+		return nil, ErrNoRowsAffected
+	}
+
+	return result, nil
+}
+
+// hasSliceArg checks whether the arguments need to be expanded for IN (?) clause
+func hasSliceArg(args []interface{}) bool {
+	for _, val := range args {
+		if val == nil {
+			continue
+		}
+		if _, ok := val.([]byte); ok {
+			continue
+		}
+		if reflect.TypeOf(val).Kind() == reflect.Slice {
+			return true
+		}
+	}
+	return false
+}
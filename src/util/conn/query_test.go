@@ -0,0 +1,164 @@
+package conn
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestNewQuery(t *testing.T) {
+	cases := []struct {
+		name      string
+		query     string
+		args      []interface{}
+		wantQuery string
+		wantArgs  []interface{}
+		wantErr   bool
+	}{
+		{
+			name:      "Test Case 1",
+			query:     "SELECT id FROM users WHERE email = (?)",
+			args:      []interface{}{"risal@live.com"},
+			wantQuery: "SELECT id FROM users WHERE email = (?)",
+			wantArgs:  []interface{}{"risal@live.com"},
+			wantErr:   false,
+		},
+		{
+			name:      "Test Case 2",
+			query:     "SELECT id FROM users WHERE id IN (?) AND status = (?)",
+			args:      []interface{}{[]int64{1, 2, 3}, 1},
+			wantQuery: "SELECT id FROM users WHERE id IN (?, ?, ?) AND status = (?)",
+			wantArgs:  []interface{}{int64(1), int64(2), int64(3), 1},
+			wantErr:   false,
+		},
+		{
+			name:      "Test Case 3",
+			query:     "UPDATE files SET data = (?) WHERE id = (?)",
+			args:      []interface{}{[]byte("x"), "1"},
+			wantQuery: "UPDATE files SET data = (?) WHERE id = (?)",
+			wantArgs:  []interface{}{[]byte("x"), "1"},
+			wantErr:   false,
+		},
+		{
+			name:    "Test Case 4",
+			query:   "SELECT id FROM users WHERE id IN (?)",
+			args:    []interface{}{[]int64{}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQuery(tt.query, tt.args...)
+			if (q.err != nil) != tt.wantErr {
+				t.Errorf("NewQuery() error = %v, wantErr %v", q.err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if q.query != tt.wantQuery {
+				t.Errorf("NewQuery() query = %v, want %v", q.query, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(q.args, tt.wantArgs) {
+				t.Errorf("NewQuery() args = %v, want %v", q.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestQueryExecAffected(t *testing.T) {
+	cases := []struct {
+		name         string
+		rowsAffected int64
+		err          error
+		wantErr      error
+	}{
+		{
+			name:         "Test Case 1",
+			rowsAffected: 1,
+			err:          nil,
+			wantErr:      nil,
+		},
+		{
+			name:         "Test Case 2",
+			rowsAffected: 0,
+			err:          nil,
+			wantErr:      ErrNoRowsAffected,
+		},
+		{
+			name:         "Test Case 3",
+			rowsAffected: 0,
+			err:          fmt.Errorf("Error connection"),
+			wantErr:      fmt.Errorf("Error connection"),
+		},
+	}
+	for _, tt := range cases {
+		db, _ := InitDBMock()
+		q := db.ExpectExec(`^DELETE FROM users WHERE identity_code = \(\?\)$`).
+			WithArgs(140810140016)
+		if tt.err == nil {
+			q.WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+		} else {
+			q.WillReturnError(tt.err)
+		}
+
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewQuery("DELETE FROM users WHERE identity_code = (?)", 140810140016).ExecAffected()
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("ExecAffected() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueryWithTx(t *testing.T) {
+	db, _ := InitDBMock()
+	db.ExpectBegin()
+	db.ExpectQuery(`^SELECT name FROM users WHERE id IN \(\?, \?\)$`).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).
+			AddRow(driver.Value("Risal Falah")).
+			AddRow(driver.Value("Rifki Muhammad")))
+	db.ExpectCommit()
+
+	tx, err := DB.Beginx()
+	if err != nil {
+		t.Fatalf("Beginx() error = %v", err)
+	}
+
+	var names []string
+	err = NewQuery("SELECT name FROM users WHERE id IN (?)", []int{1, 2}).
+		WithTx(nil, tx).
+		Select(&names)
+	if err != nil {
+		t.Errorf("Select() error = %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Errorf("Commit() error = %v", err)
+	}
+
+	want := []string{"Risal Falah", "Rifki Muhammad"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Select() = %v, want %v", names, want)
+	}
+	if err := db.ExpectationsWereMet(); err != nil {
+		t.Errorf("ExpectationsWereMet() error = %v", err)
+	}
+}
+
+func TestQueryGet(t *testing.T) {
+	db, _ := InitDBMock()
+	db.ExpectQuery(`^SELECT name FROM users WHERE email = \(\?\) LIMIT 1$`).
+		WithArgs("viavallen@metal.com").
+		WillReturnError(sql.ErrNoRows)
+
+	var name string
+	err := NewQuery("SELECT name FROM users WHERE email = (?) LIMIT 1", "viavallen@metal.com").Get(&name)
+	if err != sql.ErrNoRows {
+		t.Errorf("Get() error = %v, want %v", err, sql.ErrNoRows)
+	}
+}
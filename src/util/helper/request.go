@@ -0,0 +1,99 @@
+package helper
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// trustedProxies are the networks of the reverse proxies whose forwarded headers are honoured
+var trustedProxies []*net.IPNet
+
+// SetTrustedProxies sets the reverse proxies which are allowed to forward the client ip address. Both the
+// single address and the CIDR notation are accepted
+/*
+	@params:
+		proxies	= []string
+	@example:
+		proxies	= []string{"127.0.0.1", "10.0.0.0/8"}
+	@return
+		err		= nil
+*/
+func SetTrustedProxies(proxies []string) error {
+	networks := []*net.IPNet{}
+	for _, val := range proxies {
+		val = strings.TrimSpace(val)
+		if !strings.Contains(val, "/") {
+			ip := net.ParseIP(val)
+			if ip == nil {
+				return fmt.Errorf("Invalid trusted proxy: %s", val)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(val)
+		if err != nil {
+			return fmt.Errorf("Invalid trusted proxy: %s", val)
+		}
+		networks = append(networks, network)
+	}
+	trustedProxies = networks
+	return nil
+}
+
+// ClientIP returns the ip address of the request. The address of the connection is used unless it comes from
+// a trusted proxy, then X-Forwarded-For is read from the right and the first untrusted address is the client
+/*
+	@params:
+		r	= *http.Request
+	@example:
+		r	= request from the trusted proxy 127.0.0.1 with X-Forwarded-For: 10.0.0.1, 10.0.0.2
+	@return
+		ip	= 10.0.0.2
+*/
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !isTrustedProxy(host) {
+		return host
+	}
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(hops[i])
+			if net.ParseIP(ip) == nil {
+				break
+			}
+			if !isTrustedProxy(ip) || i == 0 {
+				return ip
+			}
+		}
+		return host
+	}
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(ip) != nil {
+		return ip
+	}
+	return host
+}
+
+// isTrustedProxy checks whether the address belongs to the trusted proxies
+func isTrustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package helper
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	if err := SetTrustedProxies([]string{"10.1.0.0/16", "192.0.2.10"}); err != nil {
+		t.Fatal(err)
+	}
+	defer SetTrustedProxies(nil)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     map[string]string
+		want       string
+	}{
+		{
+			name:       "Test Case 1",
+			remoteAddr: "192.0.2.1:1234",
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "Test Case 2",
+			remoteAddr: "192.0.2.1:1234",
+			header:     map[string]string{"X-Forwarded-For": "10.0.0.1, 10.0.0.2"},
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "Test Case 3",
+			remoteAddr: "192.0.2.1:1234",
+			header:     map[string]string{"X-Real-IP": "10.0.0.3"},
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "Test Case 4",
+			remoteAddr: "192.0.2.10:1234",
+			header:     map[string]string{"X-Forwarded-For": "10.0.0.1, 10.0.0.2"},
+			want:       "10.0.0.2",
+		},
+		{
+			name:       "Test Case 5",
+			remoteAddr: "192.0.2.10:1234",
+			header:     map[string]string{"X-Forwarded-For": "10.0.0.1, 10.0.0.2, 10.1.3.4"},
+			want:       "10.0.0.2",
+		},
+		{
+			name:       "Test Case 6",
+			remoteAddr: "10.1.0.5:1234",
+			header:     map[string]string{"X-Real-IP": "10.0.0.3"},
+			want:       "10.0.0.3",
+		},
+		{
+			name:       "Test Case 7",
+			remoteAddr: "10.1.0.5:1234",
+			header:     map[string]string{"X-Forwarded-For": "not an ip"},
+			want:       "10.1.0.5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for key, val := range tt.header {
+				r.Header.Set(key, val)
+			}
+			if got := ClientIP(r); got != tt.want {
+				t.Errorf("ClientIP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTrustedProxies(t *testing.T) {
+	defer SetTrustedProxies(nil)
+
+	tests := []struct {
+		name    string
+		proxies []string
+		wantErr bool
+	}{
+		{name: "Test Case 1", proxies: []string{"127.0.0.1", "::1", "10.0.0.0/8"}, wantErr: false},
+		{name: "Test Case 2", proxies: []string{"localhost"}, wantErr: true},
+		{name: "Test Case 3", proxies: []string{"10.0.0.0/33"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetTrustedProxies(tt.proxies); (err != nil) != tt.wantErr {
+				t.Errorf("SetTrustedProxies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
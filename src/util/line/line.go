@@ -0,0 +1,127 @@
+// Package line contains the client of the LINE Messaging API used by the bot webhook
+package line
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// NewClient returns the LINE Messaging API client of the channel
+/*
+	@params:
+		cfg		= Config
+	@example:
+		cfg		= {channel_secret: 8f7d..., channel_token: Bearer token, endpoint: http://127.0.0.1:9000}
+	@return
+		*Client
+*/
+func NewClient(cfg Config) *Client {
+	endpoint := strings.TrimRight(cfg.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return &Client{
+		endpoint: endpoint,
+		token:    cfg.ChannelToken,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// VerifySignature checks whether the signature is the base64 encoded HMAC-SHA256 of the body
+// signed using the channel secret
+/*
+	@params:
+		secret		= string
+		body		= []byte
+		signature	= string
+	@example:
+		secret		= 8f7d...
+		body		= {"events": []}
+		signature	= Vb3sD...
+	@return
+		true
+*/
+func VerifySignature(secret string, body []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(decoded, mac.Sum(nil))
+}
+
+// NewTextMessage returns the text message, the text is truncated if it's longer than the LINE limit
+func NewTextMessage(text string) Message {
+	runes := []rune(text)
+	if len(runes) > MaximumTextLength {
+		text = string(runes[:MaximumTextLength-3]) + "..."
+	}
+	return Message{
+		Type: MessageText,
+		Text: text,
+	}
+}
+
+// Reply sends the messages using the reply token of the event
+/*
+	@params:
+		replyToken	= string
+		messages	= []Message
+	@example:
+		replyToken	= nHuyWiB7yP5Zw52FIkcQobQuGDXCTA
+		messages	= [{type: text, text: Hello}]
+	@return
+*/
+func (c *Client) Reply(replyToken string, messages ...Message) error {
+
+	if len(messages) < 1 {
+		return fmt.Errorf("Messages cannot be empty")
+	}
+	if len(messages) > MaximumReplyMessage {
+		messages = messages[:MaximumReplyMessage]
+	}
+
+	body, err := json.Marshal(replyRequest{
+		ReplyToken: replyToken,
+		Messages:   messages,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.endpoint+pathReply, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var e errorResponse
+		json.NewDecoder(resp.Body).Decode(&e)
+		return fmt.Errorf("LINE reply failed with status %d: %s", resp.StatusCode, e.Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package line
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"events":[]}`)
+	type args struct {
+		secret    string
+		body      []byte
+		signature string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "Test Case 1",
+			args: args{
+				secret:    "secret",
+				body:      body,
+				signature: sign("secret", body),
+			},
+			want: true,
+		},
+		{
+			name: "Test Case 2",
+			args: args{
+				secret:    "secret",
+				body:      body,
+				signature: sign("other", body),
+			},
+			want: false,
+		},
+		{
+			name: "Test Case 3",
+			args: args{
+				secret:    "secret",
+				body:      []byte(`{"events":[{}]}`),
+				signature: sign("secret", body),
+			},
+			want: false,
+		},
+		{
+			name: "Test Case 4",
+			args: args{
+				secret:    "secret",
+				body:      body,
+				signature: "not base64!",
+			},
+			want: false,
+		},
+		{
+			name: "Test Case 5",
+			args: args{
+				secret:    "",
+				body:      body,
+				signature: sign("", body),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySignature(tt.args.secret, tt.args.body, tt.args.signature); got != tt.want {
+				t.Errorf("VerifySignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTextMessage(t *testing.T) {
+	got := NewTextMessage(strings.Repeat("a", MaximumTextLength+10))
+	if len([]rune(got.Text)) != MaximumTextLength || got.Type != MessageText {
+		t.Errorf("NewTextMessage() length = %d, want %d", len([]rune(got.Text)), MaximumTextLength)
+	}
+}
+
+func TestReply(t *testing.T) {
+	var got replyRequest
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != pathReply || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"Authentication failed"}`))
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{}`))
+	}))
+	defer stub.Close()
+
+	err := NewClient(Config{ChannelToken: "token", Endpoint: stub.URL}).
+		Reply("reply-token", NewTextMessage("Hello"))
+	if err != nil {
+		t.Fatalf("Reply() error = %v", err)
+	}
+	if got.ReplyToken != "reply-token" || len(got.Messages) != 1 || got.Messages[0].Text != "Hello" {
+		t.Errorf("Reply() request = %+v", got)
+	}
+
+	err = NewClient(Config{ChannelToken: "invalid", Endpoint: stub.URL}).
+		Reply("reply-token", NewTextMessage("Hello"))
+	if err == nil {
+		t.Error("Reply() expected error for unauthorized token")
+	}
+}
@@ -0,0 +1,79 @@
+package line
+
+import "net/http"
+
+const (
+	// DefaultEndpoint is the LINE Messaging API endpoint used when the endpoint isn't configured
+	DefaultEndpoint = "https://api.line.me"
+
+	// EventMessage is the webhook event sent when the user sends a message
+	EventMessage = "message"
+	// MessageText is the type of the text message
+	MessageText = "text"
+	// SourceUser is the source type of the message sent from the 1-on-1 chat
+	SourceUser = "user"
+
+	// HeaderSignature is the header containing the signature of the webhook body
+	HeaderSignature = "X-Line-Signature"
+
+	// MaximumReplyMessage is the maximum number of messages in a reply
+	MaximumReplyMessage = 5
+	// MaximumTextLength is the maximum number of characters of the text message
+	MaximumTextLength = 2000
+
+	pathReply = "/v2/bot/message/reply"
+)
+
+// Config is the channel configuration of the LINE Messaging API
+type Config struct {
+	ChannelSecret string `json:"channel_secret"`
+	ChannelToken  string `json:"channel_token"`
+	// Endpoint can be replaced with the local stub for testing
+	Endpoint string `json:"endpoint"`
+}
+
+// Client is used for sending the message to the LINE Messaging API
+type Client struct {
+	endpoint   string
+	token      string
+	httpClient *http.Client
+}
+
+// Webhook is the request body sent by LINE platform to the webhook url
+type Webhook struct {
+	Destination string  `json:"destination"`
+	Events      []Event `json:"events"`
+}
+
+// Event is the webhook event, only the message event is used by the bot
+type Event struct {
+	Type       string  `json:"type"`
+	ReplyToken string  `json:"replyToken"`
+	Timestamp  int64   `json:"timestamp"`
+	Source     Source  `json:"source"`
+	Message    Message `json:"message"`
+}
+
+// Source is the sender of the event
+type Source struct {
+	Type    string `json:"type"`
+	UserID  string `json:"userId"`
+	GroupID string `json:"groupId,omitempty"`
+	RoomID  string `json:"roomId,omitempty"`
+}
+
+// Message is the message object of the event and the reply
+type Message struct {
+	ID   string `json:"id,omitempty"`
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+type replyRequest struct {
+	ReplyToken string    `json:"replyToken"`
+	Messages   []Message `json:"messages"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,208 @@
+// Package ratelimit contains the Redis fixed window rate limiter and the temporary lockout
+package ratelimit
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/julienschmidt/httprouter"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/melodiez14/meiko/src/util/helper"
+	"github.com/melodiez14/meiko/src/webserver/template"
+)
+
+const keyPrefix = "ratelimit:"
+
+// Limit allows Max hits of the same id in the Window. If the Lockout is set, the id is locked
+// for the Lockout duration once it reaches the Max hits
+type Limit struct {
+	Name    string
+	Max     int64
+	Window  time.Duration
+	Lockout time.Duration
+}
+
+// KeyFunc returns the id of the request which is limited
+type KeyFunc func(r *http.Request) string
+
+// Hit counts the hit of the id and returns false if the id exceeds the limit or it's locked
+/*
+	@params:
+		id	= string
+	@example:
+		id	= risal@live.com
+	@return
+		isAllowed	= false
+		retryAfter	= 15m0s
+*/
+func (l Limit) Hit(id string) (bool, time.Duration, error) {
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	retryAfter, err := ttl(client, l.lockKey(id))
+	if err != nil {
+		return true, 0, err
+	}
+	if retryAfter > 0 {
+		return false, retryAfter, nil
+	}
+
+	key := l.key(id)
+	count, err := redis.Int64(client.Do("INCR", key))
+	if err != nil {
+		return true, 0, err
+	}
+
+	if count == 1 {
+		_, err = client.Do("EXPIRE", key, seconds(l.Window))
+		if err != nil {
+			return true, 0, err
+		}
+	}
+
+	if l.Lockout > 0 && count >= l.Max {
+		_, err = client.Do("SET", l.lockKey(id), count, "EX", seconds(l.Lockout))
+		if err != nil {
+			return true, 0, err
+		}
+		_, err = client.Do("DEL", key)
+		if err != nil {
+			return true, 0, err
+		}
+		return count == l.Max, l.Lockout, nil
+	}
+
+	if count > l.Max {
+		retryAfter, err = ttl(client, key)
+		if err != nil {
+			return true, 0, err
+		}
+		return false, retryAfter, nil
+	}
+
+	return true, 0, nil
+}
+
+// Blocked returns whether the id is locked or exceeds the limit without counting the hit
+/*
+	@params:
+		id	= string
+	@example:
+		id	= risal@live.com
+	@return
+		isBlocked	= true
+		retryAfter	= 15m0s
+*/
+func (l Limit) Blocked(id string) (bool, time.Duration, error) {
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	retryAfter, err := ttl(client, l.lockKey(id))
+	if err != nil {
+		return false, 0, err
+	}
+	if retryAfter > 0 {
+		return true, retryAfter, nil
+	}
+
+	key := l.key(id)
+	count, err := redis.Int64(client.Do("GET", key))
+	if err == redis.ErrNil {
+		return false, 0, nil
+	}
+	if err != nil {
+		return false, 0, err
+	}
+
+	if count < l.Max || (l.Lockout <= 0 && count == l.Max) {
+		return false, 0, nil
+	}
+
+	retryAfter, err = ttl(client, key)
+	if err != nil {
+		return false, 0, err
+	}
+	return true, retryAfter, nil
+}
+
+// Reset removes the hits and the lock of the id
+func (l Limit) Reset(id string) error {
+
+	client := conn.Redis.Get()
+	defer client.Close()
+
+	_, err := client.Do("DEL", l.key(id), l.lockKey(id))
+	return err
+}
+
+// Middleware limits the request of the handler by the id returned by the key function. It responds
+// 429 Too Many Requests with the Retry-After header when the limit is exceeded. The request is allowed
+// if Redis isn't available so the limiter doesn't take the route down
+/*
+	@example:
+		r.POST("/api/v1/user/signin", ratelimit.Middleware(limit, ratelimit.ByIP, handler))
+*/
+func Middleware(l Limit, key KeyFunc, h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		isAllowed, retryAfter, err := l.Hit(key(r))
+		if err != nil {
+			log.Printf("[ratelimit][%s] %s", l.Name, err.Error())
+		}
+
+		if !isAllowed {
+			Reject(w, retryAfter)
+			return
+		}
+
+		h(w, r, ps)
+	}
+}
+
+// ByIP is the key function which limits the request by the client ip address. The forwarded headers are only
+// honoured when the connection comes from a trusted proxy, so the client can't rotate its own key
+func ByIP(r *http.Request) string {
+	return helper.ClientIP(r)
+}
+
+// Reject writes the 429 Too Many Requests response with the Retry-After header
+func Reject(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.FormatInt(seconds(retryAfter), 10))
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusTooManyRequests).
+		AddError(fmt.Sprintf("Too many requests, please try again in %d seconds", seconds(retryAfter))))
+}
+
+func (l Limit) key(id string) string {
+	return keyPrefix + l.Name + ":" + id
+}
+
+func (l Limit) lockKey(id string) string {
+	return keyPrefix + l.Name + ":lock:" + id
+}
+
+// ttl returns the remaining lifetime of the key, it's zero if the key doesn't exist
+func ttl(client redis.Conn, key string) (time.Duration, error) {
+	t, err := redis.Int64(client.Do("TTL", key))
+	if err != nil {
+		return 0, err
+	}
+	if t < 0 {
+		return 0, nil
+	}
+	return time.Duration(t) * time.Second, nil
+}
+
+// seconds rounds up the duration into seconds, Redis expiry must be at least a second
+func seconds(d time.Duration) int64 {
+	s := int64((d + time.Second - 1) / time.Second)
+	if s < 1 {
+		return 1
+	}
+	return s
+}
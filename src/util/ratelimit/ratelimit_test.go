@@ -0,0 +1,218 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/julienschmidt/httprouter"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/rafaeljusto/redigomock"
+)
+
+func initRedisMock() *redigomock.Conn {
+	mock := redigomock.NewConn()
+	conn.Redis = &redis.Pool{
+		MaxIdle:     3,
+		IdleTimeout: 10 * time.Second,
+		Dial:        func() (redis.Conn, error) { return mock, nil },
+	}
+	return mock
+}
+
+func TestHit(t *testing.T) {
+	limit := Limit{Name: "test", Max: 3, Window: time.Minute}
+	lockout := Limit{Name: "test", Max: 3, Window: time.Minute, Lockout: 15 * time.Minute}
+
+	tests := []struct {
+		name           string
+		limit          Limit
+		lockTTL        int64
+		count          int64
+		counterTTL     int64
+		wantAllowed    bool
+		wantRetryAfter time.Duration
+		wantLock       int
+	}{
+		{
+			name:        "Test Case 1",
+			limit:       limit,
+			lockTTL:     -2,
+			count:       1,
+			wantAllowed: true,
+		},
+		{
+			name:           "Test Case 2",
+			limit:          limit,
+			lockTTL:        -2,
+			count:          4,
+			counterTTL:     30,
+			wantAllowed:    false,
+			wantRetryAfter: 30 * time.Second,
+		},
+		{
+			name:           "Test Case 3",
+			limit:          lockout,
+			lockTTL:        -2,
+			count:          3,
+			wantAllowed:    true,
+			wantRetryAfter: 15 * time.Minute,
+			wantLock:       1,
+		},
+		{
+			name:           "Test Case 4",
+			limit:          lockout,
+			lockTTL:        600,
+			wantAllowed:    false,
+			wantRetryAfter: 10 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := initRedisMock()
+			mock.Command("TTL", "ratelimit:test:lock:1.1.1.1").Expect(tt.lockTTL)
+			mock.Command("TTL", "ratelimit:test:1.1.1.1").Expect(tt.counterTTL)
+			mock.Command("INCR", "ratelimit:test:1.1.1.1").Expect(tt.count)
+			expire := mock.Command("EXPIRE", "ratelimit:test:1.1.1.1", int64(60)).Expect(int64(1))
+			lock := mock.Command("SET", "ratelimit:test:lock:1.1.1.1", tt.count, "EX", int64(900)).Expect("OK")
+			mock.Command("DEL", "ratelimit:test:1.1.1.1").Expect(int64(1))
+
+			isAllowed, retryAfter, err := tt.limit.Hit("1.1.1.1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if isAllowed != tt.wantAllowed || retryAfter != tt.wantRetryAfter {
+				t.Errorf("Hit() = %v, %v, want %v, %v", isAllowed, retryAfter, tt.wantAllowed, tt.wantRetryAfter)
+			}
+			if tt.count == 1 && mock.Stats(expire) != 1 {
+				t.Error("Hit() doesn't set the window of the first hit")
+			}
+			if mock.Stats(lock) != tt.wantLock {
+				t.Errorf("Hit() lock = %d, want %d", mock.Stats(lock), tt.wantLock)
+			}
+		})
+	}
+}
+
+func TestBlocked(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       Limit
+		lockTTL     int64
+		count       interface{}
+		wantBlocked bool
+	}{
+		{
+			name:        "Test Case 1",
+			limit:       Limit{Name: "test", Max: 3, Window: time.Minute},
+			lockTTL:     -2,
+			count:       nil,
+			wantBlocked: false,
+		},
+		{
+			name:        "Test Case 2",
+			limit:       Limit{Name: "test", Max: 3, Window: time.Minute},
+			lockTTL:     -2,
+			count:       []byte("3"),
+			wantBlocked: false,
+		},
+		{
+			name:        "Test Case 3",
+			limit:       Limit{Name: "test", Max: 3, Window: time.Minute},
+			lockTTL:     -2,
+			count:       []byte("4"),
+			wantBlocked: true,
+		},
+		{
+			name:        "Test Case 4",
+			limit:       Limit{Name: "test", Max: 3, Window: time.Minute, Lockout: time.Minute},
+			lockTTL:     60,
+			wantBlocked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := initRedisMock()
+			mock.Command("TTL", "ratelimit:test:lock:risal@live.com").Expect(tt.lockTTL)
+			mock.Command("TTL", "ratelimit:test:risal@live.com").Expect(int64(30))
+			mock.Command("GET", "ratelimit:test:risal@live.com").Expect(tt.count)
+
+			isBlocked, _, err := tt.limit.Blocked("risal@live.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if isBlocked != tt.wantBlocked {
+				t.Errorf("Blocked() = %v, want %v", isBlocked, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	limit := Limit{Name: "test", Max: 1, Window: time.Minute}
+
+	mock := initRedisMock()
+	mock.Command("TTL", "ratelimit:test:lock:192.0.2.1").Expect(int64(-2))
+	mock.Command("TTL", "ratelimit:test:192.0.2.1").Expect(int64(42))
+	mock.Command("INCR", "ratelimit:test:192.0.2.1").Expect(int64(2))
+
+	var isCalled bool
+	h := Middleware(limit, ByIP, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		isCalled = true
+	})
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest("POST", "/api/v1/user/signin", nil), nil)
+
+	if isCalled || w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "42" {
+		t.Errorf("Middleware() = %d, Retry-After %s", w.Code, w.Header().Get("Retry-After"))
+	}
+}
+
+func TestByIP(t *testing.T) {
+	tests := []struct {
+		name      string
+		forwarded string
+		realIP    string
+	}{
+		{name: "Test Case 1"},
+		{name: "Test Case 2", forwarded: "10.0.0.1"},
+		{name: "Test Case 3", forwarded: "10.0.0.2, 10.0.0.3"},
+		{name: "Test Case 4", realIP: "10.0.0.4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/v1/user/signin", nil)
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+			if got := ByIP(r); got != "192.0.2.1" {
+				t.Errorf("ByIP() = %v, want %v", got, "192.0.2.1")
+			}
+		})
+	}
+}
+
+func TestSeconds(t *testing.T) {
+	tests := []struct {
+		name string
+		d    time.Duration
+		want int64
+	}{
+		{name: "Test Case 1", d: 0, want: 1},
+		{name: "Test Case 2", d: 1500 * time.Millisecond, want: 2},
+		{name: "Test Case 3", d: time.Hour, want: 3600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := seconds(tt.d); got != tt.want {
+				t.Errorf("seconds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package spreadsheet
+
+import (
+	"strconv"
+	"strings"
+)
+
+// workbook is the xl/workbook.xml part, only the order of the sheets is read
+type workbook struct {
+	Sheets []struct {
+		RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// relationships is the xl/_rels/workbook.xml.rels part which maps the sheet to its path
+type relationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// sharedStrings is the xl/sharedStrings.xml part, the text cell refers to its index
+type sharedStrings struct {
+	Items []richText `xml:"si"`
+}
+
+// richText is the text which may be split into several formatted runs
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (rt richText) String() string {
+	if len(rt.Runs) < 1 {
+		return rt.T
+	}
+	var s string
+	for _, run := range rt.Runs {
+		s += run.T
+	}
+	return s
+}
+
+type worksheet struct {
+	Rows []struct {
+		R     string `xml:"r,attr"`
+		Cells []cell `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type cell struct {
+	R      string   `xml:"r,attr"`
+	T      string   `xml:"t,attr"`
+	V      string   `xml:"v"`
+	Inline richText `xml:"is"`
+}
+
+// value returns the text of the cell. The large number is written by excel in the scientific notation,
+// so it's formatted back into the plain number, e.g. the identity code
+func (c cell) value(sst sharedStrings) string {
+	switch c.T {
+	case "s":
+		i, err := strconv.Atoi(c.V)
+		if err != nil || i < 0 || i >= len(sst.Items) {
+			return ""
+		}
+		return sst.Items[i].String()
+	case "inlineStr":
+		return c.Inline.String()
+	case "", "n":
+		if strings.ContainsAny(c.V, "eE") {
+			if f, err := strconv.ParseFloat(c.V, 64); err == nil {
+				return strconv.FormatFloat(f, 'f', -1, 64)
+			}
+		}
+	}
+	return c.V
+}
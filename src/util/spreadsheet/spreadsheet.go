@@ -0,0 +1,200 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/melodiez14/meiko/src/util/alias"
+)
+
+// the formats which can be read
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// maxPartSize limits the uncompressed size of every part of the xlsx file
+const maxPartSize = 16 << 20
+
+// the row and column references of the xlsx file are bounded, so a tiny sheet can't claim billions of cells.
+// The header row is read along with the accounts
+const (
+	maxRows    = alias.UserImportMax + 1
+	maxColumns = 64
+)
+
+// ErrUnsupportedFormat is returned when the file isn't a csv or xlsx file
+var ErrUnsupportedFormat = fmt.Errorf("Unsupported format, only csv and xlsx are supported")
+
+// Read returns the rows of the csv file or the first sheet of the xlsx file. The format is the file extension
+/*
+	@params:
+		r		= io.ReaderAt, e.g. multipart.File
+		size	= int64
+		format	= csv/xlsx
+	@example:
+		r		= students.xlsx
+		size	= 8102
+		format	= xlsx
+	@return
+		[][]string{{"identity_code", "name", "email"}, {"140810140016", "Risal Falah", "risal@live.com"}}
+*/
+func Read(r io.ReaderAt, size int64, format string) ([][]string, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return ReadCSV(io.NewSectionReader(r, 0, size))
+	case FormatXLSX:
+		return ReadXLSX(r, size)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// ReadCSV returns the rows of the csv file, the rows may have different number of the fields
+func ReadCSV(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid csv file: %s", err.Error())
+	}
+
+	// the csv exported by excel starts with the utf-8 byte order mark
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+	}
+	return rows, nil
+}
+
+// ReadXLSX returns the rows of the first sheet of the xlsx file. Only the cell values are read, the formula
+// returns its cached value and the empty cells are returned as empty string
+func ReadXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid xlsx file")
+	}
+
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var sst sharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodePart(f, &sst); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[firstSheet(files)]
+	if !ok {
+		return nil, fmt.Errorf("Invalid xlsx file: sheet not found")
+	}
+
+	var ws worksheet
+	if err := decodePart(f, &ws); err != nil {
+		return nil, err
+	}
+
+	rows := [][]string{}
+	for _, row := range ws.Rows {
+		// the empty rows are skipped by excel, so the row number is used to keep their position
+		if n, err := strconv.Atoi(row.R); err == nil {
+			if n > maxRows {
+				return nil, fmt.Errorf("Invalid xlsx file: maximum %d rows", maxRows)
+			}
+			for len(rows) < n-1 {
+				rows = append(rows, []string{})
+			}
+		}
+
+		values := []string{}
+		for _, c := range row.Cells {
+			col := len(values)
+			if c.R != "" {
+				col = columnIndex(c.R)
+			}
+			if col >= maxColumns {
+				return nil, fmt.Errorf("Invalid xlsx file: maximum %d columns", maxColumns)
+			}
+			for len(values) < col {
+				values = append(values, "")
+			}
+			values = append(values, c.value(sst))
+		}
+		if len(rows) >= maxRows {
+			return nil, fmt.Errorf("Invalid xlsx file: maximum %d rows", maxRows)
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+// firstSheet returns the path of the first sheet of the workbook
+func firstSheet(files map[string]*zip.File) string {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	var wb workbook
+	var rels relationships
+	fwb, okWB := files["xl/workbook.xml"]
+	frels, okRels := files["xl/_rels/workbook.xml.rels"]
+	if !okWB || !okRels || decodePart(fwb, &wb) != nil || decodePart(frels, &rels) != nil || len(wb.Sheets) < 1 {
+		return fallback
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != wb.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/")
+		}
+		return path.Join("xl", rel.Target)
+	}
+	return fallback
+}
+
+// decodePart decodes the xml part of the xlsx file
+func decodePart(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("Invalid xlsx file")
+	}
+	defer rc.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(rc, maxPartSize+1))
+	if err != nil {
+		return fmt.Errorf("Invalid xlsx file")
+	}
+	if len(data) > maxPartSize {
+		return fmt.Errorf("Invalid xlsx file: file is too large")
+	}
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("Invalid xlsx file")
+	}
+	return nil
+}
+
+// columnIndex returns the zero based column of the cell reference, e.g. 0 for A1 and 27 for AB3. The column
+// past maxColumns is returned as maxColumns so the long reference can't overflow
+func columnIndex(ref string) int {
+	col := 0
+	for _, ch := range strings.ToUpper(ref) {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		col = col*26 + int(ch-'A') + 1
+		if col > maxColumns {
+			return maxColumns
+		}
+	}
+	return col - 1
+}
@@ -0,0 +1,187 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newXLSX zips the parts into the xlsx file
+func newXLSX(t *testing.T, parts map[string]string) *bytes.Reader {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, content := range parts {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+const (
+	testWorkbook = `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+	<sheets>
+		<sheet name="Students" sheetId="1" r:id="rId2"/>
+		<sheet name="Other" sheetId="2" r:id="rId1"/>
+	</sheets>
+</workbook>`
+	testRels = `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+	<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+	<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet2.xml"/>
+</Relationships>`
+	testSharedStrings = `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+	<si><t>identity_code</t></si>
+	<si><t>name</t></si>
+	<si><t>email</t></si>
+	<si><r><t>Risal </t></r><r><t>Falah</t></r></si>
+	<si><t>risal@live.com</t></si>
+</sst>`
+	testSheet = `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+	<sheetData>
+		<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c></row>
+		<row r="2"><c r="A2"><v>1.40810140016E11</v></c><c r="B2" t="s"><v>3</v></c><c r="C2" t="s"><v>4</v></c></row>
+		<row r="4"><c r="A4"><v>140810140060</v></c><c r="C4" t="inlineStr"><is><t>asep@live.com</t></is></c></row>
+	</sheetData>
+</worksheet>`
+)
+
+func TestReadXLSX(t *testing.T) {
+	r := newXLSX(t, map[string]string{
+		"xl/workbook.xml":            testWorkbook,
+		"xl/_rels/workbook.xml.rels": testRels,
+		"xl/sharedStrings.xml":       testSharedStrings,
+		"xl/worksheets/sheet1.xml":   `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>wrong sheet</t></is></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml":   testSheet,
+	})
+
+	got, err := ReadXLSX(r, r.Size())
+	if err != nil {
+		t.Fatalf("ReadXLSX() error = %v", err)
+	}
+	want := [][]string{
+		{"identity_code", "name", "email"},
+		{"140810140016", "Risal Falah", "risal@live.com"},
+		{},
+		{"140810140060", "", "asep@live.com"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadXLSX() = %v, want %v", got, want)
+	}
+}
+
+func TestReadXLSXBounds(t *testing.T) {
+	tests := []struct {
+		name  string
+		sheet string
+	}{
+		{
+			name:  "Test Case 1",
+			sheet: `<worksheet><sheetData><row r="2000000000"><c r="A2000000000"><v>1</v></c></row></sheetData></worksheet>`,
+		},
+		{
+			name:  "Test Case 2",
+			sheet: `<worksheet><sheetData><row r="1"><c r="ZZZZZZZ1"><v>1</v></c></row></sheetData></worksheet>`,
+		},
+		{
+			name:  "Test Case 3",
+			sheet: `<worksheet><sheetData><row r="1"><c r="ZZZZZZZZZZZZZZZZZZZZZZZZ1"><v>1</v></c></row></sheetData></worksheet>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": tt.sheet})
+			got, err := ReadXLSX(r, r.Size())
+			if err == nil {
+				t.Errorf("ReadXLSX() = %d rows, want error", len(got))
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		format  string
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name:    "Test Case 1",
+			content: "\ufeffidentity_code,name,email\n140810140016, Risal Falah,risal@live.com\n",
+			format:  "CSV",
+			want: [][]string{
+				{"identity_code", "name", "email"},
+				{"140810140016", "Risal Falah", "risal@live.com"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Test Case 2",
+			content: "140810140016,\"Falah, Risal\"\n140810140060\n",
+			format:  "csv",
+			want: [][]string{
+				{"140810140016", "Falah, Risal"},
+				{"140810140060"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Test Case 3",
+			content: "not a zip file",
+			format:  "xlsx",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 4",
+			content: "140810140016",
+			format:  "xls",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := strings.NewReader(tt.content)
+			got, err := Read(r, r.Size(), tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Read() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Read() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{ref: "A1", want: 0},
+		{ref: "C12", want: 2},
+		{ref: "AB3", want: 27},
+		{ref: "ZZZZZZZZZZZZZZZZ1", want: maxColumns},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			if got := columnIndex(tt.ref); got != tt.want {
+				t.Errorf("columnIndex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
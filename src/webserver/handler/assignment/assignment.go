@@ -8,19 +8,22 @@ import (
 
 	"github.com/julienschmidt/httprouter"
 	as "github.com/melodiez14/meiko/src/module/assignment"
+	"github.com/melodiez14/meiko/src/module/audit"
 	cs "github.com/melodiez14/meiko/src/module/course"
 	fs "github.com/melodiez14/meiko/src/module/file"
+	gd "github.com/melodiez14/meiko/src/module/grade"
 	rg "github.com/melodiez14/meiko/src/module/rolegroup"
 	usr "github.com/melodiez14/meiko/src/module/user"
 	"github.com/melodiez14/meiko/src/util/auth"
 	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/melodiez14/meiko/src/util/helper"
 	"github.com/melodiez14/meiko/src/webserver/template"
 )
 
 // CreateHandler function is
 func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleAssignment, rg.RoleCreate, rg.RoleXCreate) {
+	if sess.Scope(rg.ModuleAssignment, rg.RoleCreate) == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -49,6 +52,12 @@ func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 			AddError("Grade parameters id does not exist!"))
 		return
 	}
+	if !isHasAccess(sess, cs.GetScheduleID(args.GradeParametersID), rg.RoleCreate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
 	// Insert to table assignments
 	tx := conn.DB.MustBegin()
 	TableID, err := as.Insert(
@@ -111,7 +120,8 @@ func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 // GetAllAssignmentHandler func is ...
 func GetAllAssignmentHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleAssignment, rg.RoleRead, rg.RoleXRead) {
+	scope := sess.Scope(rg.ModuleAssignment, rg.RoleRead)
+	if scope == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -128,15 +138,31 @@ func GetAllAssignmentHandler(w http.ResponseWriter, r *http.Request, ps httprout
 			AddError("Invalid request"))
 		return
 	}
+	// the READ ability only lists the assignments of the schedules owned by the user
+	var scheduleID []int64
+	if scope == auth.ScopeOwn {
+		scheduleID, err = cs.SelectOwnedScheduleID(sess.ID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+	var status string
+	var res []readResponse
+	if scope == auth.ScopeOwn && len(scheduleID) < 1 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusOK).
+			SetData(res))
+		return
+	}
 	offset := (args.Page - 1) * args.Total
-	assignments, err := as.SelectByPage(args.Total, offset)
+	assignments, err := as.SelectByPage(args.Total, offset, scheduleID...)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError))
 		return
 	}
-	var status string
-	var res []readResponse
 	for _, val := range assignments {
 
 		if val.Assignment.Status == as.StatusAssignmentActive {
@@ -164,7 +190,7 @@ func GetAllAssignmentHandler(w http.ResponseWriter, r *http.Request, ps httprout
 func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleAssignment, rg.RoleRead, rg.RoleXRead) {
+	if sess.Scope(rg.ModuleAssignment, rg.RoleRead) == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -188,6 +214,12 @@ func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 			SetCode(http.StatusNotFound))
 		return
 	}
+	if !isHasAccess(sess, cs.GetScheduleID(int64(u.Assignment.GradeParameterID)), rg.RoleRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
 
 	var status string
 	switch u.Assignment.Status {
@@ -219,7 +251,7 @@ func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 // UpdateHandler func is ...
 func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleAssignment, rg.RoleUpdate, rg.RoleXRead) {
+	if sess.Scope(rg.ModuleAssignment, rg.RoleUpdate) == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -256,6 +288,15 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
+	// the user must own both the current schedule and the schedule of the new grade parameter
+	if !isHasAccess(sess, cs.GetScheduleID(cs.GetGradeParametersID(args.ID)), rg.RoleUpdate) ||
+		!isHasAccess(sess, cs.GetScheduleID(args.GradeParametersID), rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
 	// Insert to table assignments
 	tx := conn.DB.MustBegin()
 	err = as.Update(
@@ -397,8 +438,8 @@ func CreateHandlerByUser(w http.ResponseWriter, r *http.Request, ps httprouter.P
 func GetUploadedAssignmentByUserHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 	sess := r.Context().Value("User").(*auth.User)
-	params := readUploadedAssignmentParams{
-		ScheudleID:   ps.ByName("schedule_id"),
+	params := getUploadedAssignmentParams{
+		ScheduleID:   ps.ByName("schedule_id"),
 		AssignmentID: ps.ByName("assignment_id"),
 	}
 
@@ -408,13 +449,18 @@ func GetUploadedAssignmentByUserHandler(w http.ResponseWriter, r *http.Request,
 			SetCode(http.StatusBadRequest).AddError(err.Error()))
 		return
 	}
-	if !usr.IsUserTakeSchedule(sess.ID, args.ScheudleID) {
+	if !usr.IsUserTakeSchedule(sess.ID, args.ScheduleID) {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusBadRequest).AddError("Wrong Schedule ID"))
 		return
 	}
 	// Get Assignments Detail
 	assignment, err := as.GetUploadedAssignmentByID(args.AssignmentID, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).AddError("You haven't uploaded this assignment"))
+		return
+	}
 	key := fmt.Sprintf("%d%d", args.AssignmentID, sess.ID)
 	tableID, err := strconv.ParseInt(key, 10, 64)
 	if err != nil {
@@ -432,10 +478,12 @@ func GetUploadedAssignmentByUserHandler(w http.ResponseWriter, r *http.Request,
 	}
 
 	res := readUploadedAssignmentArgs{
-		ScheudleID:   args.ScheudleID,
+		ScheudleID:   args.ScheduleID,
 		AssignmentID: args.AssignmentID,
 		Name:         assignment.Name,
 		Description:  assignment.DescriptionAssignment,
+		Score:        assignment.Score.String,
+		Comment:      assignment.Comment.String,
 		PathFile:     files,
 	}
 	template.RenderJSONResponse(w, new(template.Response).
@@ -448,7 +496,7 @@ func GetUploadedAssignmentByUserHandler(w http.ResponseWriter, r *http.Request,
 // GetUploadedAssignmentByAdminHandler func ...
 func GetUploadedAssignmentByAdminHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleAssignment, rg.RoleRead, rg.RoleXRead) {
+	if sess.Scope(rg.ModuleAssignment, rg.RoleRead) == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -481,6 +529,12 @@ func GetUploadedAssignmentByAdminHandler(w http.ResponseWriter, r *http.Request,
 			AddError("Assignment ID does not exist"))
 		return
 	}
+	if !isHasAccess(sess, cs.GetScheduleID(cs.GetGradeParametersID(args.AssignmentID)), rg.RoleRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
 	// Get all data p_users_assignment
 	assignments, err := as.GetAllUserAssignmentByAssignmentID(args.AssignmentID, args.Total, offset)
 	if err != nil {
@@ -495,11 +549,122 @@ func GetUploadedAssignmentByAdminHandler(w http.ResponseWriter, r *http.Request,
 	// serve json
 }
 
+// UpdateScoreHandler handles the http request for grading the uploaded assignments.
+// Accessing this handler needs UPDATE or XUPDATE ability
+/*
+	@params:
+		assignment_id	= required, positive numeric
+		scores			= required, json of []{id, score, comment}, score = 0 - 100, comment = optional
+	@example:
+		assignment_id	= 3
+		scores			= [{"id":140810140016,"score":85.5,"comment":"Good analysis"},{"id":140810140020,"score":70}]
+	@return
+*/
+func UpdateScoreHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleAssignment, rg.RoleUpdate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := scoreParams{
+		AssignmentID: ps.ByName("assignment_id"),
+		Scores:       r.FormValue("scores"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !as.IsAssignmentExist(args.AssignmentID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Assignment not found"))
+		return
+	}
+
+	// the X ability grants every schedule, the other one only grants the schedules created or assisted by the user
+	scheduleID := cs.GetScheduleID(cs.GetGradeParametersID(args.AssignmentID))
+	if !isHasAccess(sess, scheduleID, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	userIDs, err := as.SelectSubmittedUserID(args.AssignmentID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// map identity code to user id, only the uploaded assignments can be graded
+	userID := map[int64]int64{}
+	if len(userIDs) > 0 {
+		users, err := usr.SelectByID(userIDs, usr.ColID, usr.ColIdentityCode)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		for _, val := range users {
+			userID[val.IdentityCode] = val.ID
+		}
+	}
+	for identity := range args.Scores {
+		if _, ok := userID[identity]; !ok {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError(fmt.Sprintf("%d hasn't uploaded this assignment", identity)))
+			return
+		}
+	}
+
+	tx := conn.DB.MustBegin()
+	for identity, val := range args.Scores {
+		err = as.UpdateScore(args.AssignmentID, userID[identity], val.Score, val.Comment, tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	err = gd.Recompute(scheduleID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Success"))
+	return
+}
+
 // DeleteAssignmentHandler func ...
 func DeleteAssignmentHandler(w http.ResponseWriter, r *http.Request, pr httprouter.Params) {
 
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleAssignment, rg.RoleDelete, rg.RoleXDelete) {
+	if sess.Scope(rg.ModuleAssignment, rg.RoleDelete) == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -521,12 +686,24 @@ func DeleteAssignmentHandler(w http.ResponseWriter, r *http.Request, pr httprout
 			AddError("Wrong Assignment ID"))
 		return
 	}
+	if !isHasAccess(sess, cs.GetScheduleID(cs.GetGradeParametersID(args.ID)), rg.RoleDelete) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
 	if as.IsUserHaveUploadedAsssignment(args.ID) {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusBadRequest).
 			AddError("Forbiden to delete this assignments"))
 		return
 	}
+	assignment, err := as.GetByAssignementID(args.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
 	tx := conn.DB.MustBegin()
 	err = as.DeleteAssignment(args.ID, tx)
 	if err != nil {
@@ -542,6 +719,20 @@ func DeleteAssignmentHandler(w http.ResponseWriter, r *http.Request, pr httprout
 			SetCode(http.StatusInternalServerError))
 		return
 	}
+	diff := audit.NewDiff(map[string]interface{}{
+		"name":                assignment.Assignment.Name,
+		"status":              assignment.Assignment.Status,
+		"description":         assignment.Assignment.Description.String,
+		"grade_parameters_id": assignment.Assignment.GradeParameterID,
+		"due_date":            assignment.Assignment.DueDate,
+	}, nil)
+	err = audit.Insert(sess.ID, audit.ActionDelete, audit.TableAssignments, strconv.FormatInt(args.ID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
 	err = tx.Commit()
 	if err != nil {
 		tx.Rollback()
@@ -554,6 +745,16 @@ func DeleteAssignmentHandler(w http.ResponseWriter, r *http.Request, pr httprout
 	return
 }
 
+// isHasAccess checks the assignment ability of the user to the schedule.
+// The X ability grants every schedule, the other one only grants the schedules created or assisted by the user
+func isHasAccess(sess *auth.User, scheduleID int64, action string) bool {
+	owners, err := cs.SelectOwnerID(scheduleID)
+	if err != nil {
+		return false
+	}
+	return sess.Authorize(rg.ModuleAssignment, action, owners...)
+}
+
 // func GetIncompleteHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 // 	u := r.Context().Value("User").(*auth.User)
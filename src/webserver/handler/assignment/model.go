@@ -131,10 +131,42 @@ type readUploadedAssignmentArgs struct {
 	Name         string
 	Description  sql.NullString
 	Score        string
+	Comment      string
 	DueDate      string
 	PathFile     []fs.File
 }
 
+type getUploadedAssignmentParams struct {
+	ScheduleID   string
+	AssignmentID string
+}
+
+type getUploadedAssignmentArgs struct {
+	ScheduleID   int64
+	AssignmentID int64
+}
+
+type scoreParams struct {
+	AssignmentID string
+	Scores       string
+}
+
+type scoreArgs struct {
+	AssignmentID int64
+	Scores       map[int64]scoreArg
+}
+
+type studentScore struct {
+	ID      int64   `json:"id"`
+	Score   float32 `json:"score"`
+	Comment string  `json:"comment"`
+}
+
+type scoreArg struct {
+	Score   float32
+	Comment sql.NullString
+}
+
 type deleteParams struct {
 	ID string
 }
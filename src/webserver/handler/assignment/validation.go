@@ -2,6 +2,7 @@ package assignment
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"html"
 	"strconv"
@@ -9,6 +10,8 @@ import (
 	"github.com/melodiez14/meiko/src/util/helper"
 )
 
+const maximumScore = 100
+
 func (params createParams) validate() (createArgs, error) {
 	var args createArgs
 	params = createParams{
@@ -235,6 +238,83 @@ func (params readUploadedAssignmentParams) validate() (readUploadedAssignmentArg
 
 }
 
+func (params getUploadedAssignmentParams) validate() (getUploadedAssignmentArgs, error) {
+
+	var args getUploadedAssignmentArgs
+	if helper.IsEmpty(params.ScheduleID) {
+		return args, fmt.Errorf("Schedule ID cannot be empty")
+	}
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Can not convert schedule ID to int64")
+	}
+
+	if helper.IsEmpty(params.AssignmentID) {
+		return args, fmt.Errorf("Assignment ID cannot be empty")
+	}
+	assignmentID, err := strconv.ParseInt(params.AssignmentID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Can not convert assignment ID to int64")
+	}
+
+	return getUploadedAssignmentArgs{
+		ScheduleID:   scheduleID,
+		AssignmentID: assignmentID,
+	}, nil
+}
+
+func (params scoreParams) validate() (scoreArgs, error) {
+
+	var args scoreArgs
+	if helper.IsEmpty(params.AssignmentID) {
+		return args, fmt.Errorf("Assignment ID cannot be empty")
+	}
+	assignmentID, err := strconv.ParseInt(params.AssignmentID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("Can not convert assignment ID to int64")
+	}
+
+	if helper.IsEmpty(params.Scores) {
+		return args, fmt.Errorf("scores can't be empty")
+	}
+
+	// convert json into studentScore struct
+	var students []studentScore
+	err = json.Unmarshal([]byte(params.Scores), &students)
+	if err != nil {
+		return args, fmt.Errorf("Invalid scores format")
+	}
+	if len(students) < 1 {
+		return args, fmt.Errorf("scores can't be empty")
+	}
+
+	scores := map[int64]scoreArg{}
+	for _, val := range students {
+		if _, ok := scores[val.ID]; ok {
+			return args, fmt.Errorf("Duplicate student %d", val.ID)
+		}
+		if val.Score < 0 || val.Score > maximumScore {
+			return args, fmt.Errorf("Score must be between 0 and %d", maximumScore)
+		}
+
+		var comment sql.NullString
+		val.Comment = html.EscapeString(helper.Trim(val.Comment))
+		if !helper.IsEmpty(val.Comment) {
+			comment = sql.NullString{Valid: true, String: val.Comment}
+		}
+
+		scores[val.ID] = scoreArg{
+			Score:   val.Score,
+			Comment: comment,
+		}
+	}
+
+	return scoreArgs{
+		AssignmentID: assignmentID,
+		Scores:       scores,
+	}, nil
+}
+
 func (params deleteParams) validate() (deleteArgs, error) {
 	var args deleteArgs
 	params = deleteParams{
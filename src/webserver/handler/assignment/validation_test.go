@@ -0,0 +1,126 @@
+package assignment
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func Test_getUploadedAssignmentParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  getUploadedAssignmentParams
+		want    getUploadedAssignmentArgs
+		wantErr bool
+	}{
+		{
+			name:    "All empty",
+			params:  getUploadedAssignmentParams{},
+			want:    getUploadedAssignmentArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Invalid assignment",
+			params: getUploadedAssignmentParams{
+				ScheduleID:   "12",
+				AssignmentID: "abc",
+			},
+			want:    getUploadedAssignmentArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Valid",
+			params: getUploadedAssignmentParams{
+				ScheduleID:   "12",
+				AssignmentID: "3",
+			},
+			want: getUploadedAssignmentArgs{
+				ScheduleID:   12,
+				AssignmentID: 3,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getUploadedAssignmentParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getUploadedAssignmentParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_scoreParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  scoreParams
+		want    scoreArgs
+		wantErr bool
+	}{
+		{
+			name:    "All empty",
+			params:  scoreParams{},
+			want:    scoreArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Invalid json",
+			params: scoreParams{
+				AssignmentID: "3",
+				Scores:       "85",
+			},
+			want:    scoreArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Score out of range",
+			params: scoreParams{
+				AssignmentID: "3",
+				Scores:       `[{"id":140810140016,"score":101}]`,
+			},
+			want:    scoreArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Duplicate student",
+			params: scoreParams{
+				AssignmentID: "3",
+				Scores:       `[{"id":140810140016,"score":80},{"id":140810140016,"score":90}]`,
+			},
+			want:    scoreArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Valid",
+			params: scoreParams{
+				AssignmentID: "3",
+				Scores:       `[{"id":140810140016,"score":85.5,"comment":" Good <b>analysis</b> "},{"id":140810140020,"score":0}]`,
+			},
+			want: scoreArgs{
+				AssignmentID: 3,
+				Scores: map[int64]scoreArg{
+					140810140016: {Score: 85.5, Comment: sql.NullString{Valid: true, String: "Good &lt;b&gt;analysis&lt;/b&gt;"}},
+					140810140020: {Score: 0},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("scoreParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scoreParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
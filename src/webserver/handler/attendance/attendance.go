@@ -1,51 +1,724 @@
 package attendance
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
+	at "github.com/melodiez14/meiko/src/module/attendance"
+	cs "github.com/melodiez14/meiko/src/module/course"
+	gd "github.com/melodiez14/meiko/src/module/grade"
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/module/user"
+	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/util/conn"
 	"github.com/melodiez14/meiko/src/webserver/template"
+	qrcode "github.com/skip2/go-qrcode"
 )
 
+// GetSummaryHandler handles the http request return the attendance percentage of the current courses
+/*
+	@params:
+	@example:
+	@return
+		[]{schedule_id, course, class, meeting_total, attendance_total, percentage}
+*/
 func GetSummaryHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
-	// u := r.Context().Value("User").(*auth.User)
-
-	// courses, err := course.GetByUserID(u.ID)
-	// if err != nil {
-	// 	template.RenderJSONResponse(w, new(template.Response).
-	// 		SetCode(http.StatusInternalServerError).
-	// 		AddError(err.Error()))
-	// 	return
-	// }
-
-	// var res []summaryResponse
-	// var percentage float32
-	// for _, c := range courses {
-	// 	a, err := attendance.GetByUserCourseID(u.ID, c.ID)
-	// 	if err != nil {
-	// 		template.RenderJSONResponse(w, new(template.Response).
-	// 			SetCode(http.StatusInternalServerError).
-	// 			AddError(err.Error()))
-	// 		return
-	// 	}
-
-	// 	if len(a) > 0 {
-	// 		percentage = (float32(len(a)) * 100) / float32(len(a))
-	// 	} else {
-	// 		percentage = 0
-	// 	}
-
-	// 	res = append(res, summaryResponse{
-	// 		Course:     c.Name,
-	// 		Percentage: fmt.Sprintf("%.4g%%", percentage),
-	// 	})
-	// }
-
-	// template.RenderJSONResponse(w, new(template.Response).
-	// 	SetCode(http.StatusOK).
-	// 	SetData(res))
-	template.RenderJSONResponse(w, new(template.Response).
-		SetCode(http.StatusOK))
+	sess := r.Context().Value("User").(*auth.User)
+
+	scheduleID, err := cs.SelectScheduleIDByUserID(sess.ID, cs.PStatusStudent)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	courses, err := cs.SelectByScheduleID(scheduleID, cs.StatusScheduleActive)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	summaries, err := at.SelectSummaryByUserID(sess.ID, scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	summary := map[int64]at.Summary{}
+	for _, val := range summaries {
+		summary[val.ScheduleID] = val
+	}
+
+	res := []summaryResponse{}
+	for _, val := range courses {
+		var percentage float32
+		s := summary[val.Schedule.ID]
+		if s.MeetingTotal > 0 {
+			percentage = (float32(s.AttendanceTotal) * 100) / float32(s.MeetingTotal)
+		}
+
+		res = append(res, summaryResponse{
+			ScheduleID:      val.Schedule.ID,
+			Course:          val.Course.Name,
+			Class:           val.Schedule.Class,
+			MeetingTotal:    s.MeetingTotal,
+			AttendanceTotal: s.AttendanceTotal,
+			Percentage:      fmt.Sprintf("%.4g%%", percentage),
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// GetHandler handles the http request return the attendance status of every meeting in the enrolled schedule
+/*
+	@params:
+		schedule_id	= required, positive numeric
+	@example:
+		schedule_id	= 12
+	@return
+		[]{number, subject, date, status}
+*/
+func GetHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := getParams{
+		ScheduleID: r.FormValue("schedule_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsEnrolled(sess.ID, args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You are not enrolled in this course"))
+		return
+	}
+
+	meetings, err := at.SelectMeetingByScheduleID(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	attendances, err := at.SelectByUserScheduleID(sess.ID, args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	status := map[int64]int8{}
+	for _, val := range attendances {
+		status[val.MeetingID] = val.Status
+	}
+
+	res := []getResponse{}
+	for _, val := range meetings {
+		res = append(res, getResponse{
+			Number:  val.Number,
+			Subject: val.Subject,
+			Date:    val.Date.Format(dateLayout),
+			Status:  statusText[status[val.ID]],
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// CreateMeetingHandler handles the http request for opening a new meeting. Accessing this handler needs CREATE or XCREATE ability
+/*
+	@params:
+		schedule_id	= required, positive numeric
+		number		= required, positive numeric
+		subject		= required, maximum 100 characters
+		description	= optional
+		date		= required, YYYY-MM-DD
+	@example:
+		schedule_id	= 12
+		number		= 1
+		subject		= Pengenalan Sistem Informasi Multimedia
+		description	= Pertemuan pertama membahas kontrak perkuliahan
+		date		= 2017-09-04
+	@return
+		id = 1
+*/
+func CreateMeetingHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleAttendance, rg.RoleCreate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := createMeetingParams{
+		ScheduleID:  r.FormValue("schedule_id"),
+		Number:      r.FormValue("number"),
+		Subject:     r.FormValue("subject"),
+		Description: r.FormValue("description"),
+		Date:        r.FormValue("date"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsExistScheduleID(args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Schedule not found"))
+		return
+	}
+
+	if !isHasAccess(sess, args.ScheduleID, rg.RoleCreate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	if at.IsExistMeeting(args.ScheduleID, args.Number) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusConflict).
+			AddError("Meeting already exists"))
+		return
+	}
+
+	id, err := at.InsertMeeting(args.ScheduleID, args.Number, args.Subject, args.Description, args.Date)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Success").
+		SetData(map[string]int64{"id": id}))
+	return
+}
+
+// ReadMeetingHandler handles the http request return the meetings of the schedule. Accessing this handler needs READ or XREAD ability
+/*
+	@params:
+		schedule_id	= required, positive numeric
+	@example:
+		schedule_id	= 12
+	@return
+		[]{id, number, subject, description, date}
+*/
+func ReadMeetingHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleAttendance, rg.RoleRead) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := readMeetingParams{
+		ScheduleID: r.FormValue("schedule_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !isHasAccess(sess, args.ScheduleID, rg.RoleRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	meetings, err := at.SelectMeetingByScheduleID(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	res := []readMeetingResponse{}
+	for _, val := range meetings {
+		res = append(res, readMeetingResponse{
+			ID:          val.ID,
+			Number:      val.Number,
+			Subject:     val.Subject,
+			Description: val.Description.String,
+			Date:        val.Date.Format(dateLayout),
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// ReadMeetingDetailHandler handles the http request return the meeting and the attendance status of each enrolled student.
+// Accessing this handler needs READ or XREAD ability
+/*
+	@params:
+		meeting_id	= required, positive numeric
+	@example:
+		meeting_id	= 1
+	@return
+		{id, number, subject, description, date, schedule_id, students: []{id, name, status}}
+*/
+func ReadMeetingDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleAttendance, rg.RoleRead) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := readMeetingDetailParams{
+		MeetingID: ps.ByName("meeting_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	meeting, err := at.GetMeetingByID(args.MeetingID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Meeting not found"))
+		return
+	}
+
+	if !isHasAccess(sess, meeting.ScheduleID, rg.RoleRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	students, err := selectStudent(meeting.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	attendances, err := at.SelectByMeetingID(meeting.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	status := map[int64]int8{}
+	for _, val := range attendances {
+		status[val.UserID] = val.Status
+	}
+
+	res := readMeetingDetailResponse{
+		ID:          meeting.ID,
+		Number:      meeting.Number,
+		Subject:     meeting.Subject,
+		Description: meeting.Description.String,
+		Date:        meeting.Date.Format(dateLayout),
+		ScheduleID:  meeting.ScheduleID,
+		Students:    []studentResponse{},
+	}
+	for _, val := range students {
+		res.Students = append(res.Students, studentResponse{
+			ID:     val.IdentityCode,
+			Name:   val.Name,
+			Status: statusText[status[val.ID]],
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
 	return
 }
+
+// UpdateMeetingHandler handles the http request for marking the attendance of the enrolled students.
+// Accessing this handler needs UPDATE or XUPDATE ability
+/*
+	@params:
+		meeting_id	= required, positive numeric
+		attendances	= required, json of []{id, status}, status = [present, absent, sick, permitted]
+	@example:
+		meeting_id	= 1
+		attendances	= [{"id":140810140016,"status":"present"},{"id":140810140020,"status":"sick"}]
+	@return
+*/
+func UpdateMeetingHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleAttendance, rg.RoleUpdate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := updateMeetingParams{
+		MeetingID:   ps.ByName("meeting_id"),
+		Attendances: r.FormValue("attendances"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	meeting, err := at.GetMeetingByID(args.MeetingID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Meeting not found"))
+		return
+	}
+
+	if !isHasAccess(sess, meeting.ScheduleID, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	students, err := selectStudent(meeting.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// map identity code to user id, only the enrolled students can be marked
+	userID := map[int64]int64{}
+	for _, val := range students {
+		userID[val.IdentityCode] = val.ID
+	}
+	for identity := range args.Attendances {
+		if _, ok := userID[identity]; !ok {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError(fmt.Sprintf("%d is not enrolled in this course", identity)))
+			return
+		}
+	}
+
+	tx := conn.DB.MustBegin()
+	for identity, status := range args.Attendances {
+		err = at.Upsert(meeting.ID, userID[identity], status, tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	err = gd.Recompute(meeting.ScheduleID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Success"))
+	return
+}
+
+// CreateCodeHandler handles the http request for generating the self check-in code of the meeting.
+// The code is only valid for a short time, so it should be regenerated periodically while the meeting is running.
+// Accessing this handler needs UPDATE or XUPDATE ability
+/*
+	@params:
+		meeting_id	= required, positive numeric
+	@example:
+		meeting_id	= 1
+	@return
+		{code, expire_in}
+*/
+func CreateCodeHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleAttendance, rg.RoleUpdate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := createCodeParams{
+		MeetingID: ps.ByName("meeting_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	meeting, err := at.GetMeetingByID(args.MeetingID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Meeting not found"))
+		return
+	}
+
+	if !isHasAccess(sess, meeting.ScheduleID, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	code, err := at.GenerateCode(meeting.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Success").
+		SetData(createCodeResponse{
+			Code:     code,
+			ExpireIn: at.CodeExpire,
+		}))
+	return
+}
+
+// ReadQRHandler handles the http request return the QR code image of the active check-in code.
+// Accessing this handler needs UPDATE or XUPDATE ability
+/*
+	@params:
+		meeting_id	= required, positive numeric
+		code		= required, 6 characters
+		size		= optional, 128 - 1024 pixels
+	@example:
+		meeting_id	= 1
+		code		= K7PX2M
+		size		= 512
+	@return
+		image/png
+*/
+func ReadQRHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleAttendance, rg.RoleUpdate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := readQRParams{
+		MeetingID: ps.ByName("meeting_id"),
+		Code:      r.FormValue("code"),
+		Size:      r.FormValue("size"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	meeting, err := at.GetMeetingByID(args.MeetingID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Meeting not found"))
+		return
+	}
+
+	if !isHasAccess(sess, meeting.ScheduleID, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	meetingID, err := at.GetMeetingIDByCode(args.Code)
+	if err != nil || meetingID != meeting.ID {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Code is expired"))
+		return
+	}
+
+	png, err := qrcode.Encode(args.Code, qrcode.Medium, args.Size)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(png)
+}
+
+// CheckInHandler handles the http request for recording the attendance by the check-in code shown in the meeting.
+// Only the students enrolled in the schedule of the meeting can check in
+/*
+	@params:
+		code	= required, 6 characters
+	@example:
+		code	= K7PX2M
+	@return
+		{schedule_id, number, subject, status}
+*/
+func CheckInHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := checkInParams{
+		Code: r.FormValue("code"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	meetingID, err := at.GetMeetingIDByCode(args.Code)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Invalid or expired code"))
+		return
+	}
+
+	meeting, err := at.GetMeetingByID(meetingID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Meeting not found"))
+		return
+	}
+
+	if !cs.IsStudent(sess.ID, meeting.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You are not enrolled in this course"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = at.Upsert(meeting.ID, sess.ID, at.StatusPresent, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = gd.RecomputeUser(meeting.ScheduleID, sess.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Success").
+		SetData(checkInResponse{
+			ScheduleID: meeting.ScheduleID,
+			Number:     meeting.Number,
+			Subject:    meeting.Subject,
+			Status:     statusText[at.StatusPresent],
+		}))
+	return
+}
+
+// isHasAccess checks the attendance ability of the user to the schedule.
+// The X ability grants every schedule, the other one only grants the schedules created or assisted by the user
+func isHasAccess(sess *auth.User, scheduleID int64, action string) bool {
+	owners, err := cs.SelectOwnerID(scheduleID)
+	if err != nil {
+		return false
+	}
+	return sess.Authorize(rg.ModuleAttendance, action, owners...)
+}
+
+// selectStudent returns the students enrolled in the schedule
+func selectStudent(scheduleID int64) ([]user.User, error) {
+
+	userIDs, err := cs.SelectStudentID(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userIDs) < 1 {
+		return []user.User{}, nil
+	}
+
+	return user.SelectByID(userIDs, user.ColID, user.ColName, user.ColIdentityCode)
+}
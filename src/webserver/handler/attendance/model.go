@@ -1,6 +1,141 @@
 package attendance
 
+import (
+	"database/sql"
+	"time"
+)
+
 type summaryResponse struct {
-	Course     string `json:"status"`
-	Percentage string `json:"percentage"`
+	ScheduleID      int64  `json:"schedule_id"`
+	Course          string `json:"course"`
+	Class           string `json:"class"`
+	MeetingTotal    int64  `json:"meeting_total"`
+	AttendanceTotal int64  `json:"attendance_total"`
+	Percentage      string `json:"percentage"`
+}
+
+type getParams struct {
+	ScheduleID string
+}
+
+type getArgs struct {
+	ScheduleID int64
+}
+
+type getResponse struct {
+	Number  uint8  `json:"number"`
+	Subject string `json:"subject"`
+	Date    string `json:"date"`
+	Status  string `json:"status"`
+}
+
+type createMeetingParams struct {
+	ScheduleID  string
+	Number      string
+	Subject     string
+	Description string
+	Date        string
+}
+
+type createMeetingArgs struct {
+	ScheduleID  int64
+	Number      uint8
+	Subject     string
+	Description sql.NullString
+	Date        time.Time
+}
+
+type readMeetingParams struct {
+	ScheduleID string
+}
+
+type readMeetingArgs struct {
+	ScheduleID int64
+}
+
+type readMeetingResponse struct {
+	ID          int64  `json:"id"`
+	Number      uint8  `json:"number"`
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+	Date        string `json:"date"`
+}
+
+type readMeetingDetailParams struct {
+	MeetingID string
+}
+
+type readMeetingDetailArgs struct {
+	MeetingID int64
+}
+
+type readMeetingDetailResponse struct {
+	ID          int64             `json:"id"`
+	Number      uint8             `json:"number"`
+	Subject     string            `json:"subject"`
+	Description string            `json:"description"`
+	Date        string            `json:"date"`
+	ScheduleID  int64             `json:"schedule_id"`
+	Students    []studentResponse `json:"students"`
+}
+
+type studentResponse struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+type studentAttendance struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+type updateMeetingParams struct {
+	MeetingID   string
+	Attendances string
+}
+
+type updateMeetingArgs struct {
+	MeetingID   int64
+	Attendances map[int64]int8
+}
+
+type createCodeParams struct {
+	MeetingID string
+}
+
+type createCodeArgs struct {
+	MeetingID int64
+}
+
+type createCodeResponse struct {
+	Code     string `json:"code"`
+	ExpireIn int64  `json:"expire_in"`
+}
+
+type readQRParams struct {
+	MeetingID string
+	Code      string
+	Size      string
+}
+
+type readQRArgs struct {
+	MeetingID int64
+	Code      string
+	Size      int
+}
+
+type checkInParams struct {
+	Code string
+}
+
+type checkInArgs struct {
+	Code string
+}
+
+type checkInResponse struct {
+	ScheduleID int64  `json:"schedule_id"`
+	Number     uint8  `json:"number"`
+	Subject    string `json:"subject"`
+	Status     string `json:"status"`
 }
@@ -0,0 +1,278 @@
+package attendance
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	at "github.com/melodiez14/meiko/src/module/attendance"
+	"github.com/melodiez14/meiko/src/util/helper"
+)
+
+const (
+	dateLayout    = "2006-01-02"
+	qrSizeDefault = 256
+	qrSizeMin     = 128
+	qrSizeMax     = 1024
+)
+
+var statusText = map[int8]string{
+	at.StatusAbsent:    "absent",
+	at.StatusPresent:   "present",
+	at.StatusSick:      "sick",
+	at.StatusPermitted: "permitted",
+}
+
+func (params getParams) validate() (getArgs, error) {
+
+	var args getArgs
+	if helper.IsEmpty(params.ScheduleID) {
+		return args, fmt.Errorf("schedule_id can't be empty")
+	}
+
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("schedule_id must be numeric")
+	}
+
+	args = getArgs{
+		ScheduleID: scheduleID,
+	}
+	return args, nil
+}
+
+func (params createMeetingParams) validate() (createMeetingArgs, error) {
+
+	var args createMeetingArgs
+	params = createMeetingParams{
+		ScheduleID:  params.ScheduleID,
+		Number:      params.Number,
+		Subject:     html.EscapeString(helper.Trim(params.Subject)),
+		Description: html.EscapeString(helper.Trim(params.Description)),
+		Date:        helper.Trim(params.Date),
+	}
+
+	// Schedule validation
+	if helper.IsEmpty(params.ScheduleID) {
+		return args, fmt.Errorf("schedule_id can't be empty")
+	}
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("schedule_id must be numeric")
+	}
+
+	// Number validation
+	if helper.IsEmpty(params.Number) {
+		return args, fmt.Errorf("Meeting number can't be empty")
+	}
+	number, err := strconv.ParseUint(params.Number, 10, 8)
+	if err != nil || number < 1 {
+		return args, fmt.Errorf("Invalid meeting number")
+	}
+
+	// Subject validation
+	if helper.IsEmpty(params.Subject) {
+		return args, fmt.Errorf("Subject can't be empty")
+	}
+	if len(params.Subject) > 100 {
+		return args, fmt.Errorf("Subject can have only maximum 100 characters length")
+	}
+
+	// Description validation
+	var description sql.NullString
+	if !helper.IsEmpty(params.Description) {
+		description = sql.NullString{Valid: true, String: params.Description}
+	}
+
+	// Date validation
+	if helper.IsEmpty(params.Date) {
+		return args, fmt.Errorf("Date can't be empty")
+	}
+	date, err := time.Parse(dateLayout, params.Date)
+	if err != nil {
+		return args, fmt.Errorf("Invalid date")
+	}
+
+	args = createMeetingArgs{
+		ScheduleID:  scheduleID,
+		Number:      uint8(number),
+		Subject:     params.Subject,
+		Description: description,
+		Date:        date,
+	}
+	return args, nil
+}
+
+func (params readMeetingParams) validate() (readMeetingArgs, error) {
+
+	var args readMeetingArgs
+	if helper.IsEmpty(params.ScheduleID) {
+		return args, fmt.Errorf("schedule_id can't be empty")
+	}
+
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("schedule_id must be numeric")
+	}
+
+	args = readMeetingArgs{
+		ScheduleID: scheduleID,
+	}
+	return args, nil
+}
+
+func (params readMeetingDetailParams) validate() (readMeetingDetailArgs, error) {
+
+	var args readMeetingDetailArgs
+	if helper.IsEmpty(params.MeetingID) {
+		return args, fmt.Errorf("meeting_id can't be empty")
+	}
+
+	meetingID, err := strconv.ParseInt(params.MeetingID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("meeting_id must be numeric")
+	}
+
+	args = readMeetingDetailArgs{
+		MeetingID: meetingID,
+	}
+	return args, nil
+}
+
+func (params updateMeetingParams) validate() (updateMeetingArgs, error) {
+
+	var args updateMeetingArgs
+	if helper.IsEmpty(params.MeetingID) {
+		return args, fmt.Errorf("meeting_id can't be empty")
+	}
+
+	meetingID, err := strconv.ParseInt(params.MeetingID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("meeting_id must be numeric")
+	}
+
+	if helper.IsEmpty(params.Attendances) {
+		return args, fmt.Errorf("attendances can't be empty")
+	}
+
+	// convert json into studentAttendance struct
+	var students []studentAttendance
+	err = json.Unmarshal([]byte(params.Attendances), &students)
+	if err != nil {
+		return args, fmt.Errorf("Invalid attendances format")
+	}
+	if len(students) < 1 {
+		return args, fmt.Errorf("attendances can't be empty")
+	}
+
+	attendances := map[int64]int8{}
+	for _, val := range students {
+		status, err := statusToInt(val.Status)
+		if err != nil {
+			return args, err
+		}
+		if _, ok := attendances[val.ID]; ok {
+			return args, fmt.Errorf("Duplicate student %d", val.ID)
+		}
+		attendances[val.ID] = status
+	}
+
+	args = updateMeetingArgs{
+		MeetingID:   meetingID,
+		Attendances: attendances,
+	}
+	return args, nil
+}
+
+func (params createCodeParams) validate() (createCodeArgs, error) {
+
+	var args createCodeArgs
+	if helper.IsEmpty(params.MeetingID) {
+		return args, fmt.Errorf("meeting_id can't be empty")
+	}
+
+	meetingID, err := strconv.ParseInt(params.MeetingID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("meeting_id must be numeric")
+	}
+
+	args = createCodeArgs{
+		MeetingID: meetingID,
+	}
+	return args, nil
+}
+
+func (params readQRParams) validate() (readQRArgs, error) {
+
+	var args readQRArgs
+	if helper.IsEmpty(params.MeetingID) {
+		return args, fmt.Errorf("meeting_id can't be empty")
+	}
+
+	meetingID, err := strconv.ParseInt(params.MeetingID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("meeting_id must be numeric")
+	}
+
+	code, err := normalizeCode(params.Code)
+	if err != nil {
+		return args, err
+	}
+
+	// Size validation
+	size := qrSizeDefault
+	if !helper.IsEmpty(params.Size) {
+		size, err = strconv.Atoi(params.Size)
+		if err != nil || size < qrSizeMin || size > qrSizeMax {
+			return args, fmt.Errorf("size must be between %d and %d", qrSizeMin, qrSizeMax)
+		}
+	}
+
+	args = readQRArgs{
+		MeetingID: meetingID,
+		Code:      code,
+		Size:      size,
+	}
+	return args, nil
+}
+
+func (params checkInParams) validate() (checkInArgs, error) {
+
+	var args checkInArgs
+	code, err := normalizeCode(params.Code)
+	if err != nil {
+		return args, err
+	}
+
+	args = checkInArgs{
+		Code: code,
+	}
+	return args, nil
+}
+
+// normalizeCode converts the check-in code into uppercase and checks its format
+func normalizeCode(code string) (string, error) {
+	code = strings.ToUpper(helper.Trim(code))
+	if helper.IsEmpty(code) {
+		return "", fmt.Errorf("code can't be empty")
+	}
+	if !at.IsValidCode(code) {
+		return "", fmt.Errorf("Invalid code")
+	}
+	return code, nil
+}
+
+// statusToInt converts the attendance status text into the status stored in the attendances table
+func statusToInt(status string) (int8, error) {
+	status = strings.ToLower(helper.Trim(status))
+	for key, val := range statusText {
+		if val == status {
+			return key, nil
+		}
+	}
+	return 0, fmt.Errorf("Invalid attendance status")
+}
@@ -0,0 +1,268 @@
+package attendance
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	at "github.com/melodiez14/meiko/src/module/attendance"
+)
+
+func Test_createMeetingParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  createMeetingParams
+		want    createMeetingArgs
+		wantErr bool
+	}{
+		{
+			name:    "All empty",
+			params:  createMeetingParams{},
+			want:    createMeetingArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Invalid number",
+			params: createMeetingParams{
+				ScheduleID: "12",
+				Number:     "0",
+				Subject:    "Pengenalan",
+				Date:       "2017-09-04",
+			},
+			want:    createMeetingArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Invalid date",
+			params: createMeetingParams{
+				ScheduleID: "12",
+				Number:     "1",
+				Subject:    "Pengenalan",
+				Date:       "04-09-2017",
+			},
+			want:    createMeetingArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Valid",
+			params: createMeetingParams{
+				ScheduleID:  "12",
+				Number:      "1",
+				Subject:     " Pengenalan ",
+				Description: "Kontrak perkuliahan",
+				Date:        "2017-09-04",
+			},
+			want: createMeetingArgs{
+				ScheduleID:  12,
+				Number:      1,
+				Subject:     "Pengenalan",
+				Description: sql.NullString{Valid: true, String: "Kontrak perkuliahan"},
+				Date:        time.Date(2017, 9, 4, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("createMeetingParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("createMeetingParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_updateMeetingParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  updateMeetingParams
+		want    updateMeetingArgs
+		wantErr bool
+	}{
+		{
+			name:    "All empty",
+			params:  updateMeetingParams{},
+			want:    updateMeetingArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Invalid json",
+			params: updateMeetingParams{
+				MeetingID:   "1",
+				Attendances: "present",
+			},
+			want:    updateMeetingArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Invalid status",
+			params: updateMeetingParams{
+				MeetingID:   "1",
+				Attendances: `[{"id":140810140016,"status":"late"}]`,
+			},
+			want:    updateMeetingArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Duplicate student",
+			params: updateMeetingParams{
+				MeetingID:   "1",
+				Attendances: `[{"id":140810140016,"status":"present"},{"id":140810140016,"status":"sick"}]`,
+			},
+			want:    updateMeetingArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Valid",
+			params: updateMeetingParams{
+				MeetingID:   "1",
+				Attendances: `[{"id":140810140016,"status":"Present"},{"id":140810140020,"status":"permitted"}]`,
+			},
+			want: updateMeetingArgs{
+				MeetingID: 1,
+				Attendances: map[int64]int8{
+					140810140016: at.StatusPresent,
+					140810140020: at.StatusPermitted,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("updateMeetingParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("updateMeetingParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_readQRParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  readQRParams
+		want    readQRArgs
+		wantErr bool
+	}{
+		{
+			name:    "All empty",
+			params:  readQRParams{},
+			want:    readQRArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Invalid code",
+			params: readQRParams{
+				MeetingID: "1",
+				Code:      "K7PX2",
+			},
+			want:    readQRArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Invalid size",
+			params: readQRParams{
+				MeetingID: "1",
+				Code:      "K7PX2M",
+				Size:      "4096",
+			},
+			want:    readQRArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Valid default size",
+			params: readQRParams{
+				MeetingID: "1",
+				Code:      "k7px2m",
+			},
+			want: readQRArgs{
+				MeetingID: 1,
+				Code:      "K7PX2M",
+				Size:      qrSizeDefault,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Valid",
+			params: readQRParams{
+				MeetingID: "1",
+				Code:      "K7PX2M",
+				Size:      "512",
+			},
+			want: readQRArgs{
+				MeetingID: 1,
+				Code:      "K7PX2M",
+				Size:      512,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readQRParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readQRParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkInParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  checkInParams
+		want    checkInArgs
+		wantErr bool
+	}{
+		{
+			name:    "All empty",
+			params:  checkInParams{},
+			want:    checkInArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Ambiguous character",
+			params: checkInParams{
+				Code: "K7PX0O",
+			},
+			want:    checkInArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Valid",
+			params: checkInParams{
+				Code: " k7px2m ",
+			},
+			want: checkInArgs{
+				Code: "K7PX2M",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkInParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkInParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/melodiez14/meiko/src/module/audit"
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/webserver/template"
+)
+
+// ReadHandler handles the http request for listing the audit logs of the administrative actions from the newest log.
+// The logs aren't owned by anyone, so accessing this handler needs XREAD ability of audits module
+/*
+	@params:
+		pg			= required, positive numeric
+		ttl			= required, positive numeric
+		actor		= optional, identity code of the actor
+		action		= optional, create/update/delete
+		table		= optional, users/schedules/assignments/grade_parameters/rolegroups/rolegroups_modules/
+					  p_users_schedules/schedules_capacities
+		target_id	= optional, used with table
+		from		= optional, YYYY-MM-DD
+		to			= optional, YYYY-MM-DD
+	@example:
+		pg			= 1
+		ttl			= 10
+		action		= delete
+		table		= users
+		from		= 2017-10-01
+	@return
+		{total, logs: []{id, name, identity_code, action, table, target_id, diff, ip, created_at}}
+*/
+func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.Authorize(rg.ModuleAudit, rg.RoleRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := readParams{
+		Page:         r.FormValue("pg"),
+		Total:        r.FormValue("ttl"),
+		IdentityCode: r.FormValue("actor"),
+		Action:       r.FormValue("action"),
+		Table:        r.FormValue("table"),
+		TargetID:     r.FormValue("target_id"),
+		From:         r.FormValue("from"),
+		To:           r.FormValue("to"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	total, err := audit.Count(args.Filter)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	offset := (args.Page - 1) * args.Total
+	logs, err := audit.SelectByPage(args.Filter, args.Total, offset)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	res := readResponse{
+		Total: total,
+		Logs:  []logResponse{},
+	}
+	for _, val := range logs {
+		res.Logs = append(res.Logs, logResponse{
+			ID:           val.ID,
+			Name:         val.Name.String,
+			IdentityCode: val.IdentityCode.Int64,
+			Action:       val.Action,
+			Table:        val.TargetTable,
+			TargetID:     val.TargetID,
+			Diff:         json.RawMessage(val.Diff),
+			IP:           val.IP,
+			CreatedAt:    val.CreatedAt,
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/melodiez14/meiko/src/module/audit"
+)
+
+type readParams struct {
+	Page         string
+	Total        string
+	IdentityCode string
+	Action       string
+	Table        string
+	TargetID     string
+	From         string
+	To           string
+}
+
+type readArgs struct {
+	Page   uint16
+	Total  uint16
+	Filter audit.Filter
+}
+
+type readResponse struct {
+	Total int           `json:"total"`
+	Logs  []logResponse `json:"logs"`
+}
+
+type logResponse struct {
+	ID           int64           `json:"id"`
+	Name         string          `json:"name"`
+	IdentityCode int64           `json:"identity_code"`
+	Action       string          `json:"action"`
+	Table        string          `json:"table"`
+	TargetID     string          `json:"target_id"`
+	Diff         json.RawMessage `json:"diff"`
+	IP           string          `json:"ip"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
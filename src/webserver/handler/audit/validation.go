@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/melodiez14/meiko/src/module/audit"
+	"github.com/melodiez14/meiko/src/util/helper"
+)
+
+func (params readParams) validate() (readArgs, error) {
+
+	var args readArgs
+	page, err := strconv.ParseUint(helper.Trim(params.Page), 10, 16)
+	if err != nil || page < 1 {
+		return args, fmt.Errorf("Error validation: pg must be positive numeric")
+	}
+
+	total, err := strconv.ParseUint(helper.Trim(params.Total), 10, 16)
+	if err != nil || total < 1 {
+		return args, fmt.Errorf("Error validation: ttl must be positive numeric")
+	}
+
+	var filter audit.Filter
+
+	// identity code of the actor
+	params.IdentityCode = helper.Trim(params.IdentityCode)
+	if !helper.IsEmpty(params.IdentityCode) {
+		filter.IdentityCode, err = strconv.ParseInt(params.IdentityCode, 10, 64)
+		if err != nil || filter.IdentityCode < 1 {
+			return args, fmt.Errorf("Error validation: actor must be positive numeric")
+		}
+	}
+
+	params.Action = helper.Trim(params.Action)
+	if !helper.IsEmpty(params.Action) {
+		switch params.Action {
+		case audit.ActionCreate, audit.ActionUpdate, audit.ActionDelete:
+			filter.Action = params.Action
+		default:
+			return args, fmt.Errorf("Error validation: action must be create, update or delete")
+		}
+	}
+
+	params.Table = helper.Trim(params.Table)
+	if !helper.IsEmpty(params.Table) {
+		switch params.Table {
+		case audit.TableUsers, audit.TableSchedules, audit.TableAssignments, audit.TableGradeParameters,
+			audit.TableRoleGroups, audit.TableRoleGroupModules, audit.TableUsersSchedules, audit.TableCapacities:
+			filter.TargetTable = params.Table
+		default:
+			return args, fmt.Errorf("Error validation: unknown table")
+		}
+	}
+
+	params.TargetID = helper.Trim(params.TargetID)
+	if !helper.IsEmpty(params.TargetID) {
+		if helper.IsEmpty(filter.TargetTable) {
+			return args, fmt.Errorf("Error validation: target_id must be used with table")
+		}
+		if len(params.TargetID) > 45 {
+			return args, fmt.Errorf("Error validation: target_id is too long")
+		}
+		filter.TargetID = params.TargetID
+	}
+
+	// the dates are inclusive, so the to date is moved to the next day
+	params.From = helper.Trim(params.From)
+	if !helper.IsEmpty(params.From) {
+		filter.From, err = time.Parse("2006-01-02", params.From)
+		if err != nil {
+			return args, fmt.Errorf("Error validation: from must be formatted as YYYY-MM-DD")
+		}
+	}
+
+	params.To = helper.Trim(params.To)
+	if !helper.IsEmpty(params.To) {
+		filter.To, err = time.Parse("2006-01-02", params.To)
+		if err != nil {
+			return args, fmt.Errorf("Error validation: to must be formatted as YYYY-MM-DD")
+		}
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return args, fmt.Errorf("Error validation: from must be before to")
+	}
+
+	args = readArgs{
+		Page:   uint16(page),
+		Total:  uint16(total),
+		Filter: filter,
+	}
+	return args, nil
+}
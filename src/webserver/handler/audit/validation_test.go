@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/melodiez14/meiko/src/module/audit"
+)
+
+func Test_readParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  readParams
+		want    readArgs
+		wantErr bool
+	}{
+		{
+			name:    "Test Case 1",
+			params:  readParams{Page: "0", Total: "10"},
+			want:    readArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 2",
+			params:  readParams{Page: "1", Total: "10", Action: "drop"},
+			want:    readArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 3",
+			params:  readParams{Page: "1", Total: "10", TargetID: "27"},
+			want:    readArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 4",
+			params:  readParams{Page: "1", Total: "10", From: "01-10-2017"},
+			want:    readArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 5",
+			params:  readParams{Page: "1", Total: "10", From: "2017-10-02", To: "2017-10-01"},
+			want:    readArgs{},
+			wantErr: true,
+		},
+		{
+			name:   "Test Case 6",
+			params: readParams{Page: "2", Total: "10"},
+			want: readArgs{
+				Page:  2,
+				Total: 10,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test Case 7",
+			params: readParams{
+				Page:         "1",
+				Total:        "20",
+				IdentityCode: "140810140016",
+				Action:       "delete",
+				Table:        "users",
+				TargetID:     " 27 ",
+				From:         "2017-10-01",
+				To:           "2017-10-01",
+			},
+			want: readArgs{
+				Page:  1,
+				Total: 20,
+				Filter: audit.Filter{
+					IdentityCode: 140810140016,
+					Action:       audit.ActionDelete,
+					TargetTable:  audit.TableUsers,
+					TargetID:     "27",
+					From:         time.Date(2017, 10, 1, 0, 0, 0, 0, time.UTC),
+					To:           time.Date(2017, 10, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "Test Case 8",
+			params: readParams{Page: "1", Total: "10", Table: "rolegroups_modules", TargetID: "2"},
+			want: readArgs{
+				Page:  1,
+				Total: 10,
+				Filter: audit.Filter{
+					TargetTable: audit.TableRoleGroupModules,
+					TargetID:    "2",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Test Case 9",
+			params:  readParams{Page: "1", Total: "10", Table: "sessions"},
+			want:    readArgs{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
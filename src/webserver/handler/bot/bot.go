@@ -28,44 +28,86 @@ func BotHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		return
 	}
 
-	// get text intent
-	intent, err := getIntent(args.NormalizedText)
+	respData, err := answer(args, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusOK).
+			SetMessage(err.Error()))
+		return
+	}
+
+	// prepare for response
+	resp := messageResponse{
+		Status:    bot.StatusBot,
+		Text:      args.Text,
+		TimeStamp: time.Now().Unix(),
+		Response:  respData,
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(resp))
+	return
+}
+
+// answer runs the intent pipeline of the message, saves the dialogue context and logs the conversation.
+// It's shared by every bot channel, the returned error is the message shown to the user
+/*
+	@params:
+		args	= messageArgs
+		userID	= int64
+	@example:
+		args	= {text: Siapa asisten Data Warehouse?, normalized_text: siapa asisten data warehouse?}
+		userID	= 1
+	@return
+		{intent, entity, context}
+*/
+func answer(args messageArgs, userID int64) (map[string]interface{}, error) {
+
+	// the expired or broken context is treated as the new conversation
+	prev, err := bot.GetContext(userID)
+	if err != nil {
+		prev = bot.Context{}
+	}
+
+	// get text intent and entity
+	ctx, err := resolveContext(args.NormalizedText, prev)
+	if err != nil {
+		return nil, err
+	}
 
 	// convert intent into assistant
 	var data interface{}
-	switch intent {
+	switch ctx.Intent {
 	case intentAssistant:
-		data, err = handleAssistant(args.NormalizedText, sess.ID)
+		data, err = handleAssistant(ctx, userID)
 	case intentGrade:
-		break
+		data, err = handleGrade(ctx, userID)
 	case intentAssignment:
-		break
+		data, err = handleAssignment(ctx, userID)
 	case intentInformation:
-		break
+		data, err = handleInformation(ctx, userID)
 	case intentSchedule:
-		break
+		data, err = handleSchedule(ctx, userID)
+	case intentClarification:
+		data = clarify(ctx.Candidates)
 	default:
 		break
 	}
 	if err != nil {
-		template.RenderJSONResponse(w, new(template.Response).
-			SetCode(http.StatusOK).
-			SetMessage(err.Error()))
-		return
+		return nil, err
 	}
 
-	// intent and entity
-	respData := map[string]interface{}{
-		"intent": intent,
-		"entity": data,
+	// the unknown intent keeps the previous context, so the user can rephrase the question
+	if ctx.Intent != intentUnknown {
+		bot.SaveContext(userID, ctx, cfg.ContextExpire)
 	}
 
-	// prepare for response
-	resp := messageResponse{
-		Status:    bot.StatusBot,
-		Text:      args.Text,
-		TimeStamp: time.Now().Unix(),
-		Response:  respData,
+	// intent, entity and the resolved context
+	respData := map[string]interface{}{
+		"intent":  ctx.Intent,
+		"entity":  data,
+		"context": ctx,
 	}
 
 	// log message into database
@@ -79,22 +121,21 @@ func BotHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 			return
 		}
 
-		err = log.Insert(args.Text, sess.ID, bot.StatusUser, tx)
+		err = log.Insert(args.Text, userID, bot.StatusUser, tx)
 		if err != nil {
+			tx.Rollback()
 			return
 		}
 
-		err = log.Insert(jsnStr, sess.ID, bot.StatusBot, tx)
+		err = log.Insert(jsnStr, userID, bot.StatusBot, tx)
 		if err != nil {
+			tx.Rollback()
 			return
 		}
 		tx.Commit()
 	}()
 
-	template.RenderJSONResponse(w, new(template.Response).
-		SetCode(http.StatusOK).
-		SetData(resp))
-	return
+	return respData, nil
 }
 
 func LoadHistoryHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
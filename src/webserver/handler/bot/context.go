@@ -0,0 +1,155 @@
+package bot
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/melodiez14/meiko/src/module/bot"
+	"github.com/melodiez14/meiko/src/util/classifier"
+)
+
+// resolveContext detects the intent and the entities of the text. The previous context is used when
+//  - the text answers the clarifying question, e.g. "jadwal" after "Do you mean the assistant or the schedule?"
+//  - the text is the follow-up question without intent, e.g. "kalau hari rabu?" after "siapa asisten data warehouse?"
+// The entities which aren't mentioned are taken from the previous context if the intent is the same
+/*
+	@params:
+		text	= string
+		prev	= bot.Context
+	@example:
+		text	= kalau hari rabu?
+		prev	= {intent: assistant, courses: [data warehouse]}
+	@return
+		bot.Context = {intent: assistant, courses: [data warehouse], days: [3]}
+*/
+func resolveContext(text string, prev bot.Context) (bot.Context, error) {
+
+	params := sEntity{text: text}
+	ctx := bot.Context{}
+	ctx.Days = params.getDay()
+
+	dates, err := params.getTime()
+	if err != nil {
+		return ctx, err
+	}
+	ctx.Dates = dates
+	ctx.Courses = params.getCourse()
+
+	// the answer of the clarifying question uses the entities of the asked question
+	if len(prev.Candidates) > 0 {
+		if intent := chooseCandidate(text, prev.Candidates); intent != intentUnknown {
+			ctx.Intent = intent
+			return inheritEntity(ctx, prev), nil
+		}
+	}
+
+	intent, candidates := getIntent(text)
+	hasEntity := len(ctx.Days) > 0 || len(ctx.Dates) > 0 || len(ctx.Courses) > 0
+
+	switch {
+	case isResolved(prev) && regexp.MustCompile(rgxFollowUp).MatchString(text):
+		ctx.Intent = prev.Intent
+	case intent != intentUnknown:
+		ctx.Intent = intent
+	case len(candidates) > 1:
+		ctx.Intent = intentClarification
+		ctx.Candidates = candidates
+		return ctx, nil
+	case isResolved(prev) && hasEntity:
+		ctx.Intent = prev.Intent
+	default:
+		ctx.Intent = intentUnknown
+		return ctx, nil
+	}
+
+	if ctx.Intent == prev.Intent {
+		ctx = inheritEntity(ctx, prev)
+	}
+
+	return ctx, nil
+}
+
+// chooseCandidate returns the candidate chosen by the answer of the clarifying question.
+// The answer can be the position of the candidate, the keyword or the question of the candidate intent
+func chooseCandidate(text string, candidates []string) string {
+
+	for _, val := range classifier.Tokenize(text) {
+		if i, ok := ordinalAnswer[val]; ok && i < len(candidates) {
+			return candidates[i]
+		}
+	}
+
+	var matched []string
+	for _, val := range (keywordClassifier{}).Classify(text) {
+		if isCandidate(val.Intent, candidates) {
+			matched = append(matched, val.Intent)
+		}
+	}
+	if len(matched) == 1 {
+		return matched[0]
+	}
+
+	intent, _ := getIntent(text)
+	if isCandidate(intent, candidates) {
+		return intent
+	}
+
+	return intentUnknown
+}
+
+// clarify returns the clarifying question of the ambiguous intent
+func clarify(candidates []string) clarificationResponse {
+
+	res := clarificationResponse{
+		Options: []clarificationOption{},
+	}
+
+	var labels []string
+	for _, val := range candidates {
+		labels = append(labels, intentLabel[val])
+		res.Options = append(res.Options, clarificationOption{
+			Intent: val,
+			Text:   intentLabel[val],
+		})
+	}
+
+	question := labels[0]
+	if len(labels) > 1 {
+		question = fmt.Sprintf("%s or %s", strings.Join(labels[:len(labels)-1], ", "), labels[len(labels)-1])
+	}
+	res.Question = fmt.Sprintf("Do you mean %s?", question)
+
+	return res
+}
+
+// inheritEntity fills the entities which aren't mentioned using the previous context.
+// The day and the date are the same filter, so both of them are inherited together
+func inheritEntity(ctx, prev bot.Context) bot.Context {
+	if len(ctx.Courses) < 1 {
+		ctx.Courses = prev.Courses
+	}
+	if len(ctx.Days) < 1 && len(ctx.Dates) < 1 {
+		ctx.Days = prev.Days
+		ctx.Dates = prev.Dates
+	}
+	return ctx
+}
+
+// isResolved checks whether the context has the answerable intent
+func isResolved(ctx bot.Context) bool {
+	switch ctx.Intent {
+	case intentAssistant, intentSchedule, intentInformation, intentGrade, intentAssignment:
+		return true
+	}
+	return false
+}
+
+func isCandidate(intent string, candidates []string) bool {
+	for _, val := range candidates {
+		if val == intent {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package bot
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/melodiez14/meiko/src/module/bot"
+)
+
+func TestResolveContext(t *testing.T) {
+	rgxCourse = "(data warehouse)|(basis data)"
+	intentClassifier = keywordClassifier{}
+	cfg = Config{Threshold: 0.6}
+
+	type args struct {
+		text string
+		prev bot.Context
+	}
+	tests := []struct {
+		name string
+		args args
+		want bot.Context
+	}{
+		{
+			name: "Test Case 1",
+			args: args{
+				text: "siapa asisten data warehouse?",
+			},
+			want: bot.Context{
+				Intent:  intentAssistant,
+				Courses: []string{"data warehouse"},
+			},
+		},
+		{
+			name: "Test Case 2",
+			args: args{
+				text: "kalau hari rabu?",
+				prev: bot.Context{
+					Intent:  intentAssistant,
+					Courses: []string{"data warehouse"},
+				},
+			},
+			want: bot.Context{
+				Intent:  intentAssistant,
+				Courses: []string{"data warehouse"},
+				Days:    []int8{int8(time.Wednesday)},
+			},
+		},
+		{
+			name: "Test Case 3",
+			args: args{
+				text: "basis data senin",
+				prev: bot.Context{
+					Intent:  intentSchedule,
+					Courses: []string{"data warehouse"},
+					Days:    []int8{int8(time.Wednesday)},
+				},
+			},
+			want: bot.Context{
+				Intent:  intentSchedule,
+				Courses: []string{"basis data"},
+				Days:    []int8{int8(time.Monday)},
+			},
+		},
+		{
+			name: "Test Case 4",
+			args: args{
+				text: "nilai saya berapa",
+				prev: bot.Context{
+					Intent:  intentAssistant,
+					Courses: []string{"data warehouse"},
+				},
+			},
+			want: bot.Context{
+				Intent: intentGrade,
+			},
+		},
+		{
+			name: "Test Case 5",
+			args: args{
+				text: "nilai tugas data warehouse",
+			},
+			want: bot.Context{
+				Intent:     intentClarification,
+				Courses:    []string{"data warehouse"},
+				Candidates: []string{intentAssignment, intentGrade},
+			},
+		},
+		{
+			name: "Test Case 6",
+			args: args{
+				text: "yang kedua",
+				prev: bot.Context{
+					Intent:     intentClarification,
+					Courses:    []string{"data warehouse"},
+					Candidates: []string{intentAssignment, intentGrade},
+				},
+			},
+			want: bot.Context{
+				Intent:  intentGrade,
+				Courses: []string{"data warehouse"},
+			},
+		},
+		{
+			name: "Test Case 7",
+			args: args{
+				text: "tugas",
+				prev: bot.Context{
+					Intent:     intentClarification,
+					Courses:    []string{"data warehouse"},
+					Candidates: []string{intentAssignment, intentGrade},
+				},
+			},
+			want: bot.Context{
+				Intent:  intentAssignment,
+				Courses: []string{"data warehouse"},
+			},
+		},
+		{
+			name: "Test Case 8",
+			args: args{
+				text: "halo",
+				prev: bot.Context{
+					Intent: intentAssistant,
+				},
+			},
+			want: bot.Context{
+				Intent: intentUnknown,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveContext(tt.args.text, tt.args.prev)
+			if err != nil {
+				t.Fatalf("resolveContext() error = %v", err)
+			}
+			if got.Intent != tt.want.Intent ||
+				len(got.Courses)+len(tt.want.Courses) > 0 && !reflect.DeepEqual(got.Courses, tt.want.Courses) ||
+				len(got.Days)+len(tt.want.Days) > 0 && !reflect.DeepEqual(got.Days, tt.want.Days) ||
+				len(got.Candidates)+len(tt.want.Candidates) > 0 && !reflect.DeepEqual(got.Candidates, tt.want.Candidates) {
+				t.Errorf("resolveContext() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClarify(t *testing.T) {
+	got := clarify([]string{intentAssignment, intentGrade})
+	if got.Question != "Do you mean the assignment or the grade?" {
+		t.Errorf("clarify() question = %s", got.Question)
+	}
+	if len(got.Options) != 2 || got.Options[1].Intent != intentGrade {
+		t.Errorf("clarify() options = %v", got.Options)
+	}
+}
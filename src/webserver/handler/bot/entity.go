@@ -47,34 +47,30 @@ func (params *sEntity) getTime() ([]time.Time, error) {
 		}
 	}
 
-	// word expression
-	patternWord := map[string]time.Time{
-		`kemaren\s*lusa`:  now.AddDate(0, 0, -1),
-		`pekan\s*kemarin`: now.AddDate(0, 0, -7),
-		`kemarin`:         now.AddDate(0, 0, -2),
-		`kemaren`:         now.AddDate(0, 0, -2),
-		`hari\s*ini`:      now.AddDate(0, 0, -1),
-	}
-
-	for i, val := range patternWord {
-		rgx := regexp.MustCompile(i)
-		if rgx.MatchString(params.text) {
-			// get the string
-			str := rgx.FindString(params.text)
-			// append the selected date
-			date = append(date, val)
-			// replace the selected string from with (date)
-			params.text = strings.Replace(params.text, str, "(date)", -1)
-		}
-	}
-
-	for i, val := range patternWord {
-		rgx := regexp.MustCompile(i)
+	// word expression, the longer expression must be placed before the shorter one
+	// so "kemarin lusa" isn't detected as "kemarin"
+	patternWord := []struct {
+		pattern string
+		date    time.Time
+	}{
+		{`kemarin\s*lusa`, now.AddDate(0, 0, -2)},
+		{`kemaren\s*lusa`, now.AddDate(0, 0, -2)},
+		{`pekan\s*kemarin`, now.AddDate(0, 0, -7)},
+		{`kemarin`, now.AddDate(0, 0, -1)},
+		{`kemaren`, now.AddDate(0, 0, -1)},
+		{`hari\s*ini`, now},
+		{`sekarang`, now},
+		{`besok`, now.AddDate(0, 0, 1)},
+		{`lusa`, now.AddDate(0, 0, 2)},
+	}
+
+	for _, val := range patternWord {
+		rgx := regexp.MustCompile(val.pattern)
 		if rgx.MatchString(params.text) {
 			// get the string
 			str := rgx.FindString(params.text)
 			// append the selected date
-			date = append(date, val)
+			date = append(date, val.date)
 			// replace the selected string from with (date)
 			params.text = strings.Replace(params.text, str, "(date)", -1)
 		}
@@ -166,7 +162,12 @@ func (params *sEntity) getDay() []int8 {
 }
 
 func (params *sEntity) getAssistant() []string {
-	rgx := regexp.MustCompile(rgxAssistant)
+	rgxStr := getRgxAssistant()
+	// the empty regex matches everywhere when there is no assistant
+	if rgxStr == "" {
+		return nil
+	}
+	rgx := regexp.MustCompile(rgxStr)
 	str := rgx.FindAllString(params.text, -1)
 	for _, val := range str {
 		params.text = strings.Replace(params.text, val, ("(assistant)"), -1)
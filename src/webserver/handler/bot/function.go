@@ -3,44 +3,35 @@ package bot
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	as "github.com/melodiez14/meiko/src/module/assignment"
+	"github.com/melodiez14/meiko/src/module/bot"
 	cs "github.com/melodiez14/meiko/src/module/course"
+	gd "github.com/melodiez14/meiko/src/module/grade"
+	inf "github.com/melodiez14/meiko/src/module/information"
 	usr "github.com/melodiez14/meiko/src/module/user"
 	"github.com/melodiez14/meiko/src/util/helper"
 )
 
-func handleAssistant(text string, userID int64) ([]map[string]interface{}, error) {
+func handleAssistant(ctx bot.Context, userID int64) ([]map[string]interface{}, error) {
 
 	var args []map[string]interface{}
 	var filterDays []int8
 	var filterDaysLen int
-	var filterDates []time.Time
 	var filterCourses []string
 	var filterCoursesLen int
 	var filterCoursesRgx *regexp.Regexp
 
-	params := sEntity{
-		text:   text,
-		userID: userID,
-	}
-
-	// get day entity
-	filterDays = params.getDay()
-
-	// get date entity
-	filterDates, err := params.getTime()
-	if err != nil {
-		return nil, err
-	}
 	// change time into days
-	filterDays = append(filterDays, helper.TimeToDayInt(filterDates...)...)
+	filterDays = append(filterDays, ctx.Days...)
+	filterDays = append(filterDays, helper.TimeToDayInt(ctx.Dates...)...)
 	filterDaysLen = len(filterDays)
-	fmt.Println(filterDates)
 
 	// get course entity
-	filterCourses = params.getCourse()
+	filterCourses = ctx.Courses
 	filterCoursesLen = len(filterCourses)
 	filterCoursesRgx = regexp.MustCompile(strings.Join(filterCourses, "|"))
 
@@ -101,3 +92,232 @@ func handleAssistant(text string, userID int64) ([]map[string]interface{}, error
 
 	return args, nil
 }
+
+func handleSchedule(ctx bot.Context, userID int64) ([]map[string]interface{}, error) {
+
+	args := []map[string]interface{}{}
+
+	var filterDays []int8
+	filterDays = append(filterDays, ctx.Days...)
+	filterDays = append(filterDays, helper.TimeToDayInt(ctx.Dates...)...)
+
+	// today schedule is used if the day isn't mentioned
+	if len(filterDays) < 1 {
+		filterDays = helper.TimeToDayInt(time.Now())
+	}
+
+	// the assistant has the schedule too
+	courses, err := selectCourse(userID, ctx.Courses, cs.PStatusStudent, cs.PStatusAssistant)
+	if err != nil {
+		return nil, fmt.Errorf("Sorry, I can't get your schedule right now")
+	}
+
+	sort.Slice(courses, func(i, j int) bool {
+		if courses[i].Schedule.Day != courses[j].Schedule.Day {
+			return courses[i].Schedule.Day < courses[j].Schedule.Day
+		}
+		return courses[i].Schedule.StartTime < courses[j].Schedule.StartTime
+	})
+
+	for _, val := range courses {
+		if !helper.Int8InSlice(val.Schedule.Day, filterDays) {
+			continue
+		}
+
+		args = append(args, map[string]interface{}{
+			"course_name": val.Course.Name,
+			"class":       val.Schedule.Class,
+			"day":         helper.IntDayToString(val.Schedule.Day),
+			"start_time":  helper.MinutesToTimeString(val.Schedule.StartTime),
+			"end_time":    helper.MinutesToTimeString(val.Schedule.EndTime),
+			"place":       val.Schedule.PlaceID,
+		})
+	}
+
+	return args, nil
+}
+
+func handleAssignment(ctx bot.Context, userID int64) ([]map[string]interface{}, error) {
+
+	args := []map[string]interface{}{}
+
+	// the day and date entity filter the due date
+	filterDays := ctx.Days
+	filterDates := ctx.Dates
+
+	courses, err := selectCourse(userID, ctx.Courses, cs.PStatusStudent)
+	if err != nil {
+		return nil, fmt.Errorf("Sorry, I can't get your assignments right now")
+	}
+
+	courseName := map[int64]string{}
+	for _, val := range courses {
+		courseName[val.Schedule.ID] = val.Course.Name
+	}
+
+	assignments, err := as.GetIncompleteByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("Sorry, I can't get your assignments right now")
+	}
+
+	now := time.Now()
+	for _, val := range assignments {
+
+		// the assignment of the filtered course
+		name, ok := courseName[val.ScheduleID]
+		if !ok {
+			continue
+		}
+
+		if len(filterDays) > 0 && !helper.Int8InSlice(int8(val.DueDate.Weekday()), filterDays) {
+			continue
+		}
+
+		if len(filterDates) > 0 && !isInDateRange(val.DueDate, filterDates) {
+			continue
+		}
+
+		args = append(args, map[string]interface{}{
+			"course_name": name,
+			"name":        val.Name,
+			"description": val.Description.String,
+			"due_date":    val.DueDate.Format("2006-01-02 15:04"),
+			"is_overdue":  val.DueDate.Before(now),
+		})
+	}
+
+	return args, nil
+}
+
+func handleInformation(ctx bot.Context, userID int64) ([]map[string]interface{}, error) {
+
+	args := []map[string]interface{}{}
+
+	// the date entity filters the created date
+	filterDates := ctx.Dates
+	filterCourses := ctx.Courses
+
+	courses, err := selectCourse(userID, filterCourses, cs.PStatusStudent, cs.PStatusAssistant)
+	if err != nil {
+		return nil, fmt.Errorf("Sorry, I can't get the information right now")
+	}
+
+	var scheduleID []int64
+	courseName := map[int64]string{}
+	for _, val := range courses {
+		scheduleID = append(scheduleID, val.Schedule.ID)
+		courseName[val.Schedule.ID] = val.Course.Name
+	}
+
+	informations, err := inf.SelectByScheduleID(scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("Sorry, I can't get the information right now")
+	}
+
+	now := time.Now()
+	for _, val := range informations {
+		if len(args) >= maximumInformation {
+			break
+		}
+
+		// the general information isn't related to any course
+		if !val.ScheduleID.Valid && len(filterCourses) > 0 {
+			continue
+		}
+
+		if len(filterDates) > 0 && !isInDateRange(val.CreatedAt, filterDates) {
+			continue
+		}
+
+		args = append(args, map[string]interface{}{
+			"course_name": courseName[val.ScheduleID.Int64],
+			"title":       val.Title,
+			"description": val.Description.String,
+			"date":        helper.DateToString(val.CreatedAt, now),
+		})
+	}
+
+	return args, nil
+}
+
+func handleGrade(ctx bot.Context, userID int64) ([]map[string]interface{}, error) {
+
+	args := []map[string]interface{}{}
+
+	courses, err := selectCourse(userID, ctx.Courses, cs.PStatusStudent)
+	if err != nil {
+		return nil, fmt.Errorf("Sorry, I can't get your grade right now")
+	}
+
+	var scheduleID []int64
+	for _, val := range courses {
+		scheduleID = append(scheduleID, val.Schedule.ID)
+	}
+
+	grades, err := gd.SelectByUserID(userID, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("Sorry, I can't get your grade right now")
+	}
+
+	score := map[int64]float32{}
+	for _, val := range grades {
+		score[val.ScheduleID] = val.Score
+	}
+
+	for _, val := range courses {
+		s := score[val.Schedule.ID]
+		args = append(args, map[string]interface{}{
+			"course_name": val.Course.Name,
+			"class":       val.Schedule.Class,
+			"score":       s,
+			"grade":       gd.Letter(s),
+		})
+	}
+
+	return args, nil
+}
+
+// selectCourse returns the active courses enrolled by the user with the given status.
+// The courses are filtered by the course entity if it's detected
+func selectCourse(userID int64, filterCourses []string, status ...int8) ([]cs.CourseSchedule, error) {
+
+	var scheduleID []int64
+	for _, val := range status {
+		id, err := cs.SelectScheduleIDByUserID(userID, val)
+		if err != nil {
+			return nil, err
+		}
+		scheduleID = append(scheduleID, id...)
+	}
+
+	courses, err := cs.SelectByScheduleID(scheduleID, cs.StatusScheduleActive)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filterCourses) < 1 {
+		return courses, nil
+	}
+
+	var filtered []cs.CourseSchedule
+	rgx := regexp.MustCompile(strings.Join(filterCourses, "|"))
+	for _, val := range courses {
+		if rgx.MatchString(strings.ToLower(val.Course.Name)) {
+			filtered = append(filtered, val)
+		}
+	}
+
+	return filtered, nil
+}
+
+// isInDateRange checks whether t is in the same day of the date entity.
+// Two dates entity is used as the range of the days
+func isInDateRange(t time.Time, dates []time.Time) bool {
+	y, m, d := dates[0].Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+
+	y, m, d = dates[len(dates)-1].Date()
+	end := time.Date(y, m, d, 0, 0, 0, 0, t.Location()).AddDate(0, 0, 1)
+
+	return !t.Before(start) && t.Before(end)
+}
@@ -3,50 +3,107 @@ package bot
 import (
 	"fmt"
 	"log"
+	"regexp"
 	"strings"
 
 	cs "github.com/melodiez14/meiko/src/module/course"
 	usr "github.com/melodiez14/meiko/src/module/user"
+	"github.com/melodiez14/meiko/src/util/classifier"
 	"github.com/melodiez14/meiko/src/util/helper"
+	"github.com/melodiez14/meiko/src/util/line"
 )
 
 // Init used to initialize the bot
-func Init() {
+func Init(config Config) {
 	log.Println("Initializing Bot")
+	cfg = config
+	if cfg.ContextExpire <= 0 {
+		cfg.ContextExpire = defaultContextExpire
+	}
+	lineClient = line.NewClient(cfg.Line)
 	initRgxAsistant()
 	initRgxCourse()
+	initClassifier()
 	log.Println("Bot successfully initialized")
 }
 
-// initRgxAssistant gets assistant lists from database and put it into rgxassistant
+// initClassifier trains the naive Bayes classifier using the corpus. The keyword classifier is used
+// if the corpus can't be loaded, so the bot is still able to answer the common questions
+func initClassifier() {
+	corpus, err := classifier.LoadCorpus(cfg.Corpus)
+	if err != nil {
+		log.Printf("Bot init warning: %s, using keyword classifier", err.Error())
+		intentClassifier = keywordClassifier{}
+		return
+	}
+
+	nb := classifier.NewNaiveBayes()
+	nb.Train(corpus.TrainSamples())
+	intentClassifier = nb
+	log.Printf("Bot intent classifier trained using corpus version %s", corpus.Version)
+}
+
+// initRgxAsistant gets assistant lists from database and put it into rgxassistant
 func initRgxAsistant() {
+	rgx, err := loadRgxAssistant()
+	if err != nil {
+		log.Fatalf("Bot init error: %s", err.Error())
+	}
+	setRgxAssistant(rgx)
+}
+
+// RefreshAssistant rebuilds the assistant regex, it's called after the assistants of the schedules are changed.
+// The previous regex is kept if the assistants can't be loaded
+func RefreshAssistant() {
+	rgx, err := loadRgxAssistant()
+	if err != nil {
+		log.Printf("Bot refresh error: %s", err.Error())
+		return
+	}
+	setRgxAssistant(rgx)
+}
+
+// loadRgxAssistant builds the regex of the names of the assistants
+func loadRgxAssistant() (string, error) {
 	var name []string
 	var nameGroup []string
 	userID, err := cs.SelectAllAssistantID()
 	if err != nil {
-		log.Fatalf("Bot init error: cannot get all assistant id")
+		return "", fmt.Errorf("cannot get all assistant id")
 	}
 
 	user, err := usr.SelectByID(userID, usr.ColName)
 	if err != nil {
-		log.Fatalf("Bot init error: cannot get all assistant name")
+		return "", fmt.Errorf("cannot get all assistant name")
 	}
 
 	// make []string{"Risal Falah", "Rifki Muhammad"} into []string{"risal", "falah", "rifki", "muhammad"}
 	for _, val := range user {
 		str := strings.ToLower(val.Name)
-		name = append(name, strings.Split(str, " ")...)
+		name = append(name, strings.Fields(str)...)
 	}
 
 	// make []string{"risal", "falah"} into []string{"(risal)", "(falah)"} for regex purpose
 	for _, val := range name {
-		str := fmt.Sprintf("(%s)", val)
+		str := fmt.Sprintf("(%s)", regexp.QuoteMeta(val))
 		if !helper.IsStringInSlice(str, nameGroup) {
 			nameGroup = append(nameGroup, str)
 		}
 	}
 
-	rgxAssistant = strings.Join(nameGroup, "|")
+	return strings.Join(nameGroup, "|"), nil
+}
+
+func setRgxAssistant(rgx string) {
+	rgxAssistantMutex.Lock()
+	rgxAssistant = rgx
+	rgxAssistantMutex.Unlock()
+}
+
+func getRgxAssistant() string {
+	rgxAssistantMutex.RLock()
+	defer rgxAssistantMutex.RUnlock()
+	return rgxAssistant
 }
 
 // initRgxCourse gets course lists from database and put it into rgxcourse
@@ -1,44 +1,85 @@
 package bot
 
-import "regexp"
-import "fmt"
+import (
+	"regexp"
+
+	"github.com/melodiez14/meiko/src/util/classifier"
+)
+
+// getIntent returns the intent with the highest confidence. If the confidence is lower than the configured
+// threshold, the top intents whose total confidence reaches the threshold are returned as the candidates
+// so the user can be asked to choose one of them
+/*
+	@params:
+		text		= string
+	@example:
+		text		= nilai tugas data warehouse
+	@return
+		intent		= unknown
+		candidates	= [grade, assignment]
+*/
+func getIntent(text string) (string, []string) {
+
+	predictions := intentClassifier.Classify(text)
+	if len(predictions) < 1 {
+		return intentUnknown, nil
+	}
+
+	if predictions[0].Confidence >= cfg.Threshold {
+		return predictions[0].Intent, nil
+	}
+
+	var sum float64
+	var candidates []string
+	for _, val := range predictions {
+		if len(candidates) >= maximumCandidate {
+			break
+		}
+		candidates = append(candidates, val.Intent)
+		sum += val.Confidence
+		if sum >= cfg.Threshold {
+			return intentUnknown, candidates
+		}
+	}
 
-func getIntent(text string) (string, error) {
-	var intents []string
+	return intentUnknown, nil
+}
+
+// keywordClassifier detects the intent using the keyword patterns. It's used when the corpus
+// can't be loaded, every matched intent has the same confidence
+type keywordClassifier struct{}
+
+// Train does nothing since the patterns are hardcoded
+func (keywordClassifier) Train(samples []classifier.Sample) {}
 
-	// get assistant intent
+// Classify returns the intents which have the matched keyword
+func (keywordClassifier) Classify(text string) []classifier.Prediction {
+
+	var intents []string
 	if sAssistant(text).isValidIntent() {
 		intents = append(intents, intentAssistant)
 	}
-
-	// get assignment intent
 	if sAssignment(text).isValidIntent() {
 		intents = append(intents, intentAssignment)
 	}
-
-	// get information intent
 	if sInformation(text).isValidIntent() {
 		intents = append(intents, intentInformation)
 	}
-
-	// get grade intent
 	if sGrade(text).isValidIntent() {
 		intents = append(intents, intentGrade)
 	}
-
-	// get schedule intent
 	if sSchedule(text).isValidIntent() {
 		intents = append(intents, intentSchedule)
 	}
 
-	switch len(intents) {
-	case 0:
-		return intentUnknown, fmt.Errorf("Cannot get intent")
-	case 1:
-		return intents[0], nil
-	default:
-		return intentUnknown, fmt.Errorf("Detected more than 1 intents")
+	predictions := []classifier.Prediction{}
+	for _, val := range intents {
+		predictions = append(predictions, classifier.Prediction{
+			Intent:     val,
+			Confidence: 1 / float64(len(intents)),
+		})
 	}
+	return predictions
 }
 
 // validate assistant intent
@@ -0,0 +1,191 @@
+package bot
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/melodiez14/meiko/src/module/bot"
+	usr "github.com/melodiez14/meiko/src/module/user"
+	"github.com/melodiez14/meiko/src/util/line"
+	"github.com/melodiez14/meiko/src/webserver/template"
+)
+
+// LineWebhookHandler handles the webhook of the LINE Messaging API. The LINE user is mapped into the Meiko user
+// using the line id set in the user profile, then the text message is answered using the same pipeline of BotHandler
+/*
+	@params:
+		X-Line-Signature	= required, base64 HMAC-SHA256 of the body using the channel secret
+		body				= required, LINE webhook json
+	@example:
+		body				= {"events": [{"type": "message", "replyToken": "...", "source": {"type": "user", "userId": "U4af4980629"}, "message": {"type": "text", "text": "jadwal hari ini"}}]}
+	@return
+*/
+func LineWebhookHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maximumWebhookSize))
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid request body"))
+		return
+	}
+
+	if !line.VerifySignature(cfg.Line.ChannelSecret, body, r.Header.Get(line.HeaderSignature)) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Invalid signature"))
+		return
+	}
+
+	var webhook line.Webhook
+	err = json.Unmarshal(body, &webhook)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid request body"))
+		return
+	}
+
+	for _, event := range webhook.Events {
+		if event.Type != line.EventMessage || event.Source.Type != line.SourceUser {
+			continue
+		}
+
+		err = lineClient.Reply(event.ReplyToken, line.NewTextMessage(replyLine(event)))
+		if err != nil {
+			log.Printf("Bot LINE reply error: %s", err.Error())
+		}
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK))
+	return
+}
+
+// replyLine returns the answer of the LINE message event in the plain text
+func replyLine(event line.Event) string {
+
+	if event.Message.Type != line.MessageText {
+		return "Sorry, I can only read the text message"
+	}
+
+	u, err := usr.GetByLineID(event.Source.UserID, usr.ColID, usr.ColStatus)
+	if err == sql.ErrNoRows {
+		return fmt.Sprintf("Your LINE account isn't connected to Meiko yet. Please set the LINE ID in your Meiko profile to %s", event.Source.UserID)
+	}
+	if err != nil {
+		return "Sorry, I can't answer your message right now"
+	}
+
+	if u.Status != usr.StatusActivated {
+		return "Your Meiko account isn't activated yet"
+	}
+
+	params := messageParams{
+		Text: event.Message.Text,
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		return err.Error()
+	}
+
+	respData, err := answer(args, u.ID)
+	if err != nil {
+		return err.Error()
+	}
+
+	ctx, _ := respData["context"].(bot.Context)
+	return lineText(ctx, respData["entity"])
+}
+
+// lineText converts the answer of the intent into the plain text since LINE can't render the bot entity
+/*
+	@params:
+		ctx		= bot.Context
+		data	= interface{}
+	@example:
+		ctx		= {intent: grade}
+		data	= [{course_name: Data Warehouse, class: A, score: 85.5, grade: A}]
+	@return
+		Grades:
+		- Data Warehouse (A): 85.50 (A)
+*/
+func lineText(ctx bot.Context, data interface{}) string {
+
+	if ctx.Intent == intentClarification {
+		clarification, ok := data.(clarificationResponse)
+		if !ok {
+			return "Sorry, I don't understand your message"
+		}
+
+		text := []string{clarification.Question}
+		for i, val := range clarification.Options {
+			text = append(text, fmt.Sprintf("%d. %s", i+1, val.Text))
+		}
+		return strings.Join(text, "\n")
+	}
+
+	items, ok := data.([]map[string]interface{})
+	if !ok {
+		return "Sorry, I don't understand your message"
+	}
+
+	var title, empty string
+	switch ctx.Intent {
+	case intentAssistant:
+		title, empty = "Assistants:", "I can't find the assistant of your course"
+	case intentSchedule:
+		title, empty = "Schedules:", "You don't have any schedule"
+	case intentAssignment:
+		title, empty = "Assignments:", "You don't have any incomplete assignment"
+	case intentInformation:
+		title, empty = "Information:", "There isn't any information"
+	case intentGrade:
+		title, empty = "Grades:", "You don't have any grade yet"
+	default:
+		return "Sorry, I don't understand your message"
+	}
+
+	if len(items) < 1 {
+		return empty
+	}
+
+	text := []string{title}
+	for _, val := range items {
+		var item string
+		switch ctx.Intent {
+		case intentAssistant:
+			var names []string
+			assistants, _ := val["assistant"].([]map[string]string)
+			for _, v := range assistants {
+				names = append(names, v["name"])
+			}
+			item = fmt.Sprintf("%v: %s", val["course_name"], strings.Join(names, ", "))
+		case intentSchedule:
+			item = fmt.Sprintf("%v %v-%v %v (%v) at %v", val["day"], val["start_time"], val["end_time"], val["course_name"], val["class"], val["place"])
+		case intentAssignment:
+			item = fmt.Sprintf("%v: %v, due %v", val["course_name"], val["name"], val["due_date"])
+			if overdue, _ := val["is_overdue"].(bool); overdue {
+				item += " (overdue)"
+			}
+		case intentInformation:
+			item = fmt.Sprintf("%v (%v)", val["title"], val["date"])
+			if name, _ := val["course_name"].(string); name != "" {
+				item = fmt.Sprintf("%s: %s", name, item)
+			}
+		case intentGrade:
+			item = fmt.Sprintf("%v (%v): %.2f (%v)", val["course_name"], val["class"], val["score"], val["grade"])
+		}
+		text = append(text, "- "+item)
+	}
+
+	return strings.Join(text, "\n")
+}
@@ -0,0 +1,132 @@
+package bot
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/melodiez14/meiko/src/module/bot"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/melodiez14/meiko/src/util/line"
+)
+
+func TestLineText(t *testing.T) {
+	type args struct {
+		ctx  bot.Context
+		data interface{}
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Test Case 1",
+			args: args{
+				ctx: bot.Context{Intent: intentGrade},
+				data: []map[string]interface{}{
+					{"course_name": "Data Warehouse", "class": "A", "score": float32(85.5), "grade": "A"},
+				},
+			},
+			want: "Grades:\n- Data Warehouse (A): 85.50 (A)",
+		},
+		{
+			name: "Test Case 2",
+			args: args{
+				ctx: bot.Context{Intent: intentAssistant},
+				data: []map[string]interface{}{
+					{"course_name": "Data Warehouse", "assistant": []map[string]string{{"name": "Risal"}, {"name": "Rifki"}}},
+				},
+			},
+			want: "Assistants:\n- Data Warehouse: Risal, Rifki",
+		},
+		{
+			name: "Test Case 3",
+			args: args{
+				ctx:  bot.Context{Intent: intentSchedule},
+				data: []map[string]interface{}{},
+			},
+			want: "You don't have any schedule",
+		},
+		{
+			name: "Test Case 4",
+			args: args{
+				ctx:  bot.Context{Intent: intentClarification},
+				data: clarify([]string{intentAssignment, intentGrade}),
+			},
+			want: "Do you mean the assignment or the grade?\n1. the assignment\n2. the grade",
+		},
+		{
+			name: "Test Case 5",
+			args: args{
+				ctx: bot.Context{Intent: intentUnknown},
+			},
+			want: "Sorry, I don't understand your message",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineText(tt.args.ctx, tt.args.data); got != tt.want {
+				t.Errorf("lineText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineWebhookHandler(t *testing.T) {
+
+	// local stub of the LINE Messaging API
+	var replies []string
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []line.Message `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		for _, val := range req.Messages {
+			replies = append(replies, val.Text)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer stub.Close()
+
+	cfg.Line = line.Config{ChannelSecret: "secret", ChannelToken: "token", Endpoint: stub.URL}
+	lineClient = line.NewClient(cfg.Line)
+
+	body := []byte(`{"events":[{"type":"message","replyToken":"token-1","source":{"type":"user","userId":"U4af4980629"},"message":{"type":"text","text":"jadwal hari ini"}}]}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	// invalid signature
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/bot/line", bytes.NewReader(body))
+	r.Header.Set(line.HeaderSignature, "invalid")
+	w := httptest.NewRecorder()
+	LineWebhookHandler(w, r, nil)
+	if w.Code != http.StatusForbidden || len(replies) != 0 {
+		t.Fatalf("LineWebhookHandler() code = %d, replies = %v", w.Code, replies)
+	}
+
+	// the LINE user isn't connected into any Meiko user
+	db, _ := conn.InitDBMock()
+	db.ExpectQuery(`^\s*SELECT(.+)FROM(\s*)users(\s*)WHERE(\s*)line_id`).
+		WithArgs("U4af4980629").
+		WillReturnError(sql.ErrNoRows)
+
+	r = httptest.NewRequest(http.MethodPost, "/api/v1/bot/line", bytes.NewReader(body))
+	r.Header.Set(line.HeaderSignature, signature)
+	w = httptest.NewRecorder()
+	LineWebhookHandler(w, r, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("LineWebhookHandler() code = %d, want %d", w.Code, http.StatusOK)
+	}
+	if len(replies) != 1 || !strings.Contains(replies[0], "U4af4980629") {
+		t.Errorf("LineWebhookHandler() replies = %v", replies)
+	}
+}
@@ -1,6 +1,12 @@
 package bot
 
-import "time"
+import (
+	"sync"
+	"time"
+
+	"github.com/melodiez14/meiko/src/util/classifier"
+	"github.com/melodiez14/meiko/src/util/line"
+)
 
 const (
 	intentAssistant   = "assistant"
@@ -9,6 +15,17 @@ const (
 	intentGrade       = "grade"
 	intentAssignment  = "assignment"
 	intentUnknown     = "unknown"
+	// intentClarification is the response intent when the bot asks the user to choose the candidates
+	intentClarification = "clarification"
+
+	maximumInformation = 5
+	maximumCandidate   = 3
+	// maximumWebhookSize is the maximum body size of the LINE webhook in bytes
+	maximumWebhookSize = 1 << 20
+	// defaultContextExpire is the dialogue context lifetime in seconds if it isn't configured
+	defaultContextExpire = 300
+
+	rgxFollowUp = `^(kalau|kalo|klo|bagaimana|gimana|terus|trus|lalu|how about|what about|and)\b`
 
 	rgxMonday    = "(senin|senen|monday)"
 	rgxTuesday   = "(selasa|tuesday)"
@@ -19,9 +36,56 @@ const (
 	rgxSunday    = "(minggu|sunday)"
 )
 
-var rgxAssistant string
+// rgxAssistant is rebuilt by RefreshAssistant while the bot is answering, so it's guarded by rgxAssistantMutex
+var (
+	rgxAssistant      string
+	rgxAssistantMutex sync.RWMutex
+)
 var rgxCourse string
 
+var (
+	cfg              Config
+	intentClassifier classifier.Classifier = keywordClassifier{}
+	lineClient                             = line.NewClient(line.Config{})
+)
+
+// Config is used for setting the intent classifier of the bot
+type Config struct {
+	// Corpus is the path of the json training data
+	Corpus string `json:"corpus"`
+	// Threshold is the minimum confidence of the detected intent
+	Threshold float64 `json:"threshold"`
+	// ContextExpire is the lifetime of the dialogue context in seconds
+	ContextExpire int64 `json:"context_expire"`
+	// Line is the LINE Messaging API channel used by the webhook
+	Line line.Config `json:"line"`
+}
+
+// intentLabel is used for asking the clarifying question
+var intentLabel = map[string]string{
+	intentAssistant:   "the assistant",
+	intentSchedule:    "the schedule",
+	intentInformation: "the information",
+	intentGrade:       "the grade",
+	intentAssignment:  "the assignment",
+}
+
+// ordinalAnswer is the answer of the clarifying question which chooses the candidate by the position
+var ordinalAnswer = map[string]int{
+	"1":       0,
+	"satu":    0,
+	"pertama": 0,
+	"first":   0,
+	"2":       1,
+	"dua":     1,
+	"kedua":   1,
+	"second":  1,
+	"3":       2,
+	"tiga":    2,
+	"ketiga":  2,
+	"third":   2,
+}
+
 type sAssistant string
 type sSchedule string
 type sInformation string
@@ -49,6 +113,16 @@ type messageResponse struct {
 	Response  interface{} `json:"response"`
 }
 
+type clarificationOption struct {
+	Intent string `json:"intent"`
+	Text   string `json:"text"`
+}
+
+type clarificationResponse struct {
+	Question string                `json:"question"`
+	Options  []clarificationOption `json:"options"`
+}
+
 type loadHistoryParams struct {
 	Time     string
 	Position string
@@ -0,0 +1,250 @@
+package calendar
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/melodiez14/meiko/src/module/calendar"
+	"github.com/melodiez14/meiko/src/module/user"
+	"github.com/melodiez14/meiko/src/util/alias"
+	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/melodiez14/meiko/src/webserver/template"
+)
+
+// CreateTokenHandler handles the http request for enabling the personal calendar feed of the logged in user. The
+// previous feed url stops working when the token is created again and the plain token is only shown in this response
+/*
+	@params:
+	@example:
+	@return
+		{token, url}
+*/
+func CreateTokenHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.TokenID != 0 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Calendar token can't be created using api token"))
+		return
+	}
+
+	token, hash, err := calendar.NewToken()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = calendar.UpsertToken(sess.ID, hash)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(tokenResponse{
+			Token: token,
+			URL:   fmt.Sprintf("/api/v1/calendar/user/%s.ics", token),
+		}))
+	return
+}
+
+// DeleteTokenHandler handles the http request for disabling the personal calendar feed of the logged in user
+/*
+	@params:
+	@example:
+	@return
+*/
+func DeleteTokenHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	err := calendar.DeleteToken(sess.ID)
+	if err != nil {
+		if err == conn.ErrNoRowsAffected {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNotFound).
+				AddError("Calendar feed is not enabled"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Calendar feed has been disabled"))
+	return
+}
+
+// UserFeedHandler handles the http request for the personal calendar feed. The feed is authenticated by the token
+// in the url since the calendar apps can't sign in. It contains the weekly classes, meetings and assignment due
+// dates of the active schedules the user teaches, assists or takes. The feed of the inactive user isn't found
+/*
+	@params:
+		token	= required, the calendar token with optional .ics extension
+	@example:
+		token	= Xa3sd9QmZ2k.ics
+	@return
+		text/calendar
+*/
+func UserFeedHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	params := feedParams{
+		Token: ps.ByName("token"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	userID, err := calendar.GetUserIDByToken(calendar.HashToken(args.Token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNotFound).
+				AddError("Calendar is not found"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	users, err := user.SelectByID([]int64{userID}, user.ColName, user.ColStatus)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// the feed of the deactivated user stops working without revoking the token
+	if len(users) < 1 || users[0].Status != alias.UserStatusActivated {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Calendar is not found"))
+		return
+	}
+
+	schedules, err := calendar.SelectUserSchedule(userID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	c, err := newCalendar(fmt.Sprintf("Meiko - %s", users[0].Name), schedules)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	renderCalendar(w, c)
+	return
+}
+
+// ScheduleFeedHandler handles the http request for the public calendar feed of the schedule. The feed is available
+// after it's enabled by the lecturer and contains the weekly class, meetings and assignment due dates of the schedule
+/*
+	@params:
+		token	= required, the public token of the schedule with optional .ics extension
+	@example:
+		token	= Qm3a9sd0ZxK2.ics
+	@return
+		text/calendar
+*/
+func ScheduleFeedHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	params := feedParams{
+		Token: ps.ByName("token"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	scheduleID, err := calendar.GetScheduleIDByPublicToken(args.Token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNotFound).
+				AddError("Calendar is not found"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	schedule, err := calendar.GetSchedule(scheduleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNotFound).
+				AddError("Calendar is not found"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	c, err := newCalendar(fmt.Sprintf("%s (%s)", schedule.CourseName, schedule.Class), []calendar.Schedule{schedule})
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	renderCalendar(w, c)
+	return
+}
+
+// newCalendar returns the calendar of the schedules with their meetings and assignments
+func newCalendar(name string, schedules []calendar.Schedule) (calendar.Calendar, error) {
+
+	var scheduleIDs []int64
+	for _, val := range schedules {
+		scheduleIDs = append(scheduleIDs, val.ID)
+	}
+
+	meetings, err := calendar.SelectMeeting(scheduleIDs)
+	if err != nil {
+		return calendar.Calendar{}, err
+	}
+
+	assignments, err := calendar.SelectAssignment(scheduleIDs)
+	if err != nil {
+		return calendar.Calendar{}, err
+	}
+
+	return calendar.Calendar{
+		Name:        name,
+		Schedules:   schedules,
+		Meetings:    meetings,
+		Assignments: assignments,
+	}, nil
+}
+
+// renderCalendar writes the calendar as the ics file
+func renderCalendar(w http.ResponseWriter, c calendar.Calendar) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="meiko.ics"`)
+	w.Write(c.Render(time.Now()))
+}
@@ -0,0 +1,14 @@
+package calendar
+
+type feedParams struct {
+	Token string
+}
+
+type feedArgs struct {
+	Token string
+}
+
+type tokenResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
@@ -0,0 +1,36 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/melodiez14/meiko/src/util/helper"
+)
+
+func (params feedParams) validate() (feedArgs, error) {
+
+	var args feedArgs
+	params = feedParams{
+		Token: strings.TrimSuffix(helper.Trim(params.Token), ".ics"),
+	}
+
+	if helper.IsEmpty(params.Token) {
+		return args, fmt.Errorf("Token can't be empty")
+	}
+
+	// the token is encoded using the url safe base64
+	if len(params.Token) > 64 {
+		return args, fmt.Errorf("Invalid token")
+	}
+	for _, ch := range params.Token {
+		isAlphaNumeric := (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')
+		if !isAlphaNumeric && ch != '-' && ch != '_' {
+			return args, fmt.Errorf("Invalid token")
+		}
+	}
+
+	args = feedArgs{
+		Token: params.Token,
+	}
+	return args, nil
+}
@@ -0,0 +1,52 @@
+package calendar
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_feedParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  feedParams
+		want    feedArgs
+		wantErr bool
+	}{
+		{
+			name:    "Test Case 1",
+			params:  feedParams{Token: "Xa3sd9Qm-Z2_k.ics"},
+			want:    feedArgs{Token: "Xa3sd9Qm-Z2_k"},
+			wantErr: false,
+		},
+		{
+			name:    "Test Case 2",
+			params:  feedParams{Token: "Xa3sd9QmZ2k"},
+			want:    feedArgs{Token: "Xa3sd9QmZ2k"},
+			wantErr: false,
+		},
+		{
+			name:    "Test Case 3",
+			params:  feedParams{Token: ".ics"},
+			want:    feedArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 4",
+			params:  feedParams{Token: "Xa3sd9Qm/../Z2k"},
+			want:    feedArgs{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("feedParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("feedParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,426 @@
+package course
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/melodiez14/meiko/src/module/audit"
+	cs "github.com/melodiez14/meiko/src/module/course"
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/module/user"
+	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/melodiez14/meiko/src/util/helper"
+	"github.com/melodiez14/meiko/src/webserver/handler/bot"
+	"github.com/melodiez14/meiko/src/webserver/template"
+)
+
+// ReadAssistantHandler handles the http request for listing the assistants with the schedules they assist. The user
+// with READ ability only sees the assistants of the schedules he owns, the user with XREAD ability sees all of them
+/*
+	@params:
+		identity_code	= optional, identity code of the assistant
+	@example:
+		identity_code	= 140810140016
+	@return
+		[]{identity_code, name, email, schedules: []{id, name, class, semester, year, status}}
+*/
+func ReadAssistantHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	scope := sess.Scope(rg.ModuleSchedule, rg.RoleRead)
+	if scope == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := readAssistantParams{
+		IdentityCode: r.FormValue("identity_code"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	res := []readAssistantResponse{}
+	var scheduleIDs []int64
+	if scope == auth.ScopeOwn {
+		scheduleIDs, err = cs.SelectOwnedScheduleID(sess.ID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		if len(scheduleIDs) < 1 {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusOK).
+				SetData(res))
+			return
+		}
+	}
+
+	var userIDs []int64
+	if args.IdentityCode > 0 {
+		u, err := user.GetByIdentityCode(args.IdentityCode, user.ColID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				template.RenderJSONResponse(w, new(template.Response).
+					SetCode(http.StatusNotFound).
+					AddError("User is not found"))
+				return
+			}
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		userIDs = append(userIDs, u.ID)
+	}
+
+	schedules, err := cs.SelectAssistantSchedule(userIDs, scheduleIDs)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// the schedules are ordered by the assistant, so they are grouped in the same order
+	var assistantIDs []int64
+	grouped := map[int64][]assistantScheduleResponse{}
+	for _, val := range schedules {
+		if _, ok := grouped[val.UserID]; !ok {
+			assistantIDs = append(assistantIDs, val.UserID)
+		}
+		grouped[val.UserID] = append(grouped[val.UserID], assistantScheduleResponse{
+			ID:       val.ScheduleID,
+			Name:     val.CourseName,
+			Class:    val.Class,
+			Semester: val.Semester,
+			Year:     val.Year,
+			Status:   val.Status,
+		})
+	}
+
+	users, err := user.SelectByID(assistantIDs, user.ColID, user.ColIdentityCode, user.ColName, user.ColEmail)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	usersByID := map[int64]user.User{}
+	for _, val := range users {
+		usersByID[val.ID] = val
+	}
+
+	for _, id := range assistantIDs {
+		u, ok := usersByID[id]
+		if !ok {
+			continue
+		}
+		res = append(res, readAssistantResponse{
+			IdentityCode: u.IdentityCode,
+			Name:         u.Name,
+			Email:        u.Email,
+			Schedules:    grouped[id],
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// AddAssistantHandler handles the http request for adding the assistants to the schedule. The student of the schedule
+// or the user who requests to join it can't be its assistant, neither the user who has another course at the same time.
+// Accessing this handler needs UPDATE or XUPDATE ability of schedules module
+/*
+	@params:
+		schedule_id		= required, positive numeric
+		identity_code	= required, comma separated identity code
+	@example:
+		schedule_id		= 149
+		identity_code	= 140810140016,140810140060
+	@return
+*/
+func AddAssistantHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleSchedule, rg.RoleUpdate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := assistantParams{
+		ScheduleID:    ps.ByName("schedule_id"),
+		IdentityCodes: r.FormValue("identity_code"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	schedule, err := cs.GetByScheduleID(args.ScheduleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNotFound).
+				AddError("Not Found"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if !isHasAccess(sess, rg.ModuleSchedule, args.ScheduleID, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	users, code, err := selectRegisteredUser(args.IdentityCodes)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	var userIDs []int64
+	identityCodes := map[int64]int64{}
+	for _, val := range users {
+		userIDs = append(userIDs, val.ID)
+		identityCodes[val.ID] = val.IdentityCode
+	}
+
+	enrollments, err := cs.SelectEnrollment(args.ScheduleID, userIDs)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	for _, val := range enrollments {
+		if val.Status == cs.PStatusAssistant {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError(fmt.Sprintf("%d is already an assistant of this course", identityCodes[val.UserID])))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(fmt.Sprintf("%d is a student of this course", identityCodes[val.UserID])))
+		return
+	}
+
+	// the assistant can't assist or take another course at the same time
+	if schedule.Schedule.Status == cs.StatusScheduleActive {
+		conflicts, err := cs.SelectUserConflict(userIDs, scheduleTerm(schedule.Schedule), args.ScheduleID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		if len(conflicts) > 0 {
+			res, err := newConflictResponses(conflicts)
+			if err != nil {
+				template.RenderJSONResponse(w, new(template.Response).
+					SetCode(http.StatusInternalServerError))
+				return
+			}
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusConflict).
+				SetData(res).
+				AddError(fmt.Sprintf("%d has %s", res[0].IdentityCode, conflictMessage(res[0]))))
+			return
+		}
+	}
+
+	tx := conn.DB.MustBegin()
+	for _, val := range userIDs {
+		err = cs.InsertAssistant(val, args.ScheduleID, tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	diff := audit.NewDiff(nil, map[string]interface{}{"assistant": userIdentityCodes(users)})
+	err = audit.Insert(sess.ID, audit.ActionCreate, audit.TableUsersSchedules, strconv.FormatInt(args.ScheduleID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	go bot.RefreshAssistant()
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage(fmt.Sprintf("%d assistants have been added", len(userIDs))))
+	return
+}
+
+// RemoveAssistantHandler handles the http request for removing the assistants from the schedule. Accessing this handler
+// needs UPDATE or XUPDATE ability of schedules module
+/*
+	@params:
+		schedule_id		= required, positive numeric
+		identity_code	= required, comma separated identity code
+	@example:
+		schedule_id		= 149
+		identity_code	= 140810140016,140810140060
+	@return
+*/
+func RemoveAssistantHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleSchedule, rg.RoleUpdate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := assistantParams{
+		ScheduleID:    ps.ByName("schedule_id"),
+		IdentityCodes: r.FormValue("identity_code"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsExistScheduleID(args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Not Found"))
+		return
+	}
+
+	if !isHasAccess(sess, rg.ModuleSchedule, args.ScheduleID, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	users, code, err := selectRegisteredUser(args.IdentityCodes)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	var userIDs []int64
+	for _, val := range users {
+		userIDs = append(userIDs, val.ID)
+	}
+
+	// only the users who are the assistants are removed and recorded
+	enrollments, err := cs.SelectEnrollment(args.ScheduleID, userIDs)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	var assistants []user.User
+	for _, val := range users {
+		for _, e := range enrollments {
+			if e.UserID == val.ID && e.Status == cs.PStatusAssistant {
+				assistants = append(assistants, val)
+			}
+		}
+	}
+
+	tx := conn.DB.MustBegin()
+	removed, err := cs.DeleteAssistant(userIDs, args.ScheduleID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if removed > 0 {
+		diff := audit.NewDiff(map[string]interface{}{"assistant": userIdentityCodes(assistants)}, nil)
+		err = audit.Insert(sess.ID, audit.ActionDelete, audit.TableUsersSchedules, strconv.FormatInt(args.ScheduleID, 10), diff, helper.ClientIP(r), tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if removed > 0 {
+		go bot.RefreshAssistant()
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage(fmt.Sprintf("%d assistants have been removed", removed)))
+	return
+}
+
+// selectRegisteredUser returns the users of the identity codes. It returns the http status code with the error if any
+// of them isn't registered
+func selectRegisteredUser(identityCodes []int64) ([]user.User, int, error) {
+	users, err := user.SelectByIdentityCode(identityCodes, user.ColID, user.ColIdentityCode)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Internal server error")
+	}
+
+	var registered []int64
+	for _, val := range users {
+		registered = append(registered, val.IdentityCode)
+	}
+
+	for _, val := range identityCodes {
+		if !helper.Int64InSlice(val, registered) {
+			return nil, http.StatusBadRequest, fmt.Errorf("%d is not registered", val)
+		}
+	}
+	return users, http.StatusOK, nil
+}
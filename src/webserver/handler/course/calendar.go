@@ -0,0 +1,145 @@
+package course
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/melodiez14/meiko/src/module/calendar"
+	cs "github.com/melodiez14/meiko/src/module/course"
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/melodiez14/meiko/src/webserver/template"
+)
+
+// EnablePublicCalendarHandler handles the http request for enabling the public calendar feed of the schedule, anyone
+// who has the url can subscribe to it. The url of the enabled feed is returned when it's enabled again. Accessing
+// this handler needs UPDATE or XUPDATE ability of schedules module
+/*
+	@params:
+		schedule_id	= required, positive numeric
+	@example:
+		schedule_id	= 149
+	@return
+		{token, url}
+*/
+func EnablePublicCalendarHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleSchedule, rg.RoleUpdate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := publicCalendarParams{
+		ScheduleID: ps.ByName("schedule_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsExistScheduleID(args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Not Found"))
+		return
+	}
+
+	if !isHasAccess(sess, rg.ModuleSchedule, args.ScheduleID, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	token, err := calendar.GetPublicToken(args.ScheduleID)
+	if err == sql.ErrNoRows {
+		token, err = calendar.InsertPublicToken(args.ScheduleID)
+	}
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(publicCalendarResponse{
+			Token: token,
+			URL:   fmt.Sprintf("/api/v1/calendar/schedule/%s.ics", token),
+		}))
+	return
+}
+
+// DisablePublicCalendarHandler handles the http request for disabling the public calendar feed of the schedule, the
+// subscribed url stops working. Accessing this handler needs UPDATE or XUPDATE ability of schedules module
+/*
+	@params:
+		schedule_id	= required, positive numeric
+	@example:
+		schedule_id	= 149
+	@return
+*/
+func DisablePublicCalendarHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleSchedule, rg.RoleUpdate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := publicCalendarParams{
+		ScheduleID: ps.ByName("schedule_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsExistScheduleID(args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Not Found"))
+		return
+	}
+
+	if !isHasAccess(sess, rg.ModuleSchedule, args.ScheduleID, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	err = calendar.DeletePublicToken(args.ScheduleID)
+	if err != nil {
+		if err == conn.ErrNoRowsAffected {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNotFound).
+				AddError("Public calendar is not enabled"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Public calendar has been disabled"))
+	return
+}
@@ -0,0 +1,95 @@
+package course
+
+import (
+	"fmt"
+
+	cs "github.com/melodiez14/meiko/src/module/course"
+	"github.com/melodiez14/meiko/src/module/user"
+	"github.com/melodiez14/meiko/src/util/helper"
+)
+
+// scheduleTerm returns the term of the schedule which is checked for the conflict
+func scheduleTerm(s cs.Schedule) cs.Term {
+	return cs.Term{
+		Year:     s.Year,
+		Semester: s.Semester,
+		Day:      s.Day,
+		Slot: cs.Slot{
+			StartTime: s.StartTime,
+			EndTime:   s.EndTime,
+		},
+	}
+}
+
+// checkConflict returns the schedules which use the place at the same time, they reject the schedule, and the
+// schedules of the users at the same time which are only warned
+func checkConflict(placeID string, term cs.Term, userIDs []int64, scheduleID int64) ([]conflictResponse, []conflictResponse, error) {
+
+	places, err := cs.SelectPlaceConflict(placeID, term, scheduleID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	users, err := cs.SelectUserConflict(userIDs, term, scheduleID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	placeRes, err := newConflictResponses(places)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userRes, err := newConflictResponses(users)
+	if err != nil {
+		return nil, nil, err
+	}
+	return placeRes, userRes, nil
+}
+
+// newConflictResponses converts the conflicts into the response, the user is shown by the identity code
+func newConflictResponses(conflicts []cs.Conflict) ([]conflictResponse, error) {
+
+	var userIDs []int64
+	for _, val := range conflicts {
+		if val.UserID > 0 && !helper.Int64InSlice(val.UserID, userIDs) {
+			userIDs = append(userIDs, val.UserID)
+		}
+	}
+
+	users, err := user.SelectByID(userIDs, user.ColID, user.ColIdentityCode)
+	if err != nil {
+		return nil, err
+	}
+
+	identityCodes := map[int64]int64{}
+	for _, val := range users {
+		identityCodes[val.ID] = val.IdentityCode
+	}
+
+	res := []conflictResponse{}
+	for _, val := range conflicts {
+		res = append(res, conflictResponse{
+			Type:         val.Type,
+			IdentityCode: identityCodes[val.UserID],
+			ScheduleID:   val.ScheduleID,
+			Name:         val.CourseName,
+			Class:        val.Class,
+			Day:          helper.IntDayToString(val.Day),
+			StartTime:    val.StartTime,
+			EndTime:      val.EndTime,
+			PlaceID:      val.PlaceID,
+		})
+	}
+	return res, nil
+}
+
+// conflictMessage describes the first conflict
+func conflictMessage(c conflictResponse) string {
+	return fmt.Sprintf("%s %s is held on %s %s - %s",
+		c.Name,
+		c.Class,
+		c.Day,
+		helper.MinutesToTimeString(c.StartTime),
+		helper.MinutesToTimeString(c.EndTime))
+}
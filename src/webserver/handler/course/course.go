@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/melodiez14/meiko/src/util/conn"
@@ -12,7 +13,9 @@ import (
 
 	"github.com/julienschmidt/httprouter"
 	ag "github.com/melodiez14/meiko/src/module/assignment"
+	"github.com/melodiez14/meiko/src/module/audit"
 	cs "github.com/melodiez14/meiko/src/module/course"
+	gd "github.com/melodiez14/meiko/src/module/grade"
 	pl "github.com/melodiez14/meiko/src/module/place"
 	rg "github.com/melodiez14/meiko/src/module/rolegroup"
 	"github.com/melodiez14/meiko/src/module/user"
@@ -51,7 +54,7 @@ import (
 func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleSchedule, rg.RoleCreate, rg.RoleXCreate) {
+	if sess.Scope(rg.ModuleSchedule, rg.RoleCreate) == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -90,13 +93,39 @@ func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 			AddError("Schedule already exists"))
 		return
 	}
+
+	// the place can't be used by two schedules at the same time, the other schedules of the lecturer are only warned
+	term := cs.Term{
+		Year:     args.Year,
+		Semester: args.Semester,
+		Day:      args.Day,
+		Slot: cs.Slot{
+			StartTime: uint16(args.StartTime),
+			EndTime:   uint16(args.EndTime),
+		},
+	}
+	placeConflicts, warnings, err := checkConflict(args.PlaceID, term, []int64{sess.ID}, 0)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if len(placeConflicts) > 0 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusConflict).
+			SetData(placeConflicts).
+			AddError(fmt.Sprintf("%s is used by %s", args.PlaceID, conflictMessage(placeConflicts[0]))))
+		return
+	}
+
 	csExist := cs.IsExist(args.ID)
 	plExist := pl.IsExistID(args.PlaceID)
 
 	tx := conn.DB.MustBegin()
 
-	// insert new course and check create or xcreate roles
-	if !csExist && sess.IsHasRoles(rg.ModuleCourse, rg.RoleCreate, rg.RoleXCreate) {
+	// insert new course, the new course is owned by the creator of its schedule
+	if !csExist && sess.Authorize(rg.ModuleCourse, rg.RoleCreate, sess.ID) {
 		err = cs.Insert(args.ID, args.Name, args.Description, args.UCU, tx)
 		if err != nil {
 			tx.Rollback()
@@ -104,8 +133,8 @@ func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 				SetCode(http.StatusInternalServerError))
 			return
 		}
-		// update course and check update or xupdate roles
-	} else if csExist && args.IsUpdate && sess.IsHasRoles(rg.ModuleCourse, rg.RoleUpdate, rg.RoleXUpdate) {
+		// update course, the course is owned by the owners of its schedules
+	} else if csExist && args.IsUpdate && isHasCourseAccess(sess, args.ID, rg.RoleUpdate) {
 		err = cs.Update(args.ID, args.Name, args.Description, args.UCU, tx)
 		if err != nil {
 			tx.Rollback()
@@ -152,6 +181,26 @@ func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
+	diff := audit.NewDiff(nil, scheduleAuditFields(cs.Schedule{
+		Status:    cs.StatusScheduleActive,
+		StartTime: uint16(args.StartTime),
+		EndTime:   uint16(args.EndTime),
+		Day:       args.Day,
+		Class:     args.Class,
+		Semester:  args.Semester,
+		Year:      args.Year,
+		CourseID:  args.ID,
+		PlaceID:   args.PlaceID,
+		CreatedBy: sess.ID,
+	}))
+	err = audit.Insert(sess.ID, audit.ActionCreate, audit.TableSchedules, strconv.FormatInt(scheduleID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
 	// set grade parameter
 	if len(args.GradeParameter) > 0 {
 		for _, val := range args.GradeParameter {
@@ -174,11 +223,13 @@ func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 
 	template.RenderJSONResponse(w, new(template.Response).
 		SetCode(http.StatusOK).
+		SetData(saveScheduleResponse{Warnings: warnings}).
 		SetMessage("Success"))
 	return
 }
 
-// ReadHandler handles the http request for creating the course. Accessing this handler needs CREATE or XCREATE ability
+// ReadHandler handles the http request for listing the course schedules. Accessing this handler needs READ or XREAD ability,
+// the READ ability only lists the schedules created or assisted by the user
 /*
 	@params:
 		pg	= required, positive numeric
@@ -192,7 +243,8 @@ func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleCourse, rg.RoleRead, rg.RoleXRead) {
+	scope := sess.Scope(rg.ModuleCourse, rg.RoleRead)
+	if scope == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -212,16 +264,34 @@ func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		return
 	}
 
+	// the READ ability only lists the schedules owned by the user
+	var scheduleID []int64
+	if scope == auth.ScopeOwn {
+		scheduleID, err = cs.SelectOwnedScheduleID(sess.ID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	var status string
+	var res []readResponse
+	if scope == auth.ScopeOwn && len(scheduleID) < 1 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusOK).
+			SetData(res))
+		return
+	}
+
 	offset := (args.Page - 1) * args.Total
-	courses, err := cs.SelectByPage(args.Total, offset)
+	courses, err := cs.SelectByPage(args.Total, offset, scheduleID...)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError))
 		return
 	}
 
-	var status string
-	var res []readResponse
 	for _, val := range courses {
 
 		if val.Schedule.Status == cs.StatusScheduleActive {
@@ -251,7 +321,7 @@ func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 func SearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleCourse, rg.RoleRead, rg.RoleXRead) {
+	if sess.Scope(rg.ModuleCourse, rg.RoleRead) == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -323,7 +393,7 @@ func SearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 */
 func ReadDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleCourse, rg.RoleRead, rg.RoleXRead) {
+	if sess.Scope(rg.ModuleCourse, rg.RoleRead) == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -342,6 +412,13 @@ func ReadDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 		return
 	}
 
+	if !isHasAccess(sess, rg.ModuleCourse, args.ScheduleID, rg.RoleRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
 	course, err := cs.GetByScheduleID(args.ScheduleID)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
@@ -379,7 +456,7 @@ func ReadDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleSchedule, rg.RoleUpdate, rg.RoleXUpdate) {
+	if sess.Scope(rg.ModuleSchedule, rg.RoleUpdate) == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -412,6 +489,25 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
+	schedule, err := cs.GetByScheduleID(args.ScheduleID)
+	if err == sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound))
+		return
+	}
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if !isHasAccess(sess, rg.ModuleSchedule, args.ScheduleID, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
 	// check if semester, year, id, class already used by another schedule
 	if cs.IsExistSchedule(args.Semester, args.Year, args.ID, args.Class, args.ScheduleID) {
 		template.RenderJSONResponse(w, new(template.Response).
@@ -420,6 +516,49 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
+	// the inactive schedule doesn't use the place, so it's only checked when the schedule is active
+	warnings := []conflictResponse{}
+	if args.Status == cs.StatusScheduleActive {
+		userIDs, err := cs.SelectOwnerID(args.ScheduleID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		studentIDs, err := cs.SelectStudentID(args.ScheduleID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		term := cs.Term{
+			Year:     args.Year,
+			Semester: args.Semester,
+			Day:      args.Day,
+			Slot: cs.Slot{
+				StartTime: uint16(args.StartTime),
+				EndTime:   uint16(args.EndTime),
+			},
+		}
+		var placeConflicts []conflictResponse
+		placeConflicts, warnings, err = checkConflict(args.PlaceID, term, append(userIDs, studentIDs...), args.ScheduleID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		if len(placeConflicts) > 0 {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusConflict).
+				SetData(placeConflicts).
+				AddError(fmt.Sprintf("%s is used by %s", args.PlaceID, conflictMessage(placeConflicts[0]))))
+			return
+		}
+	}
+
 	// get old grade parameter
 	gpsOld, err := cs.SelectGradeParameterByScheduleID(args.ScheduleID)
 	if err != nil {
@@ -477,19 +616,13 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 	}
 
 	// is exist course and place
-	scExist := cs.IsExistScheduleID(args.ScheduleID)
-	if !scExist {
-		template.RenderJSONResponse(w, new(template.Response).
-			SetCode(http.StatusNotFound))
-		return
-	}
 	csExist := cs.IsExist(args.ID)
 	plExist := pl.IsExistID(args.PlaceID)
 
 	tx := conn.DB.MustBegin()
 
-	// insert new course and check create or xcreate roles
-	if !csExist && sess.IsHasRoles(rg.ModuleCourse, rg.RoleCreate, rg.RoleXCreate) {
+	// insert new course, the new course is owned by the creator of its schedule
+	if !csExist && sess.Authorize(rg.ModuleCourse, rg.RoleCreate, sess.ID) {
 		err = cs.Insert(args.ID, args.Name, args.Description, args.UCU, tx)
 		if err != nil {
 			tx.Rollback()
@@ -497,8 +630,8 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 				SetCode(http.StatusInternalServerError))
 			return
 		}
-		// update course and check update or xupdate roles
-	} else if csExist && args.IsUpdate && sess.IsHasRoles(rg.ModuleCourse, rg.RoleUpdate, rg.RoleXUpdate) {
+		// update course, the course is owned by the owners of its schedules
+	} else if csExist && args.IsUpdate && isHasCourseAccess(sess, args.ID, rg.RoleUpdate) {
 		err = cs.Update(args.ID, args.Name, args.Description, args.UCU, tx)
 		if err != nil {
 			tx.Rollback()
@@ -535,6 +668,21 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
+	updated := schedule.Schedule
+	updated.Status, updated.StartTime, updated.EndTime = args.Status, uint16(args.StartTime), uint16(args.EndTime)
+	updated.Day, updated.Class, updated.Semester, updated.Year = args.Day, args.Class, args.Semester, args.Year
+	updated.CourseID, updated.PlaceID = args.ID, args.PlaceID
+	scheduleDiff := audit.NewDiff(scheduleAuditFields(schedule.Schedule), scheduleAuditFields(updated))
+	if len(scheduleDiff.Before) > 0 || len(scheduleDiff.After) > 0 {
+		err = audit.Insert(sess.ID, audit.ActionUpdate, audit.TableSchedules, strconv.FormatInt(args.ScheduleID, 10), scheduleDiff, helper.ClientIP(r), tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
 	// delete old grade parameter
 	for _, val := range gpsDelete {
 		err := cs.DeleteGradeParameter(val.ID, tx)
@@ -568,6 +716,39 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		}
 	}
 
+	// record the grade parameter changes, the unchanged parameters aren't recorded
+	gpsBefore := map[string]interface{}{}
+	for _, val := range gpsOld {
+		gpsBefore[val.Type] = gradeParameter{
+			Type:         val.Type,
+			Percentage:   val.Percentage,
+			StatusChange: val.StatusChange,
+		}
+	}
+	gpsAfter := map[string]interface{}{}
+	for _, val := range args.GradeParameter {
+		gpsAfter[val.Type] = val
+	}
+	diff := audit.NewDiff(gpsBefore, gpsAfter)
+	if len(diff.Before) > 0 || len(diff.After) > 0 {
+		err = audit.Insert(sess.ID, audit.ActionUpdate, audit.TableGradeParameters, strconv.FormatInt(args.ScheduleID, 10), diff, helper.ClientIP(r), tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	// recompute the final score using the new parameter
+	err = gd.Recompute(args.ScheduleID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
@@ -577,6 +758,7 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 
 	template.RenderJSONResponse(w, new(template.Response).
 		SetCode(http.StatusOK).
+		SetData(saveScheduleResponse{Warnings: warnings}).
 		SetMessage("Success"))
 	return
 }
@@ -745,7 +927,7 @@ func GetAssistantHandler(w http.ResponseWriter, r *http.Request, ps httprouter.P
 func DeleteScheduleHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleSchedule, rg.RoleDelete, rg.RoleXDelete) {
+	if sess.Scope(rg.ModuleSchedule, rg.RoleDelete) == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -771,7 +953,39 @@ func DeleteScheduleHandler(w http.ResponseWriter, r *http.Request, ps httprouter
 		return
 	}
 
-	err = cs.DeleteSchedule(args.ScheduleID)
+	if !isHasAccess(sess, rg.ModuleSchedule, args.ScheduleID, rg.RoleDelete) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	schedule, err := cs.GetByScheduleID(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = cs.DeleteSchedule(args.ScheduleID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	diff := audit.NewDiff(scheduleAuditFields(schedule.Schedule), nil)
+	err = audit.Insert(sess.ID, audit.ActionDelete, audit.TableSchedules, strconv.FormatInt(args.ScheduleID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError))
@@ -794,7 +1008,7 @@ func DeleteScheduleHandler(w http.ResponseWriter, r *http.Request, ps httprouter
 */
 func ListParameterHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleSchedule, rg.RoleRead, rg.RoleXRead) {
+	if sess.Scope(rg.ModuleSchedule, rg.RoleRead) == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -840,7 +1054,7 @@ func ListParameterHandler(w http.ResponseWriter, r *http.Request, ps httprouter.
 */
 func ReadScheduleParameterHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleSchedule, rg.RoleRead, rg.RoleXRead) {
+	if sess.Scope(rg.ModuleSchedule, rg.RoleRead) == auth.ScopeNone {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -859,6 +1073,13 @@ func ReadScheduleParameterHandler(w http.ResponseWriter, r *http.Request, ps htt
 		return
 	}
 
+	if !isHasAccess(sess, rg.ModuleSchedule, args.ScheduleID, rg.RoleRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
 	gps, err := cs.SelectGradeParameterByScheduleID(args.ScheduleID)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
@@ -880,3 +1101,37 @@ func ReadScheduleParameterHandler(w http.ResponseWriter, r *http.Request, ps htt
 		SetData(resp))
 	return
 }
+
+// isHasAccess checks the ability of the user to the schedule which is owned by its creator and assistants
+func isHasAccess(sess *auth.User, module string, scheduleID int64, action string) bool {
+	owners, err := cs.SelectOwnerID(scheduleID)
+	if err != nil {
+		return false
+	}
+	return sess.Authorize(module, action, owners...)
+}
+
+// isHasCourseAccess checks the ability of the user to the course which is owned by the owners of its schedules
+func isHasCourseAccess(sess *auth.User, courseID, action string) bool {
+	owners, err := cs.SelectCourseOwnerID(courseID)
+	if err != nil {
+		return false
+	}
+	return sess.Authorize(rg.ModuleCourse, action, owners...)
+}
+
+// scheduleAuditFields returns the fields of the schedule which are recorded by the audit log
+func scheduleAuditFields(s cs.Schedule) map[string]interface{} {
+	return map[string]interface{}{
+		"courses_id": s.CourseID,
+		"status":     s.Status,
+		"start_time": s.StartTime,
+		"end_time":   s.EndTime,
+		"day":        s.Day,
+		"class":      s.Class,
+		"semester":   s.Semester,
+		"year":       s.Year,
+		"places_id":  s.PlaceID,
+		"created_by": s.CreatedBy,
+	}
+}
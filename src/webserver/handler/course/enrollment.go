@@ -0,0 +1,695 @@
+package course
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/melodiez14/meiko/src/email"
+	"github.com/melodiez14/meiko/src/module/audit"
+	cs "github.com/melodiez14/meiko/src/module/course"
+	"github.com/melodiez14/meiko/src/module/notification"
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/module/user"
+	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/melodiez14/meiko/src/util/helper"
+	"github.com/melodiez14/meiko/src/webserver/template"
+)
+
+// RequestEnrollmentHandler handles the http request of the student for joining the active schedule. The request
+// waits for the approval of the assistant or the lecturer of the schedule. The schedule which overlaps the other
+// schedules of the student is rejected
+/*
+	@params:
+		schedule_id	= required, positive numeric
+	@example:
+		schedule_id	= 149
+	@return
+*/
+func RequestEnrollmentHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := enrollmentParams{
+		ScheduleID: ps.ByName("schedule_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	schedule, err := cs.GetByScheduleID(args.ScheduleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNotFound).
+				AddError("Course is not found"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if schedule.Schedule.Status != cs.StatusScheduleActive {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Course is not open for enrollment"))
+		return
+	}
+
+	if cs.IsEnrolled(sess.ID, args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("You have joined or requested to join this course"))
+		return
+	}
+
+	isFull, err := isScheduleFull(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if isFull {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Course is full"))
+		return
+	}
+
+	// the student can't take two courses at the same time
+	conflicts, err := cs.SelectUserConflict([]int64{sess.ID}, scheduleTerm(schedule.Schedule), args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if len(conflicts) > 0 {
+		res, err := newConflictResponses(conflicts)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusConflict).
+			SetData(res).
+			AddError(fmt.Sprintf("Course overlaps with %s", conflictMessage(res[0]))))
+		return
+	}
+
+	err = cs.RequestEnrollment(sess.ID, args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Enrollment request has been sent"))
+	return
+}
+
+// WithdrawEnrollmentHandler handles the http request of the student for withdrawing the enrollment request which
+// hasn't been approved
+/*
+	@params:
+		schedule_id	= required, positive numeric
+	@example:
+		schedule_id	= 149
+	@return
+*/
+func WithdrawEnrollmentHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := enrollmentParams{
+		ScheduleID: ps.ByName("schedule_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	err = cs.WithdrawEnrollment(sess.ID, args.ScheduleID)
+	if err != nil {
+		if err == conn.ErrNoRowsAffected {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNotFound).
+				AddError("Enrollment request is not found"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Enrollment request has been withdrawn"))
+	return
+}
+
+// ReadEnrollmentHandler handles the http request for listing the pending enrollment requests of the schedule from
+// the oldest request. Accessing this handler needs READ or XREAD ability of schedules module
+/*
+	@params:
+		schedule_id	= required, positive numeric
+	@example:
+		schedule_id	= 149
+	@return
+		{capacity, students, requests: []{identity_code, name, email, requested_at}}
+*/
+func ReadEnrollmentHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleSchedule, rg.RoleRead) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := enrollmentParams{
+		ScheduleID: ps.ByName("schedule_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsExistScheduleID(args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Not Found"))
+		return
+	}
+
+	if !isHasAccess(sess, rg.ModuleSchedule, args.ScheduleID, rg.RoleRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	capacity, err := cs.GetCapacity(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	students, err := cs.CountStudent(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	enrollments, err := cs.SelectEnrollmentRequest(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	users, err := selectEnrollmentUser(enrollments)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	res := readEnrollmentResponse{
+		Capacity: capacity,
+		Students: students,
+		Requests: []enrollmentResponse{},
+	}
+	for _, val := range enrollments {
+		u, ok := users[val.UserID]
+		if !ok {
+			continue
+		}
+		res.Requests = append(res.Requests, enrollmentResponse{
+			IdentityCode: u.IdentityCode,
+			Name:         u.Name,
+			Email:        u.Email,
+			RequestedAt:  val.CreatedAt,
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// ApproveEnrollmentHandler handles the http request for approving the enrollment requests of the schedule. The requests
+// are approved in a transaction which fails if the capacity of the schedule is exceeded. The approved students are
+// notified by the in-app notification and the email. Accessing this handler needs UPDATE or XUPDATE ability of schedules module
+/*
+	@params:
+		schedule_id		= required, positive numeric
+		identity_code	= required, comma separated identity code
+	@example:
+		schedule_id		= 149
+		identity_code	= 140810140016,140810140060
+	@return
+*/
+func ApproveEnrollmentHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleSchedule, rg.RoleUpdate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := decideEnrollmentParams{
+		ScheduleID:    ps.ByName("schedule_id"),
+		IdentityCodes: r.FormValue("identity_code"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	schedule, err := cs.GetByScheduleID(args.ScheduleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNotFound).
+				AddError("Not Found"))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if !isHasAccess(sess, rg.ModuleSchedule, args.ScheduleID, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	users, code, err := selectRequestingUser(args.ScheduleID, args.IdentityCodes)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	var userIDs []int64
+	for _, val := range users {
+		userIDs = append(userIDs, val.ID)
+	}
+
+	tx := conn.DB.MustBegin()
+	capacity, err := cs.GetCapacity(args.ScheduleID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	approved, err := cs.ApproveEnrollment(userIDs, args.ScheduleID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// the request may be withdrawn or decided by the others after it's selected
+	if approved != int64(len(userIDs)) {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusConflict).
+			AddError("Some enrollment requests have changed, please reload the requests"))
+		return
+	}
+
+	students, err := cs.CountStudent(args.ScheduleID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if capacity > 0 && students > int64(capacity) {
+		tx.Rollback()
+		seats := int64(capacity) - (students - approved)
+		if seats < 0 {
+			seats = 0
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(fmt.Sprintf("Course only has %d seats left", seats)))
+		return
+	}
+
+	diff := audit.NewDiff(map[string]interface{}{"unapproved": userIdentityCodes(users)}, map[string]interface{}{"student": userIdentityCodes(users)})
+	err = audit.Insert(sess.ID, audit.ActionUpdate, audit.TableUsersSchedules, strconv.FormatInt(args.ScheduleID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	courseName := fmt.Sprintf("%s %s", schedule.Course.Name, schedule.Schedule.Class)
+	for _, val := range users {
+		err = notification.Insert(val.ID,
+			"Enrollment approved",
+			fmt.Sprintf("Your enrollment to %s has been approved", courseName),
+			notification.TableSchedules,
+			strconv.FormatInt(args.ScheduleID, 10),
+			tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	go func() {
+		for _, val := range users {
+			email.SendEnrollmentApproved(val.Name, val.Email, courseName)
+		}
+	}()
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage(fmt.Sprintf("%d enrollment requests have been approved", approved)))
+	return
+}
+
+// RejectEnrollmentHandler handles the http request for rejecting the enrollment requests of the schedule. The
+// rejected requests are deleted, so the students can request again. Accessing this handler needs UPDATE or XUPDATE
+// ability of schedules module
+/*
+	@params:
+		schedule_id		= required, positive numeric
+		identity_code	= required, comma separated identity code
+	@example:
+		schedule_id		= 149
+		identity_code	= 140810140016,140810140060
+	@return
+*/
+func RejectEnrollmentHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleSchedule, rg.RoleUpdate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := decideEnrollmentParams{
+		ScheduleID:    ps.ByName("schedule_id"),
+		IdentityCodes: r.FormValue("identity_code"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsExistScheduleID(args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Not Found"))
+		return
+	}
+
+	if !isHasAccess(sess, rg.ModuleSchedule, args.ScheduleID, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	users, code, err := selectRequestingUser(args.ScheduleID, args.IdentityCodes)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	var userIDs []int64
+	for _, val := range users {
+		userIDs = append(userIDs, val.ID)
+	}
+
+	tx := conn.DB.MustBegin()
+	rejected, err := cs.RejectEnrollment(userIDs, args.ScheduleID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// the request may be withdrawn or decided by the others after it's selected
+	if rejected != int64(len(userIDs)) {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusConflict).
+			AddError("Some enrollment requests have changed, please reload the requests"))
+		return
+	}
+
+	diff := audit.NewDiff(map[string]interface{}{"unapproved": userIdentityCodes(users)}, nil)
+	err = audit.Insert(sess.ID, audit.ActionDelete, audit.TableUsersSchedules, strconv.FormatInt(args.ScheduleID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage(fmt.Sprintf("%d enrollment requests have been rejected", rejected)))
+	return
+}
+
+// UpdateCapacityHandler handles the http request for changing the maximum number of the students of the schedule,
+// 0 means unlimited. Accessing this handler needs UPDATE or XUPDATE ability of schedules module
+/*
+	@params:
+		schedule_id	= required, positive numeric
+		capacity	= required, numeric, max 500
+	@example:
+		schedule_id	= 149
+		capacity	= 40
+	@return
+*/
+func UpdateCapacityHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleSchedule, rg.RoleUpdate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := updateCapacityParams{
+		ScheduleID: ps.ByName("schedule_id"),
+		Capacity:   r.FormValue("capacity"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsExistScheduleID(args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Not Found"))
+		return
+	}
+
+	if !isHasAccess(sess, rg.ModuleSchedule, args.ScheduleID, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	students, err := cs.CountStudent(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if args.Capacity > 0 && int64(args.Capacity) < students {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(fmt.Sprintf("Capacity cannot be less than %d enrolled students", students)))
+		return
+	}
+
+	capacity, err := cs.GetCapacity(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = cs.UpdateCapacity(args.ScheduleID, args.Capacity, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	diff := audit.NewDiff(map[string]interface{}{"capacity": capacity}, map[string]interface{}{"capacity": args.Capacity})
+	err = audit.Insert(sess.ID, audit.ActionUpdate, audit.TableCapacities, strconv.FormatInt(args.ScheduleID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Capacity has been updated"))
+	return
+}
+
+// isScheduleFull checks whether the approved students of the schedule have reached its capacity
+func isScheduleFull(scheduleID int64) (bool, error) {
+	capacity, err := cs.GetCapacity(scheduleID)
+	if err != nil || capacity < 1 {
+		return false, err
+	}
+
+	students, err := cs.CountStudent(scheduleID)
+	if err != nil {
+		return false, err
+	}
+	return students >= int64(capacity), nil
+}
+
+// selectEnrollmentUser returns the users of the enrollments mapped by their id
+func selectEnrollmentUser(enrollments []cs.Enrollment) (map[int64]user.User, error) {
+	var userIDs []int64
+	for _, val := range enrollments {
+		userIDs = append(userIDs, val.UserID)
+	}
+
+	users, err := user.SelectByID(userIDs, user.ColID, user.ColIdentityCode, user.ColName, user.ColEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[int64]user.User{}
+	for _, val := range users {
+		res[val.ID] = val
+	}
+	return res, nil
+}
+
+// selectRequestingUser returns the users of the identity codes which are requesting to join the schedule. It returns
+// the http status code with the error if any of them isn't requesting
+func selectRequestingUser(scheduleID int64, identityCodes []int64) ([]user.User, int, error) {
+	enrollments, err := cs.SelectEnrollmentRequest(scheduleID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Internal server error")
+	}
+
+	users, err := selectEnrollmentUser(enrollments)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("Internal server error")
+	}
+
+	requesting := map[int64]user.User{}
+	for _, val := range users {
+		requesting[val.IdentityCode] = val
+	}
+
+	res := []user.User{}
+	for _, val := range identityCodes {
+		u, ok := requesting[val]
+		if !ok {
+			return nil, http.StatusBadRequest, fmt.Errorf("%d doesn't request to join this course", val)
+		}
+		res = append(res, u)
+	}
+	return res, http.StatusOK, nil
+}
+
+// userIdentityCodes returns the identity codes of the users, they're recorded by the audit log instead of the user id
+func userIdentityCodes(users []user.User) []int64 {
+	res := []int64{}
+	for _, val := range users {
+		res = append(res, val.IdentityCode)
+	}
+	return res
+}
@@ -2,6 +2,10 @@ package course
 
 import (
 	"database/sql"
+	"time"
+
+	cs "github.com/melodiez14/meiko/src/module/course"
+	tt "github.com/melodiez14/meiko/src/module/timetable"
 )
 
 type readParams struct {
@@ -207,3 +211,175 @@ type readScheduleParameterResponse struct {
 	Percentage   float32 `json:"percentage"`
 	StatusChange uint8   `json:"status_change"`
 }
+
+type enrollmentParams struct {
+	ScheduleID string
+}
+
+type enrollmentArgs struct {
+	ScheduleID int64
+}
+
+type readEnrollmentResponse struct {
+	Capacity uint16               `json:"capacity"`
+	Students int64                `json:"students"`
+	Requests []enrollmentResponse `json:"requests"`
+}
+
+type enrollmentResponse struct {
+	IdentityCode int64     `json:"identity_code"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	RequestedAt  time.Time `json:"requested_at"`
+}
+
+type decideEnrollmentParams struct {
+	ScheduleID    string
+	IdentityCodes string
+}
+
+type decideEnrollmentArgs struct {
+	ScheduleID    int64
+	IdentityCodes []int64
+}
+
+type updateCapacityParams struct {
+	ScheduleID string
+	Capacity   string
+}
+
+type updateCapacityArgs struct {
+	ScheduleID int64
+	Capacity   uint16
+}
+
+type assistantParams struct {
+	ScheduleID    string
+	IdentityCodes string
+}
+
+type assistantArgs struct {
+	ScheduleID    int64
+	IdentityCodes []int64
+}
+
+type readAssistantParams struct {
+	IdentityCode string
+}
+
+type readAssistantArgs struct {
+	IdentityCode int64
+}
+
+type readAssistantResponse struct {
+	IdentityCode int64                       `json:"identity_code"`
+	Name         string                      `json:"name"`
+	Email        string                      `json:"email"`
+	Schedules    []assistantScheduleResponse `json:"schedules"`
+}
+
+type assistantScheduleResponse struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Class    string `json:"class"`
+	Semester int8   `json:"semester"`
+	Year     int16  `json:"year"`
+	Status   int8   `json:"status"`
+}
+
+type saveScheduleResponse struct {
+	Warnings []conflictResponse `json:"warnings"`
+}
+
+type conflictResponse struct {
+	Type         string `json:"type"`
+	IdentityCode int64  `json:"identity_code,omitempty"`
+	ScheduleID   int64  `json:"schedule_id"`
+	Name         string `json:"name"`
+	Class        string `json:"class"`
+	Day          string `json:"day"`
+	StartTime    uint16 `json:"start_time"`
+	EndTime      uint16 `json:"end_time"`
+	PlaceID      string `json:"place"`
+}
+
+type generateTimetableParams struct {
+	Year         string
+	Semester     string
+	Courses      string
+	Places       string
+	Days         string
+	Windows      string
+	Availability string
+}
+
+type timetableCourseParams struct {
+	ID         string  `json:"id"`
+	Classes    int     `json:"classes"`
+	Duration   int     `json:"duration"`
+	Assistants []int64 `json:"assistants"`
+}
+
+type timetableSlotParams struct {
+	IdentityCode int64  `json:"identity_code"`
+	Day          string `json:"day"`
+	StartTime    int    `json:"start_time"`
+	EndTime      int    `json:"end_time"`
+}
+
+type generateTimetableArgs struct {
+	Year     int16
+	Semester int8
+	Courses  []timetableCourseArgs
+	Places   []string
+	Days     []int8
+	Windows  []cs.Slot
+	// Availability is mapped by the identity code of the assistant
+	Availability map[int64][]tt.Availability
+}
+
+type timetableCourseArgs struct {
+	ID         string
+	Classes    int
+	Duration   uint16
+	Assistants []int64
+}
+
+type draftParams struct {
+	ID string
+}
+
+type draftArgs struct {
+	ID string
+}
+
+type draftResponse struct {
+	ID          string                  `json:"id"`
+	Year        int16                   `json:"year"`
+	Semester    int8                    `json:"semester"`
+	Schedules   []draftScheduleResponse `json:"schedules"`
+	Unscheduled []tt.Unscheduled        `json:"unscheduled"`
+}
+
+type draftScheduleResponse struct {
+	CourseID   string  `json:"course_id"`
+	Class      string  `json:"class"`
+	Day        string  `json:"day"`
+	StartTime  uint16  `json:"start_time"`
+	EndTime    uint16  `json:"end_time"`
+	PlaceID    string  `json:"place"`
+	Assistants []int64 `json:"assistants"`
+}
+
+type publicCalendarParams struct {
+	ScheduleID string
+}
+
+type publicCalendarArgs struct {
+	ScheduleID int64
+}
+
+type publicCalendarResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
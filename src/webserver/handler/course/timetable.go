@@ -0,0 +1,507 @@
+package course
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/melodiez14/meiko/src/module/audit"
+	cs "github.com/melodiez14/meiko/src/module/course"
+	pl "github.com/melodiez14/meiko/src/module/place"
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	tt "github.com/melodiez14/meiko/src/module/timetable"
+	"github.com/melodiez14/meiko/src/module/user"
+	"github.com/melodiez14/meiko/src/util/alias"
+	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/melodiez14/meiko/src/util/helper"
+	"github.com/melodiez14/meiko/src/webserver/handler/bot"
+	"github.com/melodiez14/meiko/src/webserver/template"
+)
+
+// GenerateTimetableHandler handles the http request for generating the schedules of the semester. The classes are
+// placed into the places, days and windows without using the same place or assistant at the same time, neither
+// conflicting with the active schedules of the term. The result is stored as a draft which is reviewed and committed
+// by the user who generates it. Accessing this handler needs CREATE or XCREATE ability of schedules module
+/*
+	@params:
+		year			= required, numeric
+		semester		= required, numeric
+		courses			= required, json of []{id, classes, duration, assistants: []identity_code}
+		places			= required, comma separated place id
+		days			= required, comma separated day
+		windows			= optional, json of []{start_time, end_time}, the opening hours of the places by default
+		availability	= optional, json of []{identity_code, day, start_time, end_time}
+	@example:
+		year			= 2017
+		semester		= 5
+		courses			= [{"id":"D10K-7D02","classes":2,"duration":100,"assistants":[140810140016]}]
+		places			= UDJT-102,UDJT-103
+		days			= monday,tuesday,wednesday
+		windows			= [{"start_time":420,"end_time":720},{"start_time":780,"end_time":1080}]
+		availability	= [{"identity_code":140810140016,"day":"monday","start_time":420,"end_time":720}]
+	@return
+		{id, year, semester, schedules: []{course_id, class, day, start_time, end_time, place, assistants}, unscheduled: []{course_id, class}}
+*/
+func GenerateTimetableHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleSchedule, rg.RoleCreate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := generateTimetableParams{
+		Year:         r.FormValue("year"),
+		Semester:     r.FormValue("semester"),
+		Courses:      r.FormValue("courses"),
+		Places:       r.FormValue("places"),
+		Days:         r.FormValue("days"),
+		Windows:      r.FormValue("windows"),
+		Availability: r.FormValue("availability"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	var identityCodes []int64
+	for _, val := range args.Courses {
+		for _, identityCode := range val.Assistants {
+			if !helper.Int64InSlice(identityCode, identityCodes) {
+				identityCodes = append(identityCodes, identityCode)
+			}
+		}
+	}
+	for identityCode := range args.Availability {
+		if !helper.Int64InSlice(identityCode, identityCodes) {
+			identityCodes = append(identityCodes, identityCode)
+		}
+	}
+
+	userIDs := map[int64]int64{}
+	var assistantIDs []int64
+	if len(identityCodes) > 0 {
+		users, code, err := selectRegisteredUser(identityCodes)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(code).
+				AddError(err.Error()))
+			return
+		}
+		for _, val := range users {
+			userIDs[val.IdentityCode] = val.ID
+			assistantIDs = append(assistantIDs, val.ID)
+		}
+	}
+
+	req := tt.Request{
+		Places:       args.Places,
+		Days:         args.Days,
+		Windows:      args.Windows,
+		Step:         alias.TimetableStep,
+		Availability: map[int64][]tt.Availability{},
+		BusyPlaces:   map[string]map[int8][]cs.Slot{},
+	}
+	for identityCode, val := range args.Availability {
+		req.Availability[userIDs[identityCode]] = val
+	}
+
+	for _, val := range args.Courses {
+		if !cs.IsExist(val.ID) {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError(fmt.Sprintf("Course %s is not found", val.ID)))
+			return
+		}
+
+		used, err := cs.SelectClass(val.ID, args.Semester, args.Year)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		classes := nextClasses(used, val.Classes)
+		if len(classes) < val.Classes {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusBadRequest).
+				AddError(fmt.Sprintf("Course %s doesn't have %d more classes", val.ID, val.Classes)))
+			return
+		}
+
+		var assistants []int64
+		for _, identityCode := range val.Assistants {
+			assistants = append(assistants, userIDs[identityCode])
+		}
+
+		req.Courses = append(req.Courses, tt.Course{
+			ID:         val.ID,
+			Classes:    classes,
+			Duration:   val.Duration,
+			Assistants: assistants,
+		})
+	}
+
+	for _, val := range args.Places {
+		req.BusyPlaces[val], err = cs.SelectPlaceSlot(val, args.Year, args.Semester)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	req.BusyUsers, err = cs.SelectUserSlot(assistantIDs, args.Year, args.Semester)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	draft := tt.Draft{
+		UserID:   sess.ID,
+		Year:     args.Year,
+		Semester: args.Semester,
+	}
+	draft.Schedules, draft.Unscheduled = tt.Solve(req)
+
+	draft.ID, err = tt.SaveDraft(draft)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	res, err := newDraftResponse(draft)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// ReadTimetableHandler handles the http request for reviewing the generated timetable. Only the user who generates
+// the draft can read it. Accessing this handler needs CREATE or XCREATE ability of schedules module
+/*
+	@params:
+		draft_id	= required
+	@example:
+		draft_id	= Zx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq
+	@return
+		{id, year, semester, schedules: []{course_id, class, day, start_time, end_time, place, assistants}, unscheduled: []{course_id, class}}
+*/
+func ReadTimetableHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleSchedule, rg.RoleCreate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := draftParams{
+		ID: ps.ByName("draft_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	draft, code, err := getOwnedDraft(args.ID, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	res, err := newDraftResponse(draft)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// CommitTimetableHandler handles the http request for creating the active schedules of the generated timetable. The
+// schedules are checked again since the schedules of the term may change after the draft is generated, then all of
+// them are created with their assistants in a transaction and the draft is removed. The unscheduled classes are left
+// to be created manually. Accessing this handler needs CREATE or XCREATE ability of schedules module
+/*
+	@params:
+		draft_id	= required
+	@example:
+		draft_id	= Zx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq
+	@return
+*/
+func CommitTimetableHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleSchedule, rg.RoleCreate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := draftParams{
+		ID: ps.ByName("draft_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	draft, code, err := getOwnedDraft(args.ID, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(code).
+			AddError(err.Error()))
+		return
+	}
+
+	if len(draft.Schedules) < 1 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Draft doesn't have any schedules"))
+		return
+	}
+
+	for _, val := range draft.Schedules {
+		if cs.IsExistSchedule(draft.Semester, draft.Year, val.CourseID, val.Class) {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusConflict).
+				AddError(fmt.Sprintf("Class %s of course %s already exists", val.Class, val.CourseID)))
+			return
+		}
+
+		term := cs.Term{
+			Year:     draft.Year,
+			Semester: draft.Semester,
+			Day:      val.Day,
+			Slot:     cs.Slot{StartTime: val.StartTime, EndTime: val.EndTime},
+		}
+		placeConflicts, userConflicts, err := checkConflict(val.PlaceID, term, val.Assistants, 0)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		if len(placeConflicts) > 0 {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusConflict).
+				SetData(placeConflicts).
+				AddError(fmt.Sprintf("%s is used by %s", val.PlaceID, conflictMessage(placeConflicts[0]))))
+			return
+		}
+
+		if len(userConflicts) > 0 {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusConflict).
+				SetData(userConflicts).
+				AddError(fmt.Sprintf("%d has %s", userConflicts[0].IdentityCode, conflictMessage(userConflicts[0]))))
+			return
+		}
+	}
+
+	isAssisted := false
+	var places []string
+	tx := conn.DB.MustBegin()
+	for _, val := range draft.Schedules {
+		if !helper.IsStringInSlice(val.PlaceID, places) && !pl.IsExistID(val.PlaceID) {
+			err = pl.Insert(val.PlaceID, sql.NullString{}, tx)
+			if err != nil {
+				tx.Rollback()
+				template.RenderJSONResponse(w, new(template.Response).
+					SetCode(http.StatusInternalServerError))
+				return
+			}
+		}
+		places = append(places, val.PlaceID)
+
+		scheduleID, err := cs.InsertSchedule(sess.ID,
+			int16(val.StartTime),
+			int16(val.EndTime),
+			draft.Year,
+			draft.Semester,
+			val.Day,
+			cs.StatusScheduleActive,
+			val.Class,
+			val.CourseID,
+			val.PlaceID,
+			tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		for _, userID := range val.Assistants {
+			err = cs.InsertAssistant(userID, scheduleID, tx)
+			if err != nil {
+				tx.Rollback()
+				template.RenderJSONResponse(w, new(template.Response).
+					SetCode(http.StatusInternalServerError))
+				return
+			}
+			isAssisted = true
+		}
+
+		fields := scheduleAuditFields(cs.Schedule{
+			Status:    cs.StatusScheduleActive,
+			StartTime: val.StartTime,
+			EndTime:   val.EndTime,
+			Day:       val.Day,
+			Class:     val.Class,
+			Semester:  draft.Semester,
+			Year:      draft.Year,
+			CourseID:  val.CourseID,
+			PlaceID:   val.PlaceID,
+			CreatedBy: sess.ID,
+		})
+		fields["assistants"] = val.Assistants
+		diff := audit.NewDiff(nil, fields)
+		err = audit.Insert(sess.ID, audit.ActionCreate, audit.TableSchedules, strconv.FormatInt(scheduleID, 10), diff, helper.ClientIP(r), tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	// the draft is removed before committing, so the concurrent request can't commit it twice
+	err = tt.DeleteDraft(draft.ID)
+	if err != nil {
+		tx.Rollback()
+		if err == tt.ErrDraftNotFound {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusNotFound).
+				AddError(err.Error()))
+			return
+		}
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if isAssisted {
+		go bot.RefreshAssistant()
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage(fmt.Sprintf("%d schedules have been created", len(draft.Schedules))))
+	return
+}
+
+// getOwnedDraft returns the draft of the user. The draft of another user is treated as not found. It returns the
+// http status code with the error if the draft can't be returned
+func getOwnedDraft(id string, userID int64) (tt.Draft, int, error) {
+	draft, err := tt.GetDraft(id)
+	if err != nil {
+		if err == tt.ErrDraftNotFound {
+			return draft, http.StatusNotFound, err
+		}
+		return draft, http.StatusInternalServerError, fmt.Errorf("Internal server error")
+	}
+	if draft.UserID != userID {
+		return tt.Draft{}, http.StatusNotFound, tt.ErrDraftNotFound
+	}
+	return draft, http.StatusOK, nil
+}
+
+// newDraftResponse converts the draft into the response, the assistants are shown by the identity code
+func newDraftResponse(draft tt.Draft) (draftResponse, error) {
+
+	var userIDs []int64
+	for _, val := range draft.Schedules {
+		for _, userID := range val.Assistants {
+			if !helper.Int64InSlice(userID, userIDs) {
+				userIDs = append(userIDs, userID)
+			}
+		}
+	}
+
+	users, err := user.SelectByID(userIDs, user.ColID, user.ColIdentityCode)
+	if err != nil {
+		return draftResponse{}, err
+	}
+
+	identityCodes := map[int64]int64{}
+	for _, val := range users {
+		identityCodes[val.ID] = val.IdentityCode
+	}
+
+	res := draftResponse{
+		ID:          draft.ID,
+		Year:        draft.Year,
+		Semester:    draft.Semester,
+		Schedules:   []draftScheduleResponse{},
+		Unscheduled: draft.Unscheduled,
+	}
+	for _, val := range draft.Schedules {
+		assistants := []int64{}
+		for _, userID := range val.Assistants {
+			assistants = append(assistants, identityCodes[userID])
+		}
+		res.Schedules = append(res.Schedules, draftScheduleResponse{
+			CourseID:   val.CourseID,
+			Class:      val.Class,
+			Day:        helper.IntDayToString(val.Day),
+			StartTime:  val.StartTime,
+			EndTime:    val.EndTime,
+			PlaceID:    val.PlaceID,
+			Assistants: assistants,
+		})
+	}
+	return res, nil
+}
+
+// nextClasses returns n class letters which aren't used, it returns less than n letters if the letters run out
+func nextClasses(used []string, n int) []string {
+	classes := []string{}
+	for ch := 'A'; ch <= 'Z' && len(classes) < n; ch++ {
+		if !helper.IsStringInSlice(string(ch), used) {
+			classes = append(classes, string(ch))
+		}
+	}
+	return classes
+}
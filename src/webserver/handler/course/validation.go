@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	cs "github.com/melodiez14/meiko/src/module/course"
+	tt "github.com/melodiez14/meiko/src/module/timetable"
+	"github.com/melodiez14/meiko/src/util/alias"
 	"github.com/melodiez14/meiko/src/util/helper"
 )
 
@@ -528,3 +530,339 @@ func (params readScheduleParameterParams) validate() (readScheduleParameterArgs,
 		ScheduleID: scheduleID,
 	}, nil
 }
+
+func (params enrollmentParams) validate() (enrollmentArgs, error) {
+
+	var args enrollmentArgs
+	if helper.IsEmpty(params.ScheduleID) {
+		return args, fmt.Errorf("Schedule ID cannot be empty")
+	}
+
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil || scheduleID < 1 {
+		return args, fmt.Errorf("Schedule ID must be positive numeric")
+	}
+
+	return enrollmentArgs{
+		ScheduleID: scheduleID,
+	}, nil
+}
+
+func (params decideEnrollmentParams) validate() (decideEnrollmentArgs, error) {
+
+	var args decideEnrollmentArgs
+	schedule, err := enrollmentParams{ScheduleID: params.ScheduleID}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	identityCodes, err := validateIdentityCodes(params.IdentityCodes)
+	if err != nil {
+		return args, err
+	}
+
+	return decideEnrollmentArgs{
+		ScheduleID:    schedule.ScheduleID,
+		IdentityCodes: identityCodes,
+	}, nil
+}
+
+func (params updateCapacityParams) validate() (updateCapacityArgs, error) {
+
+	var args updateCapacityArgs
+	schedule, err := enrollmentParams{ScheduleID: params.ScheduleID}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	if helper.IsEmpty(params.Capacity) {
+		return args, fmt.Errorf("Capacity cannot be empty")
+	}
+
+	capacity, err := strconv.ParseUint(params.Capacity, 10, 16)
+	if err != nil {
+		return args, fmt.Errorf("Capacity must be numeric")
+	}
+	if capacity > alias.CourseCapacityMax {
+		return args, fmt.Errorf("Capacity cannot be more than %d", alias.CourseCapacityMax)
+	}
+
+	return updateCapacityArgs{
+		ScheduleID: schedule.ScheduleID,
+		Capacity:   uint16(capacity),
+	}, nil
+}
+
+func (params assistantParams) validate() (assistantArgs, error) {
+
+	var args assistantArgs
+	schedule, err := enrollmentParams{ScheduleID: params.ScheduleID}.validate()
+	if err != nil {
+		return args, err
+	}
+
+	identityCodes, err := validateIdentityCodes(params.IdentityCodes)
+	if err != nil {
+		return args, err
+	}
+
+	return assistantArgs{
+		ScheduleID:    schedule.ScheduleID,
+		IdentityCodes: identityCodes,
+	}, nil
+}
+
+func (params readAssistantParams) validate() (readAssistantArgs, error) {
+
+	var args readAssistantArgs
+	if helper.IsEmpty(params.IdentityCode) {
+		return args, nil
+	}
+
+	identityCode, err := helper.NormalizeIdentity(params.IdentityCode)
+	if err != nil {
+		return args, fmt.Errorf("Identity code is invalid")
+	}
+
+	return readAssistantArgs{
+		IdentityCode: identityCode,
+	}, nil
+}
+
+// validateIdentityCodes parses the comma separated identity codes, the duplicated identity codes are removed
+func validateIdentityCodes(str string) ([]int64, error) {
+
+	identityCodes := []int64{}
+	for _, val := range strings.Split(str, ",") {
+		val = helper.Trim(val)
+		if helper.IsEmpty(val) {
+			continue
+		}
+		identityCode, err := helper.NormalizeIdentity(val)
+		if err != nil {
+			return nil, fmt.Errorf("Identity code %s is invalid", val)
+		}
+		if !helper.Int64InSlice(identityCode, identityCodes) {
+			identityCodes = append(identityCodes, identityCode)
+		}
+	}
+	if len(identityCodes) < 1 {
+		return nil, fmt.Errorf("Identity code cannot be empty")
+	}
+	if len(identityCodes) > alias.CourseEnrollmentMax {
+		return nil, fmt.Errorf("Identity code cannot be more than %d", alias.CourseEnrollmentMax)
+	}
+	return identityCodes, nil
+}
+
+func (params generateTimetableParams) validate() (generateTimetableArgs, error) {
+
+	var args generateTimetableArgs
+
+	// Semester validation
+	if helper.IsEmpty(params.Semester) {
+		return args, fmt.Errorf("Semester can't be empty")
+	}
+	semester, err := strconv.ParseInt(params.Semester, 10, 8)
+	if err != nil {
+		return args, fmt.Errorf("Semester must be numeric")
+	}
+	if semester < 1 || semester > 7 {
+		return args, fmt.Errorf("Invalid semester")
+	}
+
+	// Year validation
+	if helper.IsEmpty(params.Year) {
+		return args, fmt.Errorf("Year can't be empty")
+	}
+	year, err := strconv.ParseInt(params.Year, 10, 16)
+	if err != nil {
+		return args, fmt.Errorf("Year must be numeric")
+	}
+	if year < 2017 || year > 2020 {
+		return args, fmt.Errorf("Invalid year")
+	}
+
+	// Courses validation
+	if helper.IsEmpty(params.Courses) {
+		return args, fmt.Errorf("Courses can't be empty")
+	}
+	var courses []timetableCourseParams
+	err = json.Unmarshal([]byte(params.Courses), &courses)
+	if err != nil {
+		return args, fmt.Errorf("Invalid courses")
+	}
+	if len(courses) < 1 {
+		return args, fmt.Errorf("Courses can't be empty")
+	}
+	if len(courses) > alias.TimetableCourseMax {
+		return args, fmt.Errorf("Courses cannot be more than %d", alias.TimetableCourseMax)
+	}
+
+	var courseIDs []string
+	var courseArgs []timetableCourseArgs
+	for _, val := range courses {
+		id := html.EscapeString(helper.Trim(val.ID))
+		if helper.IsEmpty(id) {
+			return args, fmt.Errorf("course_id cannot be empty")
+		}
+		if len(id) > cs.MaximumID {
+			return args, fmt.Errorf("course_id can have only maximum 45 characters length")
+		}
+		if helper.IsStringInSlice(id, courseIDs) {
+			return args, fmt.Errorf("Course %s is duplicated", id)
+		}
+		courseIDs = append(courseIDs, id)
+
+		if val.Classes < 1 || val.Classes > 26 {
+			return args, fmt.Errorf("Invalid classes of course %s", id)
+		}
+		if val.Duration < 1 || val.Duration >= 1440 {
+			return args, fmt.Errorf("Invalid duration of course %s", id)
+		}
+
+		var assistants []int64
+		for _, identityCode := range val.Assistants {
+			_, err := helper.NormalizeIdentity(strconv.FormatInt(identityCode, 10))
+			if err != nil {
+				return args, fmt.Errorf("Identity code %d is invalid", identityCode)
+			}
+			if !helper.Int64InSlice(identityCode, assistants) {
+				assistants = append(assistants, identityCode)
+			}
+		}
+
+		courseArgs = append(courseArgs, timetableCourseArgs{
+			ID:         id,
+			Classes:    val.Classes,
+			Duration:   uint16(val.Duration),
+			Assistants: assistants,
+		})
+	}
+
+	// Places validation
+	var places []string
+	for _, val := range strings.Split(params.Places, ",") {
+		val = html.EscapeString(strings.ToUpper(helper.Trim(val)))
+		if helper.IsEmpty(val) {
+			continue
+		}
+		if len(val) > 30 {
+			return args, fmt.Errorf("Invalid place id")
+		}
+		if !helper.IsStringInSlice(val, places) {
+			places = append(places, val)
+		}
+	}
+	if len(places) < 1 {
+		return args, fmt.Errorf("Places can't be empty")
+	}
+	if len(places) > alias.TimetablePlaceMax {
+		return args, fmt.Errorf("Places cannot be more than %d", alias.TimetablePlaceMax)
+	}
+
+	// Days validation
+	var days []int8
+	for _, val := range strings.Split(params.Days, ",") {
+		val = strings.ToLower(helper.Trim(val))
+		if helper.IsEmpty(val) {
+			continue
+		}
+		day, err := helper.DayStringToInt(val)
+		if err != nil {
+			return args, err
+		}
+		if !helper.Int8InSlice(day, days) {
+			days = append(days, day)
+		}
+	}
+	if len(days) < 1 {
+		return args, fmt.Errorf("Days can't be empty")
+	}
+
+	// Windows validation, the opening hours of the places are used by default
+	windows := []cs.Slot{{StartTime: alias.PlaceOpenTime, EndTime: alias.PlaceCloseTime}}
+	if !helper.IsEmpty(params.Windows) {
+		var slots []timetableSlotParams
+		err = json.Unmarshal([]byte(params.Windows), &slots)
+		if err != nil || len(slots) < 1 {
+			return args, fmt.Errorf("Invalid windows")
+		}
+		windows = []cs.Slot{}
+		for _, val := range slots {
+			if val.StartTime < 0 || val.EndTime >= 1440 || val.StartTime >= val.EndTime {
+				return args, fmt.Errorf("Invalid windows")
+			}
+			windows = append(windows, cs.Slot{StartTime: uint16(val.StartTime), EndTime: uint16(val.EndTime)})
+		}
+	}
+
+	// Availability validation
+	availability := map[int64][]tt.Availability{}
+	if !helper.IsEmpty(params.Availability) {
+		var slots []timetableSlotParams
+		err = json.Unmarshal([]byte(params.Availability), &slots)
+		if err != nil {
+			return args, fmt.Errorf("Invalid availability")
+		}
+		for _, val := range slots {
+			_, err := helper.NormalizeIdentity(strconv.FormatInt(val.IdentityCode, 10))
+			if err != nil {
+				return args, fmt.Errorf("Identity code %d is invalid", val.IdentityCode)
+			}
+			day, err := helper.DayStringToInt(val.Day)
+			if err != nil {
+				return args, err
+			}
+			if val.StartTime < 0 || val.EndTime >= 1440 || val.StartTime >= val.EndTime {
+				return args, fmt.Errorf("Invalid availability of %d", val.IdentityCode)
+			}
+			availability[val.IdentityCode] = append(availability[val.IdentityCode], tt.Availability{
+				Day:  day,
+				Slot: cs.Slot{StartTime: uint16(val.StartTime), EndTime: uint16(val.EndTime)},
+			})
+		}
+	}
+
+	args = generateTimetableArgs{
+		Year:         int16(year),
+		Semester:     int8(semester),
+		Courses:      courseArgs,
+		Places:       places,
+		Days:         days,
+		Windows:      windows,
+		Availability: availability,
+	}
+	return args, nil
+}
+
+func (params draftParams) validate() (draftArgs, error) {
+
+	var args draftArgs
+	params = draftParams{
+		ID: helper.Trim(params.ID),
+	}
+
+	if helper.IsEmpty(params.ID) {
+		return args, fmt.Errorf("Draft id can't be empty")
+	}
+
+	args = draftArgs{
+		ID: params.ID,
+	}
+	return args, nil
+}
+
+func (params publicCalendarParams) validate() (publicCalendarArgs, error) {
+	var args publicCalendarArgs
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("schedule id must be numeric")
+	}
+	if scheduleID < 1 {
+		return args, fmt.Errorf("Invalid schedule id")
+	}
+	return publicCalendarArgs{
+		ScheduleID: scheduleID,
+	}, nil
+}
@@ -4,6 +4,9 @@ import (
 	"database/sql"
 	"reflect"
 	"testing"
+
+	cs "github.com/melodiez14/meiko/src/module/course"
+	"github.com/melodiez14/meiko/src/module/timetable"
 )
 
 func Test_createParams_validate(t *testing.T) {
@@ -582,3 +585,317 @@ func Test_createParams_validate(t *testing.T) {
 		})
 	}
 }
+
+func Test_decideEnrollmentParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  decideEnrollmentParams
+		want    decideEnrollmentArgs
+		wantErr bool
+	}{
+		{
+			name:    "Test Case 1",
+			params:  decideEnrollmentParams{},
+			want:    decideEnrollmentArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 2",
+			params: decideEnrollmentParams{
+				ScheduleID:    "abc",
+				IdentityCodes: "140810140016",
+			},
+			want:    decideEnrollmentArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 3",
+			params: decideEnrollmentParams{
+				ScheduleID:    "149",
+				IdentityCodes: " , ",
+			},
+			want:    decideEnrollmentArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 4",
+			params: decideEnrollmentParams{
+				ScheduleID:    "149",
+				IdentityCodes: "140810140016,abc",
+			},
+			want:    decideEnrollmentArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 5",
+			params: decideEnrollmentParams{
+				ScheduleID:    "149",
+				IdentityCodes: "140810140016, 140810140060,140810140016",
+			},
+			want: decideEnrollmentArgs{
+				ScheduleID:    149,
+				IdentityCodes: []int64{140810140016, 140810140060},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decideEnrollmentParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decideEnrollmentParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_updateCapacityParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  updateCapacityParams
+		want    updateCapacityArgs
+		wantErr bool
+	}{
+		{
+			name:    "Test Case 1",
+			params:  updateCapacityParams{ScheduleID: "149"},
+			want:    updateCapacityArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 2",
+			params:  updateCapacityParams{ScheduleID: "149", Capacity: "-1"},
+			want:    updateCapacityArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 3",
+			params:  updateCapacityParams{ScheduleID: "149", Capacity: "501"},
+			want:    updateCapacityArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 4",
+			params:  updateCapacityParams{ScheduleID: "149", Capacity: "0"},
+			want:    updateCapacityArgs{ScheduleID: 149, Capacity: 0},
+			wantErr: false,
+		},
+		{
+			name:    "Test Case 5",
+			params:  updateCapacityParams{ScheduleID: "0", Capacity: "40"},
+			want:    updateCapacityArgs{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("updateCapacityParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("updateCapacityParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_readAssistantParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  readAssistantParams
+		want    readAssistantArgs
+		wantErr bool
+	}{
+		{
+			name:    "Test Case 1",
+			params:  readAssistantParams{},
+			want:    readAssistantArgs{},
+			wantErr: false,
+		},
+		{
+			name:    "Test Case 2",
+			params:  readAssistantParams{IdentityCode: "abc"},
+			want:    readAssistantArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 3",
+			params:  readAssistantParams{IdentityCode: "140810140016"},
+			want:    readAssistantArgs{IdentityCode: 140810140016},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readAssistantParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readAssistantParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_generateTimetableParams_validate(t *testing.T) {
+	valid := generateTimetableParams{
+		Year:     "2017",
+		Semester: "5",
+		Courses:  `[{"id":"d10k-7d02","classes":2,"duration":100,"assistants":[140810140016,140810140016]}]`,
+		Places:   "udjt-102, UDJT-103,udjt-102",
+		Days:     "Monday,tuesday",
+	}
+
+	withWindows := valid
+	withWindows.Windows = `[{"start_time":420,"end_time":720}]`
+	withWindows.Availability = `[{"identity_code":140810140016,"day":"monday","start_time":420,"end_time":600}]`
+
+	invalidWindows := valid
+	invalidWindows.Windows = `[{"start_time":720,"end_time":420}]`
+
+	duplicatedCourse := valid
+	duplicatedCourse.Courses = `[{"id":"D10K-7D02","classes":1,"duration":100},{"id":"D10K-7D02","classes":1,"duration":100}]`
+
+	invalidClasses := valid
+	invalidClasses.Courses = `[{"id":"D10K-7D02","classes":27,"duration":100}]`
+
+	invalidDay := valid
+	invalidDay.Days = "monday,someday"
+
+	emptyPlaces := valid
+	emptyPlaces.Places = " , "
+
+	courses := []timetableCourseArgs{{ID: "d10k-7d02", Classes: 2, Duration: 100, Assistants: []int64{140810140016}}}
+	tests := []struct {
+		name    string
+		params  generateTimetableParams
+		want    generateTimetableArgs
+		wantErr bool
+	}{
+		{
+			name:   "Test Case 1",
+			params: valid,
+			want: generateTimetableArgs{
+				Year:         2017,
+				Semester:     5,
+				Courses:      courses,
+				Places:       []string{"UDJT-102", "UDJT-103"},
+				Days:         []int8{1, 2},
+				Windows:      []cs.Slot{{StartTime: 420, EndTime: 1080}},
+				Availability: map[int64][]timetable.Availability{},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "Test Case 2",
+			params: withWindows,
+			want: generateTimetableArgs{
+				Year:     2017,
+				Semester: 5,
+				Courses:  courses,
+				Places:   []string{"UDJT-102", "UDJT-103"},
+				Days:     []int8{1, 2},
+				Windows:  []cs.Slot{{StartTime: 420, EndTime: 720}},
+				Availability: map[int64][]timetable.Availability{
+					140810140016: {{Day: 1, Slot: cs.Slot{StartTime: 420, EndTime: 600}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Test Case 3",
+			params:  invalidWindows,
+			want:    generateTimetableArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 4",
+			params:  duplicatedCourse,
+			want:    generateTimetableArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 5",
+			params:  invalidClasses,
+			want:    generateTimetableArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 6",
+			params:  invalidDay,
+			want:    generateTimetableArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 7",
+			params:  emptyPlaces,
+			want:    generateTimetableArgs{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("generateTimetableParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("generateTimetableParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_nextClasses(t *testing.T) {
+	tests := []struct {
+		name string
+		used []string
+		n    int
+		want []string
+	}{
+		{name: "Test Case 1", used: []string{}, n: 2, want: []string{"A", "B"}},
+		{name: "Test Case 2", used: []string{"A", "C"}, n: 2, want: []string{"B", "D"}},
+		{name: "Test Case 3", used: []string{"A", "B", "C"}, n: 24, want: []string{"D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextClasses(tt.used, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nextClasses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_publicCalendarParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  publicCalendarParams
+		want    publicCalendarArgs
+		wantErr bool
+	}{
+		{name: "Test Case 1", params: publicCalendarParams{ScheduleID: "149"}, want: publicCalendarArgs{ScheduleID: 149}, wantErr: false},
+		{name: "Test Case 2", params: publicCalendarParams{ScheduleID: "0"}, want: publicCalendarArgs{}, wantErr: true},
+		{name: "Test Case 3", params: publicCalendarParams{ScheduleID: "abc"}, want: publicCalendarArgs{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("publicCalendarParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("publicCalendarParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
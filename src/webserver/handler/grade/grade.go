@@ -0,0 +1,234 @@
+package grade
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	cs "github.com/melodiez14/meiko/src/module/course"
+	gd "github.com/melodiez14/meiko/src/module/grade"
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/module/user"
+	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/webserver/template"
+)
+
+// GetHandler handles the http request return the final score and the letter grade of the current courses
+/*
+	@params:
+	@example:
+	@return
+		[]{schedule_id, course, class, score, grade}
+*/
+func GetHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	scheduleID, err := cs.SelectScheduleIDByUserID(sess.ID, cs.PStatusStudent)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	courses, err := cs.SelectByScheduleID(scheduleID, cs.StatusScheduleActive)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	grades, err := gd.SelectByUserID(sess.ID, scheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	score := map[int64]float32{}
+	for _, val := range grades {
+		score[val.ScheduleID] = val.Score
+	}
+
+	res := []getResponse{}
+	for _, val := range courses {
+		s := score[val.Schedule.ID]
+		res = append(res, getResponse{
+			ScheduleID: val.Schedule.ID,
+			Course:     val.Course.Name,
+			Class:      val.Schedule.Class,
+			Score:      s,
+			Grade:      gd.Letter(s),
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// GetDetailHandler handles the http request return the final score and the score of each grade parameter of the enrolled schedule
+/*
+	@params:
+		schedule_id	= required, positive numeric
+	@example:
+		schedule_id	= 12
+	@return
+		{schedule_id, course, class, score, grade, components: []{type, percentage, score, is_graded}}
+*/
+func GetDetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := getDetailParams{
+		ScheduleID: ps.ByName("schedule_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !cs.IsStudent(sess.ID, args.ScheduleID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You are not enrolled in this course"))
+		return
+	}
+
+	course, err := cs.GetByScheduleID(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	components, err := gd.SelectComponentByUserID(args.ScheduleID, sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	score := gd.Calculate(components)
+	res := getDetailResponse{
+		ScheduleID: args.ScheduleID,
+		Course:     course.Course.Name,
+		Class:      course.Schedule.Class,
+		Score:      score,
+		Grade:      gd.Letter(score),
+		Components: []componentResponse{},
+	}
+	for _, val := range components {
+		res.Components = append(res.Components, componentResponse{
+			Type:       val.Type,
+			Percentage: val.Percentage,
+			Score:      val.Score,
+			IsGraded:   val.IsGraded,
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// ReadHandler handles the http request return the final score and the letter grade of every student in the schedule.
+// Accessing this handler needs READ or XREAD ability of assignments module
+/*
+	@params:
+		schedule_id	= required, positive numeric
+	@example:
+		schedule_id	= 12
+	@return
+		[]{id, name, score, grade}
+*/
+func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleAssignment, rg.RoleRead) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := readParams{
+		ScheduleID: r.FormValue("schedule_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	owners, err := cs.SelectOwnerID(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if !sess.Authorize(rg.ModuleAssignment, rg.RoleRead, owners...) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	userIDs, err := cs.SelectStudentID(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	res := []readResponse{}
+	if len(userIDs) < 1 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusOK).
+			SetData(res))
+		return
+	}
+
+	students, err := user.SelectByID(userIDs, user.ColID, user.ColName, user.ColIdentityCode)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	grades, err := gd.SelectByScheduleID(args.ScheduleID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	score := map[int64]float32{}
+	for _, val := range grades {
+		score[val.UserID] = val.Score
+	}
+
+	for _, val := range students {
+		s := score[val.ID]
+		res = append(res, readResponse{
+			ID:    val.IdentityCode,
+			Name:  val.Name,
+			Score: s,
+			Grade: gd.Letter(s),
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
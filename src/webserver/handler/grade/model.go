@@ -0,0 +1,48 @@
+package grade
+
+type getResponse struct {
+	ScheduleID int64   `json:"schedule_id"`
+	Course     string  `json:"course"`
+	Class      string  `json:"class"`
+	Score      float32 `json:"score"`
+	Grade      string  `json:"grade"`
+}
+
+type getDetailParams struct {
+	ScheduleID string
+}
+
+type getDetailArgs struct {
+	ScheduleID int64
+}
+
+type getDetailResponse struct {
+	ScheduleID int64               `json:"schedule_id"`
+	Course     string              `json:"course"`
+	Class      string              `json:"class"`
+	Score      float32             `json:"score"`
+	Grade      string              `json:"grade"`
+	Components []componentResponse `json:"components"`
+}
+
+type componentResponse struct {
+	Type       string  `json:"type"`
+	Percentage float32 `json:"percentage"`
+	Score      float32 `json:"score"`
+	IsGraded   bool    `json:"is_graded"`
+}
+
+type readParams struct {
+	ScheduleID string
+}
+
+type readArgs struct {
+	ScheduleID int64
+}
+
+type readResponse struct {
+	ID    int64   `json:"id"`
+	Name  string  `json:"name"`
+	Score float32 `json:"score"`
+	Grade string  `json:"grade"`
+}
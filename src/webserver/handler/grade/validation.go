@@ -0,0 +1,44 @@
+package grade
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/melodiez14/meiko/src/util/helper"
+)
+
+func (params getDetailParams) validate() (getDetailArgs, error) {
+
+	var args getDetailArgs
+	if helper.IsEmpty(params.ScheduleID) {
+		return args, fmt.Errorf("schedule_id can't be empty")
+	}
+
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("schedule_id must be numeric")
+	}
+
+	args = getDetailArgs{
+		ScheduleID: scheduleID,
+	}
+	return args, nil
+}
+
+func (params readParams) validate() (readArgs, error) {
+
+	var args readArgs
+	if helper.IsEmpty(params.ScheduleID) {
+		return args, fmt.Errorf("schedule_id can't be empty")
+	}
+
+	scheduleID, err := strconv.ParseInt(params.ScheduleID, 10, 64)
+	if err != nil {
+		return args, fmt.Errorf("schedule_id must be numeric")
+	}
+
+	args = readArgs{
+		ScheduleID: scheduleID,
+	}
+	return args, nil
+}
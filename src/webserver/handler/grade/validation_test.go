@@ -0,0 +1,90 @@
+package grade
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_getDetailParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  getDetailParams
+		want    getDetailArgs
+		wantErr bool
+	}{
+		{
+			name:    "All empty",
+			params:  getDetailParams{},
+			want:    getDetailArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Invalid schedule",
+			params: getDetailParams{
+				ScheduleID: "abc",
+			},
+			want:    getDetailArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Valid",
+			params: getDetailParams{
+				ScheduleID: "12",
+			},
+			want: getDetailArgs{
+				ScheduleID: 12,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getDetailParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getDetailParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_readParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  readParams
+		want    readArgs
+		wantErr bool
+	}{
+		{
+			name:    "All empty",
+			params:  readParams{},
+			want:    readArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Valid",
+			params: readParams{
+				ScheduleID: "12",
+			},
+			want: readArgs{
+				ScheduleID: 12,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
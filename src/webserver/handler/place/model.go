@@ -11,3 +11,25 @@ type searchArgs struct {
 type searchResponse struct {
 	ID []string `json:"places"`
 }
+
+type readSlotParams struct {
+	PlaceID  string
+	Year     string
+	Semester string
+}
+
+type readSlotArgs struct {
+	PlaceID  string
+	Year     int16
+	Semester int8
+}
+
+type readSlotResponse struct {
+	Day   string         `json:"day"`
+	Slots []slotResponse `json:"slots"`
+}
+
+type slotResponse struct {
+	StartTime uint16 `json:"start_time"`
+	EndTime   uint16 `json:"end_time"`
+}
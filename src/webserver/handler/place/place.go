@@ -2,9 +2,15 @@ package place
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
+	cs "github.com/melodiez14/meiko/src/module/course"
 	pl "github.com/melodiez14/meiko/src/module/place"
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/util/alias"
+	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/util/helper"
 	"github.com/melodiez14/meiko/src/webserver/template"
 )
 
@@ -46,3 +52,77 @@ func SearchHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 	return
 
 }
+
+// ReadSlotHandler handles the http request for listing the free slots of the place from monday to saturday in the
+// semester, the slots are counted between alias.PlaceOpenTime and alias.PlaceCloseTime. Accessing this handler needs
+// READ or XREAD ability of schedules module
+/*
+	@params:
+		place_id	= required
+		year		= required, positive numeric
+		semester	= required, positive numeric
+	@example:
+		place_id	= UDJT-102
+		year		= 2017
+		semester	= 1
+	@return
+		[]{day, slots: []{start_time, end_time}}
+*/
+func ReadSlotHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleSchedule, rg.RoleRead) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := readSlotParams{
+		PlaceID:  ps.ByName("place_id"),
+		Year:     r.FormValue("year"),
+		Semester: r.FormValue("semester"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !pl.IsExistID(args.PlaceID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Place is not found"))
+		return
+	}
+
+	busy, err := cs.SelectPlaceSlot(args.PlaceID, args.Year, args.Semester)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	res := []readSlotResponse{}
+	for day := time.Monday; day <= time.Saturday; day++ {
+		slots := []slotResponse{}
+		for _, val := range cs.FreeSlots(busy[int8(day)], alias.PlaceOpenTime, alias.PlaceCloseTime) {
+			slots = append(slots, slotResponse{
+				StartTime: val.StartTime,
+				EndTime:   val.EndTime,
+			})
+		}
+		res = append(res, readSlotResponse{
+			Day:   helper.IntDayToString(int8(day)),
+			Slots: slots,
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
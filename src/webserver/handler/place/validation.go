@@ -1,7 +1,12 @@
 package place
 
 import (
+	"fmt"
 	"html"
+	"strconv"
+	"strings"
+
+	"github.com/melodiez14/meiko/src/util/helper"
 )
 
 func (params searchParams) Validate() (searchArgs, error) {
@@ -9,3 +14,34 @@ func (params searchParams) Validate() (searchArgs, error) {
 		Query: html.EscapeString(params.Query),
 	}, nil
 }
+
+func (params readSlotParams) validate() (readSlotArgs, error) {
+
+	var args readSlotArgs
+	placeID := html.EscapeString(strings.ToUpper(helper.Trim(params.PlaceID)))
+	if helper.IsEmpty(placeID) {
+		return args, fmt.Errorf("Place cannot be empty")
+	}
+
+	if helper.IsEmpty(params.Year) {
+		return args, fmt.Errorf("Year cannot be empty")
+	}
+	year, err := strconv.ParseInt(params.Year, 10, 16)
+	if err != nil || year < 1 {
+		return args, fmt.Errorf("Year must be positive numeric")
+	}
+
+	if helper.IsEmpty(params.Semester) {
+		return args, fmt.Errorf("Semester cannot be empty")
+	}
+	semester, err := strconv.ParseInt(params.Semester, 10, 8)
+	if err != nil || semester < 1 {
+		return args, fmt.Errorf("Semester must be positive numeric")
+	}
+
+	return readSlotArgs{
+		PlaceID:  placeID,
+		Year:     int16(year),
+		Semester: int8(semester),
+	}, nil
+}
@@ -4,3 +4,107 @@ type getPrivilegeResponse struct {
 	IsLoggedIn bool                `json:"is_logged_in"`
 	Modules    map[string][]string `json:"modules"`
 }
+
+type updateTwoFactorParams struct {
+	ID         string
+	IsRequired string
+}
+
+type updateTwoFactorArgs struct {
+	ID         int64
+	IsRequired bool
+}
+
+type readParams struct {
+	Page  string
+	Total string
+}
+
+type readArgs struct {
+	Page  uint16
+	Total uint16
+}
+
+type readResponse struct {
+	ID                  int64  `json:"id"`
+	Name                string `json:"name"`
+	IsTwoFactorRequired bool   `json:"is_2fa_required"`
+}
+
+type detailParams struct {
+	ID string
+}
+
+type detailArgs struct {
+	ID int64
+}
+
+type detailResponse struct {
+	ID                  int64               `json:"id"`
+	Name                string              `json:"name"`
+	IsTwoFactorRequired bool                `json:"is_2fa_required"`
+	Modules             map[string][]string `json:"modules"`
+	TotalUser           int                 `json:"total_user"`
+}
+
+type createParams struct {
+	Name string
+}
+
+type createArgs struct {
+	Name string
+}
+
+type createResponse struct {
+	ID int64 `json:"id"`
+}
+
+type updateParams struct {
+	ID   string
+	Name string
+}
+
+type updateArgs struct {
+	ID   int64
+	Name string
+}
+
+type privilegeParams struct {
+	ID        string
+	Module    string
+	Abilities string
+}
+
+type privilegeArgs struct {
+	ID        int64
+	Module    string
+	Abilities []string
+}
+
+type readUserParams struct {
+	ID    string
+	Page  string
+	Total string
+}
+
+type readUserArgs struct {
+	ID    int64
+	Page  uint16
+	Total uint16
+}
+
+type readUserResponse struct {
+	IdentityCode int64  `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+}
+
+type assignUserParams struct {
+	ID            string
+	IdentityCodes string
+}
+
+type assignUserArgs struct {
+	ID            int64
+	IdentityCodes []int64
+}
@@ -1,11 +1,18 @@
 package rolegroup
 
 import (
+	"database/sql"
+	"log"
 	"net/http"
+	"strconv"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/julienschmidt/httprouter"
+	"github.com/melodiez14/meiko/src/module/audit"
 	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/module/user"
 	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/util/conn"
 	"github.com/melodiez14/meiko/src/util/helper"
 	"github.com/melodiez14/meiko/src/webserver/template"
 )
@@ -33,29 +40,154 @@ func GetPrivilege(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 	}
 
 	// set response data
-	var role string
-	roles := map[string][]string{}
-	for i, val := range sess.Roles {
-		for _, v := range val {
-			switch v {
-			case rg.RoleCreate, rg.RoleXCreate:
-				role = "CREATE"
-			case rg.RoleRead, rg.RoleXRead:
-				role = "READ"
-			case rg.RoleUpdate, rg.RoleXUpdate:
-				role = "UPDATE"
-			case rg.RoleDelete, rg.RoleXDelete:
-				role = "DELETE"
-			}
-			if !helper.IsStringInSlice(role, roles[i]) {
-				roles[i] = append(roles[i], role)
-			}
-		}
-	}
-
 	res = getPrivilegeResponse{
 		IsLoggedIn: true,
-		Modules:    roles,
+		Modules:    sess.Abilities(),
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// UpdateTwoFactorHandler handles the http request for making the two factor mandatory or optional for the members
+// of the rolegroup. The members without the two factor must enroll on their next sign in.
+// Accessing this handler needs XUPDATE ability of roles module
+/*
+	@params:
+		id			= required, positive numeric
+		is_required	= required, true or false
+	@example:
+		id			= 2
+		is_required	= true
+	@return
+*/
+func UpdateTwoFactorHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.Authorize(rg.ModuleRole, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := updateTwoFactorParams{
+		ID:         ps.ByName("id"),
+		IsRequired: r.FormValue("is_required"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	rolegroup, err := rg.Get(args.ID)
+	if err == sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Rolegroup not found"))
+		return
+	}
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if rolegroup.IsTwoFactorRequired == args.IsRequired {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusOK).
+			SetMessage("Two factor requirement has been updated"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = rg.UpdateTwoFactorRequired(args.ID, args.IsRequired, tx)
+	if err != nil && err != conn.ErrNoRowsAffected {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	diff := audit.NewDiff(map[string]interface{}{"is_2fa_required": rolegroup.IsTwoFactorRequired},
+		map[string]interface{}{"is_2fa_required": args.IsRequired})
+	err = audit.Insert(sess.ID, audit.ActionUpdate, audit.TableRoleGroups, strconv.FormatInt(args.ID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Two factor requirement has been updated"))
+	return
+}
+
+// ReadHandler handles the http request for listing the rolegroups.
+// Accessing this handler needs XREAD ability of roles module
+/*
+	@params:
+		pg	= required, positive numeric
+		ttl	= required, positive numeric
+	@example:
+		pg	= 1
+		ttl	= 10
+	@return
+		[]{id, name, is_2fa_required}
+*/
+func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.Authorize(rg.ModuleRole, rg.RoleRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := readParams{
+		Page:  r.FormValue("pg"),
+		Total: r.FormValue("ttl"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	offset := (args.Page - 1) * args.Total
+	rolegroups, err := rg.SelectByPage(args.Total, offset)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	res := []readResponse{}
+	for _, val := range rolegroups {
+		res = append(res, readResponse{
+			ID:                  val.ID,
+			Name:                val.Name,
+			IsTwoFactorRequired: val.IsTwoFactorRequired,
+		})
 	}
 
 	template.RenderJSONResponse(w, new(template.Response).
@@ -63,3 +195,797 @@ func GetPrivilege(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		SetData(res))
 	return
 }
+
+// DetailHandler handles the http request for getting the rolegroup with its privileges.
+// Accessing this handler needs XREAD ability of roles module
+/*
+	@params:
+		id	= required, positive numeric
+	@example:
+		id	= 2
+	@return
+		{id, name, is_2fa_required, modules, total_user}
+*/
+func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.Authorize(rg.ModuleRole, rg.RoleRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := detailParams{
+		ID: ps.ByName("id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	rolegroup, err := rg.Get(args.ID)
+	if err == sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Rolegroup not found"))
+		return
+	}
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	total, err := user.CountByRoleGroup(args.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	modules, err := rg.GetModuleAccess(rolegroup.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	res := detailResponse{
+		ID:                  rolegroup.ID,
+		Name:                rolegroup.Name,
+		IsTwoFactorRequired: rolegroup.IsTwoFactorRequired,
+		Modules:             modules,
+		TotalUser:           total,
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// CreateHandler handles the http request for creating the rolegroup without any privilege.
+// Accessing this handler needs XCREATE ability of roles module
+/*
+	@params:
+		name	= required, alphanumeric and space, characters<=15
+	@example:
+		name	= Lecturer
+	@return
+		{id}
+*/
+func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.Authorize(rg.ModuleRole, rg.RoleCreate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := createParams{
+		Name: r.FormValue("name"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if rg.IsNameExist(args.Name, 0) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Rolegroup name has been used"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	id, err := rg.Insert(args.Name, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	diff := audit.NewDiff(nil, map[string]interface{}{"name": args.Name})
+	err = audit.Insert(sess.ID, audit.ActionCreate, audit.TableRoleGroups, strconv.FormatInt(id, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(createResponse{ID: id}).
+		SetMessage("Rolegroup has been created"))
+	return
+}
+
+// UpdateHandler handles the http request for renaming the rolegroup.
+// Accessing this handler needs XUPDATE ability of roles module
+/*
+	@params:
+		id		= required, positive numeric
+		name	= required, alphanumeric and space, characters<=15
+	@example:
+		id		= 2
+		name	= Lecturer
+	@return
+*/
+func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.Authorize(rg.ModuleRole, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := updateParams{
+		ID:   ps.ByName("id"),
+		Name: r.FormValue("name"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	rolegroup, err := rg.Get(args.ID)
+	if err == sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Rolegroup not found"))
+		return
+	}
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if rg.IsNameExist(args.Name, args.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Rolegroup name has been used"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = rg.Update(args.ID, args.Name, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	diff := audit.NewDiff(map[string]interface{}{"name": rolegroup.Name}, map[string]interface{}{"name": args.Name})
+	err = audit.Insert(sess.ID, audit.ActionUpdate, audit.TableRoleGroups, strconv.FormatInt(args.ID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Rolegroup has been updated"))
+	return
+}
+
+// DeleteHandler handles the http request for deleting the rolegroup and its privileges. The rolegroup
+// which still has members can't be deleted. Accessing this handler needs XDELETE ability of roles module
+/*
+	@params:
+		id	= required, positive numeric
+	@example:
+		id	= 3
+	@return
+*/
+func DeleteHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.Authorize(rg.ModuleRole, rg.RoleDelete) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := detailParams{
+		ID: ps.ByName("id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	total, err := user.CountByRoleGroup(args.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if total > 0 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Rolegroup still has members"))
+		return
+	}
+
+	rolegroup, err := rg.Get(args.ID)
+	if err == sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Rolegroup not found"))
+		return
+	}
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	modules, err := rg.GetModuleAccess(args.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = rg.Delete(args.ID, tx)
+	if err == conn.ErrNoRowsAffected {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Rolegroup not found"))
+		return
+	}
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	diff := audit.NewDiff(map[string]interface{}{
+		"name":            rolegroup.Name,
+		"is_2fa_required": rolegroup.IsTwoFactorRequired,
+		"modules":         modules,
+	}, nil)
+	err = audit.Insert(sess.ID, audit.ActionDelete, audit.TableRoleGroups, strconv.FormatInt(args.ID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Rolegroup has been deleted"))
+	return
+}
+
+// GrantHandler handles the http request for granting the abilities of the module to the rolegroup. The abilities
+// which aren't owned by the logged in user can't be granted. The signed in members get the new abilities without
+// signing in again. Accessing this handler needs XUPDATE ability of roles module
+/*
+	@params:
+		id			= required, positive numeric
+		module		= required, one of the modules
+		abilities	= required, comma separated abilities
+	@example:
+		id			= 2
+		module		= courses
+		abilities	= XREAD,XUPDATE
+	@return
+*/
+func GrantHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	updatePrivilege(w, r, ps, true)
+}
+
+// RevokeHandler handles the http request for revoking the abilities of the module from the rolegroup. The abilities
+// are also revoked from the active sessions of the members. Accessing this handler needs XUPDATE ability of roles module
+/*
+	@params:
+		id			= required, positive numeric
+		module		= required, one of the modules
+		abilities	= required, comma separated abilities
+	@example:
+		id			= 2
+		module		= courses
+		abilities	= XUPDATE
+	@return
+*/
+func RevokeHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	updatePrivilege(w, r, ps, false)
+}
+
+// ReadUserHandler handles the http request for listing the members of the rolegroup.
+// Accessing this handler needs XREAD ability of roles module
+/*
+	@params:
+		id	= required, positive numeric
+		pg	= required, positive numeric
+		ttl	= required, positive numeric
+	@example:
+		id	= 2
+		pg	= 1
+		ttl	= 10
+	@return
+		[]{id, name, email}
+*/
+func ReadUserHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.Authorize(rg.ModuleRole, rg.RoleRead) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := readUserParams{
+		ID:    ps.ByName("id"),
+		Page:  r.FormValue("pg"),
+		Total: r.FormValue("ttl"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	offset := (args.Page - 1) * args.Total
+	users, err := user.SelectByRoleGroup(args.ID, args.Total, offset)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	res := []readUserResponse{}
+	for _, val := range users {
+		res = append(res, readUserResponse{
+			IdentityCode: val.IdentityCode,
+			Name:         val.Name,
+			Email:        val.Email,
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// AssignUserHandler handles the http request for assigning the users to the rolegroup. The rolegroup whose privileges
+// aren't owned by the logged in user can't be assigned, neither the users whose current rolegroup has such
+// privileges can be reassigned. The active sessions of the users are refreshed with the
+// privileges of the rolegroup. Accessing this handler needs XUPDATE ability of roles module
+/*
+	@params:
+		id				= required, positive numeric
+		identity_code	= required, comma separated identity code
+	@example:
+		id				= 2
+		identity_code	= 140810140016,140810140060
+	@return
+*/
+func AssignUserHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.Authorize(rg.ModuleRole, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := assignUserParams{
+		ID:            ps.ByName("id"),
+		IdentityCodes: r.FormValue("identity_code"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !rg.IsExist(args.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Rolegroup not found"))
+		return
+	}
+
+	modules, err := rg.GetModuleAccess(args.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if !isHasPrivileges(sess, modules) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You can't assign the rolegroup which has higher privilege than yours"))
+		return
+	}
+
+	// the previous rolegroup of the users is kept in the audit logs
+	users, err := user.SelectByIdentityCode(args.IdentityCodes, user.ColID, user.ColRoleGroupsID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// moving the users out of the rolegroup which has higher privilege is as much as removing them from it
+	checked := map[int64]bool{args.ID: true}
+	for _, u := range users {
+		if !u.RoleGroupsID.Valid || checked[u.RoleGroupsID.Int64] {
+			continue
+		}
+		checked[u.RoleGroupsID.Int64] = true
+
+		current, err := rg.GetModuleAccess(u.RoleGroupsID.Int64)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		if !isHasPrivileges(sess, current) {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusForbidden).
+				AddError("You can't reassign the users whose rolegroup has higher privilege than yours"))
+			return
+		}
+	}
+
+	tx := conn.DB.MustBegin()
+	err = user.UpdateRoleGroup(args.IdentityCodes, args.ID, tx)
+	if err != nil && err != conn.ErrNoRowsAffected {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	for _, u := range users {
+		if u.RoleGroupsID.Valid && u.RoleGroupsID.Int64 == args.ID {
+			continue
+		}
+		var before interface{}
+		if u.RoleGroupsID.Valid {
+			before = u.RoleGroupsID.Int64
+		}
+		err = insertMemberAudit(sess.ID, u.ID, before, args.ID, helper.ClientIP(r), tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = refreshSession(args.ID, args.IdentityCodes...)
+	if err != nil {
+		log.Printf("[rolegroup][assign] %s", err.Error())
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError).
+			AddError("Users have been assigned, but their active sessions can't be refreshed"))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Users have been assigned"))
+	return
+}
+
+// RemoveUserHandler handles the http request for unassigning the members of the rolegroup, their active sessions
+// lose the privileges immediately. The members of the rolegroup whose privileges aren't owned by the logged in user
+// can't be removed. Accessing this handler needs XUPDATE ability of roles module
+/*
+	@params:
+		id				= required, positive numeric
+		identity_code	= required, comma separated identity code
+	@example:
+		id				= 2
+		identity_code	= 140810140016
+	@return
+*/
+func RemoveUserHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.Authorize(rg.ModuleRole, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := assignUserParams{
+		ID:            ps.ByName("id"),
+		IdentityCodes: r.FormValue("identity_code"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	modules, err := rg.GetModuleAccess(args.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if !isHasPrivileges(sess, modules) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You can't remove the members of the rolegroup which has higher privilege than yours"))
+		return
+	}
+
+	// the members are resolved before removing because they aren't the members anymore afterwards
+	userIDs, err := user.SelectIDByRoleGroup(args.ID, args.IdentityCodes...)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = user.RemoveRoleGroup(args.IdentityCodes, args.ID, tx)
+	if err == conn.ErrNoRowsAffected {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Users aren't the members of the rolegroup"))
+		return
+	}
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	for _, id := range userIDs {
+		err = insertMemberAudit(sess.ID, id, args.ID, nil, helper.ClientIP(r), tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = auth.UpdateSessionRoles(userIDs, map[string][]string{})
+	if err != nil {
+		log.Printf("[rolegroup][remove] %s", err.Error())
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError).
+			AddError("Users have been removed, but their active sessions can't be refreshed"))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Users have been removed from the rolegroup"))
+	return
+}
+
+// updatePrivilege grants or revokes the abilities of the module, it's shared by GrantHandler and RevokeHandler
+func updatePrivilege(w http.ResponseWriter, r *http.Request, ps httprouter.Params, isGrant bool) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.Authorize(rg.ModuleRole, rg.RoleUpdate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := privilegeParams{
+		ID:        ps.ByName("id"),
+		Module:    r.FormValue("module"),
+		Abilities: r.FormValue("abilities"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if !isHasPrivileges(sess, map[string][]string{args.Module: args.Abilities}) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You can't change the ability which you don't have"))
+		return
+	}
+
+	if !rg.IsExist(args.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Rolegroup not found"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	if isGrant {
+		err = rg.GrantModuleAccess(args.ID, args.Module, args.Abilities, tx)
+	} else {
+		err = rg.RevokeModuleAccess(args.ID, args.Module, args.Abilities, tx)
+	}
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// the granted abilities are created rows of rolegroups_modules and the revoked ones are deleted rows
+	privilege := map[string]interface{}{"module": args.Module, "abilities": args.Abilities}
+	action, diff := audit.ActionCreate, audit.NewDiff(nil, privilege)
+	if !isGrant {
+		action, diff = audit.ActionDelete, audit.NewDiff(privilege, nil)
+	}
+	err = audit.Insert(sess.ID, action, audit.TableRoleGroupModules, strconv.FormatInt(args.ID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = refreshSession(args.ID)
+	if err != nil {
+		log.Printf("[rolegroup][privilege] %s", err.Error())
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError).
+			AddError("Privilege has been updated, but the active sessions of the members can't be refreshed"))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Privilege has been updated"))
+	return
+}
+
+// refreshSession replaces the privileges of the active sessions of the rolegroup members with the current privileges
+// of the rolegroup. If the identity codes are given, only the sessions of the members among them are refreshed.
+// The sessions are left untouched when the privileges can't be loaded
+func refreshSession(rolegroupID int64, identityCodes ...int64) error {
+	modules, err := rg.GetModuleAccess(rolegroupID)
+	if err != nil {
+		return err
+	}
+	userIDs, err := user.SelectIDByRoleGroup(rolegroupID, identityCodes...)
+	if err != nil {
+		return err
+	}
+	return auth.UpdateSessionRoles(userIDs, modules)
+}
+
+// insertMemberAudit records the change of the rolegroup of the user, the nil rolegroup means the user isn't a member
+// of any rolegroup
+func insertMemberAudit(actorID, userID int64, before, after interface{}, ip string, tx *sqlx.Tx) error {
+	diff := audit.NewDiff(map[string]interface{}{"rolegroups_id": before}, map[string]interface{}{"rolegroups_id": after})
+	return audit.Insert(actorID, audit.ActionUpdate, audit.TableUsers, strconv.FormatInt(userID, 10), diff, ip, tx)
+}
+
+// isHasPrivileges checks whether the user owns every ability of the modules
+func isHasPrivileges(sess *auth.User, modules map[string][]string) bool {
+	for module, abilities := range modules {
+		for _, ability := range abilities {
+			if !sess.IsHasRoles(module, ability) {
+				return false
+			}
+		}
+	}
+	return true
+}
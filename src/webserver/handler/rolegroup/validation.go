@@ -1 +1,234 @@
 package rolegroup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/util/alias"
+	"github.com/melodiez14/meiko/src/util/helper"
+)
+
+func (params readParams) validate() (readArgs, error) {
+
+	var args readArgs
+	page, total, err := validatePage(params.Page, params.Total)
+	if err != nil {
+		return args, err
+	}
+
+	args = readArgs{
+		Page:  page,
+		Total: total,
+	}
+	return args, nil
+}
+
+func (params detailParams) validate() (detailArgs, error) {
+
+	var args detailArgs
+	id, err := validateID(params.ID)
+	if err != nil {
+		return args, err
+	}
+
+	args = detailArgs{
+		ID: id,
+	}
+	return args, nil
+}
+
+func (params createParams) validate() (createArgs, error) {
+
+	var args createArgs
+	name, err := validateName(params.Name)
+	if err != nil {
+		return args, err
+	}
+
+	args = createArgs{
+		Name: name,
+	}
+	return args, nil
+}
+
+func (params updateParams) validate() (updateArgs, error) {
+
+	var args updateArgs
+	id, err := validateID(params.ID)
+	if err != nil {
+		return args, err
+	}
+
+	name, err := validateName(params.Name)
+	if err != nil {
+		return args, err
+	}
+
+	args = updateArgs{
+		ID:   id,
+		Name: name,
+	}
+	return args, nil
+}
+
+func (params privilegeParams) validate() (privilegeArgs, error) {
+
+	var args privilegeArgs
+	id, err := validateID(params.ID)
+	if err != nil {
+		return args, err
+	}
+
+	module := strings.ToLower(helper.Trim(params.Module))
+	if !helper.IsStringInSlice(module, rg.GetModuleList()) {
+		return args, fmt.Errorf("Error validation: module must be one of %s", strings.Join(rg.GetModuleList(), ", "))
+	}
+
+	abilities := []string{}
+	for _, val := range strings.Split(params.Abilities, ",") {
+		ability := strings.ToUpper(helper.Trim(val))
+		if helper.IsEmpty(ability) {
+			continue
+		}
+		if !helper.IsStringInSlice(ability, rg.GetRoleList()) {
+			return args, fmt.Errorf("Error validation: ability %s is invalid", ability)
+		}
+		if !helper.IsStringInSlice(ability, abilities) {
+			abilities = append(abilities, ability)
+		}
+	}
+	if len(abilities) < 1 {
+		return args, fmt.Errorf("Error validation: abilities can't be empty")
+	}
+
+	args = privilegeArgs{
+		ID:        id,
+		Module:    module,
+		Abilities: abilities,
+	}
+	return args, nil
+}
+
+func (params readUserParams) validate() (readUserArgs, error) {
+
+	var args readUserArgs
+	id, err := validateID(params.ID)
+	if err != nil {
+		return args, err
+	}
+
+	page, total, err := validatePage(params.Page, params.Total)
+	if err != nil {
+		return args, err
+	}
+
+	args = readUserArgs{
+		ID:    id,
+		Page:  page,
+		Total: total,
+	}
+	return args, nil
+}
+
+func (params assignUserParams) validate() (assignUserArgs, error) {
+
+	var args assignUserArgs
+	id, err := validateID(params.ID)
+	if err != nil {
+		return args, err
+	}
+
+	identityCodes := []int64{}
+	for _, val := range strings.Split(params.IdentityCodes, ",") {
+		val = helper.Trim(val)
+		if helper.IsEmpty(val) {
+			continue
+		}
+		identityCode, err := helper.NormalizeIdentity(val)
+		if err != nil {
+			return args, fmt.Errorf("Error validation: identity_code %s is invalid", val)
+		}
+		if !helper.Int64InSlice(identityCode, identityCodes) {
+			identityCodes = append(identityCodes, identityCode)
+		}
+	}
+	if len(identityCodes) < 1 {
+		return args, fmt.Errorf("Error validation: identity_code can't be empty")
+	}
+	if len(identityCodes) > alias.RoleGroupUserMax {
+		return args, fmt.Errorf("Error validation: identity_code can't be more than %d", alias.RoleGroupUserMax)
+	}
+
+	args = assignUserArgs{
+		ID:            id,
+		IdentityCodes: identityCodes,
+	}
+	return args, nil
+}
+
+func (params updateTwoFactorParams) validate() (updateTwoFactorArgs, error) {
+
+	var args updateTwoFactorArgs
+	id, err := validateID(params.ID)
+	if err != nil {
+		return args, err
+	}
+
+	var isRequired bool
+	switch helper.Trim(params.IsRequired) {
+	case "true":
+		isRequired = true
+	case "false":
+		isRequired = false
+	default:
+		return args, fmt.Errorf("Error validation: is_required must be true or false")
+	}
+
+	args = updateTwoFactorArgs{
+		ID:         id,
+		IsRequired: isRequired,
+	}
+	return args, nil
+}
+
+// validateID parses the rolegroup id from the url
+func validateID(id string) (int64, error) {
+	val, err := strconv.ParseInt(helper.Trim(id), 10, 64)
+	if err != nil || val < 1 {
+		return 0, fmt.Errorf("Error validation: id must be positive numeric")
+	}
+	return val, nil
+}
+
+// validateName normalizes the rolegroup name which is alphanumeric and space
+func validateName(name string) (string, error) {
+	name = helper.Trim(name)
+	if helper.IsEmpty(name) {
+		return "", fmt.Errorf("Error validation: name can't be empty")
+	}
+
+	name, err := helper.Normalize(name, helper.IsAlphaNumericSpace)
+	if err != nil {
+		return "", fmt.Errorf("Error validation: name must be alphanumeric")
+	}
+	if len(name) > alias.RoleGroupNameLengthMax {
+		return "", fmt.Errorf("Error validation: name can't be more than %d characters", alias.RoleGroupNameLengthMax)
+	}
+	return name, nil
+}
+
+// validatePage parses the pg and ttl pagination params
+func validatePage(page, total string) (uint16, uint16, error) {
+	pg, err := strconv.ParseUint(helper.Trim(page), 10, 16)
+	if err != nil || pg < 1 {
+		return 0, 0, fmt.Errorf("Error validation: pg must be positive numeric")
+	}
+
+	ttl, err := strconv.ParseUint(helper.Trim(total), 10, 16)
+	if err != nil || ttl < 1 {
+		return 0, 0, fmt.Errorf("Error validation: ttl must be positive numeric")
+	}
+	return uint16(pg), uint16(ttl), nil
+}
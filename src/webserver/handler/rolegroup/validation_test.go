@@ -0,0 +1,157 @@
+package rolegroup
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_createParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  createParams
+		want    createArgs
+		wantErr bool
+	}{
+		{
+			name:    "Test Case 1",
+			params:  createParams{Name: ""},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 2",
+			params:  createParams{Name: "Lecturer<script>"},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 3",
+			params:  createParams{Name: "Teaching Assistant"},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 4",
+			params:  createParams{Name: "  Head   Lab 2 "},
+			want:    createArgs{Name: "Head Lab 2"},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("createParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("createParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_privilegeParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  privilegeParams
+		want    privilegeArgs
+		wantErr bool
+	}{
+		{
+			name:    "Test Case 1",
+			params:  privilegeParams{ID: "0", Module: "courses", Abilities: "XREAD"},
+			want:    privilegeArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 2",
+			params:  privilegeParams{ID: "2", Module: "grades", Abilities: "XREAD"},
+			want:    privilegeArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 3",
+			params:  privilegeParams{ID: "2", Module: "courses", Abilities: "XREAD,WRITE"},
+			want:    privilegeArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 4",
+			params:  privilegeParams{ID: "2", Module: "courses", Abilities: " , "},
+			want:    privilegeArgs{},
+			wantErr: true,
+		},
+		{
+			name:   "Test Case 5",
+			params: privilegeParams{ID: "2", Module: "Informations", Abilities: "xread, XUPDATE,XREAD"},
+			want: privilegeArgs{
+				ID:        2,
+				Module:    "informations",
+				Abilities: []string{"XREAD", "XUPDATE"},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("privilegeParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("privilegeParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_assignUserParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  assignUserParams
+		want    assignUserArgs
+		wantErr bool
+	}{
+		{
+			name:    "Test Case 1",
+			params:  assignUserParams{ID: "abc", IdentityCodes: "140810140016"},
+			want:    assignUserArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 2",
+			params:  assignUserParams{ID: "2", IdentityCodes: ""},
+			want:    assignUserArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 3",
+			params:  assignUserParams{ID: "2", IdentityCodes: "140810140016,1408"},
+			want:    assignUserArgs{},
+			wantErr: true,
+		},
+		{
+			name:   "Test Case 4",
+			params: assignUserParams{ID: "2", IdentityCodes: "140810140016, 140810140060,140810140016,"},
+			want: assignUserArgs{
+				ID:            2,
+				IdentityCodes: []int64{140810140016, 140810140060},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("assignUserParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("assignUserParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package token
+
+import (
+	"time"
+)
+
+type readResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type createParams struct {
+	Name   string
+	Scopes string
+	Expire string
+}
+
+type createArgs struct {
+	Name   string
+	Scopes map[string][]string
+	Expire uint16
+}
+
+type createResponse struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Token     string     `json:"token"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type revokeParams struct {
+	ID string
+}
+
+type revokeArgs struct {
+	ID int64
+}
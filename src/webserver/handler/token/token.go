@@ -0,0 +1,277 @@
+package token
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/julienschmidt/httprouter"
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/module/token"
+	"github.com/melodiez14/meiko/src/module/user"
+	"github.com/melodiez14/meiko/src/util/alias"
+	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/melodiez14/meiko/src/webserver/template"
+)
+
+// ReadHandler handles the http request for listing the api tokens of the logged in user
+/*
+	@params:
+	@example:
+	@return
+		[]{id, name, prefix, scopes, expires_at, last_used_at, created_at}
+*/
+func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	tokens, err := token.SelectByUserID(sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	res := []readResponse{}
+	for _, val := range tokens {
+		res = append(res, readResponse{
+			ID:         val.ID,
+			Name:       val.Name,
+			Prefix:     val.Prefix,
+			Scopes:     splitScope(val.Scopes),
+			ExpiresAt:  nullTime(val.ExpiresAt),
+			LastUsedAt: nullTime(val.LastUsedAt),
+			CreatedAt:  val.CreatedAt,
+		})
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// CreateHandler handles the http request for creating the api token of the logged in user.
+// The scopes must be owned by the user and the plain token is only shown in this response
+/*
+	@params:
+		name	= required, characters<=45
+		scopes	= optional, comma separated module:ability
+		expire	= optional, days between 1 and 365
+	@example:
+		name	= mobile app
+		scopes	= users:XREAD,courses:XREAD
+		expire	= 90
+	@return
+		{id, name, token, prefix, scopes, expires_at}
+*/
+func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.TokenID != 0 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Api token can't be created using api token"))
+		return
+	}
+
+	params := createParams{
+		Name:   r.FormValue("name"),
+		Scopes: r.FormValue("scopes"),
+		Expire: r.FormValue("expire"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	for module, abilities := range args.Scopes {
+		for _, ability := range abilities {
+			if !sess.IsHasRoles(module, ability) {
+				template.RenderJSONResponse(w, new(template.Response).
+					SetCode(http.StatusForbidden).
+					AddError(fmt.Sprintf("You don't have privilege for scope %s:%s", module, ability)))
+				return
+			}
+		}
+	}
+
+	tokens, err := token.SelectByUserID(sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	if len(tokens) >= token.MaxTokenPerUser {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(fmt.Sprintf("You can only have %d api tokens", token.MaxTokenPerUser)))
+		return
+	}
+
+	plain, prefix, hash, err := auth.NewAPIToken()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	var expiresAt mysql.NullTime
+	if args.Expire > 0 {
+		expiresAt = mysql.NullTime{
+			Time:  time.Now().AddDate(0, 0, int(args.Expire)),
+			Valid: true,
+		}
+	}
+
+	scopes := token.JoinScope(args.Scopes)
+	id, err := token.Insert(sess.ID, args.Name, prefix, hash, scopes, expiresAt)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	res := createResponse{
+		ID:        id,
+		Name:      args.Name,
+		Token:     plain,
+		Prefix:    prefix,
+		Scopes:    splitScope(scopes),
+		ExpiresAt: nullTime(expiresAt),
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// RevokeHandler handles the http request for revoking the api token of the logged in user
+/*
+	@params:
+		id	= required, positive numeric
+	@example:
+		id	= 3
+	@return
+*/
+func RevokeHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := revokeParams{
+		ID: ps.ByName("id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	err = token.Delete(args.ID, sess.ID)
+	if err == conn.ErrNoRowsAffected {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Api token not found"))
+		return
+	}
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Api token revoked"))
+	return
+}
+
+// ValidateToken returns the user of the api token. It's registered as the auth token validator,
+// the roles of the user are restricted to the token scopes
+/*
+	@params:
+		hash	= string
+	@example:
+		hash	= 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae
+	@return
+		*auth.User
+*/
+func ValidateToken(hash string) (*auth.User, error) {
+
+	t, err := token.GetByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := user.SelectByID([]int64{t.UserID},
+		user.ColID,
+		user.ColName,
+		user.ColEmail,
+		user.ColGender,
+		user.ColNote,
+		user.ColStatus,
+		user.ColIdentityCode,
+		user.ColLineID,
+		user.ColPhone,
+		user.ColRoleGroupsID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) < 1 || users[0].Status != alias.UserStatusActivated {
+		return nil, fmt.Errorf("Api token owner isn't active")
+	}
+	u := users[0]
+
+	roles := map[string][]string{}
+	if u.RoleGroupsID.Valid {
+		roles, err = rg.GetModuleAccess(u.RoleGroupsID.Int64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	go token.UpdateLastUsed(t.ID)
+
+	return &auth.User{
+		ID:           u.ID,
+		Name:         u.Name,
+		Email:        u.Email,
+		Gender:       u.Gender,
+		Note:         u.Note,
+		Status:       u.Status,
+		IdentityCode: u.IdentityCode,
+		LineID:       u.LineID.String,
+		Phone:        u.Phone.String,
+		Roles:        token.Restrict(roles, token.ParseScope(t.Scopes)),
+		TokenID:      t.ID,
+	}, nil
+}
+
+// splitScope converts the stored scopes into the response
+func splitScope(scopes string) []string {
+	if scopes == "" {
+		return []string{}
+	}
+	return strings.Split(scopes, ",")
+}
+
+// nullTime returns nil for the null time so it's rendered as null
+func nullTime(t mysql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
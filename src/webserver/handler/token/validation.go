@@ -0,0 +1,82 @@
+package token
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/util/alias"
+	"github.com/melodiez14/meiko/src/util/helper"
+)
+
+func (params createParams) validate() (createArgs, error) {
+
+	var args createArgs
+	params = createParams{
+		Name:   helper.Trim(html.EscapeString(params.Name)),
+		Scopes: helper.Trim(params.Scopes),
+		Expire: helper.Trim(params.Expire),
+	}
+
+	// name validation
+	if helper.IsEmpty(params.Name) {
+		return args, fmt.Errorf("Error validation: name can't be empty")
+	}
+	if len(params.Name) > alias.TokenNameLengthMax {
+		return args, fmt.Errorf("Error validation: name is too long")
+	}
+
+	// scopes validation
+	scopes := map[string][]string{}
+	if !helper.IsEmpty(params.Scopes) {
+		for _, val := range strings.Split(params.Scopes, ",") {
+			scope := strings.SplitN(strings.TrimSpace(val), ":", 2)
+			if len(scope) != 2 {
+				return args, fmt.Errorf("Error validation: scope %s is invalid", val)
+			}
+
+			module := strings.ToLower(scope[0])
+			ability := strings.ToUpper(scope[1])
+			if !helper.IsStringInSlice(module, rg.GetModuleList()) || !helper.IsStringInSlice(ability, rg.GetRoleList()) {
+				return args, fmt.Errorf("Error validation: scope %s is invalid", val)
+			}
+
+			if !helper.IsStringInSlice(ability, scopes[module]) {
+				scopes[module] = append(scopes[module], ability)
+			}
+		}
+	}
+
+	// expire validation, the token never expires if it's empty
+	var expire uint16
+	if !helper.IsEmpty(params.Expire) {
+		e, err := strconv.ParseUint(params.Expire, 10, 16)
+		if err != nil || e < 1 || e > alias.TokenExpireMax {
+			return args, fmt.Errorf("Error validation: expire must be between 1 and %d days", alias.TokenExpireMax)
+		}
+		expire = uint16(e)
+	}
+
+	args = createArgs{
+		Name:   params.Name,
+		Scopes: scopes,
+		Expire: expire,
+	}
+	return args, nil
+}
+
+func (params revokeParams) validate() (revokeArgs, error) {
+
+	var args revokeArgs
+	id, err := strconv.ParseInt(helper.Trim(params.ID), 10, 64)
+	if err != nil || id < 1 {
+		return args, fmt.Errorf("Error validation: id must be positive numeric")
+	}
+
+	args = revokeArgs{
+		ID: id,
+	}
+	return args, nil
+}
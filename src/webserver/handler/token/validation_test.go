@@ -0,0 +1,150 @@
+package token
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_createParams_validate(t *testing.T) {
+	type fields struct {
+		Name   string
+		Scopes string
+		Expire string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    createArgs
+		wantErr bool
+	}{
+		{
+			name: "Test Case 1",
+			fields: fields{
+				Name: "",
+			},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 2",
+			fields: fields{
+				Name:   "mobile app",
+				Scopes: "users",
+			},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 3",
+			fields: fields{
+				Name:   "mobile app",
+				Scopes: "unknown:READ",
+			},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 4",
+			fields: fields{
+				Name:   "mobile app",
+				Scopes: "users:WRITE",
+			},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 5",
+			fields: fields{
+				Name:   "mobile app",
+				Expire: "366",
+			},
+			want:    createArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 6",
+			fields: fields{
+				Name: " mobile app ",
+			},
+			want: createArgs{
+				Name:   "mobile app",
+				Scopes: map[string][]string{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test Case 7",
+			fields: fields{
+				Name:   "script",
+				Scopes: "users:read, Courses:XREAD,users:READ",
+				Expire: "90",
+			},
+			want: createArgs{
+				Name: "script",
+				Scopes: map[string][]string{
+					"users":   {"READ"},
+					"courses": {"XREAD"},
+				},
+				Expire: 90,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := createParams{
+				Name:   tt.fields.Name,
+				Scopes: tt.fields.Scopes,
+				Expire: tt.fields.Expire,
+			}
+			got, err := params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("createParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("createParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_revokeParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    revokeArgs
+		wantErr bool
+	}{
+		{
+			name:    "Test Case 1",
+			id:      "abc",
+			want:    revokeArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 2",
+			id:      "0",
+			want:    revokeArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 3",
+			id:      "3",
+			want:    revokeArgs{ID: 3},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := revokeParams{ID: tt.id}.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("revokeParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("revokeParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package user
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/melodiez14/meiko/src/email"
+	"github.com/melodiez14/meiko/src/module/audit"
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/module/user"
+	"github.com/melodiez14/meiko/src/util/alias"
+	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/melodiez14/meiko/src/util/helper"
+	"github.com/melodiez14/meiko/src/util/spreadsheet"
+	"github.com/melodiez14/meiko/src/webserver/template"
+)
+
+// ImportHandler handles the http request for creating the student accounts from a csv or xlsx file. Without
+// is_confirm the file is only validated and previewed. With is_confirm the accounts are created in a transaction
+// if every row is valid, then the account created email is sent to every account.
+// Accessing this handler needs XCREATE ability of users module
+/*
+	@params:
+		file		= required, csv or xlsx file of identity_code, name and email
+		is_confirm	= optional, true/false
+	@example:
+		file		= students.xlsx
+		is_confirm	= true
+	@return
+		{total, valid, is_created, rows: []{row, identity_code, name, email, errors}}
+*/
+func ImportHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if !sess.Authorize(rg.ModuleUser, rg.RoleCreate) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	r.ParseMultipartForm(alias.UserImportSizeMax)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("File is not exist"))
+		return
+	}
+	defer file.Close()
+
+	if header.Size > alias.UserImportSizeMax {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(fmt.Sprintf("Maximum file size is %d MB", alias.UserImportSizeMax>>20)))
+		return
+	}
+
+	_, ext, err := helper.ExtractExtension(header.Filename)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("File doesn't have an extension"))
+		return
+	}
+
+	rows, err := spreadsheet.Read(file, header.Size, ext)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	params := importParams{
+		Rows:      rows,
+		IsConfirm: r.FormValue("is_confirm"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	err = checkImportRows(args.Rows)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	res := importResponse{
+		Total: len(args.Rows),
+		Rows:  []importRowResponse{},
+	}
+	for _, val := range args.Rows {
+		if len(val.Errors) < 1 {
+			res.Valid++
+		}
+		res.Rows = append(res.Rows, importRowResponse{
+			Row:          val.Row,
+			IdentityCode: val.Params.IdentityCode,
+			Name:         val.Params.Name,
+			Email:        val.Params.Email,
+			Errors:       val.Errors,
+		})
+	}
+
+	if !args.IsConfirm {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusOK).
+			SetData(res))
+		return
+	}
+
+	// nothing is created unless every row is valid
+	if res.Valid != res.Total {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			SetData(res).
+			AddError("Some rows are invalid"))
+		return
+	}
+
+	var verifications []user.Verification
+	tx := conn.DB.MustBegin()
+	for _, val := range args.Rows {
+		id, err := user.Create(val.Args.IdentityCode, val.Args.Name, val.Args.Email, tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		diff := audit.NewDiff(nil, map[string]interface{}{
+			"identity_code": val.Args.IdentityCode,
+			"name":          val.Args.Name,
+			"email":         val.Args.Email,
+		})
+		err = audit.Insert(sess.ID, audit.ActionCreate, audit.TableUsers, strconv.FormatInt(id, 10), diff, helper.ClientIP(r), tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		verification, err := user.GenerateVerification(val.Args.IdentityCode, tx)
+		if err != nil {
+			tx.Rollback()
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+		verifications = append(verifications, verification)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	go func() {
+		for i, val := range args.Rows {
+			email.SendAccountCreated(val.Args.Name, val.Args.Email, verifications[i].Code)
+		}
+	}()
+
+	res.IsCreated = true
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res).
+		SetMessage(fmt.Sprintf("%d users successfully created", res.Total)))
+	return
+}
+
+// checkImportRows adds the error to the row whose identity code or email is used by the previous row
+// or has been registered
+func checkImportRows(rows []importRow) error {
+
+	var identityCodes []int64
+	var emails []string
+	identityRow := map[int64]int{}
+	emailRow := map[string]int{}
+	for i, val := range rows {
+		if len(val.Errors) > 0 {
+			continue
+		}
+		if row, ok := identityRow[val.Args.IdentityCode]; ok {
+			rows[i].Errors = append(rows[i].Errors, fmt.Sprintf("%d is used by row %d", val.Args.IdentityCode, row))
+		} else {
+			identityRow[val.Args.IdentityCode] = val.Row
+		}
+		if row, ok := emailRow[val.Args.Email]; ok {
+			rows[i].Errors = append(rows[i].Errors, fmt.Sprintf("%s is used by row %d", val.Args.Email, row))
+		} else {
+			emailRow[val.Args.Email] = val.Row
+		}
+		identityCodes = append(identityCodes, val.Args.IdentityCode)
+		emails = append(emails, val.Args.Email)
+	}
+
+	registered, err := user.SelectRegistered(identityCodes, emails)
+	if err != nil {
+		return err
+	}
+
+	registeredIdentity := map[int64]bool{}
+	registeredEmail := map[string]bool{}
+	for _, val := range registered {
+		registeredIdentity[val.IdentityCode] = true
+		registeredEmail[strings.ToLower(val.Email)] = true
+	}
+
+	for i, val := range rows {
+		if val.Args.IdentityCode == 0 {
+			continue
+		}
+		if registeredIdentity[val.Args.IdentityCode] {
+			rows[i].Errors = append(rows[i].Errors, fmt.Sprintf("%d has been registered", val.Args.IdentityCode))
+		}
+		if registeredEmail[val.Args.Email] {
+			rows[i].Errors = append(rows[i].Errors, fmt.Sprintf("%s has been registered", val.Args.Email))
+		}
+	}
+	return nil
+}
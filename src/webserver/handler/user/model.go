@@ -2,6 +2,39 @@ package user
 
 import (
 	"database/sql"
+	"time"
+
+	"github.com/melodiez14/meiko/src/util/ratelimit"
+)
+
+var (
+	// signInLimit locks the email for 15 minutes after 5 failed sign in
+	signInLimit = ratelimit.Limit{
+		Name:    "signin:email",
+		Max:     5,
+		Window:  15 * time.Minute,
+		Lockout: 15 * time.Minute,
+	}
+	// forgotCodeLimit limits the forgot password code sent to the email, every code has its own attempt limit
+	forgotCodeLimit = ratelimit.Limit{
+		Name:   "forgot:email",
+		Max:    3,
+		Window: time.Hour,
+	}
+	// verificationCodeLimit limits the verification code sent to the email, every code has its own attempt limit
+	verificationCodeLimit = ratelimit.Limit{
+		Name:   "verification:email",
+		Max:    3,
+		Window: time.Hour,
+	}
+	// twoFactorLimit locks the two factor of the user for 15 minutes after 5 failed verifications, it covers both
+	// the sign in challenge and the changes of the two factor
+	twoFactorLimit = ratelimit.Limit{
+		Name:    "2fa:user",
+		Max:     5,
+		Window:  15 * time.Minute,
+		Lockout: 15 * time.Minute,
+	}
 )
 
 type signUpParams struct {
@@ -41,8 +74,12 @@ type signInArgs struct {
 }
 
 type signInResponse struct {
-	IsLoggedIn bool                `json:"is_logged_in"`
-	Modules    map[string][]string `json:"modules"`
+	IsLoggedIn          bool                `json:"is_logged_in"`
+	Modules             map[string][]string `json:"modules"`
+	IsTwoFactorRequired bool                `json:"is_2fa_required,omitempty"`
+	IsEnrollRequired    bool                `json:"is_2fa_enroll_required,omitempty"`
+	Challenge           string              `json:"challenge,omitempty"`
+	RecoveryCodes       []string            `json:"recovery_codes,omitempty"`
 }
 
 type forgotResponse struct {
@@ -198,3 +235,125 @@ type createArgs struct {
 	Name         string
 	Email        string
 }
+
+type sessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	IsCurrent  bool      `json:"is_current"`
+}
+
+type revokeSessionParams struct {
+	SessionID string
+}
+
+type revokeSessionArgs struct {
+	SessionID string
+}
+
+type userSessionParams struct {
+	IdentityCode string
+}
+
+type userSessionArgs struct {
+	IdentityCode int64
+}
+
+type revokeUserSessionParams struct {
+	IdentityCode string
+	SessionID    string
+}
+
+type revokeUserSessionArgs struct {
+	IdentityCode int64
+	SessionID    string
+}
+
+type signInTwoFactorParams struct {
+	Challenge    string
+	Code         string
+	RecoveryCode string
+}
+
+type signInTwoFactorArgs struct {
+	Challenge    string
+	Code         string
+	RecoveryCode string
+}
+
+type enrollTwoFactorParams struct {
+	Challenge string
+}
+
+type enrollTwoFactorArgs struct {
+	Challenge string
+}
+
+type enrollTwoFactorResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+	QRCode string `json:"qr_code"`
+}
+
+type getTwoFactorResponse struct {
+	IsEnabled        bool `json:"is_enabled"`
+	IsRequired       bool `json:"is_required"`
+	RecoveryCodeLeft int  `json:"recovery_code_left"`
+}
+
+type confirmTwoFactorParams struct {
+	Code string
+}
+
+type confirmTwoFactorArgs struct {
+	Code string
+}
+
+type disableTwoFactorParams struct {
+	Password string
+	Code     string
+}
+
+type disableTwoFactorArgs struct {
+	Password string
+	Code     string
+}
+
+type recoveryCodeResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type importParams struct {
+	Rows      [][]string
+	IsConfirm string
+}
+
+type importArgs struct {
+	Rows      []importRow
+	IsConfirm bool
+}
+
+// importRow is the account of a row of the imported file, the row is numbered as shown in the spreadsheet
+type importRow struct {
+	Row    int
+	Params createParams
+	Args   createArgs
+	Errors []string
+}
+
+type importResponse struct {
+	Total     int                 `json:"total"`
+	Valid     int                 `json:"valid"`
+	IsCreated bool                `json:"is_created"`
+	Rows      []importRowResponse `json:"rows"`
+}
+
+type importRowResponse struct {
+	Row          int      `json:"row"`
+	IdentityCode string   `json:"identity_code"`
+	Name         string   `json:"name"`
+	Email        string   `json:"email"`
+	Errors       []string `json:"errors"`
+}
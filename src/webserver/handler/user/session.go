@@ -0,0 +1,309 @@
+package user
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/module/user"
+	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/webserver/template"
+)
+
+// GetSessionHandler handles the http request for listing the active sessions of the logged in user
+/*
+	@params:
+	@example:
+	@return
+		[]{id, user_agent, ip, created_at, last_seen_at, is_current}
+*/
+func GetSessionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	sessions, err := auth.SelectSession(sess.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(sessionResponses(sessions, auth.SessionID(r))))
+	return
+}
+
+// RevokeSessionHandler handles the http request for signing out one of the active sessions of the logged in user
+/*
+	@params:
+		session_id	= required, 32 characters hexadecimal
+	@example:
+		session_id	= 9f86d081884c7d659a2feaa0c55ad015
+	@return
+*/
+func RevokeSessionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	params := revokeSessionParams{
+		SessionID: r.FormValue("session_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	if args.SessionID == auth.SessionID(r) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Use sign out for the current session"))
+		return
+	}
+
+	err = auth.DestroySessionByID(sess.ID, args.SessionID)
+	if err == auth.ErrSessionNotFound {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Session not found"))
+		return
+	}
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Session revoked"))
+	return
+}
+
+// RevokeOtherSessionHandler handles the http request for signing out every session of the logged in user except the current session
+/*
+	@params:
+	@example:
+	@return
+*/
+func RevokeOtherSessionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	count, err := auth.DestroyOtherSession(sess.ID, auth.SessionID(r))
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage(fmt.Sprintf("%d session revoked", count)))
+	return
+}
+
+// GetUserSessionHandler handles the http request for listing the active sessions of the specific user.
+// Accessing this handler needs READ ability of users module for the own sessions or XREAD for any user
+/*
+	@params:
+		id	= required, numeric
+	@example:
+		id	= 140810140016
+	@return
+		[]{id, user_agent, ip, created_at, last_seen_at, is_current}
+*/
+func GetUserSessionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleUser, rg.RoleRead) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := userSessionParams{
+		IdentityCode: ps.ByName("id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	u, err := user.GetByIdentityCode(args.IdentityCode, user.ColID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound))
+		return
+	}
+
+	if !sess.Authorize(rg.ModuleUser, rg.RoleRead, u.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	sessions, err := auth.SelectSession(u.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(sessionResponses(sessions, auth.SessionID(r))))
+	return
+}
+
+// RevokeUserSessionHandler handles the http request for signing out one of the active sessions of the specific user.
+// Accessing this handler needs UPDATE ability of users module for the own sessions or XUPDATE for any user
+/*
+	@params:
+		id			= required, numeric
+		session_id	= required, 32 characters hexadecimal
+	@example:
+		id			= 140810140016
+		session_id	= 9f86d081884c7d659a2feaa0c55ad015
+	@return
+*/
+func RevokeUserSessionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleUser, rg.RoleUpdate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := revokeUserSessionParams{
+		IdentityCode: ps.ByName("id"),
+		SessionID:    r.FormValue("session_id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	u, err := user.GetByIdentityCode(args.IdentityCode, user.ColID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound))
+		return
+	}
+
+	if !sess.Authorize(rg.ModuleUser, rg.RoleUpdate, u.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	err = auth.DestroySessionByID(u.ID, args.SessionID)
+	if err == auth.ErrSessionNotFound {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound).
+			AddError("Session not found"))
+		return
+	}
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Session revoked"))
+	return
+}
+
+// RevokeAllUserSessionHandler handles the http request for signing out every session of the specific user.
+// Accessing this handler needs UPDATE ability of users module for the own sessions or XUPDATE for any user
+/*
+	@params:
+		id	= required, numeric
+	@example:
+		id	= 140810140016
+	@return
+*/
+func RevokeAllUserSessionHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.Scope(rg.ModuleUser, rg.RoleUpdate) == auth.ScopeNone {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	params := userSessionParams{
+		IdentityCode: ps.ByName("id"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	u, err := user.GetByIdentityCode(args.IdentityCode, user.ColID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusNotFound))
+		return
+	}
+
+	if !sess.Authorize(rg.ModuleUser, rg.RoleUpdate, u.ID) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You don't have privilege"))
+		return
+	}
+
+	err = auth.DestroyAllSession(u.ID)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("All session revoked"))
+	return
+}
+
+// sessionResponses converts the active sessions into the response and marks the session of the request
+func sessionResponses(sessions []auth.Session, currentID string) []sessionResponse {
+	res := []sessionResponse{}
+	for _, val := range sessions {
+		res = append(res, sessionResponse{
+			ID:         val.ID,
+			UserAgent:  val.UserAgent,
+			IP:         val.IP,
+			CreatedAt:  val.CreatedAt,
+			LastSeenAt: val.LastSeenAt,
+			IsCurrent:  val.ID == currentID,
+		})
+	}
+	return res
+}
@@ -0,0 +1,626 @@
+package user
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	rg "github.com/melodiez14/meiko/src/module/rolegroup"
+	"github.com/melodiez14/meiko/src/module/twofactor"
+	"github.com/melodiez14/meiko/src/module/user"
+	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/util/conn"
+	"github.com/melodiez14/meiko/src/util/ratelimit"
+	"github.com/melodiez14/meiko/src/webserver/template"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// qrCodeSize is the size in pixel of the enrollment QR code
+const qrCodeSize = 256
+
+// SignInTwoFactorHandler handles the http request for the second sign in step. The session is created
+// if the authenticator app code or the unused recovery code is valid. If the two factor is required by
+// the rolegroup but it isn't enabled, the code confirms the enrollment and the recovery codes are returned
+/*
+	@params:
+		challenge		= required
+		code			= required if recovery_code is empty, numeric, characters=6
+		recovery_code	= required if code is empty, characters=10 excluding the dash
+	@example:
+		challenge		= 7Jx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq5Q0pMmJ7tXA
+		code			= 492039
+		recovery_code	= k3pxp-jbswy
+	@return
+		is_logged_in	= true
+		modules			= {users: [READ, UPDATE]}
+		recovery_codes	= [k3pxp-jbswy, ...] only if the enrollment is confirmed
+*/
+func SignInTwoFactorHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You have already logged in"))
+		return
+	}
+
+	params := signInTwoFactorParams{
+		Challenge:    r.FormValue("challenge"),
+		Code:         r.FormValue("code"),
+		RecoveryCode: r.FormValue("recovery_code"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	userID, err := auth.GetChallenge(args.Challenge)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusUnauthorized).
+			AddError("Invalid or expired challenge, please sign in again"))
+		return
+	}
+
+	limitID := fmt.Sprintf("%d", userID)
+	isBlocked, retryAfter, err := twoFactorLimit.Blocked(limitID)
+	if err == nil && isBlocked {
+		ratelimit.Reject(w, retryAfter)
+		return
+	}
+
+	users, err := user.SelectByID([]int64{userID})
+	if err != nil || len(users) < 1 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	t, err := twofactor.Get(userID)
+	if err != nil && err != sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if err == sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Please enroll the authenticator app first"))
+		return
+	}
+
+	var isValid bool
+	var recoveryCodes []string
+	switch {
+	case !t.IsEnabled():
+		// the mandatory enrollment is confirmed using the authenticator app code only
+		step, ok := auth.ValidateTOTP(t.Secret, args.Code, time.Now())
+		if ok {
+			recoveryCodes, err = enableTwoFactor(userID, step)
+			if err != nil && err != conn.ErrNoRowsAffected {
+				template.RenderJSONResponse(w, new(template.Response).
+					SetCode(http.StatusInternalServerError))
+				return
+			}
+			isValid = err == nil
+		}
+	case args.RecoveryCode != "":
+		isValid = twofactor.UseRecoveryCode(userID, auth.HashRecoveryCode(args.RecoveryCode)) == nil
+	default:
+		step, ok := auth.ValidateTOTP(t.Secret, args.Code, time.Now())
+		isValid = ok && twofactor.UpdateLastStep(userID, step) == nil
+	}
+
+	if !isValid {
+		twoFactorLimit.Hit(limitID)
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusUnauthorized).
+			AddError("Invalid code"))
+		return
+	}
+
+	err = auth.DestroyChallenge(args.Challenge)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusUnauthorized).
+			AddError("Invalid or expired challenge, please sign in again"))
+		return
+	}
+	twoFactorLimit.Reset(limitID)
+
+	createSession(w, r, users[0], recoveryCodes)
+	return
+}
+
+// SignInEnrollHandler handles the http request for enrolling the authenticator app during the sign in.
+// It's used when the two factor is required by the rolegroup but it isn't enabled yet
+/*
+	@params:
+		challenge	= required
+	@example:
+		challenge	= 7Jx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq5Q0pMmJ7tXA
+	@return
+		secret	= JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP
+		uri		= otpauth://totp/Meiko:risal@live.com?secret=...
+		qr_code	= data:image/png;base64,...
+*/
+func SignInEnrollHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("You have already logged in"))
+		return
+	}
+
+	params := enrollTwoFactorParams{
+		Challenge: r.FormValue("challenge"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	userID, err := auth.GetChallenge(args.Challenge)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusUnauthorized).
+			AddError("Invalid or expired challenge, please sign in again"))
+		return
+	}
+
+	users, err := user.SelectByID([]int64{userID}, user.ColID, user.ColEmail)
+	if err != nil || len(users) < 1 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	enrollTwoFactor(w, users[0].ID, users[0].Email)
+	return
+}
+
+// GetTwoFactorHandler handles the http request for showing the two factor status of the logged in user
+/*
+	@params:
+	@example:
+	@return
+		is_enabled			= true
+		is_required			= false
+		recovery_code_left	= 8
+*/
+func GetTwoFactorHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+
+	users, err := user.SelectByID([]int64{sess.ID}, user.ColID, user.ColRoleGroupsID)
+	if err != nil || len(users) < 1 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	isEnabled, isRequired, err := twoFactorStatus(users[0])
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	res := getTwoFactorResponse{
+		IsEnabled:  isEnabled,
+		IsRequired: isRequired,
+	}
+	if isEnabled {
+		res.RecoveryCodeLeft, err = twofactor.CountRecoveryCode(sess.ID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+	}
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(res))
+	return
+}
+
+// EnrollTwoFactorHandler handles the http request for enrolling the authenticator app of the logged in user.
+// The two factor isn't enabled until the enrollment is confirmed
+/*
+	@params:
+	@example:
+	@return
+		secret	= JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP
+		uri		= otpauth://totp/Meiko:risal@live.com?secret=...
+		qr_code	= data:image/png;base64,...
+*/
+func EnrollTwoFactorHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.TokenID != 0 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Two factor can't be changed using api token"))
+		return
+	}
+
+	enrollTwoFactor(w, sess.ID, sess.Email)
+	return
+}
+
+// ConfirmTwoFactorHandler handles the http request for enabling the two factor of the logged in user
+/*
+	@params:
+		code	= required, numeric, characters=6
+	@example:
+		code	= 492039
+	@return
+		recovery_codes	= [k3pxp-jbswy, ...]
+*/
+func ConfirmTwoFactorHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.TokenID != 0 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Two factor can't be changed using api token"))
+		return
+	}
+
+	params := confirmTwoFactorParams{
+		Code: r.FormValue("code"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	limitID := fmt.Sprintf("%d", sess.ID)
+	isBlocked, retryAfter, err := twoFactorLimit.Blocked(limitID)
+	if err == nil && isBlocked {
+		ratelimit.Reject(w, retryAfter)
+		return
+	}
+
+	t, err := twofactor.Get(sess.ID)
+	if err == sql.ErrNoRows || (err == nil && t.IsEnabled()) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("There is no pending enrollment"))
+		return
+	}
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	step, ok := auth.ValidateTOTP(t.Secret, args.Code, time.Now())
+	if !ok {
+		twoFactorLimit.Hit(limitID)
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Invalid code"))
+		return
+	}
+
+	codes, err := enableTwoFactor(sess.ID, step)
+	if err == conn.ErrNoRowsAffected {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("There is no pending enrollment"))
+		return
+	}
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	twoFactorLimit.Reset(limitID)
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(recoveryCodeResponse{RecoveryCodes: codes}))
+	return
+}
+
+// DisableTwoFactorHandler handles the http request for disabling the two factor of the logged in user.
+// The two factor can't be disabled if it's required by the rolegroup
+/*
+	@params:
+		password	= required
+		code		= required, numeric, characters=6
+	@example:
+		password	= Qwerty123
+		code		= 492039
+	@return
+*/
+func DisableTwoFactorHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.TokenID != 0 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Two factor can't be changed using api token"))
+		return
+	}
+
+	params := disableTwoFactorParams{
+		Password: r.FormValue("password"),
+		Code:     r.FormValue("code"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	limitID := fmt.Sprintf("%d", sess.ID)
+	isBlocked, retryAfter, err := twoFactorLimit.Blocked(limitID)
+	if err == nil && isBlocked {
+		ratelimit.Reject(w, retryAfter)
+		return
+	}
+
+	u, err := user.SignIn(sess.Email, args.Password)
+	if err != nil {
+		twoFactorLimit.Hit(limitID)
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusUnauthorized).
+			AddError("Invalid password"))
+		return
+	}
+
+	if u.RoleGroupsID.Valid && rg.IsTwoFactorRequired(u.RoleGroupsID.Int64) {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Two factor is required by your role"))
+		return
+	}
+
+	if !isValidTOTP(sess.ID, args.Code) {
+		twoFactorLimit.Hit(limitID)
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusUnauthorized).
+			AddError("Invalid code"))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = twofactor.Delete(sess.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = twofactor.DeleteRecoveryCode(sess.ID, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	twoFactorLimit.Reset(limitID)
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetMessage("Two factor has been disabled"))
+	return
+}
+
+// RecoveryCodeHandler handles the http request for replacing the recovery codes of the logged in user
+/*
+	@params:
+		code	= required, numeric, characters=6
+	@example:
+		code	= 492039
+	@return
+		recovery_codes	= [k3pxp-jbswy, ...]
+*/
+func RecoveryCodeHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+
+	sess := r.Context().Value("User").(*auth.User)
+	if sess.TokenID != 0 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusForbidden).
+			AddError("Two factor can't be changed using api token"))
+		return
+	}
+
+	params := confirmTwoFactorParams{
+		Code: r.FormValue("code"),
+	}
+
+	args, err := params.validate()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError(err.Error()))
+		return
+	}
+
+	limitID := fmt.Sprintf("%d", sess.ID)
+	isBlocked, retryAfter, err := twoFactorLimit.Blocked(limitID)
+	if err == nil && isBlocked {
+		ratelimit.Reject(w, retryAfter)
+		return
+	}
+
+	if !isValidTOTP(sess.ID, args.Code) {
+		twoFactorLimit.Hit(limitID)
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusUnauthorized).
+			AddError("Invalid code"))
+		return
+	}
+
+	codes, hashes, err := auth.NewRecoveryCodes(auth.RecoveryCodeTotal)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	tx := conn.DB.MustBegin()
+	err = twofactor.ReplaceRecoveryCode(sess.ID, hashes, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	twoFactorLimit.Reset(limitID)
+
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(recoveryCodeResponse{RecoveryCodes: codes}))
+	return
+}
+
+// twoFactorStatus returns whether the two factor of the user is enabled and whether it's required by the rolegroup
+func twoFactorStatus(u user.User) (bool, bool, error) {
+
+	t, err := twofactor.Get(u.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return false, false, err
+	}
+
+	isEnabled := err == nil && t.IsEnabled()
+	isRequired := u.RoleGroupsID.Valid && rg.IsTwoFactorRequired(u.RoleGroupsID.Int64)
+	return isEnabled, isRequired, nil
+}
+
+// isValidTOTP checks the authenticator app code of the enabled two factor, the code can't be used twice
+func isValidTOTP(userID int64, code string) bool {
+
+	t, err := twofactor.Get(userID)
+	if err != nil || !t.IsEnabled() {
+		return false
+	}
+
+	step, ok := auth.ValidateTOTP(t.Secret, code, time.Now())
+	return ok && twofactor.UpdateLastStep(userID, step) == nil
+}
+
+// enrollTwoFactor stores the new secret and renders the QR code scanned by the authenticator app
+func enrollTwoFactor(w http.ResponseWriter, userID int64, email string) {
+
+	t, err := twofactor.Get(userID)
+	if err != nil && err != sql.ErrNoRows {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+	if err == nil && t.IsEnabled() {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Two factor has been enabled"))
+		return
+	}
+
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// the two factor may be enabled by the concurrent request after it's checked
+	err = twofactor.Enroll(userID, secret)
+	if err == conn.ErrNoRowsAffected {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Two factor has been enabled"))
+		return
+	}
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	uri := auth.TOTPURI(secret, email)
+	png, err := qrcode.Encode(uri, qrcode.Medium, qrCodeSize)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	template.RenderJSONResponse(w, new(template.Response).
+		SetCode(http.StatusOK).
+		SetData(enrollTwoFactorResponse{
+			Secret: secret,
+			URI:    uri,
+			QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+		}))
+}
+
+// enableTwoFactor confirms the enrollment and returns the new recovery codes. It returns conn.ErrNoRowsAffected
+// if the enrollment has been confirmed by the concurrent request
+func enableTwoFactor(userID, step int64) ([]string, error) {
+
+	codes, hashes, err := auth.NewRecoveryCodes(auth.RecoveryCodeTotal)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := conn.DB.MustBegin()
+	err = twofactor.Enable(userID, step, tx)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = twofactor.ReplaceRecoveryCode(userID, hashes, tx)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
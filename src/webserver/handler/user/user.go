@@ -3,17 +3,21 @@ package user
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/melodiez14/meiko/src/util/helper"
+	"github.com/melodiez14/meiko/src/util/ratelimit"
 
 	"database/sql"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/melodiez14/meiko/src/email"
+	"github.com/melodiez14/meiko/src/module/audit"
 	rg "github.com/melodiez14/meiko/src/module/rolegroup"
 	"github.com/melodiez14/meiko/src/module/user"
 	"github.com/melodiez14/meiko/src/util/alias"
 	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/util/conn"
 	"github.com/melodiez14/meiko/src/webserver/template"
 )
 
@@ -152,6 +156,12 @@ func EmailVerificationHandler(w http.ResponseWriter, r *http.Request, ps httprou
 	}
 
 	if args.IsResendCode {
+		isAllowed, retryAfter, err := verificationCodeLimit.Hit(args.Email)
+		if err == nil && !isAllowed {
+			ratelimit.Reject(w, retryAfter)
+			return
+		}
+
 		// generate verification code
 		verification, err := user.GenerateVerification(u.IdentityCode)
 		if err != nil {
@@ -188,7 +198,7 @@ func EmailVerificationHandler(w http.ResponseWriter, r *http.Request, ps httprou
 	return
 }
 
-// ReadHandler handles the http request for listing all verified and activated users. Accessing this handler needs READ or XREAD ability
+// ReadHandler handles the http request for listing all verified and activated users. Accessing this handler needs XREAD ability
 /*
 	@params:
 		pg	= required, positive numeric
@@ -203,7 +213,7 @@ func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 	sess := r.Context().Value("User").(*auth.User)
 
-	if !sess.IsHasRoles(rg.ModuleUser, rg.RoleRead, rg.RoleXRead) {
+	if !sess.Authorize(rg.ModuleUser, rg.RoleRead) {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -249,7 +259,7 @@ func ReadHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	return
 }
 
-// ActivationHandler handles the http request for changing user status to activated or verified. Accessing this handler needs UPDATE or XUPDATE ability
+// ActivationHandler handles the http request for changing user status to activated or verified. Accessing this handler needs XUPDATE ability
 /*
 	@params:
 		identity	= required, numeric, characters=12
@@ -263,7 +273,7 @@ func ActivationHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 
 	sess := r.Context().Value("User").(*auth.User)
 
-	if !sess.IsHasRoles(rg.ModuleUser, rg.RoleUpdate, rg.RoleXUpdate) {
+	if !sess.Authorize(rg.ModuleUser, rg.RoleUpdate) {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -304,16 +314,34 @@ func ActivationHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 		return
 	}
 
+	tx := conn.DB.MustBegin()
+	err = user.UpdateStatus(u.IdentityCode, args.Status, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	diff := audit.NewDiff(map[string]interface{}{"status": u.Status}, map[string]interface{}{"status": args.Status})
+	err = audit.Insert(sess.ID, audit.ActionUpdate, audit.TableUsers, strconv.FormatInt(u.ID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
 	go func() {
 		// change if args.Status == activated update redis
 		// if args.Status == Verified delete redis
-		_ = user.UpdateStatus(u.IdentityCode, args.Status)
-
-		roles := make(map[string][]string)
-		if u.RoleGroupsID.Valid {
-			roles = rg.GetModuleAccess(u.RoleGroupsID.Int64)
-		}
-
 		sess = &auth.User{
 			ID:           u.ID,
 			Name:         u.Name,
@@ -324,7 +352,6 @@ func ActivationHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Par
 			IdentityCode: u.IdentityCode,
 			LineID:       u.LineID.String,
 			Phone:        u.Phone.String,
-			Roles:        roles,
 		}
 		sess.UpdateSession()
 	}()
@@ -368,13 +395,21 @@ func SignInHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
+	isBlocked, retryAfter, err := signInLimit.Blocked(args.Email)
+	if err == nil && isBlocked {
+		ratelimit.Reject(w, retryAfter)
+		return
+	}
+
 	u, err := user.SignIn(args.Email, args.Password)
 	if err != nil {
+		signInLimit.Hit(args.Email)
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusUnauthorized).
 			AddError("Invalid email or password"))
 		return
 	}
+	signInLimit.Reset(args.Email)
 
 	// check whether user activated
 	switch u.Status {
@@ -397,12 +432,52 @@ func SignInHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
+	isEnabled, isRequired, err := twoFactorStatus(u)
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	// the session is created after the second step if the two factor is enabled or required
+	if isEnabled || isRequired {
+		challenge, err := auth.NewChallenge(u.ID)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError))
+			return
+		}
+
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusOK).
+			SetData(signInResponse{
+				IsTwoFactorRequired: true,
+				IsEnrollRequired:    !isEnabled,
+				Challenge:           challenge,
+			}))
+		return
+	}
+
+	createSession(w, r, u, nil)
+	return
+}
+
+// createSession creates the session of the signed in user and renders the privilege of the user
+func createSession(w http.ResponseWriter, r *http.Request, u user.User, recoveryCodes []string) {
+
 	roles := make(map[string][]string)
 	if u.RoleGroupsID.Valid {
-		roles = rg.GetModuleAccess(u.RoleGroupsID.Int64)
+		var err error
+		roles, err = rg.GetModuleAccess(u.RoleGroupsID.Int64)
+		if err != nil {
+			template.RenderJSONResponse(w, new(template.Response).
+				SetCode(http.StatusInternalServerError).
+				SetMessage("Internal server error"))
+			return
+		}
 	}
 
-	sess = &auth.User{
+	sess := &auth.User{
 		ID:           u.ID,
 		Name:         u.Name,
 		Email:        u.Email,
@@ -415,7 +490,7 @@ func SignInHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		Roles:        roles,
 	}
 
-	cookie, err := sess.SetSession()
+	cookie, err := sess.SetSession(r)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError).
@@ -426,35 +501,15 @@ func SignInHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 	http.SetCookie(w, cookie)
 
 	// set response data
-	var role string
-	roles = map[string][]string{}
-	for i, val := range sess.Roles {
-		for _, v := range val {
-			switch v {
-			case rg.RoleCreate, rg.RoleXCreate:
-				role = "CREATE"
-			case rg.RoleRead, rg.RoleXRead:
-				role = "READ"
-			case rg.RoleUpdate, rg.RoleXUpdate:
-				role = "UPDATE"
-			case rg.RoleDelete, rg.RoleXDelete:
-				role = "DELETE"
-			}
-			if !helper.IsStringInSlice(role, roles[i]) {
-				roles[i] = append(roles[i], role)
-			}
-		}
-	}
-
 	res := signInResponse{
-		IsLoggedIn: true,
-		Modules:    roles,
+		IsLoggedIn:    true,
+		Modules:       sess.Abilities(),
+		RecoveryCodes: recoveryCodes,
 	}
 
 	template.RenderJSONResponse(w, new(template.Response).
 		SetCode(http.StatusOK).
 		SetData(res))
-	return
 }
 
 // ForgotHandler handles the http request for create a new session of user
@@ -509,6 +564,12 @@ func ForgotHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 			return
 		}
 
+		isAllowed, retryAfter, err := forgotCodeLimit.Hit(args.Email)
+		if err == nil && !isAllowed {
+			ratelimit.Reject(w, retryAfter)
+			return
+		}
+
 		// generate verification code
 		verification, err := user.GenerateVerification(u.IdentityCode)
 		if err != nil {
@@ -524,7 +585,7 @@ func ForgotHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		res := forgotResponse{
 			Email:          args.Email,
 			ExpireDuration: verification.ExpireDuration,
-			MaxAttempt:     3,
+			MaxAttempt:     user.MaxVerificationAttempt,
 		}
 
 		template.RenderJSONResponse(w, new(template.Response).
@@ -688,11 +749,6 @@ func UpdateProfileHandler(w http.ResponseWriter, r *http.Request, ps httprouter.
 		return
 	}
 
-	roles := make(map[string][]string)
-	if u.RoleGroupsID.Valid {
-		roles = rg.GetModuleAccess(u.RoleGroupsID.Int64)
-	}
-
 	sess = &auth.User{
 		ID:           u.ID,
 		Name:         u.Name,
@@ -703,7 +759,6 @@ func UpdateProfileHandler(w http.ResponseWriter, r *http.Request, ps httprouter.
 		IdentityCode: u.IdentityCode,
 		LineID:       u.LineID.String,
 		Phone:        u.Phone.String,
-		Roles:        roles,
 	}
 
 	sess.UpdateSession()
@@ -784,11 +839,19 @@ func SignOutHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params
 
 	sess := r.Context().Value("User").(*auth.User)
 
+	if sess.TokenID != 0 {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusBadRequest).
+			AddError("Revoke the api token instead"))
+		return
+	}
+
 	cookie, err := sess.DestroySession(r)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError).
 			SetMessage("Internal server error"))
+		return
 	}
 	http.SetCookie(w, cookie)
 
@@ -814,7 +877,7 @@ func SignOutHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params
 func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleUser, rg.RoleRead, rg.RoleXRead) {
+	if !sess.Authorize(rg.ModuleUser, rg.RoleRead) {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -892,7 +955,7 @@ func DetailHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleUser, rg.RoleUpdate, rg.RoleXUpdate) {
+	if !sess.Authorize(rg.ModuleUser, rg.RoleUpdate) {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -943,7 +1006,7 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		}
 	}
 
-	err = user.Update(args.IdentityCode, args.Name, args.Note, args.Phone, args.LineID, args.Gender, args.Status)
+	old, err := user.GetByIdentityCode(args.IdentityCode)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError).
@@ -951,17 +1014,38 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
-	u, err := user.GetByIdentityCode(args.IdentityCode)
+	tx := conn.DB.MustBegin()
+	err = user.Update(args.IdentityCode, args.Name, args.Note, args.Phone, args.LineID, args.Gender, args.Status, tx)
 	if err != nil {
+		tx.Rollback()
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError).
 			AddError("Internal server error"))
 		return
 	}
 
-	roles := make(map[string][]string)
-	if u.RoleGroupsID.Valid {
-		roles = rg.GetModuleAccess(u.RoleGroupsID.Int64)
+	u := old
+	u.Name, u.Note, u.Phone, u.LineID, u.Gender, u.Status = args.Name, args.Note, args.Phone, args.LineID, args.Gender, args.Status
+	if u.Gender != user.GenderMale && u.Gender != user.GenderFemale {
+		u.Gender = user.GenderUndefined
+	}
+
+	diff := audit.NewDiff(auditFields(old), auditFields(u))
+	err = audit.Insert(sess.ID, audit.ActionUpdate, audit.TableUsers, strconv.FormatInt(u.ID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError).
+			AddError("Internal server error"))
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError).
+			AddError("Internal server error"))
+		return
 	}
 
 	sess = &auth.User{
@@ -974,7 +1058,6 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		IdentityCode: u.IdentityCode,
 		LineID:       u.LineID.String,
 		Phone:        u.Phone.String,
-		Roles:        roles,
 	}
 
 	go sess.UpdateSession()
@@ -996,7 +1079,7 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 func DeleteHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleUser, rg.RoleDelete, rg.RoleXDelete) {
+	if !sess.Authorize(rg.ModuleUser, rg.RoleDelete) {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -1022,7 +1105,7 @@ func DeleteHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
-	u, err := user.GetByIdentityCode(args.IdentityCode, user.ColID)
+	u, err := user.GetByIdentityCode(args.IdentityCode)
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusBadRequest).
@@ -1030,7 +1113,25 @@ func DeleteHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
-	err = user.Delete(args.IdentityCode)
+	tx := conn.DB.MustBegin()
+	err = user.Delete(args.IdentityCode, tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	diff := audit.NewDiff(auditFields(u), nil)
+	err = audit.Insert(sess.ID, audit.ActionDelete, audit.TableUsers, strconv.FormatInt(u.ID, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError))
@@ -1065,7 +1166,7 @@ func DeleteHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 
 	sess := r.Context().Value("User").(*auth.User)
-	if !sess.IsHasRoles(rg.ModuleUser, rg.RoleCreate, rg.RoleXCreate) {
+	if !sess.Authorize(rg.ModuleUser, rg.RoleCreate) {
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusForbidden).
 			AddError("You don't have privilege"))
@@ -1104,22 +1205,45 @@ func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		return
 	}
 
-	err = user.Create(args.IdentityCode, args.Name, args.Email)
+	tx := conn.DB.MustBegin()
+	id, err := user.Create(args.IdentityCode, args.Name, args.Email, tx)
 	if err != nil {
+		tx.Rollback()
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
+	diff := audit.NewDiff(nil, map[string]interface{}{
+		"identity_code": args.IdentityCode,
+		"name":          args.Name,
+		"email":         args.Email,
+	})
+	err = audit.Insert(sess.ID, audit.ActionCreate, audit.TableUsers, strconv.FormatInt(id, 10), diff, helper.ClientIP(r), tx)
+	if err != nil {
+		tx.Rollback()
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError))
 		return
 	}
 
 	// generate verification code
-	verification, err := user.GenerateVerification(args.IdentityCode)
+	verification, err := user.GenerateVerification(args.IdentityCode, tx)
 	if err != nil {
+		tx.Rollback()
 		template.RenderJSONResponse(w, new(template.Response).
 			SetCode(http.StatusInternalServerError).
 			AddError("Internal server error"))
 		return
 	}
 
+	err = tx.Commit()
+	if err != nil {
+		template.RenderJSONResponse(w, new(template.Response).
+			SetCode(http.StatusInternalServerError))
+		return
+	}
+
 	// change to email template
 	go email.SendAccountCreated(args.Name, args.Email, verification.Code)
 
@@ -1128,3 +1252,18 @@ func CreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params)
 		SetCode(http.StatusOK))
 	return
 }
+
+// auditFields returns the fields of the user which are recorded by the audit log, the password is never recorded
+func auditFields(u user.User) map[string]interface{} {
+	return map[string]interface{}{
+		"name":          u.Name,
+		"email":         u.Email,
+		"gender":        u.Gender,
+		"note":          u.Note,
+		"status":        u.Status,
+		"identity_code": u.IdentityCode,
+		"line_id":       u.LineID.String,
+		"phone":         u.Phone.String,
+		"rolegroups_id": u.RoleGroupsID.Int64,
+	}
+}
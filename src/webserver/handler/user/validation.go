@@ -2,9 +2,11 @@ package user
 
 import (
 	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"html"
 	"strconv"
+	"strings"
 
 	"github.com/melodiez14/meiko/src/module/user"
 
@@ -47,6 +49,9 @@ func (params signUpParams) validate() (signUpArgs, error) {
 	if len(params.Password) < alias.UserPasswordLengthMin {
 		return args, fmt.Errorf("Error validation: password at least consist of 6 characters")
 	}
+	if len(params.Password) > alias.UserPasswordLengthMax {
+		return args, fmt.Errorf("Error validation: password at most consist of 72 characters")
+	}
 	if !helper.IsPassword(params.Password) {
 		return args, fmt.Errorf("Error validation: password should contains at least uppercase, lowercase, and numeric")
 	}
@@ -55,7 +60,7 @@ func (params signUpParams) validate() (signUpArgs, error) {
 		IdentityCode: id,
 		Name:         name,
 		Email:        email,
-		Password:     helper.StringToMD5(params.Password),
+		Password:     params.Password,
 	}
 	return args, nil
 }
@@ -192,7 +197,7 @@ func (params signInParams) validate() (signInArgs, error) {
 
 	args = signInArgs{
 		Email:    email,
-		Password: helper.StringToMD5(params.Password),
+		Password: params.Password,
 	}
 	return args, nil
 }
@@ -320,6 +325,9 @@ func (params changePasswordParams) validate() (changePasswordArgs, error) {
 	if len(params.Password) < alias.UserPasswordLengthMin {
 		return args, fmt.Errorf("Error validation: password at least consist of 6 characters")
 	}
+	if len(params.Password) > alias.UserPasswordLengthMax {
+		return args, fmt.Errorf("Error validation: password at most consist of 72 characters")
+	}
 	if !helper.IsPassword(params.Password) {
 		return args, fmt.Errorf("Error validation: password should contains at least uppercase, lowercase, and numeric")
 	}
@@ -331,8 +339,8 @@ func (params changePasswordParams) validate() (changePasswordArgs, error) {
 	args = changePasswordArgs{
 		IdentityCode: identityCode,
 		Email:        email,
-		OldPassword:  helper.StringToMD5(params.OldPassword),
-		Password:     helper.StringToMD5(params.Password),
+		OldPassword:  params.OldPassword,
+		Password:     params.Password,
 	}
 	return args, nil
 }
@@ -390,6 +398,9 @@ func (params forgotParams) validate() (forgotArgs, error) {
 	if len(params.Password) < alias.UserPasswordLengthMin {
 		return args, fmt.Errorf("Error validation: password at least consist of 6 characters")
 	}
+	if len(params.Password) > alias.UserPasswordLengthMax {
+		return args, fmt.Errorf("Error validation: password at most consist of 72 characters")
+	}
 	if !helper.IsPassword(params.Password) {
 		return args, fmt.Errorf("Error validation: password should contains at least uppercase, lowercase, and numeric")
 	}
@@ -398,7 +409,7 @@ func (params forgotParams) validate() (forgotArgs, error) {
 		Email:      email,
 		IsSendCode: false,
 		Code:       uint16(code),
-		Password:   helper.StringToMD5(params.Password),
+		Password:   params.Password,
 	}
 	return args, nil
 }
@@ -558,3 +569,242 @@ func (params createParams) validate() (createArgs, error) {
 
 	return args, nil
 }
+
+func (params revokeSessionParams) validate() (revokeSessionArgs, error) {
+	var args revokeSessionArgs
+	sessionID, err := normalizeSessionID(params.SessionID)
+	if err != nil {
+		return args, err
+	}
+
+	args = revokeSessionArgs{
+		SessionID: sessionID,
+	}
+	return args, nil
+}
+
+func (params userSessionParams) validate() (userSessionArgs, error) {
+	var args userSessionArgs
+	identityCode, err := helper.NormalizeIdentity(params.IdentityCode)
+	if err != nil {
+		return args, fmt.Errorf("Error validation: ID should be numeric")
+	}
+
+	args = userSessionArgs{
+		IdentityCode: identityCode,
+	}
+	return args, nil
+}
+
+func (params revokeUserSessionParams) validate() (revokeUserSessionArgs, error) {
+	var args revokeUserSessionArgs
+	identityCode, err := helper.NormalizeIdentity(params.IdentityCode)
+	if err != nil {
+		return args, fmt.Errorf("Error validation: ID should be numeric")
+	}
+
+	sessionID, err := normalizeSessionID(params.SessionID)
+	if err != nil {
+		return args, err
+	}
+
+	args = revokeUserSessionArgs{
+		IdentityCode: identityCode,
+		SessionID:    sessionID,
+	}
+	return args, nil
+}
+
+// normalizeSessionID validates the public session id, it's the 32 characters hexadecimal
+func normalizeSessionID(sessionID string) (string, error) {
+	sessionID = helper.Trim(sessionID)
+	if helper.IsEmpty(sessionID) {
+		return "", fmt.Errorf("Error validation: session_id can't be empty")
+	}
+
+	if _, err := hex.DecodeString(sessionID); err != nil || len(sessionID) != alias.UserSessionIDLength {
+		return "", fmt.Errorf("Error validation: session_id is invalid")
+	}
+	return strings.ToLower(sessionID), nil
+}
+
+func (params signInTwoFactorParams) validate() (signInTwoFactorArgs, error) {
+
+	var args signInTwoFactorArgs
+	params = signInTwoFactorParams{
+		Challenge:    helper.Trim(params.Challenge),
+		Code:         helper.Trim(params.Code),
+		RecoveryCode: helper.Trim(params.RecoveryCode),
+	}
+
+	if helper.IsEmpty(params.Challenge) {
+		return args, fmt.Errorf("Error validation: challenge can't be empty")
+	}
+
+	if helper.IsEmpty(params.Code) == helper.IsEmpty(params.RecoveryCode) {
+		return args, fmt.Errorf("Error validation: either code or recovery_code must be filled")
+	}
+
+	if !helper.IsEmpty(params.Code) && !isTOTPCode(params.Code) {
+		return args, fmt.Errorf("Error validation: code must be 6 digits")
+	}
+
+	if !helper.IsEmpty(params.RecoveryCode) && !isRecoveryCode(params.RecoveryCode) {
+		return args, fmt.Errorf("Error validation: recovery_code is invalid")
+	}
+
+	args = signInTwoFactorArgs{
+		Challenge:    params.Challenge,
+		Code:         params.Code,
+		RecoveryCode: params.RecoveryCode,
+	}
+	return args, nil
+}
+
+func (params enrollTwoFactorParams) validate() (enrollTwoFactorArgs, error) {
+
+	var args enrollTwoFactorArgs
+	challenge := helper.Trim(params.Challenge)
+	if helper.IsEmpty(challenge) {
+		return args, fmt.Errorf("Error validation: challenge can't be empty")
+	}
+
+	args = enrollTwoFactorArgs{
+		Challenge: challenge,
+	}
+	return args, nil
+}
+
+func (params confirmTwoFactorParams) validate() (confirmTwoFactorArgs, error) {
+
+	var args confirmTwoFactorArgs
+	code := helper.Trim(params.Code)
+	if !isTOTPCode(code) {
+		return args, fmt.Errorf("Error validation: code must be 6 digits")
+	}
+
+	args = confirmTwoFactorArgs{
+		Code: code,
+	}
+	return args, nil
+}
+
+func (params disableTwoFactorParams) validate() (disableTwoFactorArgs, error) {
+
+	var args disableTwoFactorArgs
+	params = disableTwoFactorParams{
+		Password: html.EscapeString(params.Password),
+		Code:     helper.Trim(params.Code),
+	}
+
+	if helper.IsEmpty(params.Password) {
+		return args, fmt.Errorf("Error validation: password can't be empty")
+	}
+
+	if !isTOTPCode(params.Code) {
+		return args, fmt.Errorf("Error validation: code must be 6 digits")
+	}
+
+	args = disableTwoFactorArgs{
+		Password: params.Password,
+		Code:     params.Code,
+	}
+	return args, nil
+}
+
+// isTOTPCode returns whether the code is the 6 digits authenticator app code
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	_, err := strconv.ParseUint(code, 10, 32)
+	return err == nil
+}
+
+// isRecoveryCode returns whether the code has the recovery code format, the dash is optional
+func isRecoveryCode(code string) bool {
+	code = strings.Replace(code, "-", "", -1)
+	if len(code) != 10 {
+		return false
+	}
+	for _, c := range strings.ToUpper(code) {
+		if !(c >= 'A' && c <= 'Z') && !(c >= '2' && c <= '7') {
+			return false
+		}
+	}
+	return true
+}
+
+// validate reads the accounts of the imported rows. The first row may be the header which names the columns,
+// otherwise the columns are identity_code, name and email. The invalid row is reported in its errors so the
+// whole file can be previewed, the error is only returned if the file can't be imported at all
+func (params importParams) validate() (importArgs, error) {
+
+	var args importArgs
+	switch helper.Trim(params.IsConfirm) {
+	case "", "false":
+	case "true":
+		args.IsConfirm = true
+	default:
+		return args, fmt.Errorf("Error validation: is_confirm must be true or false")
+	}
+
+	columns := map[string]int{"identity_code": 0, "name": 1, "email": 2}
+	start := 0
+	if len(params.Rows) > 0 {
+		header := map[string]int{}
+		for i, val := range params.Rows[0] {
+			header[strings.ToLower(helper.Trim(val))] = i
+		}
+		if _, ok := header["identity_code"]; ok {
+			for col := range columns {
+				i, ok := header[col]
+				if !ok {
+					return args, fmt.Errorf("Error validation: %s column is not found", col)
+				}
+				columns[col] = i
+			}
+			start = 1
+		}
+	}
+
+	cell := func(row []string, col string) string {
+		if columns[col] >= len(row) {
+			return ""
+		}
+		return row[columns[col]]
+	}
+
+	for i := start; i < len(params.Rows); i++ {
+		row := params.Rows[i]
+		if helper.IsEmpty(strings.Join(row, "")) {
+			continue
+		}
+
+		p := createParams{
+			IdentityCode: helper.Trim(cell(row, "identity_code")),
+			Name:         helper.Trim(cell(row, "name")),
+			Email:        helper.Trim(cell(row, "email")),
+		}
+		r := importRow{
+			Row:    i + 1,
+			Params: p,
+			Errors: []string{},
+		}
+		a, err := p.validate()
+		if err != nil {
+			r.Errors = append(r.Errors, err.Error())
+		} else {
+			r.Args = a
+		}
+		args.Rows = append(args.Rows, r)
+	}
+
+	if len(args.Rows) < 1 {
+		return args, fmt.Errorf("Error validation: file doesn't have any account")
+	}
+	if len(args.Rows) > alias.UserImportMax {
+		return args, fmt.Errorf("Error validation: maximum %d accounts per file", alias.UserImportMax)
+	}
+	return args, nil
+}
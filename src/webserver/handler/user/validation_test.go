@@ -7,7 +7,6 @@ import (
 	"testing"
 
 	"github.com/melodiez14/meiko/src/module/user"
-	"github.com/melodiez14/meiko/src/util/helper"
 )
 
 func Test_signUpParams_validate(t *testing.T) {
@@ -163,7 +162,7 @@ func Test_signUpParams_validate(t *testing.T) {
 				IdentityCode: 123456789012,
 				Name:         "Risal Falah",
 				Email:        "risal@live.com",
-				Password:     helper.StringToMD5(html.EscapeString(("<script>alert('Mantap123')</script>"))),
+				Password:     html.EscapeString("<script>alert('Mantap123')</script>"),
 			},
 			wantErr: false,
 		},
@@ -590,7 +589,7 @@ func Test_signInParams_validate(t *testing.T) {
 			},
 			want: signInArgs{
 				Email:    "risal@live.com",
-				Password: helper.StringToMD5(html.EscapeString(("<script>alert('Mantap123')</script>"))),
+				Password: html.EscapeString("<script>alert('Mantap123')</script>"),
 			},
 			wantErr: false,
 		},
@@ -1079,8 +1078,8 @@ func Test_changePasswordParams_validate(t *testing.T) {
 			want: changePasswordArgs{
 				IdentityCode: 140810140016,
 				Email:        "risal@live.com",
-				OldPassword:  helper.StringToMD5(html.EscapeString(("<script>alert('Mantap123')</script>"))),
-				Password:     helper.StringToMD5(html.EscapeString(("<script>alert('Mantap123')</script>"))),
+				OldPassword:  html.EscapeString("<script>alert('Mantap123')</script>"),
+				Password:     html.EscapeString("<script>alert('Mantap123')</script>"),
 			},
 			wantErr: false,
 		},
@@ -1258,7 +1257,7 @@ func Test_forgotParams_validate(t *testing.T) {
 				Email:      "risal@live.com",
 				IsSendCode: false,
 				Code:       1234,
-				Password:   helper.StringToMD5(html.EscapeString(("<script>alert('Mantap123')</script>"))),
+				Password:   html.EscapeString("<script>alert('Mantap123')</script>"),
 			},
 			wantErr: false,
 		},
@@ -1888,3 +1887,273 @@ func Test_createParams_validate(t *testing.T) {
 		})
 	}
 }
+
+func Test_revokeUserSessionParams_validate(t *testing.T) {
+	type fields struct {
+		IdentityCode string
+		SessionID    string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    revokeUserSessionArgs
+		wantErr bool
+	}{
+		{
+			name: "Test Case 1",
+			fields: fields{
+				IdentityCode: "abc",
+				SessionID:    "9f86d081884c7d659a2feaa0c55ad015",
+			},
+			want:    revokeUserSessionArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 2",
+			fields: fields{
+				IdentityCode: "140810140016",
+				SessionID:    "",
+			},
+			want:    revokeUserSessionArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 3",
+			fields: fields{
+				IdentityCode: "140810140016",
+				SessionID:    "9f86d081884c7d659a2feaa0c55ad01",
+			},
+			want:    revokeUserSessionArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 4",
+			fields: fields{
+				IdentityCode: "140810140016",
+				SessionID:    "9f86d081884c7d659a2feaa0c55ad01z",
+			},
+			want:    revokeUserSessionArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 5",
+			fields: fields{
+				IdentityCode: "140810140016",
+				SessionID:    " 9F86D081884C7D659A2FEAA0C55AD015 ",
+			},
+			want: revokeUserSessionArgs{
+				IdentityCode: 140810140016,
+				SessionID:    "9f86d081884c7d659a2feaa0c55ad015",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := revokeUserSessionParams{
+				IdentityCode: tt.fields.IdentityCode,
+				SessionID:    tt.fields.SessionID,
+			}
+			got, err := params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("revokeUserSessionParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("revokeUserSessionParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_signInTwoFactorParams_validate(t *testing.T) {
+	type fields struct {
+		Challenge    string
+		Code         string
+		RecoveryCode string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    signInTwoFactorArgs
+		wantErr bool
+	}{
+		{
+			name: "Test Case 1",
+			fields: fields{
+				Challenge: "",
+				Code:      "492039",
+			},
+			want:    signInTwoFactorArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 2",
+			fields: fields{
+				Challenge: "7Jx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq5Q0pMmJ7tXA",
+			},
+			want:    signInTwoFactorArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 3",
+			fields: fields{
+				Challenge:    "7Jx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq5Q0pMmJ7tXA",
+				Code:         "492039",
+				RecoveryCode: "k3pxp-jbswy",
+			},
+			want:    signInTwoFactorArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 4",
+			fields: fields{
+				Challenge: "7Jx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq5Q0pMmJ7tXA",
+				Code:      "49203a",
+			},
+			want:    signInTwoFactorArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 5",
+			fields: fields{
+				Challenge:    "7Jx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq5Q0pMmJ7tXA",
+				RecoveryCode: "k3pxp-jbs!y",
+			},
+			want:    signInTwoFactorArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 6",
+			fields: fields{
+				Challenge: " 7Jx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq5Q0pMmJ7tXA ",
+				Code:      " 492039 ",
+			},
+			want: signInTwoFactorArgs{
+				Challenge: "7Jx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq5Q0pMmJ7tXA",
+				Code:      "492039",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test Case 7",
+			fields: fields{
+				Challenge:    "7Jx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq5Q0pMmJ7tXA",
+				RecoveryCode: "K3PXP-JBSWY",
+			},
+			want: signInTwoFactorArgs{
+				Challenge:    "7Jx0b4Ra1k6mWb0U3p2kz2dQyJjvC5nq5Q0pMmJ7tXA",
+				RecoveryCode: "K3PXP-JBSWY",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := signInTwoFactorParams{
+				Challenge:    tt.fields.Challenge,
+				Code:         tt.fields.Code,
+				RecoveryCode: tt.fields.RecoveryCode,
+			}
+			got, err := params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("signInTwoFactorParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("signInTwoFactorParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_importParams_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  importParams
+		want    importArgs
+		wantErr bool
+	}{
+		{
+			name:    "Test Case 1",
+			params:  importParams{Rows: [][]string{{"identity_code", "name", "email"}, {"", "", ""}}},
+			want:    importArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 2",
+			params:  importParams{Rows: [][]string{{"identity_code", "name"}, {"140810140016", "Risal Falah"}}},
+			want:    importArgs{},
+			wantErr: true,
+		},
+		{
+			name:    "Test Case 3",
+			params:  importParams{Rows: [][]string{{"140810140016", "Risal Falah", "risal@live.com"}}, IsConfirm: "yes"},
+			want:    importArgs{},
+			wantErr: true,
+		},
+		{
+			name: "Test Case 4",
+			params: importParams{
+				Rows: [][]string{
+					{"Email", "Identity_Code", "Name"},
+					{"Risal@Live.com", "140810140016", " Risal Falah "},
+					{},
+					{"", "14081014", "Asep"},
+				},
+				IsConfirm: "true",
+			},
+			want: importArgs{
+				Rows: []importRow{
+					{
+						Row:    2,
+						Params: createParams{IdentityCode: "140810140016", Name: "Risal Falah", Email: "Risal@Live.com"},
+						Args:   createArgs{IdentityCode: 140810140016, Name: "Risal Falah", Email: "risal@live.com"},
+						Errors: []string{},
+					},
+					{
+						Row:    4,
+						Params: createParams{IdentityCode: "14081014", Name: "Asep"},
+						Errors: []string{"Error validation: ID should be numeric"},
+					},
+				},
+				IsConfirm: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test Case 5",
+			params: importParams{
+				Rows: [][]string{
+					{"140810140016", "Risal Falah", "risal@live.com"},
+				},
+			},
+			want: importArgs{
+				Rows: []importRow{
+					{
+						Row:    1,
+						Params: createParams{IdentityCode: "140810140016", Name: "Risal Falah", Email: "risal@live.com"},
+						Args:   createArgs{IdentityCode: 140810140016, Name: "Risal Falah", Email: "risal@live.com"},
+						Errors: []string{},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("importParams.validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("importParams.validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
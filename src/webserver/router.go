@@ -1,19 +1,35 @@
 package webserver
 
 import (
+	"time"
+
 	"github.com/julienschmidt/httprouter"
 	"github.com/melodiez14/meiko/src/util/auth"
+	"github.com/melodiez14/meiko/src/util/ratelimit"
 	"github.com/melodiez14/meiko/src/webserver/handler"
 	"github.com/melodiez14/meiko/src/webserver/handler/assignment"
+	"github.com/melodiez14/meiko/src/webserver/handler/attendance"
+	"github.com/melodiez14/meiko/src/webserver/handler/audit"
 	"github.com/melodiez14/meiko/src/webserver/handler/bot"
+	"github.com/melodiez14/meiko/src/webserver/handler/calendar"
 	"github.com/melodiez14/meiko/src/webserver/handler/course"
 	"github.com/melodiez14/meiko/src/webserver/handler/file"
+	"github.com/melodiez14/meiko/src/webserver/handler/grade"
 	"github.com/melodiez14/meiko/src/webserver/handler/information"
 	"github.com/melodiez14/meiko/src/webserver/handler/place"
 	"github.com/melodiez14/meiko/src/webserver/handler/rolegroup"
+	"github.com/melodiez14/meiko/src/webserver/handler/token"
 	"github.com/melodiez14/meiko/src/webserver/handler/user"
 )
 
+// the limit of the unauthenticated account request per ip address
+var (
+	registerIPLimit     = ratelimit.Limit{Name: "register:ip", Max: 10, Window: time.Hour}
+	verificationIPLimit = ratelimit.Limit{Name: "verification:ip", Max: 20, Window: 15 * time.Minute}
+	signInIPLimit       = ratelimit.Limit{Name: "signin:ip", Max: 20, Window: 15 * time.Minute}
+	forgotIPLimit       = ratelimit.Limit{Name: "forgot:ip", Max: 20, Window: 15 * time.Minute}
+)
+
 // Load returns all routing of this server
 func loadRouter(r *httprouter.Router) {
 
@@ -22,29 +38,62 @@ func loadRouter(r *httprouter.Router) {
 
 	// ========================== User Handler ==========================
 	// User section
-	r.POST("/api/v1/user/register", auth.OptionalAuthorize(user.SignUpHandler))
-	r.POST("/api/v1/user/verify", auth.OptionalAuthorize(user.EmailVerificationHandler))
-	r.POST("/api/v1/user/signin", auth.OptionalAuthorize(user.SignInHandler))
-	r.POST("/api/v1/user/forgot", auth.OptionalAuthorize(user.ForgotHandler))
+	r.POST("/api/v1/user/register", ratelimit.Middleware(registerIPLimit, ratelimit.ByIP, auth.OptionalAuthorize(user.SignUpHandler)))
+	r.POST("/api/v1/user/verify", ratelimit.Middleware(verificationIPLimit, ratelimit.ByIP, auth.OptionalAuthorize(user.EmailVerificationHandler)))
+	r.POST("/api/v1/user/signin", ratelimit.Middleware(signInIPLimit, ratelimit.ByIP, auth.OptionalAuthorize(user.SignInHandler)))
+	r.POST("/api/v1/user/forgot", ratelimit.Middleware(forgotIPLimit, ratelimit.ByIP, auth.OptionalAuthorize(user.ForgotHandler)))
+	r.POST("/api/v1/user/signin/2fa", ratelimit.Middleware(signInIPLimit, ratelimit.ByIP, auth.OptionalAuthorize(user.SignInTwoFactorHandler)))
+	r.POST("/api/v1/user/signin/2fa/enroll", ratelimit.Middleware(signInIPLimit, ratelimit.ByIP, auth.OptionalAuthorize(user.SignInEnrollHandler)))
 	r.POST("/api/v1/user/signout", auth.MustAuthorize(user.SignOutHandler)) // delete
 	r.POST("/api/v1/user/profile", auth.MustAuthorize(user.UpdateProfileHandler))
 	r.GET("/api/v1/user/profile", auth.MustAuthorize(user.GetProfileHandler))
 	r.POST("/api/v1/user/changepassword", auth.MustAuthorize(user.ChangePasswordHandler))
+	r.GET("/api/v1/user/session", auth.MustAuthorize(user.GetSessionHandler))
+	r.POST("/api/v1/user/session/revoke", auth.MustAuthorize(user.RevokeSessionHandler))           // delete
+	r.POST("/api/v1/user/session/revokeother", auth.MustAuthorize(user.RevokeOtherSessionHandler)) // delete
+	r.GET("/api/v1/user/2fa", auth.MustAuthorize(user.GetTwoFactorHandler))
+	r.POST("/api/v1/user/2fa/enroll", auth.MustAuthorize(user.EnrollTwoFactorHandler))
+	r.POST("/api/v1/user/2fa/confirm", auth.MustAuthorize(user.ConfirmTwoFactorHandler))
+	r.POST("/api/v1/user/2fa/disable", auth.MustAuthorize(user.DisableTwoFactorHandler)) // delete
+	r.POST("/api/v1/user/2fa/recovery", auth.MustAuthorize(user.RecoveryCodeHandler))
+	r.GET("/api/v1/user/token", auth.MustAuthorize(token.ReadHandler))
+	r.POST("/api/v1/user/token", auth.MustAuthorize(token.CreateHandler))
+	r.POST("/api/v1/user/token/:id/revoke", auth.MustAuthorize(token.RevokeHandler)) // delete
 
 	// Admin section
 	r.GET("/api/admin/v1/user", auth.MustAuthorize(user.ReadHandler))
 	r.POST("/api/admin/v1/user", auth.MustAuthorize(user.CreateHandler))
+	r.POST("/api/admin/v1/import/user", auth.MustAuthorize(user.ImportHandler))
 	r.GET("/api/admin/v1/user/:id", auth.MustAuthorize(user.DetailHandler))
 	r.POST("/api/admin/v1/user/:id", auth.MustAuthorize(user.UpdateHandler))              // patch
 	r.POST("/api/admin/v1/user/:id/activate", auth.MustAuthorize(user.ActivationHandler)) // patch
 	r.POST("/api/admin/v1/user/:id/delete", auth.MustAuthorize(user.DeleteHandler))       // delete
+	r.GET("/api/admin/v1/user/:id/session", auth.MustAuthorize(user.GetUserSessionHandler))
+	r.POST("/api/admin/v1/user/:id/session/revoke", auth.MustAuthorize(user.RevokeUserSessionHandler))       // delete
+	r.POST("/api/admin/v1/user/:id/session/revokeall", auth.MustAuthorize(user.RevokeAllUserSessionHandler)) // delete
 	// ======================== End User Handler ========================
 
 	// ======================== Rolegroup Handler =======================
 	// Admin section
 	r.GET("/api/v1/role", auth.OptionalAuthorize(auth.OptionalAuthorize(rolegroup.GetPrivilege)))
+	r.GET("/api/admin/v1/role", auth.MustAuthorize(rolegroup.ReadHandler))
+	r.POST("/api/admin/v1/role", auth.MustAuthorize(rolegroup.CreateHandler))
+	r.GET("/api/admin/v1/role/:id", auth.MustAuthorize(rolegroup.DetailHandler))
+	r.POST("/api/admin/v1/role/:id", auth.MustAuthorize(rolegroup.UpdateHandler))                  // patch
+	r.POST("/api/admin/v1/role/:id/delete", auth.MustAuthorize(rolegroup.DeleteHandler))           // delete
+	r.POST("/api/admin/v1/role/:id/2fa", auth.MustAuthorize(rolegroup.UpdateTwoFactorHandler))     // patch
+	r.POST("/api/admin/v1/role/:id/privilege", auth.MustAuthorize(rolegroup.GrantHandler))         // patch
+	r.POST("/api/admin/v1/role/:id/privilege/revoke", auth.MustAuthorize(rolegroup.RevokeHandler)) // delete
+	r.GET("/api/admin/v1/role/:id/user", auth.MustAuthorize(rolegroup.ReadUserHandler))
+	r.POST("/api/admin/v1/role/:id/user", auth.MustAuthorize(rolegroup.AssignUserHandler))        // patch
+	r.POST("/api/admin/v1/role/:id/user/remove", auth.MustAuthorize(rolegroup.RemoveUserHandler)) // delete
 	// ====================== End Rolegroup Handler =====================
 
+	// ========================== Audit Handler =========================
+	// Admin section
+	r.GET("/api/admin/v1/audit", auth.MustAuthorize(audit.ReadHandler))
+	// ======================== End Audit Handler =======================
+
 	// ========================== File Handler ==========================
 	// User section
 	r.GET("/api/v1/files/:payload/:filename", file.GetFileHandler)
@@ -57,21 +106,43 @@ func loadRouter(r *httprouter.Router) {
 	// User section
 	r.GET("/api/v1/course", auth.MustAuthorize(course.GetHandler))
 	r.GET("/api/v1/course/assistant", auth.MustAuthorize(course.GetAssistantHandler))
+	r.POST("/api/v1/enrollment/:schedule_id", auth.MustAuthorize(course.RequestEnrollmentHandler))
+	r.POST("/api/v1/enrollment/:schedule_id/withdraw", auth.MustAuthorize(course.WithdrawEnrollmentHandler)) //delete
 	// Admin section
 	r.POST("/api/admin/v1/course", auth.MustAuthorize(course.CreateHandler))
 	r.GET("/api/admin/v1/course", auth.MustAuthorize(course.ReadHandler))
-	r.GET("/api/admin/v1/course/:schedule_id", auth.MustAuthorize(course.ReadDetailHandler))                      //read
-	r.GET("/api/admin/v1/course/:schedule_id/parameter", auth.MustAuthorize(course.ReadScheduleParameterHandler)) //read
-	r.POST("/api/admin/v1/course/:schedule_id", auth.MustAuthorize(course.UpdateHandler))                         //patch
-	r.POST("/api/admin/v1/course/:schedule_id/delete", auth.MustAuthorize(course.DeleteScheduleHandler))          //delete
+	r.GET("/api/admin/v1/course/:schedule_id", auth.MustAuthorize(course.ReadDetailHandler))                            //read
+	r.GET("/api/admin/v1/course/:schedule_id/parameter", auth.MustAuthorize(course.ReadScheduleParameterHandler))       //read
+	r.POST("/api/admin/v1/course/:schedule_id", auth.MustAuthorize(course.UpdateHandler))                               //patch
+	r.POST("/api/admin/v1/course/:schedule_id/delete", auth.MustAuthorize(course.DeleteScheduleHandler))                //delete
+	r.GET("/api/admin/v1/course/:schedule_id/enrollment", auth.MustAuthorize(course.ReadEnrollmentHandler))             //read
+	r.POST("/api/admin/v1/course/:schedule_id/enrollment/approve", auth.MustAuthorize(course.ApproveEnrollmentHandler)) //patch
+	r.POST("/api/admin/v1/course/:schedule_id/enrollment/reject", auth.MustAuthorize(course.RejectEnrollmentHandler))   //delete
+	r.POST("/api/admin/v1/course/:schedule_id/capacity", auth.MustAuthorize(course.UpdateCapacityHandler))              //patch
+	r.POST("/api/admin/v1/course/:schedule_id/assistant", auth.MustAuthorize(course.AddAssistantHandler))
+	r.POST("/api/admin/v1/course/:schedule_id/assistant/delete", auth.MustAuthorize(course.RemoveAssistantHandler)) //delete
+	r.POST("/api/admin/v1/course/:schedule_id/calendar", auth.MustAuthorize(course.EnablePublicCalendarHandler))
+	r.POST("/api/admin/v1/course/:schedule_id/calendar/delete", auth.MustAuthorize(course.DisablePublicCalendarHandler)) //delete
+	r.GET("/api/admin/v1/assistant", auth.MustAuthorize(course.ReadAssistantHandler))
+	r.POST("/api/admin/v1/timetable", auth.MustAuthorize(course.GenerateTimetableHandler))
+	r.GET("/api/admin/v1/timetable/:draft_id", auth.MustAuthorize(course.ReadTimetableHandler))
+	r.POST("/api/admin/v1/timetable/:draft_id/commit", auth.MustAuthorize(course.CommitTimetableHandler))
 	r.GET("/api/admin/v1/list/course/parameter", auth.MustAuthorize(course.ListParameterHandler))
 	r.GET("/api/admin/v1/list/course/search", auth.MustAuthorize(course.SearchHandler))
 	// ======================== End Course Handler ======================
 
+	// ======================== Calendar Handler ========================
+	r.POST("/api/v1/calendar", auth.MustAuthorize(calendar.CreateTokenHandler))
+	r.POST("/api/v1/calendar/delete", auth.MustAuthorize(calendar.DeleteTokenHandler)) //delete
+	r.GET("/api/v1/calendar/user/:token", calendar.UserFeedHandler)
+	r.GET("/api/v1/calendar/schedule/:token", calendar.ScheduleFeedHandler)
+	// ====================== End Calendar Handler ======================
+
 	// =========================== Bot Handler ==========================
 	// User section
 	r.GET("/api/v1/bot", auth.MustAuthorize(bot.LoadHistoryHandler))
 	r.POST("/api/v1/bot", auth.MustAuthorize(bot.BotHandler))
+	r.POST("/api/v1/bot/line", bot.LineWebhookHandler)
 	// ========================= End Bot Handler ========================
 
 	// ========================= Assignment Handler ========================
@@ -80,14 +151,36 @@ func loadRouter(r *httprouter.Router) {
 	r.GET("/api/admin/v1/assignment", auth.MustAuthorize(assignment.GetAllAssignmentHandler))
 	r.POST("/api/admin/v1/assignment/update/:id", auth.MustAuthorize(assignment.UpdateHandler))
 	r.POST("/api/admin/v1/assignment/delete/:assignment_id", auth.MustAuthorize(assignment.DeleteAssignmentHandler))
+	r.POST("/api/admin/v1/assignment/score/:assignment_id", auth.MustAuthorize(assignment.UpdateScoreHandler))
 	r.GET("/api/admin/v1/assignment/:id/:assignment_id", auth.MustAuthorize(assignment.GetUploadedAssignmentByAdminHandler))
 	r.POST("/api/v1/assignment/create", auth.MustAuthorize(assignment.CreateHandlerByUser))
 	r.GET("/api/v1/assignment/:schedule_id/:assignment_id", auth.MustAuthorize(assignment.GetUploadedAssignmentByUserHandler))
 
 	// r.GET("/api/v1/assignment/summary", auth.MustAuthorize(assignment.GetSummaryHandler))
 	// ========================= End Assignment Handler ========================
-	// // Attendance Handler
-	// r.GET("/api/v1/attendance/summary", auth.MustAuthorize(attendance.GetSummaryHandler))
+
+	// ======================= Attendance Handler =======================
+	// User section
+	r.GET("/api/v1/attendance", auth.MustAuthorize(attendance.GetHandler))
+	r.GET("/api/v1/attendance/summary", auth.MustAuthorize(attendance.GetSummaryHandler))
+	r.POST("/api/v1/attendance/checkin", auth.MustAuthorize(attendance.CheckInHandler))
+	// Admin section
+	r.POST("/api/admin/v1/attendance/meeting", auth.MustAuthorize(attendance.CreateMeetingHandler))
+	r.GET("/api/admin/v1/attendance/meeting", auth.MustAuthorize(attendance.ReadMeetingHandler))
+	r.GET("/api/admin/v1/attendance/meeting/:meeting_id", auth.MustAuthorize(attendance.ReadMeetingDetailHandler))
+	r.POST("/api/admin/v1/attendance/meeting/:meeting_id", auth.MustAuthorize(attendance.UpdateMeetingHandler)) // patch
+	r.POST("/api/admin/v1/attendance/meeting/:meeting_id/code", auth.MustAuthorize(attendance.CreateCodeHandler))
+	r.GET("/api/admin/v1/attendance/meeting/:meeting_id/qr", auth.MustAuthorize(attendance.ReadQRHandler))
+	// ===================== End Attendance Handler =====================
+
+	// ========================= Grade Handler ==========================
+	// User section
+	r.GET("/api/v1/grade", auth.MustAuthorize(grade.GetHandler))
+	r.GET("/api/v1/grade/:schedule_id", auth.MustAuthorize(grade.GetDetailHandler))
+	// Admin section
+	r.GET("/api/admin/v1/grade", auth.MustAuthorize(grade.ReadHandler))
+	// ======================= End Grade Handler ========================
+
 	// r.GET("/api/v1/notification", auth.MustAuthorize(notification.GetHandler))
 
 	// ======================= Information Handler ======================
@@ -98,6 +191,7 @@ func loadRouter(r *httprouter.Router) {
 	// ========================== Place Handler =========================
 	// Public section
 	r.GET("/api/v1/place/search", place.SearchHandler)
+	r.GET("/api/admin/v1/place/:place_id/slot", auth.MustAuthorize(place.ReadSlotHandler))
 	// ======================== End Place Handler =======================
 
 	// Catch
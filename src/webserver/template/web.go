@@ -12,7 +12,7 @@ type Response struct {
 	Message string      `json:"message,omitempty"`
 	Error   []string    `json:"error,omitempty"`
 	Code    int         `json:"code"`
-	Data    interface{} `json:"data, omitempty"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
 func (r *Response) SetMessage(msg string) *Response {
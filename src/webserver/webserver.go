@@ -7,11 +7,14 @@ import (
 	"time"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/melodiez14/meiko/src/util/helper"
 )
 
 // Config is used for the setting of web server
 type Config struct {
 	Port string
+	// TrustedProxies are the reverse proxies allowed to set X-Forwarded-For, e.g. 127.0.0.1 or 10.0.0.0/8
+	TrustedProxies []string `json:"trusted_proxies"`
 }
 
 type requestLogger struct {
@@ -29,6 +32,10 @@ func Start(cfg Config) {
 		port = ":" + os.Getenv("PORT")
 	}
 
+	if err := helper.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal(err)
+	}
+
 	r := httprouter.New()
 	loadRouter(r)
 